@@ -0,0 +1,238 @@
+package acm_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrCertificateNotFound = awserrors.New(awserrors.ErrNotFound, "certificate not found")
+	ErrNoRoute53           = awserrors.New(awserrors.ErrValidation, "no route53 connector")
+)
+
+// ListCertificates returns the certificates of the account with one of
+// statuses, or all of them when none is given.
+func (c *ACMConnector) ListCertificates(ctx context.Context, statuses ...types.CertificateStatus) (_ []types.CertificateSummary, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ACMConnector", "ListCertificates")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	paginator := acm.NewListCertificatesPaginator(c.client, &acm.ListCertificatesInput{
+		CertificateStatuses: statuses,
+	})
+
+	certificates := []types.CertificateSummary{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("List certificates error", zap.Error(err))
+			return nil, err
+		}
+
+		certificates = append(certificates, page.CertificateSummaryList...)
+	}
+
+	return certificates, nil
+}
+
+// DescribeCertificate returns a certificate or ErrCertificateNotFound.
+func (c *ACMConnector) DescribeCertificate(ctx context.Context, certificateArn string) (_ *types.CertificateDetail, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ACMConnector", "DescribeCertificate")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+		CertificateArn: aws.String(certificateArn),
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("%w: %s: %w", ErrCertificateNotFound, certificateArn, err)
+		}
+
+		c.logger.Error("Describe certificate error", zap.String("certificate_arn", certificateArn), zap.Error(err))
+		return nil, err
+	}
+
+	return result.Certificate, nil
+}
+
+// ExpiringCertificates returns the issued certificates expiring within
+// expiry_warning_days. Certificates ACM renews itself are included, since
+// a renewal stuck on validation still lets them expire.
+func (c *ACMConnector) ExpiringCertificates(ctx context.Context) (_ []types.CertificateSummary, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ACMConnector", "ExpiringCertificates")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	certificates, err := c.ListCertificates(ctx, types.CertificateStatusIssued)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().AddDate(0, 0, c.config.ExpiryWarningDays)
+
+	expiring := []types.CertificateSummary{}
+	for _, certificate := range certificates {
+		if certificate.NotAfter == nil || certificate.NotAfter.After(deadline) {
+			continue
+		}
+
+		c.logger.Warn("Certificate expiring",
+			zap.String("certificate_arn", aws.ToString(certificate.CertificateArn)),
+			zap.String("domain_name", aws.ToString(certificate.DomainName)),
+			zap.Time("not_after", *certificate.NotAfter),
+		)
+
+		expiring = append(expiring, certificate)
+	}
+
+	return expiring, nil
+}
+
+// RequestCertificate requests a DNS validated certificate for domain and
+// sans and returns its ARN. The certificate stays PENDING_VALIDATION until
+// its validation records exist, see ValidateDNS.
+func (c *ACMConnector) RequestCertificate(ctx context.Context, domain string, sans ...string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ACMConnector", "RequestCertificate")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	input := &acm.RequestCertificateInput{
+		DomainName:       aws.String(domain),
+		ValidationMethod: types.ValidationMethodDns,
+	}
+
+	if len(sans) > 0 {
+		input.SubjectAlternativeNames = sans
+	}
+
+	result, err := c.client.RequestCertificate(ctx, input)
+	if err != nil {
+		c.logger.Error("Request certificate error", zap.String("domain_name", domain), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.CertificateArn), nil
+}
+
+// RequestAndValidate requests a certificate and validates it through
+// Route 53, returning once it is issued.
+func (c *ACMConnector) RequestAndValidate(ctx context.Context, domain string, sans ...string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ACMConnector", "RequestAndValidate")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	certificateArn, err := c.RequestCertificate(ctx, domain, sans...)
+	if err != nil {
+		return "", err
+	}
+
+	return certificateArn, c.ValidateDNS(ctx, certificateArn)
+}
+
+// ValidationRecords returns the CNAME records that validate a
+// certificate. ACM adds them shortly after the request, so they are
+// polled for every poll_interval seconds. Domains sharing a record, such
+// as example.com and *.example.com, yield it once.
+func (c *ACMConnector) ValidationRecords(ctx context.Context, certificateArn string) (_ []types.ResourceRecord, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ACMConnector", "ValidationRecords")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Second
+
+	for {
+		certificate, err := c.DescribeCertificate(ctx, certificateArn)
+		if err != nil {
+			return nil, err
+		}
+
+		records, complete := validationRecords(certificate)
+		if complete {
+			return records, nil
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// ValidateDNS upserts the validation records of a certificate in the
+// Route 53 hosted zone and waits up to validation_timeout seconds for the
+// certificate to be issued.
+func (c *ACMConnector) ValidateDNS(ctx context.Context, certificateArn string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ACMConnector", "ValidateDNS")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if c.params.Route53 == nil {
+		return ErrNoRoute53
+	}
+
+	records, err := c.ValidationRecords(ctx, certificateArn)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		_, err := c.params.Route53.UpsertRecord(ctx, aws.ToString(record.Name), route53types.RRType(record.Type), aws.ToString(record.Value))
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.WaitForIssued(ctx, certificateArn)
+}
+
+// WaitForIssued waits up to validation_timeout seconds for a certificate
+// to be issued.
+func (c *ACMConnector) WaitForIssued(ctx context.Context, certificateArn string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ACMConnector", "WaitForIssued")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	waiter := acm.NewCertificateValidatedWaiter(c.client)
+	timeout := time.Duration(c.config.ValidationTimeout) * time.Second
+
+	err = waiter.Wait(ctx, &acm.DescribeCertificateInput{CertificateArn: aws.String(certificateArn)}, timeout)
+	if err != nil {
+		c.logger.Error("Wait for certificate validation error", zap.String("certificate_arn", certificateArn), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// validationRecords collects the DNS validation records of a certificate
+// and reports whether every domain has one yet.
+func validationRecords(certificate *types.CertificateDetail) ([]types.ResourceRecord, bool) {
+	seen := map[string]bool{}
+
+	var records []types.ResourceRecord
+	for _, validation := range certificate.DomainValidationOptions {
+		if validation.ValidationMethod != types.ValidationMethodDns {
+			continue
+		}
+
+		if validation.ResourceRecord == nil {
+			return nil, false
+		}
+
+		name := strings.ToLower(aws.ToString(validation.ResourceRecord.Name))
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		records = append(records, *validation.ResourceRecord)
+	}
+
+	return records, len(records) > 0
+}
@@ -0,0 +1,29 @@
+package acm_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. ACM_EXPIRY_WARNING_DAYS for the acm
+// scope.
+type Config struct {
+	ExpiryWarningDays int    `mapstructure:"expiry_warning_days" default:"30"`
+	ValidationTimeout int    `mapstructure:"validation_timeout" default:"1800"`
+	PollInterval      int    `mapstructure:"poll_interval" default:"5"`
+	ACMKey            string `mapstructure:"acm_key" default:"ABCDE"`
+	ACMSecret         string `mapstructure:"acm_secret" default:"example_secret"`
+	ACMToken          string `mapstructure:"acm_token" default:""`
+	ACMRegion         string `mapstructure:"acm_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *ACMConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *ACMConnector) Config() Config {
+	return c.config
+}
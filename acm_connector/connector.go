@@ -0,0 +1,250 @@
+package acm_connector
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/route53_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultExpiryWarningDays = 30
+	DefaultValidationTimeout = 1800
+	DefaultPollInterval      = 5
+	DefaultACMKey            = "ABCDE"
+	DefaultACMSecret         = "example_secret"
+	DefaultACMToken          = ""
+	DefaultACMRegion         = "us-west-1"
+)
+
+var preflightActions = []string{
+	"acm:ListCertificates",
+	"acm:DescribeCertificate",
+	"acm:RequestCertificate",
+}
+
+type ACMConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *acm.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider             `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig                `optional:"true"`
+	CredentialChain *awscredentials.Chain               `optional:"true"`
+	Route53         *route53_connector.Route53Connector `optional:"true"`
+	Tracer          *xray.Tracer                        `optional:"true"`
+	Breaker         *circuitbreaker.Breaker             `optional:"true"`
+	Prometheus      *metrics.Metrics                    `optional:"true"`
+	Telemetry       *telemetry.Telemetry                `optional:"true"`
+	Health          *health.Health                      `optional:"true"`
+	Faults          *faults.Injector                    `optional:"true"`
+	Client          *acm.Client                         `optional:"true"`
+}
+
+// Module provides the ACMConnector of scope. It uses the *acm.Client in
+// the graph, if any, instead of building one, and adds the connector's
+// middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the ACMConnector of scope, and its interfaces,
+// named after scope, for apps with more than one; see package instance.
+// It uses the *acm.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *ACMConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*acm.Client](scope, named),
+		instance.Provide[*ACMConnector](scope, named, func(p Params) *ACMConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &ACMConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *ACMConnector) CertificateManager {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *ACMConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting ACMConnector",
+		zap.String("acm_region", c.config.ACMRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = acm.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = acm.New(c.client.Options(), func(o *acm.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *ACMConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped ACMConnector")
+
+	return nil
+}
+
+func (c *ACMConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.ACMRegion,
+		Key:         c.config.ACMKey,
+		Secret:      c.config.ACMSecret,
+		Token:       c.config.ACMToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *ACMConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("acm_region")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("acm_key", DefaultACMKey)
+		v.NotPlaceholder("acm_secret", DefaultACMSecret)
+	}
+
+	return v.Err()
+}
+
+// probe lists a certificate.
+func (c *ACMConnector) probe(ctx context.Context) error {
+	_, err := c.client.ListCertificates(ctx, &acm.ListCertificatesInput{
+		MaxItems: aws.Int32(1),
+	})
+
+	return err
+}
+
+func (c *ACMConnector) GetClient() *acm.Client {
+	return c.client
+}
@@ -0,0 +1,21 @@
+package acm_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . CertificateManager
+
+// CertificateManager requests and tracks certificates.
+type CertificateManager interface {
+	ListCertificates(ctx context.Context, statuses ...types.CertificateStatus) ([]types.CertificateSummary, error)
+	DescribeCertificate(ctx context.Context, certificateArn string) (*types.CertificateDetail, error)
+	ExpiringCertificates(ctx context.Context) ([]types.CertificateSummary, error)
+	RequestCertificate(ctx context.Context, domain string, sans ...string) (string, error)
+	RequestAndValidate(ctx context.Context, domain string, sans ...string) (string, error)
+	WaitForIssued(ctx context.Context, certificateArn string) error
+}
+
+var _ CertificateManager = (*ACMConnector)(nil)
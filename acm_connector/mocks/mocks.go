@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/acm_connector (interfaces: CertificateManager)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . CertificateManager
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCertificateManager is a mock of CertificateManager interface.
+type MockCertificateManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockCertificateManagerMockRecorder
+}
+
+// MockCertificateManagerMockRecorder is the mock recorder for MockCertificateManager.
+type MockCertificateManagerMockRecorder struct {
+	mock *MockCertificateManager
+}
+
+// NewMockCertificateManager creates a new mock instance.
+func NewMockCertificateManager(ctrl *gomock.Controller) *MockCertificateManager {
+	mock := &MockCertificateManager{ctrl: ctrl}
+	mock.recorder = &MockCertificateManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCertificateManager) EXPECT() *MockCertificateManagerMockRecorder {
+	return m.recorder
+}
+
+// DescribeCertificate mocks base method.
+func (m *MockCertificateManager) DescribeCertificate(arg0 context.Context, arg1 string) (*types.CertificateDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeCertificate", arg0, arg1)
+	ret0, _ := ret[0].(*types.CertificateDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCertificate indicates an expected call of DescribeCertificate.
+func (mr *MockCertificateManagerMockRecorder) DescribeCertificate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCertificate", reflect.TypeOf((*MockCertificateManager)(nil).DescribeCertificate), arg0, arg1)
+}
+
+// ExpiringCertificates mocks base method.
+func (m *MockCertificateManager) ExpiringCertificates(arg0 context.Context) ([]types.CertificateSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpiringCertificates", arg0)
+	ret0, _ := ret[0].([]types.CertificateSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpiringCertificates indicates an expected call of ExpiringCertificates.
+func (mr *MockCertificateManagerMockRecorder) ExpiringCertificates(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpiringCertificates", reflect.TypeOf((*MockCertificateManager)(nil).ExpiringCertificates), arg0)
+}
+
+// ListCertificates mocks base method.
+func (m *MockCertificateManager) ListCertificates(arg0 context.Context, arg1 ...types.CertificateStatus) ([]types.CertificateSummary, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListCertificates", varargs...)
+	ret0, _ := ret[0].([]types.CertificateSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCertificates indicates an expected call of ListCertificates.
+func (mr *MockCertificateManagerMockRecorder) ListCertificates(arg0 any, arg1 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCertificates", reflect.TypeOf((*MockCertificateManager)(nil).ListCertificates), varargs...)
+}
+
+// RequestAndValidate mocks base method.
+func (m *MockCertificateManager) RequestAndValidate(arg0 context.Context, arg1 string, arg2 ...string) (string, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RequestAndValidate", varargs...)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestAndValidate indicates an expected call of RequestAndValidate.
+func (mr *MockCertificateManagerMockRecorder) RequestAndValidate(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestAndValidate", reflect.TypeOf((*MockCertificateManager)(nil).RequestAndValidate), varargs...)
+}
+
+// RequestCertificate mocks base method.
+func (m *MockCertificateManager) RequestCertificate(arg0 context.Context, arg1 string, arg2 ...string) (string, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RequestCertificate", varargs...)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestCertificate indicates an expected call of RequestCertificate.
+func (mr *MockCertificateManagerMockRecorder) RequestCertificate(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestCertificate", reflect.TypeOf((*MockCertificateManager)(nil).RequestCertificate), varargs...)
+}
+
+// WaitForIssued mocks base method.
+func (m *MockCertificateManager) WaitForIssued(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForIssued", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForIssued indicates an expected call of WaitForIssued.
+func (mr *MockCertificateManagerMockRecorder) WaitForIssued(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForIssued", reflect.TypeOf((*MockCertificateManager)(nil).WaitForIssued), arg0, arg1)
+}
@@ -0,0 +1,30 @@
+package appconfig_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. APPCONFIG_APPLICATION for the appconfig
+// scope.
+type Config struct {
+	Application       string `mapstructure:"application" default:""`
+	Environment       string `mapstructure:"environment" default:""`
+	Profile           string `mapstructure:"profile" default:""`
+	PollInterval      int    `mapstructure:"poll_interval" default:"60"`
+	AppConfigKey      string `mapstructure:"appconfig_key" default:"ABCDE"`
+	AppConfigSecret   string `mapstructure:"appconfig_secret" default:"example_secret"`
+	AppConfigToken    string `mapstructure:"appconfig_token" default:""`
+	AppConfigRegion   string `mapstructure:"appconfig_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *AppConfigConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *AppConfigConnector) Config() Config {
+	return c.config
+}
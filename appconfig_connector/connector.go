@@ -0,0 +1,391 @@
+package appconfig_connector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/appconfigdata"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultApplication     = ""
+	DefaultEnvironment     = ""
+	DefaultProfile         = ""
+	DefaultPollInterval    = 60
+	DefaultAppConfigKey    = "ABCDE"
+	DefaultAppConfigSecret = "example_secret"
+	DefaultAppConfigToken  = ""
+	DefaultAppConfigRegion = "us-west-1"
+)
+
+// AppConfig rejects poll intervals below 15 seconds
+const minPollInterval = 15
+
+var preflightActions = []string{
+	"appconfig:StartConfigurationSession",
+	"appconfig:GetLatestConfiguration",
+}
+
+type AppConfigConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *appconfigdata.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	token string
+	flags *Flags
+
+	mu      sync.Mutex
+	pollErr error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *appconfigdata.Client   `optional:"true"`
+}
+
+// Module provides the AppConfigConnector of scope. It uses the
+// *appconfigdata.Client in the graph, if any, instead of building one,
+// and adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the AppConfigConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *appconfigdata.Client named after
+// scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *AppConfigConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*appconfigdata.Client](scope, named),
+		instance.Provide[*AppConfigConnector](scope, named, func(p Params) *AppConfigConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &AppConfigConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+				flags:  &Flags{},
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *AppConfigConnector) FlagSource {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *AppConfigConnector) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting AppConfigConnector",
+		zap.String("application", c.config.Application),
+		zap.String("environment", c.config.Environment),
+		zap.String("profile", c.config.Profile),
+		zap.String("appconfig_region", c.config.AppConfigRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = appconfigdata.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = appconfigdata.New(c.client.Options(), func(o *appconfigdata.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	if err := c.startSession(ctx); err != nil {
+		return err
+	}
+
+	// Serve flags from the first poll so lookups never see an empty set
+	interval, err := c.poll(ctx)
+	if err != nil {
+		return err
+	}
+
+	loopCtx, cancel := context.WithCancel(inflight.Internal(context.Background()))
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go c.pollLoop(loopCtx, interval)
+
+	return nil
+}
+
+func (c *AppConfigConnector) onStop(ctx context.Context) error {
+
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped AppConfigConnector")
+
+	return nil
+}
+
+func (c *AppConfigConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.AppConfigRegion,
+		Key:         c.config.AppConfigKey,
+		Secret:      c.config.AppConfigSecret,
+		Token:       c.config.AppConfigToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *AppConfigConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("appconfig_region")
+
+	v.Required("application", "environment", "profile")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("appconfig_key", DefaultAppConfigKey)
+		v.NotPlaceholder("appconfig_secret", DefaultAppConfigSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reports the error of the last configuration poll, since polling
+// out of turn would use up the session token.
+func (c *AppConfigConnector) probe(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pollErr
+}
+
+func (c *AppConfigConnector) pollInterval() int32 {
+	return int32(max(c.config.PollInterval, minPollInterval))
+}
+
+func (c *AppConfigConnector) startSession(ctx context.Context) error {
+	result, err := c.client.StartConfigurationSession(ctx, &appconfigdata.StartConfigurationSessionInput{
+		ApplicationIdentifier:                aws.String(c.config.Application),
+		EnvironmentIdentifier:                aws.String(c.config.Environment),
+		ConfigurationProfileIdentifier:       aws.String(c.config.Profile),
+		RequiredMinimumPollIntervalInSeconds: aws.Int32(c.pollInterval()),
+	})
+	if err != nil {
+		c.logger.Error("Start configuration session error", zap.Error(err))
+		return err
+	}
+
+	c.token = aws.ToString(result.InitialConfigurationToken)
+
+	return nil
+}
+
+// poll fetches the latest configuration and returns the interval to wait
+// before the next poll. An empty configuration means no new deployment.
+func (c *AppConfigConnector) poll(ctx context.Context) (time.Duration, error) {
+	result, err := c.client.GetLatestConfiguration(ctx, &appconfigdata.GetLatestConfigurationInput{
+		ConfigurationToken: aws.String(c.token),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	c.token = aws.ToString(result.NextPollConfigurationToken)
+
+	interval := time.Duration(result.NextPollIntervalInSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Duration(c.pollInterval()) * time.Second
+	}
+
+	if len(result.Configuration) == 0 {
+		return interval, nil
+	}
+
+	c.logger.Info("Configuration deployed", zap.String("version", aws.ToString(result.VersionLabel)))
+
+	c.flags.update(ctx, result.Configuration)
+
+	return interval, nil
+}
+
+func (c *AppConfigConnector) pollLoop(ctx context.Context, interval time.Duration) {
+	defer close(c.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		next, err := c.poll(ctx)
+
+		c.mu.Lock()
+		c.pollErr = err
+		c.mu.Unlock()
+
+		if err == nil {
+			interval = next
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		c.logger.Warn("Poll configuration error", zap.Error(err))
+
+		// Tokens expire after 24 hours without use; a new session recovers
+		// from that and from any other rejected token.
+		if err := c.startSession(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Warn("Restart configuration session error", zap.Error(err))
+		}
+	}
+}
+
+// Flags returns the current feature flags.
+func (c *AppConfigConnector) Flags() *Flags {
+	return c.flags
+}
+
+func (c *AppConfigConnector) BoolFlag(name string, def bool) bool {
+	return c.flags.Bool(name, def)
+}
+
+func (c *AppConfigConnector) StringFlag(name string, attribute string, def string) string {
+	return c.flags.String(name, attribute, def)
+}
+
+// OnChange registers handler to run after each new deployment.
+func (c *AppConfigConnector) OnChange(handler ChangeHandler) {
+	c.flags.OnChange(handler)
+}
+
+func (c *AppConfigConnector) GetClient() *appconfigdata.Client {
+	return c.client
+}
@@ -0,0 +1,123 @@
+package appconfig_connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+const attrEnabled = "enabled"
+
+// ChangeHandler is called after a new configuration was deployed.
+type ChangeHandler func(ctx context.Context, flags *Flags)
+
+// Flags holds the latest configuration. Feature flag profiles deliver
+// {"<flag>": {"enabled": bool, "<attribute>": value, ...}, ...}; other
+// JSON profiles can be read with Decode.
+type Flags struct {
+	mu       sync.RWMutex
+	raw      []byte
+	flags    map[string]map[string]interface{}
+	handlers []ChangeHandler
+}
+
+func (f *Flags) update(ctx context.Context, raw []byte) {
+	flags := map[string]map[string]interface{}{}
+
+	// Free-form profiles need not have the flag shape
+	if err := json.Unmarshal(raw, &flags); err != nil {
+		flags = nil
+	}
+
+	f.mu.Lock()
+	f.raw = raw
+	f.flags = flags
+	handlers := f.handlers
+	f.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(ctx, f)
+	}
+}
+
+func (f *Flags) OnChange(handler ChangeHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.handlers = append(f.handlers, handler)
+}
+
+func (f *Flags) attribute(name string, attribute string) (interface{}, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	flag, ok := f.flags[name]
+	if !ok {
+		return nil, false
+	}
+
+	value, ok := flag[attribute]
+
+	return value, ok
+}
+
+// Bool reports whether flag name is enabled, or def when it is unknown.
+func (f *Flags) Bool(name string, def bool) bool {
+	value, ok := f.attribute(name, attrEnabled)
+	if !ok {
+		return def
+	}
+
+	enabled, ok := value.(bool)
+	if !ok {
+		return def
+	}
+
+	return enabled
+}
+
+// String returns a string attribute of flag name, or def when the flag or
+// attribute is unknown.
+func (f *Flags) String(name string, attribute string, def string) string {
+	value, ok := f.attribute(name, attribute)
+	if !ok {
+		return def
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return def
+	}
+
+	return s
+}
+
+// Int returns a number attribute of flag name, or def when the flag or
+// attribute is unknown.
+func (f *Flags) Int(name string, attribute string, def int) int {
+	value, ok := f.attribute(name, attribute)
+	if !ok {
+		return def
+	}
+
+	n, ok := value.(float64)
+	if !ok {
+		return def
+	}
+
+	return int(n)
+}
+
+// Decode unmarshals the raw configuration into out.
+func (f *Flags) Decode(out interface{}) error {
+	f.mu.RLock()
+	raw := f.raw
+	f.mu.RUnlock()
+
+	if raw == nil {
+		return fmt.Errorf("no configuration deployed")
+	}
+
+	return json.Unmarshal(raw, out)
+}
@@ -0,0 +1,11 @@
+package appconfig_connector
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . FlagSource
+
+// FlagSource evaluates feature flags.
+type FlagSource interface {
+	BoolFlag(name string, def bool) bool
+	StringFlag(name string, attribute string, def string) string
+}
+
+var _ FlagSource = (*AppConfigConnector)(nil)
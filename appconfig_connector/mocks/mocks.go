@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/appconfig_connector (interfaces: FlagSource)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . FlagSource
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFlagSource is a mock of FlagSource interface.
+type MockFlagSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlagSourceMockRecorder
+}
+
+// MockFlagSourceMockRecorder is the mock recorder for MockFlagSource.
+type MockFlagSourceMockRecorder struct {
+	mock *MockFlagSource
+}
+
+// NewMockFlagSource creates a new mock instance.
+func NewMockFlagSource(ctrl *gomock.Controller) *MockFlagSource {
+	mock := &MockFlagSource{ctrl: ctrl}
+	mock.recorder = &MockFlagSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlagSource) EXPECT() *MockFlagSourceMockRecorder {
+	return m.recorder
+}
+
+// BoolFlag mocks base method.
+func (m *MockFlagSource) BoolFlag(arg0 string, arg1 bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BoolFlag", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// BoolFlag indicates an expected call of BoolFlag.
+func (mr *MockFlagSourceMockRecorder) BoolFlag(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BoolFlag", reflect.TypeOf((*MockFlagSource)(nil).BoolFlag), arg0, arg1)
+}
+
+// StringFlag mocks base method.
+func (m *MockFlagSource) StringFlag(arg0, arg1, arg2 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StringFlag", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// StringFlag indicates an expected call of StringFlag.
+func (mr *MockFlagSourceMockRecorder) StringFlag(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StringFlag", reflect.TypeOf((*MockFlagSource)(nil).StringFlag), arg0, arg1, arg2)
+}
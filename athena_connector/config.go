@@ -0,0 +1,30 @@
+package athena_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. ATHENA_WORKGROUP for the athena scope.
+type Config struct {
+	Workgroup         string `mapstructure:"workgroup" default:"primary"`
+	Database          string `mapstructure:"database" default:"default"`
+	OutputLocation    string `mapstructure:"output_location" default:""`
+	PollInterval      int    `mapstructure:"poll_interval" default:"500"`
+	MaxPollInterval   int    `mapstructure:"max_poll_interval" default:"5000"`
+	AthenaKey         string `mapstructure:"athena_key" default:"ABCDE"`
+	AthenaSecret      string `mapstructure:"athena_secret" default:"example_secret"`
+	AthenaToken       string `mapstructure:"athena_token" default:""`
+	AthenaRegion      string `mapstructure:"athena_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *AthenaConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *AthenaConnector) Config() Config {
+	return c.config
+}
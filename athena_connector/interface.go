@@ -0,0 +1,19 @@
+package athena_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/athena/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . QueryRunner
+
+// QueryRunner runs Athena queries.
+type QueryRunner interface {
+	ExecuteQuery(ctx context.Context, sql string, params ...string) (*Result, error)
+	StartQuery(ctx context.Context, sql string, params ...string) (string, error)
+	WaitForQuery(ctx context.Context, queryExecutionID string) (*types.QueryExecution, error)
+	GetQueryResults(ctx context.Context, execution *types.QueryExecution) (*Result, error)
+}
+
+var _ QueryRunner = (*AthenaConnector)(nil)
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/athena_connector (interfaces: QueryRunner)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . QueryRunner
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/athena/types"
+	athena_connector "github.com/elmntri/zeitgeber-aws-modules/athena_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQueryRunner is a mock of QueryRunner interface.
+type MockQueryRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueryRunnerMockRecorder
+}
+
+// MockQueryRunnerMockRecorder is the mock recorder for MockQueryRunner.
+type MockQueryRunnerMockRecorder struct {
+	mock *MockQueryRunner
+}
+
+// NewMockQueryRunner creates a new mock instance.
+func NewMockQueryRunner(ctrl *gomock.Controller) *MockQueryRunner {
+	mock := &MockQueryRunner{ctrl: ctrl}
+	mock.recorder = &MockQueryRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQueryRunner) EXPECT() *MockQueryRunnerMockRecorder {
+	return m.recorder
+}
+
+// ExecuteQuery mocks base method.
+func (m *MockQueryRunner) ExecuteQuery(arg0 context.Context, arg1 string, arg2 ...string) (*athena_connector.Result, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExecuteQuery", varargs...)
+	ret0, _ := ret[0].(*athena_connector.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteQuery indicates an expected call of ExecuteQuery.
+func (mr *MockQueryRunnerMockRecorder) ExecuteQuery(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteQuery", reflect.TypeOf((*MockQueryRunner)(nil).ExecuteQuery), varargs...)
+}
+
+// GetQueryResults mocks base method.
+func (m *MockQueryRunner) GetQueryResults(arg0 context.Context, arg1 *types.QueryExecution) (*athena_connector.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueryResults", arg0, arg1)
+	ret0, _ := ret[0].(*athena_connector.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueryResults indicates an expected call of GetQueryResults.
+func (mr *MockQueryRunnerMockRecorder) GetQueryResults(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryResults", reflect.TypeOf((*MockQueryRunner)(nil).GetQueryResults), arg0, arg1)
+}
+
+// StartQuery mocks base method.
+func (m *MockQueryRunner) StartQuery(arg0 context.Context, arg1 string, arg2 ...string) (string, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartQuery", varargs...)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartQuery indicates an expected call of StartQuery.
+func (mr *MockQueryRunnerMockRecorder) StartQuery(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartQuery", reflect.TypeOf((*MockQueryRunner)(nil).StartQuery), varargs...)
+}
+
+// WaitForQuery mocks base method.
+func (m *MockQueryRunner) WaitForQuery(arg0 context.Context, arg1 string) (*types.QueryExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForQuery", arg0, arg1)
+	ret0, _ := ret[0].(*types.QueryExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForQuery indicates an expected call of WaitForQuery.
+func (mr *MockQueryRunnerMockRecorder) WaitForQuery(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForQuery", reflect.TypeOf((*MockQueryRunner)(nil).WaitForQuery), arg0, arg1)
+}
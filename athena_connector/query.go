@@ -0,0 +1,181 @@
+package athena_connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/athena"
+	"github.com/aws/aws-sdk-go-v2/service/athena/types"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+)
+
+var ErrQueryFailed = awserrors.New(awserrors.ErrValidation, "query failed")
+
+// ExecuteQuery runs sql in the configured workgroup and database and
+// returns every row. Params bind the query's ? placeholders in order and
+// are inserted as SQL literals, so quote strings: "'us-west-1'".
+func (c *AthenaConnector) ExecuteQuery(ctx context.Context, sql string, params ...string) (_ *Result, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "AthenaConnector", "ExecuteQuery")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	queryExecutionID, err := c.StartQuery(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	execution, err := c.WaitForQuery(ctx, queryExecutionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetQueryResults(ctx, execution)
+}
+
+// StartQuery starts sql and returns the query execution ID. Results go to
+// output_location, or the workgroup's location when empty.
+func (c *AthenaConnector) StartQuery(ctx context.Context, sql string, params ...string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "AthenaConnector", "StartQuery")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	input := &athena.StartQueryExecutionInput{
+		QueryString: aws.String(sql),
+		WorkGroup:   aws.String(c.config.Workgroup),
+		QueryExecutionContext: &types.QueryExecutionContext{
+			Database: aws.String(c.config.Database),
+		},
+	}
+
+	if len(params) > 0 {
+		input.ExecutionParameters = params
+	}
+
+	if outputLocation := c.config.OutputLocation; outputLocation != "" {
+		input.ResultConfiguration = &types.ResultConfiguration{
+			OutputLocation: aws.String(outputLocation),
+		}
+	}
+
+	result, err := c.client.StartQueryExecution(ctx, input)
+	if err != nil {
+		c.logger.Error("Start query execution error", zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.QueryExecutionId), nil
+}
+
+// WaitForQuery polls the query, starting at poll_interval milliseconds
+// and doubling up to max_poll_interval, until it ends. Cancelling ctx
+// stops the query.
+func (c *AthenaConnector) WaitForQuery(ctx context.Context, queryExecutionID string) (_ *types.QueryExecution, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "AthenaConnector", "WaitForQuery")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Millisecond
+	maxInterval := time.Duration(c.config.MaxPollInterval) * time.Millisecond
+
+	for {
+		result, err := c.client.GetQueryExecution(ctx, &athena.GetQueryExecutionInput{
+			QueryExecutionId: aws.String(queryExecutionID),
+		})
+		if err != nil {
+			c.logger.Error("Get query execution error", zap.String("query_execution_id", queryExecutionID), zap.Error(err))
+			return nil, err
+		}
+
+		execution := result.QueryExecution
+		if execution.Status != nil {
+			switch execution.Status.State {
+			case types.QueryExecutionStateSucceeded:
+				return execution, nil
+
+			case types.QueryExecutionStateFailed, types.QueryExecutionStateCancelled:
+				return nil, fmt.Errorf("%w: %s: %s: %s", ErrQueryFailed, queryExecutionID, execution.Status.State, aws.ToString(execution.Status.StateChangeReason))
+			}
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			c.stopQuery(queryExecutionID)
+			return nil, ctx.Err()
+		}
+
+		interval = min(interval*2, maxInterval)
+	}
+}
+
+// GetQueryResults reads every page of results of a succeeded query.
+func (c *AthenaConnector) GetQueryResults(ctx context.Context, execution *types.QueryExecution) (_ *Result, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "AthenaConnector", "GetQueryResults")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	queryExecutionID := aws.ToString(execution.QueryExecutionId)
+
+	paginator := athena.NewGetQueryResultsPaginator(c.client, &athena.GetQueryResultsInput{
+		QueryExecutionId: aws.String(queryExecutionID),
+	})
+
+	result := &Result{
+		QueryExecutionID: queryExecutionID,
+	}
+
+	// The first row of a SELECT's results repeats the column names
+	skipHeader := execution.StatementType == types.StatementTypeDml
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("Get query results error", zap.String("query_execution_id", queryExecutionID), zap.Error(err))
+			return nil, err
+		}
+
+		if page.ResultSet == nil {
+			continue
+		}
+
+		if result.Columns == nil && page.ResultSet.ResultSetMetadata != nil {
+			for _, column := range page.ResultSet.ResultSetMetadata.ColumnInfo {
+				result.Columns = append(result.Columns, Column{
+					Name: aws.ToString(column.Name),
+					Type: aws.ToString(column.Type),
+				})
+			}
+		}
+
+		rows := page.ResultSet.Rows
+		if skipHeader && len(rows) > 0 {
+			rows = rows[1:]
+			skipHeader = false
+		}
+
+		for _, row := range rows {
+			values := make([]*string, len(row.Data))
+			for i, datum := range row.Data {
+				values[i] = datum.VarCharValue
+			}
+
+			result.Rows = append(result.Rows, values)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *AthenaConnector) stopQuery(queryExecutionID string) {
+	ctx, cancel := context.WithTimeout(inflight.Internal(context.Background()), 10*time.Second)
+	defer cancel()
+
+	_, err := c.client.StopQueryExecution(ctx, &athena.StopQueryExecutionInput{
+		QueryExecutionId: aws.String(queryExecutionID),
+	})
+	if err != nil {
+		c.logger.Warn("Stop query execution error", zap.String("query_execution_id", queryExecutionID), zap.Error(err))
+	}
+}
@@ -0,0 +1,218 @@
+package athena_connector
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrInvalidTarget = awserrors.New(awserrors.ErrValidation, "target must be a pointer to a slice")
+
+// Athena renders dates and timestamps in these layouts.
+const (
+	dateLayout      = "2006-01-02"
+	timestampLayout = "2006-01-02 15:04:05.999999999"
+)
+
+type Column struct {
+	Name string
+	Type string
+}
+
+// Result holds the rows of a query as Athena returns them: strings, with
+// nil for NULL.
+type Result struct {
+	QueryExecutionID string
+	Columns          []Column
+	Rows             [][]*string
+}
+
+// Maps returns each row keyed by column name, with integer, floating
+// point and boolean columns converted to int64, float64 and bool.
+func (r *Result) Maps() ([]map[string]interface{}, error) {
+	maps := make([]map[string]interface{}, 0, len(r.Rows))
+
+	for _, row := range r.Rows {
+		m := make(map[string]interface{}, len(r.Columns))
+
+		for i, column := range r.Columns {
+			if i >= len(row) || row[i] == nil {
+				m[column.Name] = nil
+				continue
+			}
+
+			value, err := convert(*row[i], column.Type)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", column.Name, err)
+			}
+
+			m[column.Name] = value
+		}
+
+		maps = append(maps, m)
+	}
+
+	return maps, nil
+}
+
+// Unmarshal decodes the rows into out, a pointer to a slice of structs or
+// struct pointers. Columns match the field tagged `athena:"name"`, or the
+// field whose name equals the column ignoring case and underscores.
+// Pointer fields are left nil for NULL.
+func (r *Result) Unmarshal(out interface{}) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Pointer || slice.Elem().Kind() != reflect.Slice {
+		return ErrInvalidTarget
+	}
+	slice = slice.Elem()
+
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+
+	fields := make([][]int, len(r.Columns))
+	for i, column := range r.Columns {
+		fields[i] = fieldFor(structType, column.Name)
+	}
+
+	for _, row := range r.Rows {
+		elem := reflect.New(structType).Elem()
+
+		for i, index := range fields {
+			if index == nil || i >= len(row) || row[i] == nil {
+				continue
+			}
+
+			if err := setField(elem.FieldByIndex(index), *row[i]); err != nil {
+				return fmt.Errorf("column %s: %w", r.Columns[i].Name, err)
+			}
+		}
+
+		if elemType.Kind() == reflect.Pointer {
+			elem = elem.Addr()
+		}
+
+		slice.Set(reflect.Append(slice, elem))
+	}
+
+	return nil
+}
+
+func fieldFor(t reflect.Type, column string) []int {
+	normalized := normalize(column)
+
+	var match []int
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if tag := field.Tag.Get("athena"); tag != "" {
+			if tag == column {
+				return field.Index
+			}
+			continue
+		}
+
+		if match == nil && normalize(field.Name) == normalized {
+			match = field.Index
+		}
+	}
+
+	return match
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+func setField(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Pointer {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setField(ptr.Elem(), value); err != nil {
+			return err
+		}
+
+		field.Set(ptr)
+		return nil
+	}
+
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := parseTime(value)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
+
+func convert(value string, columnType string) (interface{}, error) {
+	switch columnType {
+	case "tinyint", "smallint", "integer", "bigint":
+		return strconv.ParseInt(value, 10, 64)
+	case "float", "real", "double":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	}
+
+	return value, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if len(value) == len(dateLayout) {
+		return time.Parse(dateLayout, value)
+	}
+
+	return time.Parse(timestampLayout, value)
+}
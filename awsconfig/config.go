@@ -0,0 +1,212 @@
+package awsconfig
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultRegion          = "us-west-1"
+	DefaultKey             = ""
+	DefaultSecret          = ""
+	DefaultToken           = ""
+	DefaultMaxAttempts     = 3
+	DefaultRetryMode       = "standard"
+	DefaultEndpointURL     = ""
+	DefaultHTTPTimeout     = 0
+	DefaultMaxIdleConns    = 100
+	DefaultIdleConnTimeout = 90
+)
+
+// AWSConfig builds the aws.Config shared by the connectors: region,
+// credentials, retries, endpoint and HTTP client, including proxy and
+// CA settings. Connectors given an
+// AWSConfig use it in place of their own region and keys.
+//
+// Each connector may override the region, endpoint_url, credentials
+// profile and retry settings under its scope's aws key:
+//
+//	s3:
+//	  aws:
+//	    region: eu-west-1
+//	    endpoint_url: http://localhost:9000
+type AWSConfig struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	once sync.Once
+	cfg  aws.Config
+	err  error
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+	Chain     *awscredentials.Chain `optional:"true"`
+}
+
+func Module(scope string) fx.Option {
+
+	var a *AWSConfig
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) (*AWSConfig, error) {
+
+			logger = p.Logger.Named(scope)
+
+			a := &AWSConfig{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			a.initDefaultConfigs()
+
+			// Loaded here, as connectors may start before the module
+			if err := moduleconfig.Load(scope, &a.config); err != nil {
+				return nil, err
+			}
+
+			return a, nil
+		}),
+		fx.Populate(&a),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: a.onStart,
+					OnStop:  a.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (a *AWSConfig) onStart(ctx context.Context) error {
+
+	logger.Info("Starting AWS config",
+		zap.String("region", a.config.Region),
+		zap.String("endpoint_url", a.config.EndpointURL),
+		zap.Bool("use_fips_endpoint", a.config.UseFIPSEndpoint),
+		zap.Bool("use_dualstack_endpoint", a.config.UseDualStackEndpoint),
+		zap.String("retry_mode", a.config.RetryMode),
+		zap.Int("max_attempts", a.config.MaxAttempts),
+		zap.String("proxy_url", redactedProxy(a.config.ProxyURL)),
+		zap.Strings("ca_bundle", a.config.CABundle),
+		zap.Bool("local_mode", LocalMode()),
+	)
+
+	_, err := a.load(ctx)
+
+	return err
+}
+
+func (a *AWSConfig) onStop(ctx context.Context) error {
+
+	a.logger.Info("Stopped AWS config")
+
+	return nil
+}
+
+// load builds the shared config once. Connectors may start before the
+// module does, so whichever comes first loads it.
+func (a *AWSConfig) load(ctx context.Context) (aws.Config, error) {
+	a.once.Do(func() {
+		client, err := httpClient(a.scope, a.config)
+		if err != nil {
+			a.logger.Error("Build HTTP client error", zap.Error(err))
+			a.err = err
+			return
+		}
+
+		opts := []func(*config.LoadOptions) error{
+			config.WithRegion(a.config.Region),
+			config.WithRetryer(retrySettingsOf(a.config).retryer()),
+			config.WithHTTPClient(client),
+		}
+
+		// Without keys or a credentials chain, the SDK's default chain
+		// finds credentials in the environment, shared config files or
+		// instance metadata
+		if a.config.Key != "" {
+			opts = append(opts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+				a.config.Key,
+				a.config.Secret,
+				a.config.Token,
+			)))
+		} else if a.params.Chain != nil {
+			opts = append(opts, config.WithCredentialsProvider(a.params.Chain.Default()))
+		}
+
+		a.cfg, a.err = config.LoadDefaultConfig(ctx, opts...)
+		if a.err != nil {
+			a.logger.Error("Load AWS config error", zap.Error(a.err))
+			return
+		}
+
+		if a.config.EndpointURL != "" {
+			a.cfg.BaseEndpoint = aws.String(a.config.EndpointURL)
+		}
+	})
+
+	return a.cfg, a.err
+}
+
+// For returns a copy of the shared config for the connector of scope,
+// with the overrides under its aws key applied. Credentials, when not
+// nil, replace the shared credentials, e.g. those of the STS connector;
+// so does the profile named by aws.profile with a credentials chain.
+func (a *AWSConfig) For(ctx context.Context, scope string, creds aws.CredentialsProvider) (aws.Config, error) {
+	shared, err := a.load(ctx)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	settings, o, err := connectorConfig(a.config, scope)
+	if err != nil {
+		a.logger.Error("Invalid aws override", zap.String("connector", scope), zap.Error(err))
+		return aws.Config{}, err
+	}
+
+	cfg := shared.Copy()
+
+	// Connectors append their own middleware, such as tracing
+	cfg.APIOptions = append([]func(*middleware.Stack) error{}, shared.APIOptions...)
+
+	if creds != nil {
+		cfg.Credentials = creds
+	} else if a.params.Chain != nil && o.Profile != nil {
+		cfg.Credentials = a.params.Chain.For(scope)
+	}
+
+	// Endpoint overrides below still win, e.g. to use MinIO for S3
+	applyLocalMode(&cfg)
+	o.apply(&cfg)
+
+	if retry := retrySettingsOf(settings); !retry.equal(retrySettingsOf(a.config)) {
+		cfg.Retryer = retry.retryer()
+	}
+
+	withEndpointVariants(&cfg, endpointVariantsOf(settings))
+	withOperationTimeout(&cfg, time.Duration(settings.OperationTimeout)*time.Second)
+
+	return cfg, nil
+}
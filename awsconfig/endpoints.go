@@ -0,0 +1,65 @@
+package awsconfig
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// The endpoint variants sit with the HTTP settings, under an AWSConfig
+// module's scope or the top-level aws key, and each connector may
+// override them under its scope's aws key:
+//
+//	aws:
+//	  use_fips_endpoint: true
+//	  use_dualstack_endpoint: true
+//
+// They select the FIPS and dual-stack (IPv4 and IPv6) endpoints of the
+// services offering them. When off, the SDK's AWS_USE_FIPS_ENDPOINT and
+// AWS_USE_DUALSTACK_ENDPOINT variables and shared config still apply.
+// Custom endpoints, as in local mode, are used as they are.
+const (
+	DefaultUseFIPSEndpoint      = false
+	DefaultUseDualStackEndpoint = false
+)
+
+// endpointVariants is a config source the clients read the variants
+// from, ahead of the sources the SDK loaded.
+type endpointVariants struct {
+	fips      bool
+	dualStack bool
+}
+
+func endpointVariantsOf(cfg Config) endpointVariants {
+	return endpointVariants{
+		fips:      cfg.UseFIPSEndpoint,
+		dualStack: cfg.UseDualStackEndpoint,
+	}
+}
+
+func (v endpointVariants) GetUseFIPSEndpoint(ctx context.Context) (aws.FIPSEndpointState, bool, error) {
+	if !v.fips {
+		return aws.FIPSEndpointStateUnset, false, nil
+	}
+
+	return aws.FIPSEndpointStateEnabled, true, nil
+}
+
+func (v endpointVariants) GetUseDualStackEndpoint(ctx context.Context) (aws.DualStackEndpointState, bool, error) {
+	if !v.dualStack {
+		return aws.DualStackEndpointStateUnset, false, nil
+	}
+
+	return aws.DualStackEndpointStateEnabled, true, nil
+}
+
+// withEndpointVariants makes the clients created from cfg use the
+// variants. The SDK rejects them with a custom endpoint, so it leaves
+// those configs alone.
+func withEndpointVariants(cfg *aws.Config, v endpointVariants) {
+	if cfg.BaseEndpoint != nil || (!v.fips && !v.dualStack) {
+		return
+	}
+
+	cfg.ConfigSources = append([]interface{}{v}, cfg.ConfigSources...)
+}
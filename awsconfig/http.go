@@ -0,0 +1,157 @@
+package awsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// The HTTP settings of an AWSConfig module apply to its shared client.
+// Connectors without one read them from the top-level aws key, so egress
+// through a proxy with a private CA can be set up once for everything:
+//
+//	aws:
+//	  proxy_url: http://proxy.corp.example:3128
+//	  no_proxy: [169.254.169.254, .internal.example]
+//	  ca_bundle: [/etc/ssl/certs/corp-root.pem]
+//
+// Without proxy_url the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment
+// variables are used. Timeouts are in seconds; unset or 0 keeps the SDK
+// default.
+const (
+	DefaultProxyURL              = ""
+	DefaultMaxConnsPerHost       = 0
+	DefaultDialTimeout           = 0
+	DefaultTLSHandshakeTimeout   = 0
+	DefaultResponseHeaderTimeout = 0
+)
+
+const topLevelScope = "aws"
+
+func httpClient(scope string, cfg Config) (*awshttp.BuildableClient, error) {
+	seconds := func(n int) time.Duration {
+		return time.Duration(n) * time.Second
+	}
+
+	proxy, err := proxyFunc(cfg.ProxyURL, cfg.NoProxy)
+	if err != nil {
+		return nil, fmt.Errorf("%s: proxy_url: %w", scope, err)
+	}
+
+	roots, err := certPool(cfg.CABundle)
+	if err != nil {
+		return nil, fmt.Errorf("%s: ca_bundle: %w", scope, err)
+	}
+
+	client := awshttp.NewBuildableClient().WithTransportOptions(func(t *http.Transport) {
+		if proxy != nil {
+			t.Proxy = proxy
+		}
+
+		if roots != nil {
+			if t.TLSClientConfig == nil {
+				t.TLSClientConfig = &tls.Config{}
+			}
+			t.TLSClientConfig.RootCAs = roots
+		}
+
+		if n := cfg.MaxIdleConns; n > 0 {
+			t.MaxIdleConns = n
+			t.MaxIdleConnsPerHost = n
+		}
+
+		if n := cfg.MaxConnsPerHost; n > 0 {
+			t.MaxConnsPerHost = n
+		}
+
+		if d := seconds(cfg.IdleConnTimeout); d > 0 {
+			t.IdleConnTimeout = d
+		}
+
+		if d := seconds(cfg.TLSHandshakeTimeout); d > 0 {
+			t.TLSHandshakeTimeout = d
+		}
+
+		if d := seconds(cfg.ResponseHeaderTimeout); d > 0 {
+			t.ResponseHeaderTimeout = d
+		}
+	})
+
+	if timeout := seconds(cfg.DialTimeout); timeout > 0 {
+		client = client.WithDialerOptions(func(d *net.Dialer) {
+			d.Timeout = timeout
+		})
+	}
+
+	if timeout := seconds(cfg.HTTPTimeout); timeout > 0 {
+		client = client.WithTimeout(timeout)
+	}
+
+	return client, nil
+}
+
+// proxyFunc sends every request through proxyURL except those to hosts
+// matching noProxy, in the NO_PROXY format.
+func proxyFunc(proxyURL string, noProxy []string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return nil, nil
+	}
+
+	if _, err := url.Parse(proxyURL); err != nil {
+		return nil, err
+	}
+
+	proxy := (&httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    strings.Join(noProxy, ","),
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+// certPool adds the PEM certificates of files to the system roots.
+func certPool(files []string) (*x509.CertPool, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates in %s", file)
+		}
+	}
+
+	return pool, nil
+}
+
+// redactedProxy is proxy_url without its password, for logging.
+func redactedProxy(value string) string {
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+
+	return proxyURL.Redacted()
+}
@@ -0,0 +1,110 @@
+package awsconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/spf13/viper"
+)
+
+// Local mode is switched with top-level keys, so it applies to every
+// connector whether or not the AWSConfig module is used:
+//
+//	aws:
+//	  local_mode: true
+//	  local_endpoint: http://localhost:4566
+const (
+	localModeKey     = "aws.local_mode"
+	localEndpointKey = "aws.local_endpoint"
+	localKeyKey      = "aws.local_key"
+	localSecretKey   = "aws.local_secret"
+)
+
+const DefaultLocalEndpoint = "http://localhost:4566"
+
+// LocalStack accepts any credentials; these are the ones its docs use.
+// Emulators checking them, like MinIO, need local_key and local_secret.
+const (
+	DefaultLocalKey    = "test"
+	DefaultLocalSecret = "test"
+)
+
+// LocalMode reports whether connectors run against LocalStack. S3
+// clients should use path-style addressing then.
+func LocalMode() bool {
+	return viper.GetBool(localModeKey)
+}
+
+// LocalEndpoint is the LocalStack URL used in local mode.
+func LocalEndpoint() string {
+	return localSetting(localEndpointKey, DefaultLocalEndpoint)
+}
+
+// Load is config.LoadDefaultConfig for the connector of scope when it has
+// no shared AWSConfig. The HTTP, retry, timeout and endpoint settings
+// come from the top-level aws key, with the connector's overrides,
+// region and endpoint_url included, and it points at LocalStack in local
+// mode.
+func Load(ctx context.Context, scope string, optFns ...func(*config.LoadOptions) error) (aws.Config, error) {
+	base, err := topLevelConfig()
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	settings, o, err := connectorConfig(base, scope)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	client, err := httpClient(topLevelScope, base)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	optFns = append([]func(*config.LoadOptions) error{
+		config.WithHTTPClient(client),
+		config.WithRetryer(retrySettingsOf(settings).retryer()),
+	}, optFns...)
+
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return cfg, err
+	}
+
+	if base.EndpointURL != "" {
+		cfg.BaseEndpoint = aws.String(base.EndpointURL)
+	}
+
+	// As with For, the connector's overrides win over local mode
+	applyLocalMode(&cfg)
+	o.apply(&cfg)
+
+	withEndpointVariants(&cfg, endpointVariantsOf(settings))
+	withOperationTimeout(&cfg, time.Duration(settings.OperationTimeout)*time.Second)
+
+	return cfg, nil
+}
+
+func applyLocalMode(cfg *aws.Config) {
+	if !LocalMode() {
+		return
+	}
+
+	cfg.BaseEndpoint = aws.String(LocalEndpoint())
+	cfg.Credentials = credentials.NewStaticCredentialsProvider(
+		localSetting(localKeyKey, DefaultLocalKey),
+		localSetting(localSecretKey, DefaultLocalSecret),
+		"",
+	)
+}
+
+func localSetting(key string, fallback string) string {
+	if value := viper.GetString(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
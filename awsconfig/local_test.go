@@ -0,0 +1,65 @@
+package awsconfig_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/testsupport"
+)
+
+func load(t *testing.T, scope string) aws.Config {
+	t.Helper()
+
+	cfg, err := awsconfig.Load(context.Background(), scope, config.WithRegion("us-west-1"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	return cfg
+}
+
+func TestLoadConnectorOverrides(t *testing.T) {
+	testsupport.Set(t, "aws.endpoint_url", "http://shared:4566")
+	testsupport.Set(t, "queue.aws.region", "eu-central-1")
+	testsupport.Set(t, "queue.aws.endpoint_url", "http://queue:9324")
+
+	cfg := load(t, "queue")
+
+	if cfg.Region != "eu-central-1" {
+		t.Errorf("region = %q, want the connector's", cfg.Region)
+	}
+
+	if got := aws.ToString(cfg.BaseEndpoint); got != "http://queue:9324" {
+		t.Errorf("endpoint = %q, want the connector's", got)
+	}
+}
+
+func TestLoadTopLevelEndpoint(t *testing.T) {
+	testsupport.Set(t, "aws.endpoint_url", "http://shared:4566")
+
+	cfg := load(t, "bucket")
+
+	if cfg.Region != "us-west-1" {
+		t.Errorf("region = %q, want the connector's own", cfg.Region)
+	}
+
+	if got := aws.ToString(cfg.BaseEndpoint); got != "http://shared:4566" {
+		t.Errorf("endpoint = %q, want the top-level one", got)
+	}
+}
+
+func TestLoadLocalModeOverride(t *testing.T) {
+	testsupport.Set(t, "aws.local_mode", true)
+	testsupport.Set(t, "bucket.aws.endpoint_url", "http://minio:9000")
+
+	if got := aws.ToString(load(t, "bucket").BaseEndpoint); got != "http://minio:9000" {
+		t.Errorf("endpoint = %q, want the override of local mode", got)
+	}
+
+	if got := aws.ToString(load(t, "queue").BaseEndpoint); got != awsconfig.DefaultLocalEndpoint {
+		t.Errorf("endpoint = %q, want LocalStack", got)
+	}
+}
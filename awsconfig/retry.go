@@ -0,0 +1,94 @@
+package awsconfig
+
+import (
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// Retry settings sit next to the HTTP ones, under an AWSConfig module's
+// scope or the top-level aws key, and each connector may override them
+// under its scope's aws key:
+//
+//	aws:
+//	  retry_mode: adaptive
+//	  max_attempts: 5
+//	  max_backoff: 20
+//	  retryable_codes: [SlowDown, TransactionInProgressException]
+//	  retryable_status_codes: [409]
+//	sqs:
+//	  aws:
+//	    max_attempts: 10
+//
+// The codes are retried on top of the SDK's own retryable errors.
+// max_backoff is in seconds.
+const DefaultMaxBackoff = 20
+
+type retrySettings struct {
+	mode        aws.RetryMode
+	maxAttempts int
+	maxBackoff  time.Duration
+	codes       []string
+	statusCodes []int
+}
+
+// retrySettingsOf reads the retry settings of cfg.
+func retrySettingsOf(cfg Config) retrySettings {
+	return retrySettings{
+		mode:        aws.RetryMode(cfg.RetryMode),
+		maxAttempts: cfg.MaxAttempts,
+		maxBackoff:  time.Duration(cfg.MaxBackoff) * time.Second,
+		codes:       cfg.RetryableCodes,
+		statusCodes: cfg.RetryableStatusCodes,
+	}
+}
+
+func (s retrySettings) equal(other retrySettings) bool {
+	return s.mode == other.mode &&
+		s.maxAttempts == other.maxAttempts &&
+		s.maxBackoff == other.maxBackoff &&
+		slices.Equal(s.codes, other.codes) &&
+		slices.Equal(s.statusCodes, other.statusCodes)
+}
+
+func (s retrySettings) retryer() func() aws.Retryer {
+	standard := func(o *retry.StandardOptions) {
+		if s.maxAttempts > 0 {
+			o.MaxAttempts = s.maxAttempts
+		}
+
+		if s.maxBackoff > 0 {
+			o.MaxBackoff = s.maxBackoff
+		}
+
+		if len(s.codes) > 0 {
+			codes := make(map[string]struct{}, len(s.codes))
+			for _, code := range s.codes {
+				codes[code] = struct{}{}
+			}
+
+			o.Retryables = append(o.Retryables, retry.RetryableErrorCode{Codes: codes})
+		}
+
+		if len(s.statusCodes) > 0 {
+			codes := make(map[int]struct{}, len(s.statusCodes))
+			for _, code := range s.statusCodes {
+				codes[code] = struct{}{}
+			}
+
+			o.Retryables = append(o.Retryables, retry.RetryableHTTPStatusCode{Codes: codes})
+		}
+	}
+
+	return func() aws.Retryer {
+		if s.mode == aws.RetryModeAdaptive {
+			return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+				o.StandardOptions = append(o.StandardOptions, standard)
+			})
+		}
+
+		return retry.NewStandard(standard)
+	}
+}
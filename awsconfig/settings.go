@@ -0,0 +1,99 @@
+package awsconfig
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of an AWSConfig module under its scope, or of
+// the top-level aws key for connectors without one. A connector's aws
+// key takes the retry, timeout and endpoint variant keys as overrides.
+type Config struct {
+	Region      string `mapstructure:"region" default:"us-west-1"`
+	Key         string `mapstructure:"key" default:""`
+	Secret      string `mapstructure:"secret" default:""`
+	Token       string `mapstructure:"token" default:""`
+	EndpointURL string `mapstructure:"endpoint_url" default:""`
+
+	RetryMode            string   `mapstructure:"retry_mode" default:"standard"`
+	MaxAttempts          int      `mapstructure:"max_attempts" default:"3"`
+	MaxBackoff           int      `mapstructure:"max_backoff" default:"20"`
+	RetryableCodes       []string `mapstructure:"retryable_codes" default:""`
+	RetryableStatusCodes []int    `mapstructure:"retryable_status_codes"`
+	OperationTimeout     int      `mapstructure:"operation_timeout" default:"300"`
+
+	UseFIPSEndpoint      bool `mapstructure:"use_fips_endpoint" default:"false"`
+	UseDualStackEndpoint bool `mapstructure:"use_dualstack_endpoint" default:"false"`
+
+	HTTPTimeout           int      `mapstructure:"http_timeout" default:"0"`
+	MaxIdleConns          int      `mapstructure:"max_idle_conns" default:"100"`
+	IdleConnTimeout       int      `mapstructure:"idle_conn_timeout" default:"90"`
+	MaxConnsPerHost       int      `mapstructure:"max_conns_per_host" default:"0"`
+	DialTimeout           int      `mapstructure:"dial_timeout" default:"0"`
+	TLSHandshakeTimeout   int      `mapstructure:"tls_handshake_timeout" default:"0"`
+	ResponseHeaderTimeout int      `mapstructure:"response_header_timeout" default:"0"`
+	ProxyURL              string   `mapstructure:"proxy_url" default:""`
+	NoProxy               []string `mapstructure:"no_proxy" default:""`
+	CABundle              []string `mapstructure:"ca_bundle" default:""`
+}
+
+// overrides are the keys of a connector's aws key that apply only when
+// set there.
+type overrides struct {
+	Profile     *string `mapstructure:"profile"`
+	Region      *string `mapstructure:"region"`
+	EndpointURL *string `mapstructure:"endpoint_url"`
+}
+
+// apply sets the region and endpoint overrides on cfg.
+func (o overrides) apply(cfg *aws.Config) {
+	if o.Region != nil {
+		cfg.Region = *o.Region
+	}
+
+	if o.EndpointURL != nil {
+		cfg.BaseEndpoint = aws.String(*o.EndpointURL)
+	}
+}
+
+func (a *AWSConfig) initDefaultConfigs() {
+	moduleconfig.Register(a.scope, &Config{})
+}
+
+// Config returns the configuration of the module.
+func (a *AWSConfig) Config() Config {
+	return a.config
+}
+
+// topLevelConfig reads the top-level aws key. It has no defaults, so
+// unset settings keep those of the SDK.
+func topLevelConfig() (Config, error) {
+	cfg := Config{OperationTimeout: DefaultOperationTimeout}
+
+	if err := moduleconfig.Load(topLevelScope, &cfg); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// connectorConfig is base with the overrides under the aws key of the
+// connector of scope applied.
+func connectorConfig(base Config, scope string) (Config, overrides, error) {
+	cfg := base
+	var o overrides
+
+	key := fmt.Sprintf("%s.aws", scope)
+	if err := moduleconfig.Load(key, &cfg); err != nil {
+		return Config{}, o, err
+	}
+
+	if err := moduleconfig.Load(key, &o); err != nil {
+		return Config{}, o, err
+	}
+
+	return cfg, o, nil
+}
@@ -0,0 +1,37 @@
+package awsconfig
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+)
+
+// Source is where a connector takes its AWS config from: the shared
+// AWSConfig module when the app has one, and otherwise the connector's
+// own region and keys. Credentials, such as those of the STS connector,
+// and Chain are optional.
+type Source struct {
+	Shared      *AWSConfig
+	Credentials aws.CredentialsProvider
+	Chain       *awscredentials.Chain
+	Region      string
+	Key         string
+	Secret      string
+	Token       string
+}
+
+// Load returns the config of the connector of scope. Credentials are
+// only wrapped, not retrieved, so a provider which is not ready yet is
+// first asked when the connector makes a call.
+func (s Source) Load(ctx context.Context, scope string) (aws.Config, error) {
+	if s.Shared != nil {
+		return s.Shared.For(ctx, scope, s.Credentials)
+	}
+
+	return Load(ctx, scope,
+		config.WithCredentialsProvider(awscredentials.Resolve(s.Credentials, s.Chain, scope, s.Key, s.Secret, s.Token)),
+		config.WithRegion(s.Region),
+	)
+}
@@ -0,0 +1,97 @@
+package awsconfig
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// The operation timeout bounds each call of a connector made with a
+// context without a deadline, so a hung connection cannot block a worker
+// forever. It sits with the retry settings and is in seconds, 0 for no
+// timeout:
+//
+//	aws:
+//	  operation_timeout: 300
+//	sqs:
+//	  aws:
+//	    operation_timeout: 30
+//
+// It covers the retries of the call, and the reading of the body of
+// operations returning a stream, such as S3 GetObject.
+const DefaultOperationTimeout = 300
+
+// withOperationTimeout adds the timeout to the calls of the clients
+// created from cfg. It goes first so the timeout covers the middleware
+// connectors add.
+func withOperationTimeout(cfg *aws.Config, timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("OperationTimeout",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				if _, ok := ctx.Deadline(); ok {
+					return next.HandleInitialize(ctx, in)
+				}
+
+				ctx, cancel := context.WithTimeout(ctx, timeout)
+
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				if err != nil || !holdOpen(out.Result, cancel) {
+					cancel()
+				}
+
+				return out, metadata, err
+			},
+		), middleware.Before)
+	})
+}
+
+// holdOpen keeps the context of a call returning a stream until the
+// stream is closed, or for event streams until the timeout, since reading
+// them is bound to it.
+func holdOpen(result interface{}, cancel context.CancelFunc) bool {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	v = v.Elem()
+
+	readCloser := reflect.TypeOf((*io.ReadCloser)(nil)).Elem()
+
+	held := false
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Type() != readCloser || field.IsNil() || !field.CanSet() {
+			continue
+		}
+
+		field.Set(reflect.ValueOf(io.ReadCloser(&cancelOnClose{ReadCloser: field.Interface().(io.ReadCloser), cancel: cancel})))
+		held = true
+	}
+
+	if !held {
+		// Event stream outputs hold their stream in an unexported field
+		// behind a GetStream method returning the service's own type
+		_, held = v.Addr().Type().MethodByName("GetStream")
+	}
+
+	return held
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+
+	return c.ReadCloser.Close()
+}
@@ -0,0 +1,188 @@
+package awscredentials
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+var logger *zap.Logger
+
+var ErrUnknownProfile = awserrors.New(awserrors.ErrValidation, "unknown credentials profile")
+
+const (
+	DefaultDefaultProfile = "default"
+	DefaultRegion         = "us-east-1"
+)
+
+// Chain provides credentials from named profiles. Each profile takes its
+// credentials from a source and may assume a role on top of them:
+//
+//	credentials:
+//	  default_profile: app
+//	  profiles:
+//	    app:
+//	      source: web_identity
+//	    reporting:
+//	      base: app
+//	      assume_role_arn: arn:aws:iam::123456789012:role/reporting
+//
+// Connectors use the profile named by their aws.profile key, or the
+// default profile. An undefined profile named default uses the SDK's
+// default chain.
+type Chain struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	mu        sync.Mutex
+	providers map[string]aws.CredentialsProvider
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+}
+
+func Module(scope string) fx.Option {
+
+	var c *Chain
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) (*Chain, error) {
+
+			logger = p.Logger.Named(scope)
+
+			c := &Chain{
+				params:    p,
+				logger:    logger,
+				scope:     scope,
+				providers: make(map[string]aws.CredentialsProvider),
+			}
+
+			c.initDefaultConfigs()
+
+			// Loaded here, as connectors may start before the module
+			if err := moduleconfig.Load(scope, &c.config); err != nil {
+				return nil, err
+			}
+
+			return c, nil
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+// onStart builds every configured profile so misconfigured ones fail
+// the start rather than the first call using them.
+func (c *Chain) onStart(ctx context.Context) error {
+
+	names := c.profileNames()
+
+	logger.Info("Starting credentials chain",
+		zap.String("default_profile", c.config.DefaultProfile),
+		zap.Strings("profiles", names),
+	)
+
+	for _, name := range names {
+		if _, err := c.Provider(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Chain) onStop(ctx context.Context) error {
+
+	c.logger.Info("Stopped credentials chain")
+
+	return nil
+}
+
+func (c *Chain) profileNames() []string {
+	names := make([]string, 0)
+	for name := range c.config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Provider returns the cached credentials of a profile.
+func (c *Chain) Provider(name string) (aws.CredentialsProvider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.provider(name, map[string]bool{})
+}
+
+// Default returns the credentials of default_profile.
+func (c *Chain) Default() aws.CredentialsProvider {
+	return c.For("")
+}
+
+// For returns the credentials of the connector of scope. Profiles that
+// cannot be built return their error from Retrieve.
+func (c *Chain) For(scope string) aws.CredentialsProvider {
+	name := c.config.DefaultProfile
+	if scope != "" {
+		profile, err := profileOf(scope)
+		if err != nil {
+			return aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+				return aws.Credentials{}, err
+			})
+		}
+
+		if profile != "" {
+			name = profile
+		}
+	}
+
+	provider, err := c.Provider(name)
+	if err != nil {
+		return aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{}, err
+		})
+	}
+
+	return provider
+}
+
+// Resolve picks the credentials of a connector: provider when it is set,
+// such as the STS connector's, the connector's profile when there is a
+// chain, and otherwise the static keys the connector configures.
+func Resolve(provider aws.CredentialsProvider, chain *Chain, scope string, key string, secret string, token string) aws.CredentialsProvider {
+	if provider != nil {
+		return provider
+	}
+
+	if chain != nil {
+		return chain.For(scope)
+	}
+
+	return credentials.NewStaticCredentialsProvider(key, secret, token)
+}
@@ -0,0 +1,63 @@
+package awscredentials
+
+import (
+	"fmt"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope.
+type Config struct {
+	DefaultProfile string                   `mapstructure:"default_profile" default:"default"`
+	Region         string                   `mapstructure:"region" default:"us-east-1"`
+	Profiles       map[string]ProfileConfig `mapstructure:"profiles"`
+}
+
+// ProfileConfig is a profile under profiles. Source, or Base naming
+// another profile, gives its credentials; the other keys configure the
+// source and the role assumed on top.
+type ProfileConfig struct {
+	Base          string `mapstructure:"base"`
+	Source        string `mapstructure:"source"`
+	Region        string `mapstructure:"region"`
+	AssumeRoleARN string `mapstructure:"assume_role_arn"`
+	Duration      int    `mapstructure:"duration"`
+	ExternalID    string `mapstructure:"external_id"`
+	SessionName   string `mapstructure:"session_name"`
+
+	Key           string `mapstructure:"key"`
+	Secret        string `mapstructure:"secret"`
+	Token         string `mapstructure:"token"`
+	SharedProfile string `mapstructure:"shared_profile"`
+	RoleARN       string `mapstructure:"role_arn"`
+	TokenFile     string `mapstructure:"token_file"`
+	SSOStartURL   string `mapstructure:"sso_start_url"`
+	SSOAccountID  string `mapstructure:"sso_account_id"`
+	SSORoleName   string `mapstructure:"sso_role_name"`
+	SSOSession    string `mapstructure:"sso_session"`
+}
+
+// connectorSettings is the part of a connector's aws key the chain reads.
+type connectorSettings struct {
+	Profile string `mapstructure:"profile"`
+}
+
+func (c *Chain) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *Chain) Config() Config {
+	return c.config
+}
+
+// profileOf is the profile the connector of scope names under its aws
+// key, if any.
+func profileOf(scope string) (string, error) {
+	var s connectorSettings
+	if err := moduleconfig.Load(fmt.Sprintf("%s.aws", scope), &s); err != nil {
+		return "", err
+	}
+
+	return s.Profile, nil
+}
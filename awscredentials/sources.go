@@ -0,0 +1,248 @@
+package awscredentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/endpointcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// ecsCredentialsHost serves the credentials of ECS tasks given a relative
+// URI.
+const ecsCredentialsHost = "http://169.254.170.2"
+
+const (
+	SourceStatic      = "static"
+	SourceEnv         = "env"
+	SourceShared      = "shared"
+	SourceECS         = "ecs"
+	SourceEC2         = "ec2"
+	SourceWebIdentity = "web_identity"
+	SourceSSO         = "sso"
+	SourceDefault     = "default"
+)
+
+func (c *Chain) region(name string) string {
+	if region := c.config.Profiles[name].Region; region != "" {
+		return region
+	}
+
+	return c.config.Region
+}
+
+// provider builds a profile once, following base profiles. seen guards
+// against profiles that are their own base.
+func (c *Chain) provider(name string, seen map[string]bool) (aws.CredentialsProvider, error) {
+	if provider, ok := c.providers[name]; ok {
+		return provider, nil
+	}
+
+	if seen[name] {
+		return nil, fmt.Errorf("%s: profile %s is its own base", c.scope, name)
+	}
+	seen[name] = true
+
+	profile := c.config.Profiles[name]
+
+	var provider aws.CredentialsProvider
+	var err error
+
+	switch {
+	case profile.Base != "":
+		provider, err = c.provider(profile.Base, seen)
+
+	case profile.Source != "":
+		provider, err = c.source(name, profile.Source)
+
+	case name == DefaultDefaultProfile:
+		provider, err = c.source(name, SourceDefault)
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProfile, name)
+	}
+	if err != nil {
+		c.logger.Error("Build credentials profile error", zap.String("profile", name), zap.Error(err))
+		return nil, err
+	}
+
+	if profile.AssumeRoleARN != "" {
+		provider = c.assumeRole(name, profile.AssumeRoleARN, provider)
+	}
+
+	provider = cached(provider)
+	c.providers[name] = provider
+
+	return provider, nil
+}
+
+func (c *Chain) source(name string, source string) (aws.CredentialsProvider, error) {
+	profile := c.config.Profiles[name]
+
+	switch source {
+	case SourceStatic:
+		return credentials.NewStaticCredentialsProvider(profile.Key, profile.Secret, profile.Token), nil
+
+	case SourceEnv:
+		env, err := config.NewEnvConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		if !env.Credentials.HasKeys() {
+			return nil, fmt.Errorf("%s: profile %s: no credentials in the environment", c.scope, name)
+		}
+
+		return credentials.StaticCredentialsProvider{Value: env.Credentials}, nil
+
+	// Shared config profiles cover SSO and credential_process profiles of
+	// ~/.aws/config as well
+	case SourceShared, SourceDefault:
+		opts := []func(*config.LoadOptions) error{
+			config.WithRegion(c.region(name)),
+		}
+		if source == SourceShared {
+			opts = append(opts, config.WithSharedConfigProfile(profile.SharedProfile))
+		}
+
+		cfg, err := config.LoadDefaultConfig(context.Background(), opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return cfg.Credentials, nil
+
+	// EKS Pod Identity serves credentials the same way
+	case SourceECS:
+		endpoint := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+		if relative := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); relative != "" {
+			endpoint = ecsCredentialsHost + relative
+		}
+		if endpoint == "" {
+			return nil, fmt.Errorf("%s: profile %s: not running in an ECS task", c.scope, name)
+		}
+
+		return endpointcreds.New(endpoint, func(o *endpointcreds.Options) {
+			o.AuthorizationToken = os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+
+			// The token file is rotated, so it is read on every refresh
+			if tokenFile := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); tokenFile != "" {
+				o.AuthorizationTokenProvider = endpointcreds.TokenProviderFunc(func() (string, error) {
+					token, err := os.ReadFile(tokenFile)
+					return strings.TrimSpace(string(token)), err
+				})
+			}
+		}), nil
+
+	case SourceEC2:
+		return ec2rolecreds.New(), nil
+
+	// The IRSA webhook sets the role and token file in the environment;
+	// both can be configured explicitly too
+	case SourceWebIdentity:
+		roleArn := profile.RoleARN
+		if roleArn == "" {
+			roleArn = os.Getenv("AWS_ROLE_ARN")
+		}
+
+		tokenFile := profile.TokenFile
+		if tokenFile == "" {
+			tokenFile = os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+		}
+
+		if roleArn == "" || tokenFile == "" {
+			return nil, fmt.Errorf("%s: profile %s: role_arn and token_file are required", c.scope, name)
+		}
+
+		client := sts.NewFromConfig(aws.Config{Region: c.region(name)})
+
+		return stscreds.NewWebIdentityRoleProvider(client, roleArn, stscreds.IdentityTokenFile(tokenFile), func(o *stscreds.WebIdentityRoleOptions) {
+			o.RoleSessionName = c.sessionName(name)
+		}), nil
+
+	// The token comes from the cache `aws sso login` writes
+	case SourceSSO:
+		return c.sso(name)
+	}
+
+	return nil, fmt.Errorf("%s: profile %s: unknown source %q", c.scope, name, source)
+}
+
+func (c *Chain) sso(name string) (aws.CredentialsProvider, error) {
+	profile := c.config.Profiles[name]
+
+	startURL := profile.SSOStartURL
+	accountID := profile.SSOAccountID
+	roleName := profile.SSORoleName
+
+	if startURL == "" || accountID == "" || roleName == "" {
+		return nil, fmt.Errorf("%s: profile %s: sso_start_url, sso_account_id and sso_role_name are required", c.scope, name)
+	}
+
+	cfg := aws.Config{Region: c.region(name)}
+
+	var optFns []func(*ssocreds.Options)
+
+	// Sessions refresh their token; legacy start URL tokens expire
+	if session := profile.SSOSession; session != "" {
+		path, err := ssocreds.StandardCachedTokenFilepath(session)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens := ssocreds.NewSSOTokenProvider(ssooidc.NewFromConfig(cfg), path)
+		optFns = append(optFns, func(o *ssocreds.Options) {
+			o.SSOTokenProvider = tokens
+		})
+	}
+
+	return ssocreds.New(sso.NewFromConfig(cfg), accountID, roleName, startURL, optFns...), nil
+}
+
+func (c *Chain) assumeRole(name string, roleArn string, base aws.CredentialsProvider) aws.CredentialsProvider {
+	client := sts.NewFromConfig(aws.Config{
+		Region:      c.region(name),
+		Credentials: cached(base),
+	})
+
+	profile := c.config.Profiles[name]
+
+	return stscreds.NewAssumeRoleProvider(client, roleArn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = c.sessionName(name)
+		if profile.Duration > 0 {
+			o.Duration = time.Duration(profile.Duration) * time.Second
+		}
+		if profile.ExternalID != "" {
+			o.ExternalID = aws.String(profile.ExternalID)
+		}
+	})
+}
+
+func (c *Chain) sessionName(name string) string {
+	if sessionName := c.config.Profiles[name].SessionName; sessionName != "" {
+		return sessionName
+	}
+
+	return "zeitgeber-" + name
+}
+
+func cached(provider aws.CredentialsProvider) aws.CredentialsProvider {
+	if _, ok := provider.(*aws.CredentialsCache); ok {
+		return provider
+	}
+
+	return aws.NewCredentialsCache(provider)
+}
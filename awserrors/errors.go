@@ -0,0 +1,254 @@
+// Package awserrors sorts the errors of every connector into a few kinds,
+// so callers can handle them without knowing which AWS service was
+// involved:
+//
+//	switch {
+//	case errors.Is(err, awserrors.ErrNotFound):
+//		return http.StatusNotFound
+//	case errors.Is(err, awserrors.ErrThrottled), errors.Is(err, awserrors.ErrTransient):
+//		return http.StatusServiceUnavailable
+//	}
+//
+// The original error stays in the chain, so errors.As still finds the
+// service's own types.
+package awserrors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrThrottled    = errors.New("throttled")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrValidation   = errors.New("validation failed")
+	ErrTransient    = errors.New("transient failure")
+)
+
+// Error is an error of a kind, one of the Err values.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// New returns an error of kind with text, for the sentinel errors of
+// connectors.
+func New(kind error, text string) error {
+	return &Error{Kind: kind, Err: errors.New(text)}
+}
+
+// Wrap returns err with its kind, or err itself when it already has one
+// or fits none.
+func Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	kind := Classify(err)
+	if kind == nil {
+		return err
+	}
+
+	return &Error{Kind: kind, Err: err}
+}
+
+// Kind returns the kind of err, or nil when it fits none.
+func Kind(err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+
+	return Classify(err)
+}
+
+// Instrument wraps the errors of the clients created from cfg with their
+// kind. Call it before creating them.
+func Instrument(cfg *aws.Config) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ErrorKinds",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				out, metadata, err := next.HandleInitialize(ctx, in)
+
+				return out, metadata, Wrap(err)
+			},
+		), middleware.After)
+	})
+}
+
+// Codes shared by several services. Codes ending in NotFound or
+// NotFoundException, or starting with NoSuch, are not found errors too.
+var (
+	conflictCodes = codes(
+		"ConditionalCheckFailedException",
+		"TransactionConflictException",
+		"ConflictException",
+		"ResourceConflictException",
+		"ResourceInUseException",
+		"ConcurrentModificationException",
+		"OptimisticLockException",
+		"PreconditionFailed",
+		"BucketAlreadyExists",
+		"BucketAlreadyOwnedByYou",
+		"QueueNameExists",
+		"UsernameExistsException",
+		"AliasExistsException",
+	)
+
+	notFoundCodes = codes(
+		"QueueDoesNotExist",
+		"AWS.SimpleQueueService.NonExistentQueue",
+		"ParameterNotFound",
+		"UserNotFoundException",
+		"NotFound",
+	)
+
+	unauthorizedCodes = codes(
+		"AccessDenied",
+		"AccessDeniedException",
+		"AuthorizationError",
+		"ExpiredToken",
+		"ExpiredTokenException",
+		"Forbidden",
+		"IncompleteSignature",
+		"InvalidAccessKeyId",
+		"InvalidClientTokenId",
+		"MissingAuthenticationToken",
+		"NotAuthorizedException",
+		"SignatureDoesNotMatch",
+		"UnauthorizedOperation",
+		"UnrecognizedClientException",
+	)
+
+	validationCodes = codes(
+		"ValidationException",
+		"ValidationError",
+		"InvalidParameterException",
+		"InvalidParameterValue",
+		"InvalidParameterValueException",
+		"InvalidParameterCombination",
+		"InvalidRequestException",
+		"InvalidArgument",
+		"InvalidInputException",
+		"MalformedPolicyDocument",
+		"MissingParameter",
+		"SerializationException",
+		"EntityTooLarge",
+	)
+
+	throttleCodes = codes(
+		"TooManyRequestsException",
+		"LimitExceededException",
+		"ProvisionedThroughputExceededException",
+		"RequestLimitExceeded",
+		"SlowDown",
+	)
+)
+
+func codes(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+
+	return set
+}
+
+// Classify returns the kind of an AWS error from its code, or from its
+// HTTP status when the code is not known, or nil when it fits none.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if kind := classifyCode(apiErr.ErrorCode()); kind != nil {
+			return kind
+		}
+	}
+
+	if retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary {
+		return ErrThrottled
+	}
+
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		if kind := classifyStatus(respErr.HTTPStatusCode()); kind != nil {
+			return kind
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) ||
+		retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary {
+		return ErrTransient
+	}
+
+	return nil
+}
+
+func classifyCode(code string) error {
+	switch {
+	case code == "":
+		return nil
+	case conflictCodes[code]:
+		return ErrConflict
+	case notFoundCodes[code],
+		strings.HasPrefix(code, "NoSuch"),
+		strings.HasSuffix(code, "NotFound"),
+		strings.HasSuffix(code, "NotFoundException"):
+		return ErrNotFound
+	case unauthorizedCodes[code]:
+		return ErrUnauthorized
+	case throttleCodes[code]:
+		return ErrThrottled
+	case validationCodes[code]:
+		return ErrValidation
+	}
+
+	return nil
+}
+
+func classifyStatus(status int) error {
+	switch status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return ErrConflict
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrThrottled
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return ErrValidation
+	}
+
+	if status >= 500 {
+		return ErrTransient
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package backup_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. BACKUP_BACKUP_VAULT_NAME for the backup
+// scope.
+type Config struct {
+	BackupVaultName   string `mapstructure:"backup_vault_name" default:"Default"`
+	RoleARN           string `mapstructure:"role_arn" default:""`
+	DeleteAfterDays   int64  `mapstructure:"delete_after_days" default:"0"`
+	PollInterval      int    `mapstructure:"poll_interval" default:"30"`
+	BackupKey         string `mapstructure:"backup_key" default:"ABCDE"`
+	BackupSecret      string `mapstructure:"backup_secret" default:"example_secret"`
+	BackupToken       string `mapstructure:"backup_token" default:""`
+	BackupRegion      string `mapstructure:"backup_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *BackupConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *BackupConnector) Config() Config {
+	return c.config
+}
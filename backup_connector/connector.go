@@ -0,0 +1,260 @@
+package backup_connector
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultBackupVaultName = "Default"
+	DefaultRoleArn         = ""
+	DefaultDeleteAfterDays = 0
+	DefaultPollInterval    = 30
+	DefaultBackupKey       = "ABCDE"
+	DefaultBackupSecret    = "example_secret"
+	DefaultBackupToken     = ""
+	DefaultBackupRegion    = "us-west-1"
+)
+
+var preflightActions = []string{
+	"backup:StartBackupJob",
+	"backup:DescribeBackupJob",
+	"backup:ListRecoveryPointsByResource",
+	"backup:GetRecoveryPointRestoreMetadata",
+	"backup:StartRestoreJob",
+	"backup:DescribeRestoreJob",
+	"iam:PassRole",
+}
+
+// BackupConnector runs on-demand AWS Backup jobs into backup_vault_name
+// and restores from their recovery points. Jobs run as role_arn, which
+// AWS Backup must be able to assume.
+type BackupConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *backup.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *backup.Client          `optional:"true"`
+}
+
+// Module provides the BackupConnector of scope. It uses the
+// *backup.Client in the graph, if any, instead of building one, and
+// adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the BackupConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *backup.Client named after scope, if
+// any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *BackupConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*backup.Client](scope, named),
+		instance.Provide[*BackupConnector](scope, named, func(p Params) *BackupConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &BackupConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *BackupConnector) BackupService {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *BackupConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting BackupConnector",
+		zap.String("backup_vault_name", c.config.BackupVaultName),
+		zap.String("backup_region", c.config.BackupRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = backup.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = backup.New(c.client.Options(), func(o *backup.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *BackupConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped BackupConnector")
+
+	return nil
+}
+
+func (c *BackupConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.BackupRegion,
+		Key:         c.config.BackupKey,
+		Secret:      c.config.BackupSecret,
+		Token:       c.config.BackupToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *BackupConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("backup_region")
+
+	v.Required("role_arn")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("backup_key", DefaultBackupKey)
+		v.NotPlaceholder("backup_secret", DefaultBackupSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the backup vault.
+func (c *BackupConnector) probe(ctx context.Context) error {
+	_, err := c.client.DescribeBackupVault(ctx, &backup.DescribeBackupVaultInput{
+		BackupVaultName: aws.String(c.config.BackupVaultName),
+	})
+
+	return err
+}
+
+func (c *BackupConnector) GetClient() *backup.Client {
+	return c.client
+}
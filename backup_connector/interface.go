@@ -0,0 +1,23 @@
+package backup_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/backup/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . BackupService
+
+// BackupService backs up and restores resources.
+type BackupService interface {
+	Backup(ctx context.Context, resourceArn string) (*backup.DescribeBackupJobOutput, error)
+	StartBackup(ctx context.Context, resourceArn string) (string, error)
+	WaitForBackup(ctx context.Context, jobID string) (*backup.DescribeBackupJobOutput, error)
+	ListRecoveryPoints(ctx context.Context, resourceArn string) ([]types.RecoveryPointByResource, error)
+	LatestRecoveryPoint(ctx context.Context, resourceArn string) (*types.RecoveryPointByResource, error)
+	StartRestore(ctx context.Context, recoveryPointArn string, overrides map[string]string) (string, error)
+	WaitForRestore(ctx context.Context, jobID string) (*backup.DescribeRestoreJobOutput, error)
+}
+
+var _ BackupService = (*BackupConnector)(nil)
@@ -0,0 +1,228 @@
+package backup_connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/backup/types"
+	"github.com/google/uuid"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrJobFailed        = awserrors.New(awserrors.ErrTransient, "backup job failed")
+	ErrRestoreFailed    = awserrors.New(awserrors.ErrTransient, "restore job failed")
+	ErrNoRecoveryPoints = awserrors.New(awserrors.ErrNotFound, "no recovery points")
+)
+
+// Backup backs up a resource, such as a DynamoDB table or EBS volume
+// ARN, and waits for the recovery point.
+func (c *BackupConnector) Backup(ctx context.Context, resourceArn string) (_ *backup.DescribeBackupJobOutput, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BackupConnector", "Backup")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	jobID, err := c.StartBackup(ctx, resourceArn)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.WaitForBackup(ctx, jobID)
+}
+
+// StartBackup starts an on-demand backup of a resource and returns the
+// job ID. Recovery points are deleted after delete_after_days, or kept
+// until deleted when it is 0.
+func (c *BackupConnector) StartBackup(ctx context.Context, resourceArn string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BackupConnector", "StartBackup")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	input := &backup.StartBackupJobInput{
+		BackupVaultName:  aws.String(c.config.BackupVaultName),
+		IamRoleArn:       aws.String(c.config.RoleARN),
+		ResourceArn:      aws.String(resourceArn),
+		IdempotencyToken: aws.String(uuid.New().String()),
+	}
+
+	if days := c.config.DeleteAfterDays; days > 0 {
+		input.Lifecycle = &types.Lifecycle{
+			DeleteAfterDays: aws.Int64(days),
+		}
+	}
+
+	result, err := c.client.StartBackupJob(ctx, input)
+	if err != nil {
+		c.logger.Error("Start backup job error", zap.String("resource_arn", resourceArn), zap.Error(err))
+		return "", err
+	}
+
+	jobID := aws.ToString(result.BackupJobId)
+
+	c.logger.Info("Started backup job", zap.String("job_id", jobID), zap.String("resource_arn", resourceArn))
+
+	return jobID, nil
+}
+
+// WaitForBackup polls a backup job every poll_interval seconds until it
+// completes. Partial backups count as failed.
+func (c *BackupConnector) WaitForBackup(ctx context.Context, jobID string) (_ *backup.DescribeBackupJobOutput, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BackupConnector", "WaitForBackup")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Second
+
+	for {
+		job, err := c.client.DescribeBackupJob(ctx, &backup.DescribeBackupJobInput{
+			BackupJobId: aws.String(jobID),
+		})
+		if err != nil {
+			c.logger.Error("Describe backup job error", zap.String("job_id", jobID), zap.Error(err))
+			return nil, err
+		}
+
+		switch job.State {
+		case types.BackupJobStateCompleted:
+			return job, nil
+
+		case types.BackupJobStateFailed, types.BackupJobStateAborted, types.BackupJobStateExpired, types.BackupJobStatePartial:
+			return nil, fmt.Errorf("%w: %s: %s: %s", ErrJobFailed, jobID, job.State, aws.ToString(job.StatusMessage))
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// ListRecoveryPoints returns the recovery points of a resource, newest
+// first.
+func (c *BackupConnector) ListRecoveryPoints(ctx context.Context, resourceArn string) (_ []types.RecoveryPointByResource, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BackupConnector", "ListRecoveryPoints")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	paginator := backup.NewListRecoveryPointsByResourcePaginator(c.client, &backup.ListRecoveryPointsByResourceInput{
+		ResourceArn: aws.String(resourceArn),
+	})
+
+	points := []types.RecoveryPointByResource{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("List recovery points error", zap.String("resource_arn", resourceArn), zap.Error(err))
+			return nil, err
+		}
+
+		points = append(points, page.RecoveryPoints...)
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return aws.ToTime(points[i].CreationDate).After(aws.ToTime(points[j].CreationDate))
+	})
+
+	return points, nil
+}
+
+// LatestRecoveryPoint returns the newest completed recovery point of a
+// resource or ErrNoRecoveryPoints.
+func (c *BackupConnector) LatestRecoveryPoint(ctx context.Context, resourceArn string) (_ *types.RecoveryPointByResource, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BackupConnector", "LatestRecoveryPoint")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	points, err := c.ListRecoveryPoints(ctx, resourceArn)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range points {
+		if points[i].Status == types.RecoveryPointStatusCompleted {
+			return &points[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoRecoveryPoints, resourceArn)
+}
+
+// StartRestore starts restoring a recovery point of backup_vault_name
+// and returns the job ID. The restore uses the metadata the resource was
+// backed up with, with overrides applied; most resource types need one
+// to restore to a new resource, such as "targetTableName" for DynamoDB.
+func (c *BackupConnector) StartRestore(ctx context.Context, recoveryPointArn string, overrides map[string]string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BackupConnector", "StartRestore")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	restore, err := c.client.GetRecoveryPointRestoreMetadata(ctx, &backup.GetRecoveryPointRestoreMetadataInput{
+		BackupVaultName:  aws.String(c.config.BackupVaultName),
+		RecoveryPointArn: aws.String(recoveryPointArn),
+	})
+	if err != nil {
+		c.logger.Error("Get recovery point restore metadata error", zap.String("recovery_point_arn", recoveryPointArn), zap.Error(err))
+		return "", err
+	}
+
+	metadata := restore.RestoreMetadata
+	if metadata == nil {
+		metadata = make(map[string]string, len(overrides))
+	}
+	for key, value := range overrides {
+		metadata[key] = value
+	}
+
+	result, err := c.client.StartRestoreJob(ctx, &backup.StartRestoreJobInput{
+		RecoveryPointArn: aws.String(recoveryPointArn),
+		Metadata:         metadata,
+		IamRoleArn:       aws.String(c.config.RoleARN),
+		ResourceType:     restore.ResourceType,
+		IdempotencyToken: aws.String(uuid.New().String()),
+	})
+	if err != nil {
+		c.logger.Error("Start restore job error", zap.String("recovery_point_arn", recoveryPointArn), zap.Error(err))
+		return "", err
+	}
+
+	jobID := aws.ToString(result.RestoreJobId)
+
+	c.logger.Info("Started restore job", zap.String("job_id", jobID), zap.String("recovery_point_arn", recoveryPointArn))
+
+	return jobID, nil
+}
+
+// WaitForRestore polls a restore job every poll_interval seconds until it
+// completes. CreatedResourceArn of the result is the restored resource.
+func (c *BackupConnector) WaitForRestore(ctx context.Context, jobID string) (_ *backup.DescribeRestoreJobOutput, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BackupConnector", "WaitForRestore")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Second
+
+	for {
+		job, err := c.client.DescribeRestoreJob(ctx, &backup.DescribeRestoreJobInput{
+			RestoreJobId: aws.String(jobID),
+		})
+		if err != nil {
+			c.logger.Error("Describe restore job error", zap.String("job_id", jobID), zap.Error(err))
+			return nil, err
+		}
+
+		switch job.Status {
+		case types.RestoreJobStatusCompleted:
+			return job, nil
+
+		case types.RestoreJobStatusFailed, types.RestoreJobStatusAborted:
+			return nil, fmt.Errorf("%w: %s: %s: %s", ErrRestoreFailed, jobID, job.Status, aws.ToString(job.StatusMessage))
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/backup_connector (interfaces: BackupService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . BackupService
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	backup "github.com/aws/aws-sdk-go-v2/service/backup"
+	types "github.com/aws/aws-sdk-go-v2/service/backup/types"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBackupService is a mock of BackupService interface.
+type MockBackupService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupServiceMockRecorder
+}
+
+// MockBackupServiceMockRecorder is the mock recorder for MockBackupService.
+type MockBackupServiceMockRecorder struct {
+	mock *MockBackupService
+}
+
+// NewMockBackupService creates a new mock instance.
+func NewMockBackupService(ctrl *gomock.Controller) *MockBackupService {
+	mock := &MockBackupService{ctrl: ctrl}
+	mock.recorder = &MockBackupServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupService) EXPECT() *MockBackupServiceMockRecorder {
+	return m.recorder
+}
+
+// Backup mocks base method.
+func (m *MockBackupService) Backup(arg0 context.Context, arg1 string) (*backup.DescribeBackupJobOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backup", arg0, arg1)
+	ret0, _ := ret[0].(*backup.DescribeBackupJobOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Backup indicates an expected call of Backup.
+func (mr *MockBackupServiceMockRecorder) Backup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backup", reflect.TypeOf((*MockBackupService)(nil).Backup), arg0, arg1)
+}
+
+// LatestRecoveryPoint mocks base method.
+func (m *MockBackupService) LatestRecoveryPoint(arg0 context.Context, arg1 string) (*types.RecoveryPointByResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestRecoveryPoint", arg0, arg1)
+	ret0, _ := ret[0].(*types.RecoveryPointByResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestRecoveryPoint indicates an expected call of LatestRecoveryPoint.
+func (mr *MockBackupServiceMockRecorder) LatestRecoveryPoint(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestRecoveryPoint", reflect.TypeOf((*MockBackupService)(nil).LatestRecoveryPoint), arg0, arg1)
+}
+
+// ListRecoveryPoints mocks base method.
+func (m *MockBackupService) ListRecoveryPoints(arg0 context.Context, arg1 string) ([]types.RecoveryPointByResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecoveryPoints", arg0, arg1)
+	ret0, _ := ret[0].([]types.RecoveryPointByResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecoveryPoints indicates an expected call of ListRecoveryPoints.
+func (mr *MockBackupServiceMockRecorder) ListRecoveryPoints(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecoveryPoints", reflect.TypeOf((*MockBackupService)(nil).ListRecoveryPoints), arg0, arg1)
+}
+
+// StartBackup mocks base method.
+func (m *MockBackupService) StartBackup(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartBackup", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartBackup indicates an expected call of StartBackup.
+func (mr *MockBackupServiceMockRecorder) StartBackup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartBackup", reflect.TypeOf((*MockBackupService)(nil).StartBackup), arg0, arg1)
+}
+
+// StartRestore mocks base method.
+func (m *MockBackupService) StartRestore(arg0 context.Context, arg1 string, arg2 map[string]string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartRestore", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartRestore indicates an expected call of StartRestore.
+func (mr *MockBackupServiceMockRecorder) StartRestore(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartRestore", reflect.TypeOf((*MockBackupService)(nil).StartRestore), arg0, arg1, arg2)
+}
+
+// WaitForBackup mocks base method.
+func (m *MockBackupService) WaitForBackup(arg0 context.Context, arg1 string) (*backup.DescribeBackupJobOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForBackup", arg0, arg1)
+	ret0, _ := ret[0].(*backup.DescribeBackupJobOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForBackup indicates an expected call of WaitForBackup.
+func (mr *MockBackupServiceMockRecorder) WaitForBackup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForBackup", reflect.TypeOf((*MockBackupService)(nil).WaitForBackup), arg0, arg1)
+}
+
+// WaitForRestore mocks base method.
+func (m *MockBackupService) WaitForRestore(arg0 context.Context, arg1 string) (*backup.DescribeRestoreJobOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForRestore", arg0, arg1)
+	ret0, _ := ret[0].(*backup.DescribeRestoreJobOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForRestore indicates an expected call of WaitForRestore.
+func (mr *MockBackupServiceMockRecorder) WaitForRestore(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForRestore", reflect.TypeOf((*MockBackupService)(nil).WaitForRestore), arg0, arg1)
+}
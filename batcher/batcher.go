@@ -0,0 +1,179 @@
+// Package batcher buffers the items of a connector, such as records or
+// metrics, and sends them in batches from a background loop: every
+// interval, as soon as a batch is waiting, and on Flush.
+//
+//	b := batcher.New(batcher.Options[types.Record]{
+//		BufferSize: 10000,
+//		BatchSize:  500,
+//		Interval:   time.Second,
+//		Block:      true,
+//		Send:       c.send,
+//	})
+//	b.Start(inflight.Internal(context.Background()))
+package batcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrBufferFull = awserrors.New(awserrors.ErrThrottled, "buffer full")
+
+// Options set how a Batcher buffers and sends items.
+type Options[T any] struct {
+	// BufferSize bounds the items buffered or being sent
+	BufferSize int
+
+	// BatchSize buffered items trigger a flush before the interval
+	BatchSize int
+
+	// Interval is the time between background flushes
+	Interval time.Duration
+
+	// Block makes Add wait for room in the buffer instead of dropping
+	// the item
+	Block bool
+
+	// Send is given all the items of a flush and splits them into as
+	// many calls as the service limits require
+	Send func(ctx context.Context, items []T) error
+
+	// OnError, when not nil, receives the errors of background flushes
+	OnError func(err error)
+
+	// OnDrop, when not nil, receives the number of items dropped since
+	// the previous flush
+	OnDrop func(count int)
+}
+
+// Batcher buffers items of type T and sends them in batches.
+type Batcher[T any] struct {
+	opts Options[T]
+
+	// slots bounds the items buffered or in flight
+	slots chan struct{}
+
+	mu      sync.Mutex
+	buffer  []T
+	dropped int
+
+	flushMu sync.Mutex
+	flush   chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func New[T any](opts Options[T]) *Batcher[T] {
+	return &Batcher[T]{
+		opts:  opts,
+		slots: make(chan struct{}, max(opts.BufferSize, 1)),
+		flush: make(chan struct{}, 1),
+	}
+}
+
+// Add buffers item for the next flush. When BufferSize items are
+// buffered or being sent, Add blocks until a flush is done or ctx is,
+// or, without Block, drops item and returns ErrBufferFull.
+func (b *Batcher[T]) Add(ctx context.Context, item T) error {
+	if b.opts.Block {
+		select {
+		case b.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	} else {
+		select {
+		case b.slots <- struct{}{}:
+		default:
+			b.mu.Lock()
+			b.dropped++
+			b.mu.Unlock()
+			return ErrBufferFull
+		}
+	}
+
+	b.mu.Lock()
+	b.buffer = append(b.buffer, item)
+	full := len(b.buffer) >= b.opts.BatchSize
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.flush <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Start runs the background flushes with ctx until Stop is called.
+func (b *Batcher[T]) Start(ctx context.Context) {
+	ctx, b.cancel = context.WithCancel(ctx)
+	b.done = make(chan struct{})
+
+	go b.loop(ctx)
+}
+
+// Stop ends the background flushes, waiting for the one in progress.
+// Items still buffered are sent by the next Flush.
+func (b *Batcher[T]) Stop() {
+	if b.cancel != nil {
+		b.cancel()
+		<-b.done
+	}
+}
+
+func (b *Batcher[T]) loop(ctx context.Context) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-b.flush:
+		}
+
+		if err := b.Flush(ctx); err != nil && ctx.Err() == nil && b.opts.OnError != nil {
+			b.opts.OnError(err)
+		}
+	}
+}
+
+// Flush sends all buffered items and returns the error of Send. The
+// items are gone from the buffer either way.
+func (b *Batcher[T]) Flush(ctx context.Context) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	items := b.buffer
+	dropped := b.dropped
+	b.buffer = nil
+	b.dropped = 0
+	b.mu.Unlock()
+
+	if dropped > 0 && b.opts.OnDrop != nil {
+		b.opts.OnDrop(dropped)
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	// Free the slots whatever the outcome, blocked producers continue
+	defer func() {
+		for range items {
+			<-b.slots
+		}
+	}()
+
+	return b.opts.Send(ctx, items)
+}
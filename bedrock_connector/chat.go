@@ -0,0 +1,149 @@
+package bedrock_connector
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/elmntri/zeitgeber-aws-modules/cloudwatch_metrics_connector"
+)
+
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+type Message struct {
+	Role    string
+	Content string
+}
+
+// ChatRequest is a conversation for any supported model. Zero fields are
+// filled from config: ModelID from model_id, MaxTokens from max_tokens,
+// and so on.
+type ChatRequest struct {
+	ModelID       string
+	System        string
+	Messages      []Message
+	MaxTokens     int
+	Temperature   *float64
+	TopP          *float64
+	StopSequences []string
+}
+
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+type ChatResponse struct {
+	ModelID    string
+	Text       string
+	StopReason string
+	Usage      Usage
+}
+
+// Chat invokes the model of the request, translating the conversation to
+// the Anthropic, Titan Text or Llama 3 payload shape.
+func (c *BedrockConnector) Chat(ctx context.Context, req ChatRequest) (_ *ChatResponse, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BedrockConnector", "Chat")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	c.applyDefaults(&req)
+
+	family, err := familyFor(req.ModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := family.encode(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	result, err := c.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(req.ModelID),
+		Body:        body,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+	})
+	if err != nil {
+		c.logger.Error("Invoke model error", zap.String("model_id", req.ModelID), zap.Error(err))
+		return nil, err
+	}
+
+	resp, err := family.decode(result.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.ModelID = req.ModelID
+	c.recordUsage(req.ModelID, resp.Usage, time.Since(start))
+
+	return resp, nil
+}
+
+// Prompt sends a single user message and returns the reply.
+func (c *BedrockConnector) Prompt(ctx context.Context, prompt string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BedrockConnector", "Prompt")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	resp, err := c.Chat(ctx, ChatRequest{
+		Messages: []Message{{Role: RoleUser, Content: prompt}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Text, nil
+}
+
+func (c *BedrockConnector) applyDefaults(req *ChatRequest) {
+	if req.ModelID == "" {
+		req.ModelID = c.config.ModelID
+	}
+
+	if req.MaxTokens == 0 {
+		req.MaxTokens = c.config.MaxTokens
+	}
+
+	if req.Temperature == nil {
+		if temperature := c.config.Temperature; temperature >= 0 {
+			req.Temperature = aws.Float64(temperature)
+		}
+	}
+
+	if req.TopP == nil {
+		if topP := c.config.TopP; topP >= 0 {
+			req.TopP = aws.Float64(topP)
+		}
+	}
+
+	if req.StopSequences == nil {
+		req.StopSequences = c.config.StopSequences
+	}
+}
+
+func (c *BedrockConnector) recordUsage(modelID string, usage Usage, latency time.Duration) {
+	c.logger.Debug("Model usage",
+		zap.String("model_id", modelID),
+		zap.Int("input_tokens", usage.InputTokens),
+		zap.Int("output_tokens", usage.OutputTokens),
+		zap.Duration("latency", latency),
+	)
+
+	if c.params.Metrics == nil {
+		return
+	}
+
+	dims := cloudwatch_metrics_connector.Dimensions{"ModelId": modelID}
+
+	c.params.Metrics.Count("InputTokens", float64(usage.InputTokens), dims)
+	c.params.Metrics.Count("OutputTokens", float64(usage.OutputTokens), dims)
+	c.params.Metrics.Timing("InvokeLatency", latency, dims)
+}
@@ -0,0 +1,35 @@
+package bedrock_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. BEDROCK_MODEL_ID for the bedrock scope.
+type Config struct {
+	ModelID              string   `mapstructure:"model_id" default:"anthropic.claude-3-haiku-20240307-v1:0"`
+	MaxTokens            int      `mapstructure:"max_tokens" default:"1024"`
+	Temperature          float64  `mapstructure:"temperature" default:"-1"`
+	TopP                 float64  `mapstructure:"top_p" default:"-1"`
+	StopSequences        []string `mapstructure:"stop_sequences" default:""`
+	EmbeddingModelID     string   `mapstructure:"embedding_model_id" default:"amazon.titan-embed-text-v2:0"`
+	EmbeddingDimensions  int      `mapstructure:"embedding_dimensions" default:"1024"`
+	EmbeddingNormalize   bool     `mapstructure:"embedding_normalize" default:"true"`
+	EmbeddingInputType   string   `mapstructure:"embedding_input_type" default:"search_document"`
+	EmbeddingConcurrency int      `mapstructure:"embedding_concurrency" default:"4"`
+	BedrockKey           string   `mapstructure:"bedrock_key" default:"ABCDE"`
+	BedrockSecret        string   `mapstructure:"bedrock_secret" default:"example_secret"`
+	BedrockToken         string   `mapstructure:"bedrock_token" default:""`
+	BedrockRegion        string   `mapstructure:"bedrock_region" default:"us-west-1"`
+	VerifyCredentials    bool     `mapstructure:"verify_credentials" default:"false"`
+	Preflight            bool     `mapstructure:"preflight" default:"false"`
+}
+
+func (c *BedrockConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *BedrockConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,260 @@
+package bedrock_connector
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/cloudwatch_metrics_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultModelID              = "anthropic.claude-3-haiku-20240307-v1:0"
+	DefaultMaxTokens            = 1024
+	DefaultTemperature          = -1 // negative leaves the model's default
+	DefaultTopP                 = -1 // negative leaves the model's default
+	DefaultEmbeddingModelID     = "amazon.titan-embed-text-v2:0"
+	DefaultEmbeddingDimensions  = 1024
+	DefaultEmbeddingNormalize   = true
+	DefaultEmbeddingInputType   = "search_document"
+	DefaultEmbeddingConcurrency = 4
+	DefaultBedrockKey           = "ABCDE"
+	DefaultBedrockSecret        = "example_secret"
+	DefaultBedrockToken         = ""
+	DefaultBedrockRegion        = "us-west-1"
+)
+
+var preflightActions = []string{
+	"bedrock:InvokeModel",
+	"bedrock:InvokeModelWithResponseStream",
+}
+
+// BedrockConnector invokes foundation models through Bedrock Runtime. With
+// the CloudWatch metrics connector it reports token usage per model.
+type BedrockConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *bedrockruntime.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider                                  `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig                                     `optional:"true"`
+	CredentialChain *awscredentials.Chain                                    `optional:"true"`
+	Metrics         *cloudwatch_metrics_connector.CloudWatchMetricsConnector `optional:"true"`
+	Tracer          *xray.Tracer                                             `optional:"true"`
+	Breaker         *circuitbreaker.Breaker                                  `optional:"true"`
+	Prometheus      *metrics.Metrics                                         `optional:"true"`
+	Telemetry       *telemetry.Telemetry                                     `optional:"true"`
+	Health          *health.Health                                           `optional:"true"`
+	Faults          *faults.Injector                                         `optional:"true"`
+	Client          *bedrockruntime.Client                                   `optional:"true"`
+}
+
+// Module provides the BedrockConnector of scope. It uses the
+// *bedrockruntime.Client in the graph, if any, instead of building one,
+// and adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the BedrockConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *bedrockruntime.Client named after
+// scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *BedrockConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*bedrockruntime.Client](scope, named),
+		instance.Provide[*BedrockConnector](scope, named, func(p Params) *BedrockConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &BedrockConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *BedrockConnector) ChatModel {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *BedrockConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting BedrockConnector",
+		zap.String("model_id", c.config.ModelID),
+		zap.String("bedrock_region", c.config.BedrockRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = bedrockruntime.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = bedrockruntime.New(c.client.Options(), func(o *bedrockruntime.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *BedrockConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped BedrockConnector")
+
+	return nil
+}
+
+func (c *BedrockConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.BedrockRegion,
+		Key:         c.config.BedrockKey,
+		Secret:      c.config.BedrockSecret,
+		Token:       c.config.BedrockToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *BedrockConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("bedrock_region")
+
+	v.Required("model_id")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("bedrock_key", DefaultBedrockKey)
+		v.NotPlaceholder("bedrock_secret", DefaultBedrockSecret)
+	}
+
+	return v.Err()
+}
+
+// probe retrieves the credentials; the runtime API has no read
+// cheap enough to probe with.
+func (c *BedrockConnector) probe(ctx context.Context) error {
+	_, err := c.client.Options().Credentials.Retrieve(ctx)
+
+	return err
+}
+
+func (c *BedrockConnector) GetClient() *bedrockruntime.Client {
+	return c.client
+}
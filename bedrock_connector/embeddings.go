@@ -0,0 +1,159 @@
+package bedrock_connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+)
+
+// cohereBatchSize is the number of texts Cohere Embed accepts per call.
+const cohereBatchSize = 96
+
+type titanEmbeddingRequest struct {
+	InputText  string `json:"inputText"`
+	Dimensions int    `json:"dimensions,omitempty"`
+	Normalize  *bool  `json:"normalize,omitempty"`
+}
+
+type titanEmbeddingResponse struct {
+	Embedding           []float32 `json:"embedding"`
+	InputTextTokenCount int       `json:"inputTextTokenCount"`
+}
+
+type cohereEmbeddingRequest struct {
+	Texts     []string `json:"texts"`
+	InputType string   `json:"input_type"`
+}
+
+type cohereEmbeddingResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// Embed returns an embedding per text with embedding_model_id. Cohere
+// Embed models take batches of up to 96 texts per call; Titan Embeddings
+// takes one text per call, so texts are embedded embedding_concurrency at
+// a time.
+func (c *BedrockConnector) Embed(ctx context.Context, texts ...string) (_ [][]float32, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BedrockConnector", "Embed")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	modelID := c.config.EmbeddingModelID
+
+	switch {
+	case strings.Contains(modelID, "cohere.embed"):
+		return c.embedCohere(ctx, modelID, texts)
+	case strings.Contains(modelID, "amazon.titan-embed"):
+		return c.embedTitan(ctx, modelID, texts)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedModel, modelID)
+}
+
+func (c *BedrockConnector) embedCohere(ctx context.Context, modelID string, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+
+	for offset := 0; offset < len(texts); offset += cohereBatchSize {
+		var resp cohereEmbeddingResponse
+		err := c.invokeJSON(ctx, modelID, cohereEmbeddingRequest{
+			Texts:     texts[offset:min(offset+cohereBatchSize, len(texts))],
+			InputType: c.config.EmbeddingInputType,
+		}, &resp)
+		if err != nil {
+			return nil, err
+		}
+
+		embeddings = append(embeddings, resp.Embeddings...)
+	}
+
+	return embeddings, nil
+}
+
+func (c *BedrockConnector) embedTitan(ctx context.Context, modelID string, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+
+	// Only V2 takes dimensions and normalize; V1 rejects them
+	var dimensions int
+	var normalize *bool
+	if strings.Contains(modelID, "-v2") {
+		dimensions = c.config.EmbeddingDimensions
+		normalize = aws.Bool(c.config.EmbeddingNormalize)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	slots := make(chan struct{}, max(c.config.EmbeddingConcurrency, 1))
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	for i, text := range texts {
+		slots <- struct{}{}
+		if ctx.Err() != nil {
+			<-slots
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			var resp titanEmbeddingResponse
+			err := c.invokeJSON(ctx, modelID, titanEmbeddingRequest{
+				InputText:  text,
+				Dimensions: dimensions,
+				Normalize:  normalize,
+			}, &resp)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+
+			embeddings[i] = resp.Embedding
+		}()
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return embeddings, nil
+}
+
+func (c *BedrockConnector) invokeJSON(ctx context.Context, modelID string, in interface{}, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(modelID),
+		Body:        body,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+	})
+	if err != nil {
+		c.logger.Error("Invoke model error", zap.String("model_id", modelID), zap.Error(err))
+		return err
+	}
+
+	return json.Unmarshal(result.Body, out)
+}
@@ -0,0 +1,15 @@
+package bedrock_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . ChatModel
+
+// ChatModel is a conversational model that can also embed text.
+type ChatModel interface {
+	Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+	Prompt(ctx context.Context, prompt string) (string, error)
+	ChatStreamFunc(ctx context.Context, req ChatRequest, onText func(text string) error) (*ChatResponse, error)
+	Embed(ctx context.Context, texts ...string) ([][]float32, error)
+}
+
+var _ ChatModel = (*BedrockConnector)(nil)
@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/bedrock_connector (interfaces: ChatModel)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . ChatModel
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	bedrock_connector "github.com/elmntri/zeitgeber-aws-modules/bedrock_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockChatModel is a mock of ChatModel interface.
+type MockChatModel struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatModelMockRecorder
+}
+
+// MockChatModelMockRecorder is the mock recorder for MockChatModel.
+type MockChatModelMockRecorder struct {
+	mock *MockChatModel
+}
+
+// NewMockChatModel creates a new mock instance.
+func NewMockChatModel(ctrl *gomock.Controller) *MockChatModel {
+	mock := &MockChatModel{ctrl: ctrl}
+	mock.recorder = &MockChatModelMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChatModel) EXPECT() *MockChatModelMockRecorder {
+	return m.recorder
+}
+
+// Chat mocks base method.
+func (m *MockChatModel) Chat(arg0 context.Context, arg1 bedrock_connector.ChatRequest) (*bedrock_connector.ChatResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Chat", arg0, arg1)
+	ret0, _ := ret[0].(*bedrock_connector.ChatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Chat indicates an expected call of Chat.
+func (mr *MockChatModelMockRecorder) Chat(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Chat", reflect.TypeOf((*MockChatModel)(nil).Chat), arg0, arg1)
+}
+
+// ChatStreamFunc mocks base method.
+func (m *MockChatModel) ChatStreamFunc(arg0 context.Context, arg1 bedrock_connector.ChatRequest, arg2 func(string) error) (*bedrock_connector.ChatResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChatStreamFunc", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*bedrock_connector.ChatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChatStreamFunc indicates an expected call of ChatStreamFunc.
+func (mr *MockChatModelMockRecorder) ChatStreamFunc(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChatStreamFunc", reflect.TypeOf((*MockChatModel)(nil).ChatStreamFunc), arg0, arg1, arg2)
+}
+
+// Embed mocks base method.
+func (m *MockChatModel) Embed(arg0 context.Context, arg1 ...string) ([][]float32, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Embed", varargs...)
+	ret0, _ := ret[0].([][]float32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Embed indicates an expected call of Embed.
+func (mr *MockChatModelMockRecorder) Embed(arg0 any, arg1 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Embed", reflect.TypeOf((*MockChatModel)(nil).Embed), varargs...)
+}
+
+// Prompt mocks base method.
+func (m *MockChatModel) Prompt(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prompt", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prompt indicates an expected call of Prompt.
+func (mr *MockChatModelMockRecorder) Prompt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prompt", reflect.TypeOf((*MockChatModel)(nil).Prompt), arg0, arg1)
+}
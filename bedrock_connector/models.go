@@ -0,0 +1,292 @@
+package bedrock_connector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrUnsupportedModel = awserrors.New(awserrors.ErrValidation, "unsupported model")
+
+const anthropicVersion = "bedrock-2023-05-31"
+
+// modelFamily translates chat requests to and from a provider's payload
+// shape. decodeChunk reads the text and, once known, the stop reason of a
+// streamed chunk.
+type modelFamily interface {
+	encode(req *ChatRequest) ([]byte, error)
+	decode(body []byte) (*ChatResponse, error)
+	decodeChunk(body []byte) (string, string, error)
+}
+
+// familyFor picks the payload shape from the model ID, which may carry a
+// cross-region prefix such as "us.".
+func familyFor(modelID string) (modelFamily, error) {
+	switch {
+	case strings.Contains(modelID, "anthropic."):
+		return anthropicFamily{}, nil
+	case strings.Contains(modelID, "amazon.titan-text"):
+		return titanFamily{}, nil
+	case strings.Contains(modelID, "meta.llama3"):
+		return llamaFamily{}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedModel, modelID)
+}
+
+// Anthropic Messages API
+
+type anthropicContent struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string             `json:"role"`
+	Content []anthropicContent `json:"content"`
+}
+
+type anthropicRequest struct {
+	AnthropicVersion string             `json:"anthropic_version"`
+	MaxTokens        int                `json:"max_tokens"`
+	System           string             `json:"system,omitempty"`
+	Messages         []anthropicMessage `json:"messages"`
+	Temperature      *float64           `json:"temperature,omitempty"`
+	TopP             *float64           `json:"top_p,omitempty"`
+	StopSequences    []string           `json:"stop_sequences,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content    []anthropicContent `json:"content"`
+	StopReason string             `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+type anthropicFamily struct{}
+
+func (anthropicFamily) encode(req *ChatRequest) ([]byte, error) {
+	messages := make([]anthropicMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		messages = append(messages, anthropicMessage{
+			Role:    m.Role,
+			Content: []anthropicContent{{Type: "text", Text: m.Content}},
+		})
+	}
+
+	return json.Marshal(anthropicRequest{
+		AnthropicVersion: anthropicVersion,
+		MaxTokens:        req.MaxTokens,
+		System:           req.System,
+		Messages:         messages,
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		StopSequences:    req.StopSequences,
+	})
+}
+
+func (anthropicFamily) decode(body []byte) (*ChatResponse, error) {
+	var resp anthropicResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	for _, content := range resp.Content {
+		if content.Type == "text" {
+			text.WriteString(content.Text)
+		}
+	}
+
+	return &ChatResponse{
+		Text:       text.String(),
+		StopReason: resp.StopReason,
+		Usage: Usage{
+			InputTokens:  resp.Usage.InputTokens,
+			OutputTokens: resp.Usage.OutputTokens,
+		},
+	}, nil
+}
+
+type anthropicChunk struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+}
+
+func (anthropicFamily) decodeChunk(body []byte) (string, string, error) {
+	var chunk anthropicChunk
+	if err := json.Unmarshal(body, &chunk); err != nil {
+		return "", "", err
+	}
+
+	switch chunk.Type {
+	case "content_block_delta":
+		return chunk.Delta.Text, "", nil
+	case "message_delta":
+		return "", chunk.Delta.StopReason, nil
+	}
+
+	return "", "", nil
+}
+
+// Amazon Titan Text, which takes a single prompt
+
+type titanConfig struct {
+	MaxTokenCount int      `json:"maxTokenCount"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"topP,omitempty"`
+	StopSequences []string `json:"stopSequences,omitempty"`
+}
+
+type titanRequest struct {
+	InputText            string      `json:"inputText"`
+	TextGenerationConfig titanConfig `json:"textGenerationConfig"`
+}
+
+type titanResponse struct {
+	InputTextTokenCount int `json:"inputTextTokenCount"`
+	Results             []struct {
+		TokenCount       int    `json:"tokenCount"`
+		OutputText       string `json:"outputText"`
+		CompletionReason string `json:"completionReason"`
+	} `json:"results"`
+}
+
+type titanFamily struct{}
+
+func (titanFamily) encode(req *ChatRequest) ([]byte, error) {
+	var prompt strings.Builder
+	if req.System != "" {
+		prompt.WriteString(req.System + "\n\n")
+	}
+
+	for _, m := range req.Messages {
+		if m.Role == RoleAssistant {
+			prompt.WriteString("Bot: " + m.Content + "\n")
+		} else {
+			prompt.WriteString("User: " + m.Content + "\n")
+		}
+	}
+	prompt.WriteString("Bot:")
+
+	return json.Marshal(titanRequest{
+		InputText: prompt.String(),
+		TextGenerationConfig: titanConfig{
+			MaxTokenCount: req.MaxTokens,
+			Temperature:   req.Temperature,
+			TopP:          req.TopP,
+			StopSequences: req.StopSequences,
+		},
+	})
+}
+
+func (titanFamily) decode(body []byte) (*ChatResponse, error) {
+	var resp titanResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	chat := &ChatResponse{
+		Usage: Usage{InputTokens: resp.InputTextTokenCount},
+	}
+
+	if len(resp.Results) > 0 {
+		chat.Text = strings.TrimSpace(resp.Results[0].OutputText)
+		chat.StopReason = resp.Results[0].CompletionReason
+		chat.Usage.OutputTokens = resp.Results[0].TokenCount
+	}
+
+	return chat, nil
+}
+
+type titanChunk struct {
+	OutputText       string `json:"outputText"`
+	CompletionReason string `json:"completionReason"`
+}
+
+func (titanFamily) decodeChunk(body []byte) (string, string, error) {
+	var chunk titanChunk
+	if err := json.Unmarshal(body, &chunk); err != nil {
+		return "", "", err
+	}
+
+	return chunk.OutputText, chunk.CompletionReason, nil
+}
+
+// Meta Llama 3, which takes a prompt in its chat template
+
+type llamaRequest struct {
+	Prompt      string   `json:"prompt"`
+	MaxGenLen   int      `json:"max_gen_len"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+}
+
+type llamaResponse struct {
+	Generation           string `json:"generation"`
+	PromptTokenCount     int    `json:"prompt_token_count"`
+	GenerationTokenCount int    `json:"generation_token_count"`
+	StopReason           string `json:"stop_reason"`
+}
+
+type llamaFamily struct{}
+
+func (llamaFamily) encode(req *ChatRequest) ([]byte, error) {
+	var prompt strings.Builder
+	prompt.WriteString("<|begin_of_text|>")
+
+	turn := func(role string, content string) {
+		prompt.WriteString("<|start_header_id|>" + role + "<|end_header_id|>\n\n" + content + "<|eot_id|>")
+	}
+
+	if req.System != "" {
+		turn("system", req.System)
+	}
+
+	for _, m := range req.Messages {
+		turn(m.Role, m.Content)
+	}
+
+	prompt.WriteString("<|start_header_id|>assistant<|end_header_id|>\n\n")
+
+	// Llama has no stop sequences; StopSequences is ignored
+	return json.Marshal(llamaRequest{
+		Prompt:      prompt.String(),
+		MaxGenLen:   req.MaxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	})
+}
+
+func (llamaFamily) decode(body []byte) (*ChatResponse, error) {
+	var resp llamaResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	return &ChatResponse{
+		Text:       strings.TrimSpace(resp.Generation),
+		StopReason: resp.StopReason,
+		Usage: Usage{
+			InputTokens:  resp.PromptTokenCount,
+			OutputTokens: resp.GenerationTokenCount,
+		},
+	}, nil
+}
+
+func (llamaFamily) decodeChunk(body []byte) (string, string, error) {
+	var chunk llamaResponse
+	if err := json.Unmarshal(body, &chunk); err != nil {
+		return "", "", err
+	}
+
+	return chunk.Generation, chunk.StopReason, nil
+}
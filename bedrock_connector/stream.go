@@ -0,0 +1,183 @@
+package bedrock_connector
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+// Bedrock appends the invocation metrics to the last chunk of every
+// model's stream.
+type invocationMetrics struct {
+	Metrics *struct {
+		InputTokenCount  int `json:"inputTokenCount"`
+		OutputTokenCount int `json:"outputTokenCount"`
+	} `json:"amazon-bedrock-invocationMetrics"`
+}
+
+// Stream iterates over the text of a streamed chat response:
+//
+//	for stream.Next() {
+//		fmt.Print(stream.Text())
+//	}
+//	if err := stream.Err(); err != nil { ... }
+//
+// Close the stream when stopping early.
+type Stream struct {
+	c       *BedrockConnector
+	family  modelFamily
+	events  *bedrockruntime.InvokeModelWithResponseStreamEventStream
+	start   time.Time
+	text    string
+	err     error
+	done    bool
+	builder strings.Builder
+	resp    ChatResponse
+}
+
+// ChatStream is Chat with the reply streamed as it is generated.
+func (c *BedrockConnector) ChatStream(ctx context.Context, req ChatRequest) (_ *Stream, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BedrockConnector", "ChatStream")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	c.applyDefaults(&req)
+
+	family, err := familyFor(req.ModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := family.encode(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	result, err := c.client.InvokeModelWithResponseStream(ctx, &bedrockruntime.InvokeModelWithResponseStreamInput{
+		ModelId:     aws.String(req.ModelID),
+		Body:        body,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+	})
+	if err != nil {
+		c.logger.Error("Invoke model with response stream error", zap.String("model_id", req.ModelID), zap.Error(err))
+		return nil, err
+	}
+
+	return &Stream{
+		c:      c,
+		family: family,
+		events: result.GetStream(),
+		start:  start,
+		resp:   ChatResponse{ModelID: req.ModelID},
+	}, nil
+}
+
+// ChatStreamFunc streams the reply to onText and returns the complete
+// response. An error from onText stops the stream.
+func (c *BedrockConnector) ChatStreamFunc(ctx context.Context, req ChatRequest, onText func(text string) error) (_ *ChatResponse, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BedrockConnector", "ChatStreamFunc")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	stream, err := c.ChatStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	for stream.Next() {
+		if err := onText(stream.Text()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	return stream.Response(), nil
+}
+
+// Next advances to the next piece of text and reports whether there is
+// one.
+func (s *Stream) Next() bool {
+	for !s.done {
+		event, ok := <-s.events.Events()
+		if !ok {
+			s.finish(s.events.Err())
+			return false
+		}
+
+		chunk, ok := event.(*types.ResponseStreamMemberChunk)
+		if !ok {
+			continue
+		}
+
+		text, stopReason, err := s.family.decodeChunk(chunk.Value.Bytes)
+		if err != nil {
+			s.finish(err)
+			return false
+		}
+
+		if stopReason != "" {
+			s.resp.StopReason = stopReason
+		}
+
+		var metrics invocationMetrics
+		if err := json.Unmarshal(chunk.Value.Bytes, &metrics); err == nil && metrics.Metrics != nil {
+			s.resp.Usage = Usage{
+				InputTokens:  metrics.Metrics.InputTokenCount,
+				OutputTokens: metrics.Metrics.OutputTokenCount,
+			}
+		}
+
+		if text != "" {
+			s.text = text
+			s.builder.WriteString(text)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Text is the piece of text read by the last call to Next.
+func (s *Stream) Text() string {
+	return s.text
+}
+
+func (s *Stream) Err() error {
+	return s.err
+}
+
+// Response is the complete reply with usage, once Next returned false.
+func (s *Stream) Response() *ChatResponse {
+	resp := s.resp
+	resp.Text = s.builder.String()
+
+	return &resp
+}
+
+func (s *Stream) Close() error {
+	return s.events.Close()
+}
+
+func (s *Stream) finish(err error) {
+	s.done = true
+	s.err = err
+
+	if err != nil {
+		s.c.logger.Error("Response stream error", zap.String("model_id", s.resp.ModelID), zap.Error(err))
+		return
+	}
+
+	s.c.recordUsage(s.resp.ModelID, s.resp.Usage, time.Since(s.start))
+}
@@ -0,0 +1,66 @@
+package bucket_connector
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// URLStyleCDN makes ObjectURL return the URL of key on the CloudFront
+// connector's domain, for buckets served through a distribution.
+const URLStyleCDN = "cdn"
+
+var ErrNoCDN = awserrors.New(awserrors.ErrValidation, "no CloudFront connector")
+
+// SignedObjectURL returns a signed URL of key on the CloudFront
+// connector's domain, for private content served through it.
+func (c *BucketConnector) SignedObjectURL(key string) (string, error) {
+	if c.params.CDN == nil {
+		return "", ErrNoCDN
+	}
+
+	return c.params.CDN.SignURL(escapeKey(key))
+}
+
+// InvalidateObjects removes keys, which may end in a * wildcard, from the
+// edge caches of the CloudFront connector's distribution, such as after
+// they were overwritten or deleted, and waits until CloudFront is done.
+func (c *BucketConnector) InvalidateObjects(ctx context.Context, keys ...string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BucketConnector", "InvalidateObjects")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if c.params.CDN == nil {
+		return ErrNoCDN
+	}
+
+	paths := make([]string, len(keys))
+	for i, key := range keys {
+		prefix, wildcard := strings.CutSuffix(key, "*")
+		paths[i] = "/" + escapeKey(prefix)
+		if wildcard {
+			paths[i] += "*"
+		}
+	}
+
+	if err := c.params.CDN.Invalidate(ctx, paths...); err != nil {
+		c.logger.Error("Invalidate objects error", zap.Strings("keys", keys), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// escapeKey escapes the segments of key, keeping its slashes so the path
+// matches the one CloudFront caches.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
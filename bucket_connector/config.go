@@ -0,0 +1,33 @@
+package bucket_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. S3_BUCKET_NAME for the s3 scope.
+type Config struct {
+	BucketName        string `mapstructure:"bucket_name" default:"example.com"`
+	BucketKey         string `mapstructure:"bucket_key" default:"ABCDE"`
+	BucketSecret      string `mapstructure:"bucket_secret" default:"example_secret"`
+	BucketToken       string `mapstructure:"bucket_token" default:""`
+	BucketRegion      string `mapstructure:"bucket_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+	URLStyle          string `mapstructure:"url_style" default:"s3"`
+}
+
+// URL styles of ObjectURL; see also URLStyleCDN.
+const (
+	URLStyleS3     = "s3"
+	URLStyleDomain = "domain"
+)
+
+func (c *BucketConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *BucketConnector) Config() Config {
+	return c.config
+}
@@ -2,22 +2,37 @@ package bucket_connector
 
 import (
 	"context"
-	"log"
 	"fmt"
 	"encoding/base64"
 	"net/url"
 	"bytes"
+	"io"
+	"strings"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
-	"github.com/spf13/viper"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/google/uuid"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/cloudfront_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/partition"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
 )
 
 var logger *zap.Logger
@@ -30,6 +45,12 @@ const (
 	DefaultBucketRegion = "us-west-1"
 )
 
+var preflightActions = []string{
+	"s3:GetObject",
+	"s3:PutObject",
+	"s3:DeleteObject",
+}
+
 type UploaderReq struct {
     FileName string `json:"file_name"`
     Category string `json:"category"`
@@ -37,43 +58,74 @@ type UploaderReq struct {
 }
 
 type BucketConnector struct {
-	params Params
-	logger *zap.Logger
-	client *s3.Client
-	scope  string
+	params  Params
+	logger  *zap.Logger
+	client  *s3.Client
+	scope   string
+	tracker *inflight.Tracker
+	config  Config
+
+	interceptors []UploadInterceptor
 }
 
 type Params struct {
 	fx.In
 
-	Lifecycle fx.Lifecycle
-	Logger    *zap.Logger
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider  `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig     `optional:"true"`
+	CredentialChain *awscredentials.Chain    `optional:"true"`
+	Tracer          *xray.Tracer             `optional:"true"`
+	Breaker         *circuitbreaker.Breaker  `optional:"true"`
+	Prometheus      *metrics.Metrics         `optional:"true"`
+	Telemetry       *telemetry.Telemetry     `optional:"true"`
+	Health          *health.Health           `optional:"true"`
+	Faults          *faults.Injector         `optional:"true"`
+	Client          *s3.Client               `optional:"true"`
+	CDN             cloudfront_connector.CDN `optional:"true"`
 }
 
+// Module provides the BucketConnector of scope. It uses the *s3.Client
+// in the graph, if any, instead of building one, and adds the
+// connector's middleware to it. With the CloudFront connector's CDN in
+// the graph, objects can be served and invalidated through its
+// distribution.
 func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the BucketConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *s3.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
 
 	var m *BucketConnector
 
 	return fx.Module(
 		scope,
-		fx.Provide(func(p Params) *BucketConnector {
-
-			logger = p.Logger.Named(scope)
-
-			cfg, err := config.LoadDefaultConfig(context.TODO())
-			if err != nil {
-				log.Fatal(err)
-				return &BucketConnector{}
-			}
+		instance.Client[*s3.Client](scope, named),
+		instance.Provide[*BucketConnector](scope, named, func(p Params) *BucketConnector {
 
+			logger = requestid.Logger(p.Logger.Named(scope))
 
+			// The client is created in onStart, where config errors can be
+			// returned to fx instead of exiting the process.
 			m := &BucketConnector{
 				params: p,
 				logger: logger,
 				scope:  scope,
-				client: s3.NewFromConfig(cfg),
 			}
 
+			m.initDefaultConfigs()
+
+			return m
+		}),
+		instance.Export(scope, named, func(m *BucketConnector) ObjectStore {
 			return m
 		}),
 		fx.Populate(&m),
@@ -91,52 +143,151 @@ func Module(scope string) fx.Option {
 	)
 }
 
-func (c *BucketConnector) getConfigPath(key string) string {
-	return fmt.Sprintf("%s.%s", c.scope, key)
-}
-
-func (c *BucketConnector) initDefaultConfigs() {
-	viper.SetDefault(c.getConfigPath("bucket_name"), DefaultBucketName)
-	viper.SetDefault(c.getConfigPath("bucket_key"), DefaultBucketKey)
-	viper.SetDefault(c.getConfigPath("bucket_secret"), DefaultBucketSecret)
-	viper.SetDefault(c.getConfigPath("bucket_token"), DefaultBucketToken)
-	viper.SetDefault(c.getConfigPath("bucket_region"), DefaultBucketRegion)
-}
-
 func (c *BucketConnector) onStart(ctx context.Context) error {
-	logger.Info("Starting BucketConnector",
-		zap.String("bucket_name", viper.GetString(c.getConfigPath("bucket_name"))),
-		zap.String("bucket_key", viper.GetString(c.getConfigPath("bucket_key"))),
-		zap.String("bucket_secret", viper.GetString(c.getConfigPath("bucket_secret"))),
-		zap.String("bucket_token", viper.GetString(c.getConfigPath("bucket_token"))),
-		zap.String("bucket_region", viper.GetString(c.getConfigPath("bucket_region"))),
-	)
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
 
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			viper.GetString(c.getConfigPath("bucket_key")),
-			viper.GetString(c.getConfigPath("bucket_secret")),
-			viper.GetString(c.getConfigPath("bucket_token")),
-		)),
-		config.WithRegion(viper.GetString(c.getConfigPath("bucket_region"))),
+	c.logger.Info("Starting BucketConnector",
+		zap.String("bucket_name", c.config.BucketName),
+		zap.String("bucket_key", c.config.BucketKey),
+		zap.String("bucket_secret", c.config.BucketSecret),
+		zap.String("bucket_token", c.config.BucketToken),
+		zap.String("bucket_region", c.config.BucketRegion),
+		zap.String("url_style", c.config.URLStyle),
 	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
 	if err != nil {
 		c.logger.Error("Load AWS config error", zap.Error(err))
 		return err
 	}
 
-	c.client = s3.NewFromConfig(cfg)
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions,
+			"arn:{partition}:s3:::"+c.config.BucketName+"/*",
+		)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = s3.NewFromConfig(cfg, func(o *s3.Options) {
+			o.UsePathStyle = awsconfig.LocalMode()
+		})
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = s3.New(c.client.Options(), func(o *s3.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
 
 	return nil
 }
 
 func (c *BucketConnector) onStop(ctx context.Context) error {
 
+	c.tracker.Shutdown(ctx)
+
 	c.logger.Info("Stopped BucketConnector")
 
 	return nil
 }
 
+func (c *BucketConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.BucketRegion,
+		Key:         c.config.BucketKey,
+		Secret:      c.config.BucketSecret,
+		Token:       c.config.BucketToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *BucketConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("bucket_region")
+
+	v.Required("bucket_name")
+	v.NotPlaceholder("bucket_name", DefaultBucketName)
+	v.BucketName("bucket_name")
+
+	switch style := c.config.URLStyle; style {
+	case URLStyleS3, URLStyleDomain:
+	case URLStyleCDN:
+		if c.params.CDN == nil {
+			v.Addf("url_style %s needs the CloudFront connector", URLStyleCDN)
+		}
+	default:
+		v.Addf("url_style %q is not one of %s, %s, %s", style, URLStyleS3, URLStyleDomain, URLStyleCDN)
+	}
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("bucket_key", DefaultBucketKey)
+		v.NotPlaceholder("bucket_secret", DefaultBucketSecret)
+	}
+
+	return v.Err()
+}
+
+// probe checks the bucket exists and is accessible.
+func (c *BucketConnector) probe(ctx context.Context) error {
+	_, err := c.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(c.GetBucketName()),
+	})
+
+	return err
+}
+
 func (c *BucketConnector) ListBuckets() ([]types.Bucket, error) {
 	result, err := c.client.ListBuckets(context.TODO(), &s3.ListBucketsInput{})
 
@@ -165,7 +316,7 @@ func (c *BucketConnector) SaveFile(req *UploaderReq, contentType string) (string
 
 	c.logger.Info("Uploading file to S3", zap.String("file_path", filePath))
 
-	bucketName := viper.GetString(c.getConfigPath("bucket_name"))
+	bucketName := c.config.BucketName
 	_, err = c.client.PutObject(context.TODO(), &s3.PutObjectInput{
 		Bucket:        aws.String(bucketName),
 		Key:           aws.String(filePath),
@@ -178,9 +329,105 @@ func (c *BucketConnector) SaveFile(req *UploaderReq, contentType string) (string
 		return "", err
 	}
 
-	url := fmt.Sprintf("https://%s/%s", bucketName, url.PathEscape(filePath))
+	if err := c.intercept(context.TODO(), filePath, contentType); err != nil {
+		return "", err
+	}
+
+	return c.ObjectURL(filePath), nil
+}
+
+func (c *BucketConnector) PutObject(ctx context.Context, key string, data []byte, contentType string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BucketConnector", "PutObject")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(c.GetBucketName()),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(data),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(int64(len(data))),
+	})
+	if err != nil {
+		c.logger.Error("Put object to S3 error", zap.String("key", key), zap.Error(err))
+		return err
+	}
+
+	return c.intercept(ctx, key, contentType)
+}
+
+func (c *BucketConnector) GetObject(ctx context.Context, key string) (_ []byte, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BucketConnector", "GetObject")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.GetBucketName()),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer result.Body.Close()
+
+	return io.ReadAll(result.Body)
+}
+
+func (c *BucketConnector) DeleteObject(ctx context.Context, key string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "BucketConnector", "DeleteObject")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(c.GetBucketName()),
+		Key:    aws.String(key),
+	})
+
+	return err
+}
+
+// ObjectURL returns the URL of key in the bucket. With the s3 url_style
+// it is the S3 URL of the bucket's region, in the domain of its
+// partition, and path-style for bucket names with dots, which the
+// certificates of virtual-hosted URLs do not cover. With the domain
+// url_style it is https://<bucket>/<key>, for buckets named after the
+// domain serving them, and with the cdn url_style the URL of key on the
+// CloudFront connector's domain.
+func (c *BucketConnector) ObjectURL(key string) string {
+	if c.config.URLStyle == URLStyleCDN && c.params.CDN != nil {
+		return c.params.CDN.URL(escapeKey(key))
+	}
+
+	bucket := c.GetBucketName()
+	path := url.PathEscape(key)
+
+	if c.config.URLStyle == URLStyleDomain {
+		return fmt.Sprintf("https://%s/%s", bucket, path)
+	}
+
+	if awsconfig.LocalMode() {
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(awsconfig.LocalEndpoint(), "/"), bucket, path)
+	}
+
+	region := c.region()
+	host := partition.ForRegion(region).Host("s3", region)
+
+	if strings.Contains(bucket, ".") {
+		return fmt.Sprintf("https://%s/%s/%s", host, bucket, path)
+	}
+
+	return fmt.Sprintf("https://%s.%s/%s", bucket, host, path)
+}
+
+// region is the region of the client, which the shared AWSConfig may set,
+// or bucket_region before the connector started.
+func (c *BucketConnector) region() string {
+	if c.client != nil {
+		return c.client.Options().Region
+	}
+
+	return c.config.BucketRegion
+}
 
-	return url, nil
+func (c *BucketConnector) GetBucketName() string {
+	return c.config.BucketName
 }
 
 func (c *BucketConnector) GetClient() *s3.Client {
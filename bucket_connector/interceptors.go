@@ -0,0 +1,33 @@
+package bucket_connector
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// UploadInterceptor inspects an object after SaveFile or PutObject
+// uploaded it. Returning an error deletes the object and fails the upload.
+type UploadInterceptor func(ctx context.Context, key string, contentType string) error
+
+// AddUploadInterceptor registers an interceptor. Register interceptors
+// before the app starts.
+func (c *BucketConnector) AddUploadInterceptor(interceptor UploadInterceptor) {
+	c.interceptors = append(c.interceptors, interceptor)
+}
+
+func (c *BucketConnector) intercept(ctx context.Context, key string, contentType string) error {
+	for _, interceptor := range c.interceptors {
+		if err := interceptor(ctx, key, contentType); err != nil {
+			c.logger.Warn("Upload rejected", zap.String("key", key), zap.Error(err))
+
+			if err := c.DeleteObject(ctx, key); err != nil {
+				c.logger.Error("Delete rejected upload error", zap.String("key", key), zap.Error(err))
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package bucket_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . ObjectStore
+
+// ObjectStore reads and writes objects of the bucket.
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+	GetObject(ctx context.Context, key string) ([]byte, error)
+	DeleteObject(ctx context.Context, key string) error
+	ObjectURL(key string) string
+}
+
+var (
+	_ ObjectStore = (*BucketConnector)(nil)
+	_ ObjectStore = (*MemoryObjectStore)(nil)
+)
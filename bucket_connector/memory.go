@@ -0,0 +1,90 @@
+package bucket_connector
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+}
+
+// MemoryObjectStore is an in-process ObjectStore for tests. Missing keys
+// fail with *types.NoSuchKey, like S3.
+type MemoryObjectStore struct {
+	bucket  string
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+func NewMemoryObjectStore(bucket string) *MemoryObjectStore {
+	return &MemoryObjectStore{
+		bucket:  bucket,
+		objects: make(map[string]memoryObject),
+	}
+}
+
+func (s *MemoryObjectStore) PutObject(ctx context.Context, key string, data []byte, contentType string) error {
+	s.mu.Lock()
+	s.objects[key] = memoryObject{
+		data:        append([]byte(nil), data...),
+		contentType: contentType,
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *MemoryObjectStore) GetObject(ctx context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	object, ok := s.objects[key]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, awserrors.Wrap(&types.NoSuchKey{Message: aws.String(key)})
+	}
+
+	return append([]byte(nil), object.data...), nil
+}
+
+func (s *MemoryObjectStore) DeleteObject(ctx context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.objects, key)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *MemoryObjectStore) ObjectURL(key string) string {
+	return fmt.Sprintf("https://%s/%s", s.bucket, url.PathEscape(key))
+}
+
+// ContentType returns the content type key was put with.
+func (s *MemoryObjectStore) ContentType(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.objects[key].contentType
+}
+
+// Keys returns the stored keys in order.
+func (s *MemoryObjectStore) Keys() []string {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,273 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultEnabled          = true
+	DefaultFailureThreshold = 5
+	DefaultOpenTimeout      = 30
+	DefaultHalfOpenProbes   = 1
+	DefaultSuccessThreshold = 1
+)
+
+// Class groups the operations of a connector sharing a circuit, so
+// failing writes do not stop reads and the other way round.
+type Class string
+
+const (
+	ClassRead  Class = "read"
+	ClassWrite Class = "write"
+)
+
+var readPrefixes = []string{"Get", "List", "Describe", "Query", "Scan", "Receive", "Head", "BatchGet", "Search", "Lookup"}
+
+// Stats are the counters of one circuit since the app started.
+type Stats struct {
+	Scope     string
+	Class     Class
+	State     State
+	Successes int64
+	Failures  int64
+	Rejected  int64
+	Opened    int64
+}
+
+// Breaker fails AWS calls fast while a service keeps timing out or
+// returning server errors, instead of waiting on every call during an
+// incident. Connectors given a Breaker keep a circuit per operation
+// class, configured under the module's scope:
+//
+//	circuit_breaker:
+//	  failure_threshold: 5
+//	  open_timeout: 30
+//	  half_open_probes: 1
+//	  success_threshold: 1
+//
+// and overridden, or disabled with enabled: false, under a connector's
+// circuit_breaker key. open_timeout is in seconds. Only timeouts,
+// connection errors, throttling and server errors count as failures.
+type Breaker struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+}
+
+func Module(scope string) fx.Option {
+
+	var b *Breaker
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) (*Breaker, error) {
+
+			logger = p.Logger.Named(scope)
+
+			b := &Breaker{
+				params:   p,
+				logger:   logger,
+				scope:    scope,
+				circuits: map[string]*circuit{},
+			}
+
+			b.initDefaultConfigs()
+
+			// Loaded here, as connectors may start before the module
+			if err := moduleconfig.Load(scope, &b.config); err != nil {
+				return nil, err
+			}
+
+			return b, nil
+		}),
+		fx.Populate(&b),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: b.onStart,
+					OnStop:  b.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (b *Breaker) onStart(ctx context.Context) error {
+
+	logger.Info("Starting circuit breaker",
+		zap.Int("failure_threshold", b.config.FailureThreshold),
+		zap.Int("open_timeout", b.config.OpenTimeout),
+	)
+
+	return nil
+}
+
+func (b *Breaker) onStop(ctx context.Context) error {
+
+	b.logger.Info("Stopped circuit breaker")
+
+	return nil
+}
+
+func (b *Breaker) settings(connectorScope string) settings {
+	cfg := b.connectorConfig(connectorScope)
+
+	s := settings{
+		failureThreshold: cfg.FailureThreshold,
+		openTimeout:      time.Duration(cfg.OpenTimeout) * time.Second,
+		halfOpenProbes:   cfg.HalfOpenProbes,
+		successThreshold: cfg.SuccessThreshold,
+	}
+
+	if s.failureThreshold < 1 {
+		s.failureThreshold = 1
+	}
+
+	if s.halfOpenProbes < 1 {
+		s.halfOpenProbes = 1
+	}
+
+	if s.successThreshold < 1 {
+		s.successThreshold = 1
+	}
+
+	return s
+}
+
+// Instrument adds the breaker to the clients created from cfg by the
+// connector of scope. Call it before creating them.
+func (b *Breaker) Instrument(cfg *aws.Config, scope string) {
+	if !b.connectorConfig(scope).Enabled {
+		return
+	}
+
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		// After the service metadata, for the operation name, and before
+		// the retries, so an operation counts once however many attempts
+		// it took.
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("CircuitBreaker",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				c := b.circuit(scope, classOf(awsmiddleware.GetOperationName(ctx)))
+
+				probe, err := c.allow(time.Now())
+				if err != nil {
+					return middleware.InitializeOutput{}, middleware.Metadata{}, err
+				}
+
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				c.done(time.Now(), probe, IsFailure(err))
+
+				return out, metadata, err
+			},
+		), middleware.After)
+	})
+}
+
+func (b *Breaker) circuit(scope string, class Class) *circuit {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := scope + "/" + string(class)
+
+	c, ok := b.circuits[key]
+	if !ok {
+		c = newCircuit(scope, class, b.settings(scope), b.logChange)
+		b.circuits[key] = c
+	}
+
+	return c
+}
+
+func (b *Breaker) logChange(c *circuit, from State, to State) {
+	log := b.logger.Info
+	if to == StateOpen {
+		log = b.logger.Warn
+	}
+
+	log("Circuit state changed",
+		zap.String("connector", c.scope),
+		zap.String("class", string(c.class)),
+		zap.String("from", string(from)),
+		zap.String("to", string(to)),
+	)
+}
+
+// Stats returns the counters of every circuit, ordered by connector and
+// class.
+func (b *Breaker) Stats() []Stats {
+	b.mu.Lock()
+	circuits := make([]*circuit, 0, len(b.circuits))
+	for _, c := range b.circuits {
+		circuits = append(circuits, c)
+	}
+	b.mu.Unlock()
+
+	stats := make([]Stats, 0, len(circuits))
+	for _, c := range circuits {
+		stats = append(stats, c.snapshot())
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Scope != stats[j].Scope {
+			return stats[i].Scope < stats[j].Scope
+		}
+
+		return stats[i].Class < stats[j].Class
+	})
+
+	return stats
+}
+
+// IsFailure reports whether err is a sign of the service being
+// unavailable, rather than of a bad request. Callers cancelling their
+// own context are not failures.
+func IsFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	return retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
+
+func classOf(operation string) Class {
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return ClassRead
+		}
+	}
+
+	return ClassWrite
+}
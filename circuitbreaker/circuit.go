@@ -0,0 +1,175 @@
+package circuitbreaker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrCircuitOpen = awserrors.New(awserrors.ErrTransient, "circuit breaker is open")
+
+// OpenError is returned, without calling AWS, for operations of a class
+// whose circuit is open.
+type OpenError struct {
+	Scope      string
+	Class      Class
+	RetryAfter time.Time
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%s: %s operations: %s until %s", e.Scope, e.Class, ErrCircuitOpen, e.RetryAfter.Format(time.RFC3339))
+}
+
+func (e *OpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+type settings struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenProbes   int
+	successThreshold int
+}
+
+// circuit is the breaker of one operation class of one connector. It
+// opens after failureThreshold failures in a row, rejects calls for
+// openTimeout, then lets halfOpenProbes calls through at a time until
+// successThreshold of them succeed. A failed probe opens it again.
+type circuit struct {
+	scope    string
+	class    Class
+	settings settings
+
+	// onChange is called with the lock held.
+	onChange func(c *circuit, from State, to State)
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	successes int
+	probes    int
+	openedAt  time.Time
+
+	stats Stats
+}
+
+func newCircuit(scope string, class Class, s settings, onChange func(c *circuit, from State, to State)) *circuit {
+	return &circuit{
+		scope:    scope,
+		class:    class,
+		settings: s,
+		onChange: onChange,
+		state:    StateClosed,
+	}
+}
+
+// allow reports whether a call may go through. A call allowed in the
+// half-open state is a probe and must be followed by done.
+func (c *circuit) allow(now time.Time) (probe bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == StateOpen {
+		if now.Sub(c.openedAt) < c.settings.openTimeout {
+			c.stats.Rejected++
+			return false, &OpenError{Scope: c.scope, Class: c.class, RetryAfter: c.openedAt.Add(c.settings.openTimeout)}
+		}
+
+		c.setState(StateHalfOpen)
+	}
+
+	if c.state == StateHalfOpen {
+		if c.probes >= c.settings.halfOpenProbes {
+			c.stats.Rejected++
+			return false, &OpenError{Scope: c.scope, Class: c.class, RetryAfter: now.Add(c.settings.openTimeout)}
+		}
+
+		c.probes++
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// done records the outcome of an allowed call.
+func (c *circuit) done(now time.Time, probe bool, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if probe {
+		c.probes--
+	}
+
+	if failed {
+		c.stats.Failures++
+	} else {
+		c.stats.Successes++
+	}
+
+	switch c.state {
+	case StateClosed:
+		if !failed {
+			c.failures = 0
+			return
+		}
+
+		c.failures++
+		if c.failures >= c.settings.failureThreshold {
+			c.open(now)
+		}
+	case StateHalfOpen:
+		if !probe {
+			return
+		}
+
+		if failed {
+			c.open(now)
+			return
+		}
+
+		c.successes++
+		if c.successes >= c.settings.successThreshold {
+			c.setState(StateClosed)
+		}
+	}
+}
+
+func (c *circuit) open(now time.Time) {
+	c.openedAt = now
+	c.stats.Opened++
+	c.setState(StateOpen)
+}
+
+func (c *circuit) setState(state State) {
+	from := c.state
+
+	c.state = state
+	c.failures = 0
+	c.successes = 0
+
+	if c.onChange != nil {
+		c.onChange(c, from, state)
+	}
+}
+
+func (c *circuit) snapshot() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Scope = c.scope
+	stats.Class = c.class
+	stats.State = c.state
+
+	return stats
+}
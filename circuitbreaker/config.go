@@ -0,0 +1,45 @@
+package circuitbreaker
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. A connector's
+// circuit_breaker key takes the same keys.
+type Config struct {
+	Enabled          bool `mapstructure:"enabled" default:"true"`
+	FailureThreshold int  `mapstructure:"failure_threshold" default:"5"`
+	OpenTimeout      int  `mapstructure:"open_timeout" default:"30"`
+	HalfOpenProbes   int  `mapstructure:"half_open_probes" default:"1"`
+	SuccessThreshold int  `mapstructure:"success_threshold" default:"1"`
+}
+
+func (b *Breaker) initDefaultConfigs() {
+	moduleconfig.Register(b.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (b *Breaker) Config() Config {
+	return b.config
+}
+
+// connectorConfig is the module's config with the overrides under the
+// connector's circuit_breaker key applied.
+func (b *Breaker) connectorConfig(connectorScope string) Config {
+	cfg := b.config
+
+	if err := moduleconfig.Load(fmt.Sprintf("%s.circuit_breaker", connectorScope), &cfg); err != nil {
+		b.logger.Warn("Invalid circuit_breaker override, using the module's settings",
+			zap.String("connector", connectorScope),
+			zap.Error(err),
+		)
+
+		return b.config
+	}
+
+	return cfg
+}
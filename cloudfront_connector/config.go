@@ -0,0 +1,34 @@
+package cloudfront_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. CLOUDFRONT_DISTRIBUTION_ID for the
+// cloudfront scope.
+type Config struct {
+	DistributionID      string `mapstructure:"distribution_id" default:""`
+	Domain              string `mapstructure:"domain" default:""`
+	KeyPairID           string `mapstructure:"key_pair_id" default:""`
+	PrivateKey          string `mapstructure:"private_key" default:""`
+	PrivateKeyFile      string `mapstructure:"private_key_file" default:""`
+	URLExpiry           int    `mapstructure:"url_expiry" default:"3600"`
+	PollInterval        int    `mapstructure:"poll_interval" default:"20"`
+	InvalidationTimeout int    `mapstructure:"invalidation_timeout" default:"900"`
+	CloudFrontKey       string `mapstructure:"cloudfront_key" default:"ABCDE"`
+	CloudFrontSecret    string `mapstructure:"cloudfront_secret" default:"example_secret"`
+	CloudFrontToken     string `mapstructure:"cloudfront_token" default:""`
+	CloudFrontRegion    string `mapstructure:"cloudfront_region" default:"us-east-1"`
+	VerifyCredentials   bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight           bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *CloudFrontConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *CloudFrontConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,291 @@
+package cloudfront_connector
+
+import (
+	"context"
+	"os"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultDistributionID      = ""
+	DefaultDomain              = ""
+	DefaultKeyPairID           = ""
+	DefaultPrivateKey          = ""
+	DefaultPrivateKeyFile      = ""
+	DefaultURLExpiry           = 3600
+	DefaultPollInterval        = 20
+	DefaultInvalidationTimeout = 900
+	DefaultCloudFrontKey       = "ABCDE"
+	DefaultCloudFrontSecret    = "example_secret"
+	DefaultCloudFrontToken     = ""
+	DefaultCloudFrontRegion    = "us-east-1"
+)
+
+var preflightActions = []string{
+	"cloudfront:CreateInvalidation",
+	"cloudfront:GetInvalidation",
+	"cloudfront:GetDistribution",
+}
+
+type CloudFrontConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *cloudfront.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+	signer  *Signer
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *cloudfront.Client      `optional:"true"`
+}
+
+// Module provides the CloudFrontConnector of scope. It uses the
+// *cloudfront.Client in the graph, if any, instead of building one, and
+// adds the connector's middleware to it. The CDN it provides also serves
+// the bucket connector's cdn url_style, signed URLs and invalidations.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the CloudFrontConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *cloudfront.Client named after scope,
+// if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *CloudFrontConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*cloudfront.Client](scope, named),
+		instance.Provide[*CloudFrontConnector](scope, named, func(p Params) *CloudFrontConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &CloudFrontConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *CloudFrontConnector) CDN {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *CloudFrontConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting CloudFrontConnector",
+		zap.String("distribution_id", c.config.DistributionID),
+		zap.String("domain", c.config.Domain),
+		zap.String("cloudfront_region", c.config.CloudFrontRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	// Signing is optional, it needs a key pair of a trusted key group
+	if keyPairID := c.config.KeyPairID; keyPairID != "" {
+		signer, err := c.loadSigner(keyPairID)
+		if err != nil {
+			c.logger.Error("Load signing key error", zap.Error(err))
+			return err
+		}
+
+		c.signer = signer
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = cloudfront.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = cloudfront.New(c.client.Options(), func(o *cloudfront.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *CloudFrontConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped CloudFrontConnector")
+
+	return nil
+}
+
+func (c *CloudFrontConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.CloudFrontRegion,
+		Key:         c.config.CloudFrontKey,
+		Secret:      c.config.CloudFrontSecret,
+		Token:       c.config.CloudFrontToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *CloudFrontConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("cloudfront_region")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("cloudfront_key", DefaultCloudFrontKey)
+		v.NotPlaceholder("cloudfront_secret", DefaultCloudFrontSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the distribution, or lists one when none is configured.
+func (c *CloudFrontConnector) probe(ctx context.Context) error {
+	if id := c.config.DistributionID; id != "" {
+		_, err := c.client.GetDistribution(ctx, &cloudfront.GetDistributionInput{
+			Id: aws.String(id),
+		})
+
+		return err
+	}
+
+	_, err := c.client.ListDistributions(ctx, &cloudfront.ListDistributionsInput{
+		MaxItems: aws.Int32(1),
+	})
+
+	return err
+}
+
+func (c *CloudFrontConnector) loadSigner(keyPairID string) (*Signer, error) {
+	pemBytes := []byte(c.config.PrivateKey)
+
+	if path := c.config.PrivateKeyFile; path != "" {
+		var err error
+		if pemBytes, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewSigner(keyPairID, pemBytes)
+}
+
+func (c *CloudFrontConnector) GetClient() *cloudfront.Client {
+	return c.client
+}
@@ -0,0 +1,14 @@
+package cloudfront_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . CDN
+
+// CDN invalidates cached paths and builds (signed) URLs of the distribution.
+type CDN interface {
+	Invalidate(ctx context.Context, paths ...string) error
+	SignURL(path string) (string, error)
+	URL(path string) string
+}
+
+var _ CDN = (*CloudFrontConnector)(nil)
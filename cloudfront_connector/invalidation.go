@@ -0,0 +1,149 @@
+package cloudfront_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// maxInvalidationPaths is the number of paths one invalidation accepts.
+const maxInvalidationPaths = 3000
+
+var ErrDistributionNotFound = awserrors.New(awserrors.ErrNotFound, "distribution not found")
+
+// Invalidate removes paths from the edge caches of the configured
+// distribution and waits until CloudFront is done.
+func (c *CloudFrontConnector) Invalidate(ctx context.Context, paths ...string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CloudFrontConnector", "Invalidate")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	invalidationIDs, err := c.CreateInvalidation(ctx, paths...)
+	if err != nil {
+		return err
+	}
+
+	for _, invalidationID := range invalidationIDs {
+		if err := c.WaitForInvalidation(ctx, invalidationID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateInvalidation starts invalidations of paths, such as "/index.html"
+// or "/images/*", in batches of 3000 and returns their IDs. A missing
+// leading slash is added.
+func (c *CloudFrontConnector) CreateInvalidation(ctx context.Context, paths ...string) (_ []string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CloudFrontConnector", "CreateInvalidation")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	normalized := make([]string, len(paths))
+	for i, path := range paths {
+		normalized[i] = "/" + strings.TrimPrefix(path, "/")
+	}
+
+	invalidationIDs := []string{}
+	for offset := 0; offset < len(normalized); offset += maxInvalidationPaths {
+		batch := normalized[offset:min(offset+maxInvalidationPaths, len(normalized))]
+
+		result, err := c.client.CreateInvalidation(ctx, &cloudfront.CreateInvalidationInput{
+			DistributionId: aws.String(c.distributionID()),
+			InvalidationBatch: &types.InvalidationBatch{
+				CallerReference: aws.String(fmt.Sprintf("%s-%d-%d", c.scope, time.Now().UnixNano(), offset)),
+				Paths: &types.Paths{
+					Quantity: aws.Int32(int32(len(batch))),
+					Items:    batch,
+				},
+			},
+		})
+		if err != nil {
+			return nil, c.distributionError("Create invalidation error", err)
+		}
+
+		invalidationIDs = append(invalidationIDs, aws.ToString(result.Invalidation.Id))
+	}
+
+	return invalidationIDs, nil
+}
+
+// WaitForInvalidation polls an invalidation every poll_interval seconds
+// until it completed, for at most invalidation_timeout seconds.
+func (c *CloudFrontConnector) WaitForInvalidation(ctx context.Context, invalidationID string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CloudFrontConnector", "WaitForInvalidation")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Second
+	timeout := time.Duration(c.config.InvalidationTimeout) * time.Second
+
+	waiter := cloudfront.NewInvalidationCompletedWaiter(c.client, func(o *cloudfront.InvalidationCompletedWaiterOptions) {
+		o.MinDelay = interval
+		o.MaxDelay = max(interval, o.MaxDelay)
+	})
+
+	err = waiter.Wait(ctx, &cloudfront.GetInvalidationInput{
+		DistributionId: aws.String(c.distributionID()),
+		Id:             aws.String(invalidationID),
+	}, timeout)
+	if err != nil {
+		c.logger.Error("Wait for invalidation error", zap.String("invalidation_id", invalidationID), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetDistributionConfig returns the configuration of the configured
+// distribution with the ETag an update must send as IfMatch.
+func (c *CloudFrontConnector) GetDistributionConfig(ctx context.Context) (_ *types.DistributionConfig, _ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CloudFrontConnector", "GetDistributionConfig")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.GetDistributionConfig(ctx, &cloudfront.GetDistributionConfigInput{
+		Id: aws.String(c.distributionID()),
+	})
+	if err != nil {
+		return nil, "", c.distributionError("Get distribution config error", err)
+	}
+
+	return result.DistributionConfig, aws.ToString(result.ETag), nil
+}
+
+// GetDistribution returns the configured distribution with its status
+// and domain name.
+func (c *CloudFrontConnector) GetDistribution(ctx context.Context) (_ *types.Distribution, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CloudFrontConnector", "GetDistribution")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.GetDistribution(ctx, &cloudfront.GetDistributionInput{
+		Id: aws.String(c.distributionID()),
+	})
+	if err != nil {
+		return nil, c.distributionError("Get distribution error", err)
+	}
+
+	return result.Distribution, nil
+}
+
+func (c *CloudFrontConnector) distributionID() string {
+	return c.config.DistributionID
+}
+
+func (c *CloudFrontConnector) distributionError(msg string, err error) error {
+	var notFound *types.NoSuchDistribution
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("%w: %s", ErrDistributionNotFound, c.distributionID())
+	}
+
+	c.logger.Error(msg, zap.String("distribution_id", c.distributionID()), zap.Error(err))
+	return err
+}
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/cloudfront_connector (interfaces: CDN)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . CDN
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCDN is a mock of CDN interface.
+type MockCDN struct {
+	ctrl     *gomock.Controller
+	recorder *MockCDNMockRecorder
+}
+
+// MockCDNMockRecorder is the mock recorder for MockCDN.
+type MockCDNMockRecorder struct {
+	mock *MockCDN
+}
+
+// NewMockCDN creates a new mock instance.
+func NewMockCDN(ctrl *gomock.Controller) *MockCDN {
+	mock := &MockCDN{ctrl: ctrl}
+	mock.recorder = &MockCDNMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCDN) EXPECT() *MockCDNMockRecorder {
+	return m.recorder
+}
+
+// Invalidate mocks base method.
+func (m *MockCDN) Invalidate(arg0 context.Context, arg1 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Invalidate", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockCDNMockRecorder) Invalidate(arg0 any, arg1 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockCDN)(nil).Invalidate), varargs...)
+}
+
+// SignURL mocks base method.
+func (m *MockCDN) SignURL(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignURL", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignURL indicates an expected call of SignURL.
+func (mr *MockCDNMockRecorder) SignURL(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignURL", reflect.TypeOf((*MockCDN)(nil).SignURL), arg0)
+}
+
+// URL mocks base method.
+func (m *MockCDN) URL(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// URL indicates an expected call of URL.
+func (mr *MockCDNMockRecorder) URL(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockCDN)(nil).URL), arg0)
+}
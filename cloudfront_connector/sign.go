@@ -0,0 +1,265 @@
+package cloudfront_connector
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrNoSigner   = awserrors.New(awserrors.ErrValidation, "no signing key configured")
+	ErrInvalidKey = awserrors.New(awserrors.ErrValidation, "invalid private key")
+)
+
+// CloudFront's URL safe base64 replaces the characters query strings and
+// cookies do not allow.
+var signatureEncoding = strings.NewReplacer("+", "-", "=", "_", "/", "~")
+
+// Signer signs URLs and cookies for private content with the private key
+// of a CloudFront key pair. It holds no connection, so anything serving
+// content through the distribution can sign with it.
+type Signer struct {
+	keyPairID string
+	key       *rsa.PrivateKey
+}
+
+// Policy restricts access to Resource, a URL that may contain * wildcards,
+// until Expires and optionally from NotBefore and from the CIDR SourceIP.
+type Policy struct {
+	Resource  string
+	Expires   time.Time
+	NotBefore time.Time
+	SourceIP  string
+}
+
+type policyDocument struct {
+	Statement []policyStatement `json:"Statement"`
+}
+
+type policyStatement struct {
+	Resource  string          `json:"Resource"`
+	Condition policyCondition `json:"Condition"`
+}
+
+type policyCondition struct {
+	DateLessThan    epochTime  `json:"DateLessThan"`
+	DateGreaterThan *epochTime `json:"DateGreaterThan,omitempty"`
+	IPAddress       *sourceIP  `json:"IpAddress,omitempty"`
+}
+
+type epochTime struct {
+	EpochTime int64 `json:"AWS:EpochTime"`
+}
+
+type sourceIP struct {
+	SourceIP string `json:"AWS:SourceIp"`
+}
+
+// NewSigner parses a PEM encoded PKCS #1 or PKCS #8 RSA private key.
+func NewSigner(keyPairID string, pemBytes []byte) (*Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return &Signer{keyPairID: keyPairID, key: key}, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	return &Signer{keyPairID: keyPairID, key: key}, nil
+}
+
+// SignURL signs rawURL with a canned policy valid until expires.
+func (s *Signer) SignURL(rawURL string, expires time.Time) (string, error) {
+	policy, err := s.policy(Policy{Resource: rawURL, Expires: expires})
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := s.sign(policy)
+	if err != nil {
+		return "", err
+	}
+
+	return appendQuery(rawURL, url.Values{
+		"Expires":     {strconv.FormatInt(expires.Unix(), 10)},
+		"Signature":   {signature},
+		"Key-Pair-Id": {s.keyPairID},
+	}), nil
+}
+
+// SignURLWithPolicy signs rawURL with a custom policy. The policy's
+// Resource may cover more than rawURL, e.g. a wildcard for a directory.
+func (s *Signer) SignURLWithPolicy(rawURL string, policy Policy) (string, error) {
+	document, err := s.policy(policy)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := s.sign(document)
+	if err != nil {
+		return "", err
+	}
+
+	return appendQuery(rawURL, url.Values{
+		"Policy":      {encode(document)},
+		"Signature":   {signature},
+		"Key-Pair-Id": {s.keyPairID},
+	}), nil
+}
+
+// Cookies returns the signed cookies granting access under policy, for
+// many files such as the segments of a video stream. The caller sets
+// Domain and Path to match the distribution.
+func (s *Signer) Cookies(policy Policy) ([]*http.Cookie, error) {
+	document, err := s.policy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.sign(document)
+	if err != nil {
+		return nil, err
+	}
+
+	cookie := func(name string, value string) *http.Cookie {
+		return &http.Cookie{
+			Name:     name,
+			Value:    value,
+			Expires:  policy.Expires,
+			Secure:   true,
+			HttpOnly: true,
+		}
+	}
+
+	return []*http.Cookie{
+		cookie("CloudFront-Policy", encode(document)),
+		cookie("CloudFront-Signature", signature),
+		cookie("CloudFront-Key-Pair-Id", s.keyPairID),
+	}, nil
+}
+
+func (s *Signer) policy(policy Policy) ([]byte, error) {
+	statement := policyStatement{
+		Resource: policy.Resource,
+		Condition: policyCondition{
+			DateLessThan: epochTime{policy.Expires.Unix()},
+		},
+	}
+
+	if !policy.NotBefore.IsZero() {
+		statement.Condition.DateGreaterThan = &epochTime{policy.NotBefore.Unix()}
+	}
+
+	if policy.SourceIP != "" {
+		statement.Condition.IPAddress = &sourceIP{policy.SourceIP}
+	}
+
+	// CloudFront rebuilds canned policies from the URL, so & and friends
+	// must not be escaped
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(policyDocument{Statement: []policyStatement{statement}}); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (s *Signer) sign(policy []byte) (string, error) {
+	digest := sha1.Sum(policy)
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA1, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return encode(signature), nil
+}
+
+func encode(data []byte) string {
+	return signatureEncoding.Replace(base64.StdEncoding.EncodeToString(data))
+}
+
+func appendQuery(rawURL string, values url.Values) string {
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+
+	return rawURL + separator + values.Encode()
+}
+
+// Signer returns the signer of the configured key pair, or nil without
+// key_pair_id.
+func (c *CloudFrontConnector) Signer() *Signer {
+	return c.signer
+}
+
+// SignURL signs the URL of path on the configured domain for url_expiry
+// seconds.
+func (c *CloudFrontConnector) SignURL(path string) (string, error) {
+	if c.signer == nil {
+		return "", ErrNoSigner
+	}
+
+	return c.signer.SignURL(c.URL(path), time.Now().Add(c.urlExpiry()))
+}
+
+// SignedCookies returns cookies granting access to the paths matching
+// pattern, e.g. "/videos/123/*", on the configured domain for url_expiry
+// seconds.
+func (c *CloudFrontConnector) SignedCookies(pattern string) ([]*http.Cookie, error) {
+	if c.signer == nil {
+		return nil, ErrNoSigner
+	}
+
+	cookies, err := c.signer.Cookies(Policy{
+		Resource: c.URL(pattern),
+		Expires:  time.Now().Add(c.urlExpiry()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cookie := range cookies {
+		cookie.Domain = c.config.Domain
+		cookie.Path = "/"
+	}
+
+	return cookies, nil
+}
+
+// URL returns the https URL of path on the configured domain.
+func (c *CloudFrontConnector) URL(path string) string {
+	return "https://" + c.config.Domain + "/" + strings.TrimPrefix(path, "/")
+}
+
+func (c *CloudFrontConnector) urlExpiry() time.Duration {
+	return time.Duration(c.config.URLExpiry) * time.Second
+}
@@ -0,0 +1,34 @@
+package cloudwatch_metrics_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. CLOUDWATCH_METRICS_PUBLISHER for the
+// cloudwatch_metrics scope.
+type Config struct {
+	Publisher         string `mapstructure:"publisher" default:"api"`
+	Namespace         string `mapstructure:"namespace" default:""`
+	StorageResolution int32  `mapstructure:"storage_resolution" default:"60"`
+	BatchSize         int    `mapstructure:"batch_size" default:"20"`
+	BufferSize        int    `mapstructure:"buffer_size" default:"10000"`
+	FlushInterval     int    `mapstructure:"flush_interval" default:"60"`
+	MetricsKey        string `mapstructure:"metrics_key" default:"ABCDE"`
+	MetricsSecret     string `mapstructure:"metrics_secret" default:"example_secret"`
+	MetricsToken      string `mapstructure:"metrics_token" default:""`
+	MetricsRegion     string `mapstructure:"metrics_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+
+	Dimensions map[string]string `mapstructure:"dimensions"`
+}
+
+func (c *CloudWatchMetricsConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *CloudWatchMetricsConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,330 @@
+package cloudwatch_metrics_connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/batcher"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultPublisher         = PublisherAPI
+	DefaultNamespace         = ""
+	DefaultStorageResolution = 60
+	DefaultBatchSize         = 20
+	DefaultBufferSize        = 10000
+	DefaultFlushInterval     = 60
+	DefaultMetricsKey        = "ABCDE"
+	DefaultMetricsSecret     = "example_secret"
+	DefaultMetricsToken      = ""
+	DefaultMetricsRegion     = "us-west-1"
+)
+
+var preflightActions = []string{
+	"cloudwatch:PutMetricData",
+}
+
+type CloudWatchMetricsConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *cloudwatch.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	namespace  string
+	dimensions Dimensions
+	resolution int32
+	emf        *EMFEmitter
+	useEMF     bool
+
+	batchSize int
+	batcher   *batcher.Batcher[types.MetricDatum]
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *cloudwatch.Client      `optional:"true"`
+}
+
+// Module provides the CloudWatchMetricsConnector of scope. It uses the
+// *cloudwatch.Client in the graph, if any, instead of building one, and
+// adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the CloudWatchMetricsConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *cloudwatch.Client named after scope,
+// if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *CloudWatchMetricsConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*cloudwatch.Client](scope, named),
+		instance.Provide[*CloudWatchMetricsConnector](scope, named, func(p Params) (*CloudWatchMetricsConnector, error) {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &CloudWatchMetricsConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			// Loaded here, as the emitter and buffer are used before start
+			if err := moduleconfig.Load(scope, &c.config); err != nil {
+				return nil, err
+			}
+
+			c.namespace = c.config.Namespace
+			c.dimensions = Dimensions(c.config.Dimensions)
+			c.resolution = c.config.StorageResolution
+			c.emf = newEMFEmitter(p.Logger, c.namespace, c.dimensions, c.resolution)
+			c.batchSize = min(max(c.config.BatchSize, 1), maxBatchMetrics)
+			c.batcher = batcher.New(batcher.Options[types.MetricDatum]{
+				BufferSize: c.config.BufferSize,
+				BatchSize:  c.batchSize,
+				Interval:   time.Duration(c.config.FlushInterval) * time.Second,
+				Send:       c.publish,
+				OnError: func(err error) {
+					c.logger.Error("Flush metrics error", zap.Error(err))
+				},
+				OnDrop: func(count int) {
+					c.logger.Warn("Dropped metrics, buffer full", zap.Int("count", count))
+				},
+			})
+
+			return c, nil
+		}),
+		instance.Export(scope, named, func(c *CloudWatchMetricsConnector) *EMFEmitter {
+			return c.emf
+		}),
+		instance.Export(scope, named, func(c *CloudWatchMetricsConnector) Metrics {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *CloudWatchMetricsConnector) onStart(ctx context.Context) error {
+
+	publisher := c.config.Publisher
+
+	c.logger.Info("Starting CloudWatchMetricsConnector",
+		zap.String("publisher", publisher),
+		zap.String("namespace", c.namespace),
+		zap.Any("dimensions", c.dimensions),
+		zap.String("metrics_region", c.config.MetricsRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	switch publisher {
+	case PublisherAPI:
+	case PublisherEMF:
+		// Metrics go to the log, there is no client to set up
+		c.useEMF = true
+		return nil
+	default:
+		return fmt.Errorf("%s: unknown publisher %q", c.scope, publisher)
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = cloudwatch.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = cloudwatch.New(c.client.Options(), func(o *cloudwatch.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	c.batcher.Start(inflight.Internal(context.Background()))
+
+	return nil
+}
+
+func (c *CloudWatchMetricsConnector) onStop(ctx context.Context) error {
+
+	c.batcher.Stop()
+
+	c.tracker.Shutdown(ctx)
+
+	// Publish whatever is left before the process exits
+	if err := c.Flush(inflight.Internal(ctx)); err != nil {
+		c.logger.Warn("Flush metrics on shutdown error", zap.Error(err))
+	}
+
+	c.logger.Info("Stopped CloudWatchMetricsConnector")
+
+	return nil
+}
+
+func (c *CloudWatchMetricsConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.MetricsRegion,
+		Key:         c.config.MetricsKey,
+		Secret:      c.config.MetricsSecret,
+		Token:       c.config.MetricsToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *CloudWatchMetricsConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("metrics_region")
+
+	v.Required("namespace")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("metrics_key", DefaultMetricsKey)
+		v.NotPlaceholder("metrics_secret", DefaultMetricsSecret)
+	}
+
+	return v.Err()
+}
+
+// probe lists an alarm. Nothing is called with the EMF publisher.
+func (c *CloudWatchMetricsConnector) probe(ctx context.Context) error {
+	if c.useEMF {
+		return nil
+	}
+
+	_, err := c.client.DescribeAlarms(ctx, &cloudwatch.DescribeAlarmsInput{
+		MaxRecords: aws.Int32(1),
+	})
+
+	return err
+}
+
+// Namespace returns the configured metric namespace.
+func (c *CloudWatchMetricsConnector) Namespace() string {
+	return c.namespace
+}
+
+// EMF returns the emitter writing metrics to the log in Embedded Metric
+// Format, available whichever publisher is configured.
+func (c *CloudWatchMetricsConnector) EMF() *EMFEmitter {
+	return c.emf
+}
+
+// Dimensions returns the dimensions added to every metric.
+func (c *CloudWatchMetricsConnector) Dimensions() Dimensions {
+	return c.dimensions
+}
+
+func (c *CloudWatchMetricsConnector) GetClient() *cloudwatch.Client {
+	return c.client
+}
@@ -0,0 +1,131 @@
+package cloudwatch_metrics_connector
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+const (
+	PublisherAPI = "api"
+	PublisherEMF = "emf"
+)
+
+// Metric is one value of an EMF line.
+type Metric struct {
+	Name  string
+	Value float64
+	Unit  Unit
+}
+
+// EMFEmitter writes metrics as CloudWatch Embedded Metric Format lines
+// through a zap logger, so CloudWatch Logs extracts them without any
+// PutMetricData calls. The logger must use the JSON encoder.
+type EMFEmitter struct {
+	logger     *zap.Logger
+	namespace  string
+	dimensions Dimensions
+	resolution int32
+}
+
+func newEMFEmitter(l *zap.Logger, namespace string, dimensions Dimensions, resolution int32) *EMFEmitter {
+	return &EMFEmitter{
+		logger:     l,
+		namespace:  namespace,
+		dimensions: dimensions,
+		resolution: resolution,
+	}
+}
+
+// Count emits value occurrences of name.
+func (e *EMFEmitter) Count(name string, value float64, dims Dimensions) {
+	e.Emit(dims, Metric{Name: name, Value: value, Unit: types.StandardUnitCount})
+}
+
+// Gauge emits the current value of name.
+func (e *EMFEmitter) Gauge(name string, value float64, dims Dimensions) {
+	e.Emit(dims, Metric{Name: name, Value: value, Unit: types.StandardUnitNone})
+}
+
+// Timing emits d in milliseconds.
+func (e *EMFEmitter) Timing(name string, d time.Duration, dims Dimensions) {
+	e.Emit(dims, Metric{Name: name, Value: float64(d) / float64(time.Millisecond), Unit: types.StandardUnitMilliseconds})
+}
+
+// Emit writes metrics sharing one dimension set as a single line. The
+// configured dimensions are added, with dims taking precedence.
+func (e *EMFEmitter) Emit(dims Dimensions, metrics ...Metric) {
+	if len(metrics) == 0 {
+		return
+	}
+
+	merged, names := merge(e.dimensions, dims)
+
+	fields := make([]zap.Field, 0, 1+len(names)+len(metrics))
+	fields = append(fields, zap.Object("_aws", emfMetadata{
+		timestamp:  time.Now().UnixMilli(),
+		namespace:  e.namespace,
+		dimensions: names,
+		metrics:    metrics,
+		resolution: e.resolution,
+	}))
+	for _, name := range names {
+		fields = append(fields, zap.String(name, merged[name]))
+	}
+	for _, m := range metrics {
+		fields = append(fields, zap.Float64(m.Name, m.Value))
+	}
+
+	e.logger.Info("metrics", fields...)
+}
+
+// emfMetadata renders the "_aws" member of an EMF line.
+type emfMetadata struct {
+	timestamp  int64
+	namespace  string
+	dimensions []string
+	metrics    []Metric
+	resolution int32
+}
+
+func (m emfMetadata) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("Timestamp", m.timestamp)
+
+	return enc.AddArray("CloudWatchMetrics", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		return arr.AppendObject(zapcore.ObjectMarshalerFunc(func(directive zapcore.ObjectEncoder) error {
+			directive.AddString("Namespace", m.namespace)
+
+			if err := directive.AddArray("Dimensions", zapcore.ArrayMarshalerFunc(func(sets zapcore.ArrayEncoder) error {
+				return sets.AppendArray(zapcore.ArrayMarshalerFunc(func(set zapcore.ArrayEncoder) error {
+					for _, name := range m.dimensions {
+						set.AppendString(name)
+					}
+					return nil
+				}))
+			})); err != nil {
+				return err
+			}
+
+			return directive.AddArray("Metrics", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+				for _, metric := range m.metrics {
+					if err := arr.AppendObject(zapcore.ObjectMarshalerFunc(func(def zapcore.ObjectEncoder) error {
+						def.AddString("Name", metric.Name)
+						if metric.Unit != "" {
+							def.AddString("Unit", string(metric.Unit))
+						}
+						if m.resolution == 1 {
+							def.AddInt32("StorageResolution", m.resolution)
+						}
+						return nil
+					})); err != nil {
+						return err
+					}
+				}
+				return nil
+			}))
+		}))
+	}))
+}
@@ -0,0 +1,19 @@
+package cloudwatch_metrics_connector
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Metrics
+
+// Metrics records metric values.
+type Metrics interface {
+	Count(name string, value float64, dims Dimensions)
+	Gauge(name string, value float64, dims Dimensions)
+	Timing(name string, d time.Duration, dims Dimensions)
+	Put(name string, value float64, unit Unit, dims Dimensions)
+	Flush(ctx context.Context) error
+}
+
+var _ Metrics = (*CloudWatchMetricsConnector)(nil)
@@ -0,0 +1,105 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/cloudwatch_metrics_connector (interfaces: Metrics)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . Metrics
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	types "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	cloudwatch_metrics_connector "github.com/elmntri/zeitgeber-aws-modules/cloudwatch_metrics_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMetrics is a mock of Metrics interface.
+type MockMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricsMockRecorder
+}
+
+// MockMetricsMockRecorder is the mock recorder for MockMetrics.
+type MockMetricsMockRecorder struct {
+	mock *MockMetrics
+}
+
+// NewMockMetrics creates a new mock instance.
+func NewMockMetrics(ctrl *gomock.Controller) *MockMetrics {
+	mock := &MockMetrics{ctrl: ctrl}
+	mock.recorder = &MockMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetrics) EXPECT() *MockMetricsMockRecorder {
+	return m.recorder
+}
+
+// Count mocks base method.
+func (m *MockMetrics) Count(arg0 string, arg1 float64, arg2 cloudwatch_metrics_connector.Dimensions) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Count", arg0, arg1, arg2)
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockMetricsMockRecorder) Count(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockMetrics)(nil).Count), arg0, arg1, arg2)
+}
+
+// Flush mocks base method.
+func (m *MockMetrics) Flush(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockMetricsMockRecorder) Flush(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockMetrics)(nil).Flush), arg0)
+}
+
+// Gauge mocks base method.
+func (m *MockMetrics) Gauge(arg0 string, arg1 float64, arg2 cloudwatch_metrics_connector.Dimensions) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Gauge", arg0, arg1, arg2)
+}
+
+// Gauge indicates an expected call of Gauge.
+func (mr *MockMetricsMockRecorder) Gauge(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Gauge", reflect.TypeOf((*MockMetrics)(nil).Gauge), arg0, arg1, arg2)
+}
+
+// Put mocks base method.
+func (m *MockMetrics) Put(arg0 string, arg1 float64, arg2 types.StandardUnit, arg3 cloudwatch_metrics_connector.Dimensions) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Put", arg0, arg1, arg2, arg3)
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockMetricsMockRecorder) Put(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockMetrics)(nil).Put), arg0, arg1, arg2, arg3)
+}
+
+// Timing mocks base method.
+func (m *MockMetrics) Timing(arg0 string, arg1 time.Duration, arg2 cloudwatch_metrics_connector.Dimensions) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Timing", arg0, arg1, arg2)
+}
+
+// Timing indicates an expected call of Timing.
+func (mr *MockMetricsMockRecorder) Timing(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timing", reflect.TypeOf((*MockMetrics)(nil).Timing), arg0, arg1, arg2)
+}
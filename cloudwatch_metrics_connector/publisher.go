@@ -0,0 +1,131 @@
+package cloudwatch_metrics_connector
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// PutMetricData limits
+const (
+	maxBatchMetrics = 1000
+	maxDimensions   = 30
+)
+
+// Dimensions maps dimension names to values.
+type Dimensions map[string]string
+
+type Unit = types.StandardUnit
+
+// Count records value occurrences of name.
+func (c *CloudWatchMetricsConnector) Count(name string, value float64, dims Dimensions) {
+	c.Put(name, value, types.StandardUnitCount, dims)
+}
+
+// Gauge records the current value of name.
+func (c *CloudWatchMetricsConnector) Gauge(name string, value float64, dims Dimensions) {
+	c.Put(name, value, types.StandardUnitNone, dims)
+}
+
+// Timing records d in milliseconds.
+func (c *CloudWatchMetricsConnector) Timing(name string, d time.Duration, dims Dimensions) {
+	c.Put(name, float64(d)/float64(time.Millisecond), types.StandardUnitMilliseconds, dims)
+}
+
+// Put buffers one datum. The configured dimensions are added, with dims
+// taking precedence. A full batch triggers an early flush; when the
+// buffer is full the datum is dropped rather than blocking the caller.
+// With the emf publisher the datum is written to the log instead.
+func (c *CloudWatchMetricsConnector) Put(name string, value float64, unit Unit, dims Dimensions) {
+	if c.useEMF {
+		c.emf.Emit(dims, Metric{Name: name, Value: value, Unit: unit})
+		return
+	}
+
+	datum := types.MetricDatum{
+		MetricName:        aws.String(name),
+		Value:             aws.Float64(value),
+		Unit:              unit,
+		Timestamp:         aws.Time(time.Now()),
+		Dimensions:        c.mergeDimensions(dims),
+		StorageResolution: aws.Int32(c.resolution),
+	}
+
+	// A full buffer drops the datum, counted in the next flush
+	_ = c.batcher.Add(context.Background(), datum)
+}
+
+// mergeDimensions returns the configured dimensions overridden by dims.
+func (c *CloudWatchMetricsConnector) mergeDimensions(dims Dimensions) []types.Dimension {
+	merged, names := merge(c.dimensions, dims)
+
+	result := make([]types.Dimension, 0, len(names))
+	for _, name := range names {
+		result = append(result, types.Dimension{Name: aws.String(name), Value: aws.String(merged[name])})
+	}
+
+	return result
+}
+
+// merge overlays dims on base and returns the result with its names
+// sorted, so identical sets are the same metric, and capped at the
+// CloudWatch dimension limit.
+func merge(base Dimensions, dims Dimensions) (Dimensions, []string) {
+	merged := make(Dimensions, len(base)+len(dims))
+	for name, value := range base {
+		merged[name] = value
+	}
+	for name, value := range dims {
+		merged[name] = value
+	}
+
+	names := make([]string, 0, len(merged))
+	for name := range merged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) > maxDimensions {
+		names = names[:maxDimensions]
+	}
+
+	return merged, names
+}
+
+// Flush publishes all buffered metrics in batches of batch_size. Metrics
+// of a failed batch are dropped.
+func (c *CloudWatchMetricsConnector) Flush(ctx context.Context) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CloudWatchMetricsConnector", "Flush")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.batcher.Flush(ctx)
+}
+
+// publish sends data in PutMetricData batches.
+func (c *CloudWatchMetricsConnector) publish(ctx context.Context, data []types.MetricDatum) error {
+	if c.client == nil {
+		return nil
+	}
+
+	var errs []error
+	for len(data) > 0 {
+		n := min(len(data), c.batchSize)
+
+		_, err := c.client.PutMetricData(ctx, &cloudwatch.PutMetricDataInput{
+			Namespace:  aws.String(c.namespace),
+			MetricData: data[:n],
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		data = data[n:]
+	}
+
+	return errors.Join(errs...)
+}
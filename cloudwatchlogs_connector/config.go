@@ -0,0 +1,33 @@
+package cloudwatchlogs_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. CLOUDWATCHLOGS_LOG_GROUP for the
+// cloudwatchlogs scope.
+type Config struct {
+	LogGroup          string `mapstructure:"log_group" default:""`
+	LogStream         string `mapstructure:"log_stream" default:""`
+	CreateLogGroup    bool   `mapstructure:"create_log_group" default:"true"`
+	RetentionDays     int32  `mapstructure:"retention_days" default:"0"`
+	BatchSize         int    `mapstructure:"batch_size" default:"1000"`
+	BufferSize        int    `mapstructure:"buffer_size" default:"10000"`
+	FlushInterval     int    `mapstructure:"flush_interval" default:"5"`
+	LogsKey           string `mapstructure:"logs_key" default:"ABCDE"`
+	LogsSecret        string `mapstructure:"logs_secret" default:"example_secret"`
+	LogsToken         string `mapstructure:"logs_token" default:""`
+	LogsRegion        string `mapstructure:"logs_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *CloudWatchLogsConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *CloudWatchLogsConnector) Config() Config {
+	return c.config
+}
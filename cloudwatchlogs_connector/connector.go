@@ -0,0 +1,349 @@
+package cloudwatchlogs_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/batcher"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultLogGroup       = ""
+	DefaultLogStream      = ""
+	DefaultCreateLogGroup = true
+	DefaultRetentionDays  = 0
+	DefaultBatchSize      = 1000
+	DefaultBufferSize     = 10000
+	DefaultFlushInterval  = 5
+	DefaultLogsKey        = "ABCDE"
+	DefaultLogsSecret     = "example_secret"
+	DefaultLogsToken      = ""
+	DefaultLogsRegion     = "us-west-1"
+)
+
+var preflightActions = []string{
+	"logs:CreateLogStream",
+	"logs:PutLogEvents",
+}
+
+type CloudWatchLogsConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *cloudwatchlogs.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	group  string
+	stream string
+
+	batchSize     int
+	batcher       *batcher.Batcher[types.InputLogEvent]
+	sequenceToken *string
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *cloudwatchlogs.Client  `optional:"true"`
+}
+
+// Module provides the CloudWatchLogsConnector of scope. It uses the
+// *cloudwatchlogs.Client in the graph, if any, instead of building one,
+// and adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the CloudWatchLogsConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *cloudwatchlogs.Client named after
+// scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *CloudWatchLogsConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*cloudwatchlogs.Client](scope, named),
+		instance.Provide[*CloudWatchLogsConnector](scope, named, func(p Params) (*CloudWatchLogsConnector, error) {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &CloudWatchLogsConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			// Loaded here, as the buffer is used before start
+			if err := moduleconfig.Load(scope, &c.config); err != nil {
+				return nil, err
+			}
+
+			// A batch of no events would never empty the buffer
+			c.batchSize = min(c.config.BatchSize, maxBatchEvents)
+			if c.batchSize <= 0 {
+				c.batchSize = DefaultBatchSize
+			}
+
+			c.batcher = batcher.New(batcher.Options[types.InputLogEvent]{
+				BufferSize: c.config.BufferSize,
+				BatchSize:  c.batchSize,
+				Interval:   time.Duration(c.config.FlushInterval) * time.Second,
+				Send:       c.send,
+				// The sink may be part of our own logger, so report to stderr
+				OnError: func(err error) {
+					fmt.Fprintf(os.Stderr, "%s: flush logs error: %v\n", scope, err)
+				},
+				OnDrop: func(count int) {
+					fmt.Fprintf(os.Stderr, "%s: dropped %d log entries, buffer full\n", scope, count)
+				},
+			})
+
+			return c, nil
+		}),
+		instance.Export(scope, named, func(c *CloudWatchLogsConnector) LogSink {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *CloudWatchLogsConnector) onStart(ctx context.Context) error {
+	c.group = c.config.LogGroup
+	c.stream = c.config.LogStream
+	if c.stream == "" {
+		hostname, _ := os.Hostname()
+		c.stream = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	c.logger.Info("Starting CloudWatchLogsConnector",
+		zap.String("log_group", c.group),
+		zap.String("log_stream", c.stream),
+		zap.String("logs_region", c.config.LogsRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions,
+			"arn:{partition}:logs:{region}:{account}:log-group:"+c.config.LogGroup+":*",
+		)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = cloudwatchlogs.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = cloudwatchlogs.New(c.client.Options(), func(o *cloudwatchlogs.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	if err := c.ensureLogStream(ctx); err != nil {
+		return err
+	}
+
+	c.batcher.Start(inflight.Internal(context.Background()))
+
+	return nil
+}
+
+func (c *CloudWatchLogsConnector) onStop(ctx context.Context) error {
+
+	c.batcher.Stop()
+
+	c.tracker.Shutdown(ctx)
+
+	// Ship whatever is left before the process exits
+	if err := c.Flush(inflight.Internal(ctx)); err != nil {
+		c.logger.Warn("Flush logs on shutdown error", zap.Error(err))
+	}
+
+	c.logger.Info("Stopped CloudWatchLogsConnector")
+
+	return nil
+}
+
+func (c *CloudWatchLogsConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.LogsRegion,
+		Key:         c.config.LogsKey,
+		Secret:      c.config.LogsSecret,
+		Token:       c.config.LogsToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *CloudWatchLogsConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("logs_region")
+
+	v.Required("log_group")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("logs_key", DefaultLogsKey)
+		v.NotPlaceholder("logs_secret", DefaultLogsSecret)
+	}
+
+	return v.Err()
+}
+
+// probe looks up the log group.
+func (c *CloudWatchLogsConnector) probe(ctx context.Context) error {
+	_, err := c.client.DescribeLogGroups(ctx, &cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(c.config.LogGroup),
+		Limit:              aws.Int32(1),
+	})
+
+	return err
+}
+
+// ensureLogStream creates the log group (when create_log_group is set)
+// and the stream, tolerating both already existing.
+func (c *CloudWatchLogsConnector) ensureLogStream(ctx context.Context) error {
+	var exists *types.ResourceAlreadyExistsException
+
+	if c.config.CreateLogGroup {
+		_, err := c.client.CreateLogGroup(ctx, &cloudwatchlogs.CreateLogGroupInput{
+			LogGroupName: aws.String(c.group),
+		})
+		if err != nil && !errors.As(err, &exists) {
+			c.logger.Error("Create log group error", zap.String("log_group", c.group), zap.Error(err))
+			return err
+		}
+
+		if days := c.config.RetentionDays; days > 0 && err == nil {
+			_, err := c.client.PutRetentionPolicy(ctx, &cloudwatchlogs.PutRetentionPolicyInput{
+				LogGroupName:    aws.String(c.group),
+				RetentionInDays: aws.Int32(days),
+			})
+			if err != nil {
+				c.logger.Error("Put retention policy error", zap.String("log_group", c.group), zap.Error(err))
+				return err
+			}
+		}
+	}
+
+	_, err := c.client.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(c.group),
+		LogStreamName: aws.String(c.stream),
+	})
+	if err != nil && !errors.As(err, &exists) {
+		c.logger.Error("Create log stream error", zap.String("log_stream", c.stream), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *CloudWatchLogsConnector) GetClient() *cloudwatchlogs.Client {
+	return c.client
+}
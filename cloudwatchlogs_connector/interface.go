@@ -0,0 +1,14 @@
+package cloudwatchlogs_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . LogSink
+
+// LogSink is where log lines are written.
+type LogSink interface {
+	Write(p []byte) (int, error)
+	Sync() error
+	Flush(ctx context.Context) error
+}
+
+var _ LogSink = (*CloudWatchLogsConnector)(nil)
@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/cloudwatchlogs_connector (interfaces: LogSink)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . LogSink
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLogSink is a mock of LogSink interface.
+type MockLogSink struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogSinkMockRecorder
+}
+
+// MockLogSinkMockRecorder is the mock recorder for MockLogSink.
+type MockLogSinkMockRecorder struct {
+	mock *MockLogSink
+}
+
+// NewMockLogSink creates a new mock instance.
+func NewMockLogSink(ctrl *gomock.Controller) *MockLogSink {
+	mock := &MockLogSink{ctrl: ctrl}
+	mock.recorder = &MockLogSinkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogSink) EXPECT() *MockLogSinkMockRecorder {
+	return m.recorder
+}
+
+// Flush mocks base method.
+func (m *MockLogSink) Flush(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockLogSinkMockRecorder) Flush(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockLogSink)(nil).Flush), arg0)
+}
+
+// Sync mocks base method.
+func (m *MockLogSink) Sync() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockLogSinkMockRecorder) Sync() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockLogSink)(nil).Sync))
+}
+
+// Write mocks base method.
+func (m *MockLogSink) Write(arg0 []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockLogSinkMockRecorder) Write(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockLogSink)(nil).Write), arg0)
+}
@@ -0,0 +1,54 @@
+package cloudwatchlogs_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrLogStreamNotFound = awserrors.New(awserrors.ErrNotFound, "log stream not found")
+
+// GetLogEvents reads a log stream of any group from the start, e.g. the
+// output of a finished task.
+func (c *CloudWatchLogsConnector) GetLogEvents(ctx context.Context, group string, stream string) (_ []types.OutputLogEvent, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CloudWatchLogsConnector", "GetLogEvents")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	paginator := cloudwatchlogs.NewGetLogEventsPaginator(c.client, &cloudwatchlogs.GetLogEventsInput{
+		LogGroupName:  aws.String(group),
+		LogStreamName: aws.String(stream),
+		StartFromHead: aws.Bool(true),
+	}, func(o *cloudwatchlogs.GetLogEventsPaginatorOptions) {
+		// The forward token repeats at the end of the stream
+		o.StopOnDuplicateToken = true
+	})
+
+	events := []types.OutputLogEvent{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			var notFound *types.ResourceNotFoundException
+			if errors.As(err, &notFound) {
+				return nil, fmt.Errorf("%w: %s/%s", ErrLogStreamNotFound, group, stream)
+			}
+
+			c.logger.Error("Get log events error", zap.String("log_group", group), zap.String("log_stream", stream), zap.Error(err))
+			return nil, err
+		}
+
+		if len(page.Events) == 0 {
+			break
+		}
+
+		events = append(events, page.Events...)
+	}
+
+	return events, nil
+}
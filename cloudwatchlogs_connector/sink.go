@@ -0,0 +1,141 @@
+package cloudwatchlogs_connector
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// PutLogEvents limits
+const (
+	maxBatchEvents   = 10000
+	maxBatchBytes    = 1048576
+	maxEventBytes    = 262144
+	eventOverhead    = 26
+	maxBatchTimespan = 24 * time.Hour
+)
+
+// Write implements zapcore.WriteSyncer. Each call is one encoded entry
+// and becomes one log event; entries beyond buffer_size are dropped
+// rather than blocking the caller.
+func (c *CloudWatchLogsConnector) Write(p []byte) (int, error) {
+	message := truncate(string(p), maxEventBytes-eventOverhead)
+
+	// A full buffer drops the entry, counted in the next flush
+	_ = c.batcher.Add(context.Background(), types.InputLogEvent{
+		Message:   aws.String(message),
+		Timestamp: aws.Int64(time.Now().UnixMilli()),
+	})
+
+	return len(p), nil
+}
+
+// truncate cuts message to at most n bytes, on a rune boundary so the
+// event stays valid UTF-8.
+func truncate(message string, n int) string {
+	if len(message) <= n {
+		return message
+	}
+
+	for n > 0 && !utf8.RuneStart(message[n]) {
+		n--
+	}
+
+	return message[:n]
+}
+
+// Sync flushes buffered entries.
+func (c *CloudWatchLogsConnector) Sync() error {
+	return c.Flush(context.Background())
+}
+
+// Core returns a zap core writing JSON entries at level and above to
+// CloudWatch Logs.
+func (c *CloudWatchLogsConnector) Core(level zapcore.LevelEnabler) zapcore.Core {
+	return zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), c, level)
+}
+
+// WrapLogger returns l teed to CloudWatch Logs.
+func (c *CloudWatchLogsConnector) WrapLogger(l *zap.Logger, level zapcore.LevelEnabler) *zap.Logger {
+	return l.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, c.Core(level))
+	}))
+}
+
+// Flush sends all buffered entries in PutLogEvents batches. Entries of a
+// failed batch are dropped so a bad batch can't wedge the sink.
+func (c *CloudWatchLogsConnector) Flush(ctx context.Context) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CloudWatchLogsConnector", "Flush")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.batcher.Flush(ctx)
+}
+
+// send sorts events by time and splits them into PutLogEvents calls.
+func (c *CloudWatchLogsConnector) send(ctx context.Context, events []types.InputLogEvent) error {
+	if c.client == nil {
+		return nil
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return *events[i].Timestamp < *events[j].Timestamp
+	})
+
+	var errs []error
+	for len(events) > 0 {
+		n, bytes := 0, 0
+		first := *events[0].Timestamp
+		for n < len(events) && n < c.batchSize {
+			size := len(*events[n].Message) + eventOverhead
+			if bytes+size > maxBatchBytes || *events[n].Timestamp-first >= maxBatchTimespan.Milliseconds() {
+				break
+			}
+
+			bytes += size
+			n++
+		}
+
+		if err := c.putLogEvents(ctx, events[:n]); err != nil {
+			errs = append(errs, err)
+		}
+
+		events = events[n:]
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *CloudWatchLogsConnector) putLogEvents(ctx context.Context, events []types.InputLogEvent) error {
+	for attempt := 0; ; attempt++ {
+		result, err := c.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(c.group),
+			LogStreamName: aws.String(c.stream),
+			LogEvents:     events,
+			SequenceToken: c.sequenceToken,
+		})
+		if err != nil {
+			// Sequence tokens are ignored by current CloudWatch Logs, but
+			// older endpoints still reject stale ones
+			var invalid *types.InvalidSequenceTokenException
+			if errors.As(err, &invalid) && attempt == 0 {
+				c.sequenceToken = invalid.ExpectedSequenceToken
+				continue
+			}
+
+			return err
+		}
+
+		c.sequenceToken = result.NextSequenceToken
+
+		return nil
+	}
+}
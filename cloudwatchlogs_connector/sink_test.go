@@ -0,0 +1,83 @@
+package cloudwatchlogs_connector_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/elmntri/zeitgeber-aws-modules/cloudwatchlogs_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/testsupport"
+)
+
+// service answers every CloudWatch Logs call with an empty result and
+// counts the PutLogEvents calls.
+type service struct {
+	puts atomic.Int32
+}
+
+func (s *service) Do(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.Header.Get("X-Amz-Target"), ".PutLogEvents") {
+		s.puts.Add(1)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.1"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func TestZeroBatchSize(t *testing.T) {
+	testsupport.Set(t, "logs.log_group", "app")
+	testsupport.Set(t, "logs.logs_key", "AKIDEXAMPLE")
+	testsupport.Set(t, "logs.logs_secret", "secret")
+	testsupport.Set(t, "logs.batch_size", 0)
+
+	svc := &service{}
+	client := cloudwatchlogs.New(cloudwatchlogs.Options{
+		Region:      "us-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  svc,
+	})
+
+	var logs *cloudwatchlogs_connector.CloudWatchLogsConnector
+	testsupport.NewApp(t,
+		fx.Supply(client),
+		cloudwatchlogs_connector.Module("logs"),
+		fx.Populate(&logs),
+	)
+
+	for range 3 {
+		if _, err := logs.Write([]byte("entry")); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- logs.Flush(ctx) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("flush: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("flush did not return")
+	}
+
+	if svc.puts.Load() == 0 {
+		t.Error("no events sent")
+	}
+}
@@ -0,0 +1,389 @@
+package cognito_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrUserNotFound  = awserrors.New(awserrors.ErrNotFound, "user not found")
+	ErrUserExists    = awserrors.New(awserrors.ErrConflict, "user already exists")
+	ErrGroupNotFound = awserrors.New(awserrors.ErrNotFound, "group not found")
+	ErrGroupExists   = awserrors.New(awserrors.ErrConflict, "group already exists")
+)
+
+// User is a user of the pool with its attributes keyed by name, e.g.
+// "email" or "custom:tenant".
+type User struct {
+	Username   string
+	Status     string
+	Enabled    bool
+	Attributes map[string]string
+	CreatedAt  time.Time
+	ModifiedAt time.Time
+}
+
+// NewUser describes a user for AdminCreateUser. Without a
+// TemporaryPassword Cognito generates one; SuppressInvitation skips the
+// invitation message.
+type NewUser struct {
+	Username           string
+	Attributes         map[string]string
+	TemporaryPassword  string
+	SuppressInvitation bool
+	DeliveryMediums    []types.DeliveryMediumType
+}
+
+// AdminCreateUser creates a user in the configured user pool and returns
+// ErrUserExists when the username is taken.
+func (c *CognitoConnector) AdminCreateUser(ctx context.Context, u NewUser) (_ *User, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminCreateUser")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	params := &cognitoidentityprovider.AdminCreateUserInput{
+		UserPoolId:             aws.String(c.userPoolID()),
+		Username:               aws.String(u.Username),
+		UserAttributes:         toAttributes(u.Attributes),
+		DesiredDeliveryMediums: u.DeliveryMediums,
+	}
+	if u.TemporaryPassword != "" {
+		params.TemporaryPassword = aws.String(u.TemporaryPassword)
+	}
+	if u.SuppressInvitation {
+		params.MessageAction = types.MessageActionTypeSuppress
+	}
+
+	result, err := c.client.AdminCreateUser(ctx, params)
+	if err != nil {
+		var exists *types.UsernameExistsException
+		if errors.As(err, &exists) {
+			return nil, fmt.Errorf("%w: %w", ErrUserExists, err)
+		}
+
+		c.logger.Error("Admin create user error", zap.String("username", u.Username), zap.Error(err))
+		return nil, err
+	}
+
+	return fromUserType(result.User), nil
+}
+
+// AdminGetUser returns the user or ErrUserNotFound.
+func (c *CognitoConnector) AdminGetUser(ctx context.Context, username string) (_ *User, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminGetUser")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.AdminGetUser(ctx, &cognitoidentityprovider.AdminGetUserInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		Username:   aws.String(username),
+	})
+	if err != nil {
+		return nil, c.userError("Admin get user error", username, err)
+	}
+
+	return &User{
+		Username:   aws.ToString(result.Username),
+		Status:     string(result.UserStatus),
+		Enabled:    result.Enabled,
+		Attributes: fromAttributes(result.UserAttributes),
+		CreatedAt:  aws.ToTime(result.UserCreateDate),
+		ModifiedAt: aws.ToTime(result.UserLastModifiedDate),
+	}, nil
+}
+
+func (c *CognitoConnector) AdminDeleteUser(ctx context.Context, username string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminDeleteUser")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.AdminDeleteUser(ctx, &cognitoidentityprovider.AdminDeleteUserInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		Username:   aws.String(username),
+	})
+	if err != nil {
+		return c.userError("Admin delete user error", username, err)
+	}
+
+	return nil
+}
+
+// AdminSetUserPassword sets the password of a user. A permanent password
+// confirms the user; otherwise it must be changed at the next sign-in.
+func (c *CognitoConnector) AdminSetUserPassword(ctx context.Context, username string, password string, permanent bool) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminSetUserPassword")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.AdminSetUserPassword(ctx, &cognitoidentityprovider.AdminSetUserPasswordInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		Username:   aws.String(username),
+		Password:   aws.String(password),
+		Permanent:  permanent,
+	})
+	if err != nil {
+		return c.userError("Admin set user password error", username, err)
+	}
+
+	return nil
+}
+
+func (c *CognitoConnector) AdminEnableUser(ctx context.Context, username string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminEnableUser")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.AdminEnableUser(ctx, &cognitoidentityprovider.AdminEnableUserInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		Username:   aws.String(username),
+	})
+	if err != nil {
+		return c.userError("Admin enable user error", username, err)
+	}
+
+	return nil
+}
+
+func (c *CognitoConnector) AdminDisableUser(ctx context.Context, username string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminDisableUser")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.AdminDisableUser(ctx, &cognitoidentityprovider.AdminDisableUserInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		Username:   aws.String(username),
+	})
+	if err != nil {
+		return c.userError("Admin disable user error", username, err)
+	}
+
+	return nil
+}
+
+// AdminUpdateUserAttributes sets the given attributes and leaves the
+// others unchanged.
+func (c *CognitoConnector) AdminUpdateUserAttributes(ctx context.Context, username string, attributes map[string]string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminUpdateUserAttributes")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.AdminUpdateUserAttributes(ctx, &cognitoidentityprovider.AdminUpdateUserAttributesInput{
+		UserPoolId:     aws.String(c.userPoolID()),
+		Username:       aws.String(username),
+		UserAttributes: toAttributes(attributes),
+	})
+	if err != nil {
+		return c.userError("Admin update user attributes error", username, err)
+	}
+
+	return nil
+}
+
+func (c *CognitoConnector) AdminDeleteUserAttributes(ctx context.Context, username string, names ...string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminDeleteUserAttributes")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.AdminDeleteUserAttributes(ctx, &cognitoidentityprovider.AdminDeleteUserAttributesInput{
+		UserPoolId:         aws.String(c.userPoolID()),
+		Username:           aws.String(username),
+		UserAttributeNames: names,
+	})
+	if err != nil {
+		return c.userError("Admin delete user attributes error", username, err)
+	}
+
+	return nil
+}
+
+// CreateGroup creates a group and returns ErrGroupExists when it already
+// exists.
+func (c *CognitoConnector) CreateGroup(ctx context.Context, name string, description string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "CreateGroup")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	params := &cognitoidentityprovider.CreateGroupInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		GroupName:  aws.String(name),
+	}
+	if description != "" {
+		params.Description = aws.String(description)
+	}
+
+	_, err = c.client.CreateGroup(ctx, params)
+	if err != nil {
+		var exists *types.GroupExistsException
+		if errors.As(err, &exists) {
+			return fmt.Errorf("%w: %w", ErrGroupExists, err)
+		}
+
+		c.logger.Error("Create group error", zap.String("group", name), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *CognitoConnector) DeleteGroup(ctx context.Context, name string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "DeleteGroup")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.DeleteGroup(ctx, &cognitoidentityprovider.DeleteGroupInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		GroupName:  aws.String(name),
+	})
+	if err != nil {
+		return c.groupError("Delete group error", name, err)
+	}
+
+	return nil
+}
+
+func (c *CognitoConnector) AdminAddUserToGroup(ctx context.Context, username string, group string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminAddUserToGroup")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.AdminAddUserToGroup(ctx, &cognitoidentityprovider.AdminAddUserToGroupInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		Username:   aws.String(username),
+		GroupName:  aws.String(group),
+	})
+	if err != nil {
+		return c.userError("Admin add user to group error", username, err)
+	}
+
+	return nil
+}
+
+func (c *CognitoConnector) AdminRemoveUserFromGroup(ctx context.Context, username string, group string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminRemoveUserFromGroup")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.AdminRemoveUserFromGroup(ctx, &cognitoidentityprovider.AdminRemoveUserFromGroupInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		Username:   aws.String(username),
+		GroupName:  aws.String(group),
+	})
+	if err != nil {
+		return c.userError("Admin remove user from group error", username, err)
+	}
+
+	return nil
+}
+
+// AdminListGroupsForUser returns the names of the user's groups.
+func (c *CognitoConnector) AdminListGroupsForUser(ctx context.Context, username string) (_ []string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "AdminListGroupsForUser")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	paginator := cognitoidentityprovider.NewAdminListGroupsForUserPaginator(c.client, &cognitoidentityprovider.AdminListGroupsForUserInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		Username:   aws.String(username),
+	})
+
+	groups := []string{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, c.userError("Admin list groups for user error", username, err)
+		}
+
+		for _, group := range page.Groups {
+			groups = append(groups, aws.ToString(group.GroupName))
+		}
+	}
+
+	return groups, nil
+}
+
+func (c *CognitoConnector) ListUsersInGroup(ctx context.Context, group string) (_ []*User, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "ListUsersInGroup")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	paginator := cognitoidentityprovider.NewListUsersInGroupPaginator(c.client, &cognitoidentityprovider.ListUsersInGroupInput{
+		UserPoolId: aws.String(c.userPoolID()),
+		GroupName:  aws.String(group),
+	})
+
+	users := []*User{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, c.groupError("List users in group error", group, err)
+		}
+
+		for i := range page.Users {
+			users = append(users, fromUserType(&page.Users[i]))
+		}
+	}
+
+	return users, nil
+}
+
+func (c *CognitoConnector) userPoolID() string {
+	return c.config.UserPoolID
+}
+
+// userError maps a missing user or group to its sentinel and logs other
+// errors.
+func (c *CognitoConnector) userError(msg string, username string, err error) error {
+	var userNotFound *types.UserNotFoundException
+	if errors.As(err, &userNotFound) {
+		return fmt.Errorf("%w: %w", ErrUserNotFound, err)
+	}
+
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("%w: %w", ErrGroupNotFound, err)
+	}
+
+	c.logger.Error(msg, zap.String("username", username), zap.Error(err))
+	return err
+}
+
+func (c *CognitoConnector) groupError(msg string, group string, err error) error {
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("%w: %w", ErrGroupNotFound, err)
+	}
+
+	c.logger.Error(msg, zap.String("group", group), zap.Error(err))
+	return err
+}
+
+func toAttributes(attributes map[string]string) []types.AttributeType {
+	attrs := make([]types.AttributeType, 0, len(attributes))
+	for name, value := range attributes {
+		attrs = append(attrs, types.AttributeType{
+			Name:  aws.String(name),
+			Value: aws.String(value),
+		})
+	}
+
+	return attrs
+}
+
+func fromAttributes(attrs []types.AttributeType) map[string]string {
+	attributes := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		attributes[aws.ToString(attr.Name)] = aws.ToString(attr.Value)
+	}
+
+	return attributes
+}
+
+func fromUserType(u *types.UserType) *User {
+	if u == nil {
+		return nil
+	}
+
+	return &User{
+		Username:   aws.ToString(u.Username),
+		Status:     string(u.UserStatus),
+		Enabled:    u.Enabled,
+		Attributes: fromAttributes(u.Attributes),
+		CreatedAt:  aws.ToTime(u.UserCreateDate),
+		ModifiedAt: aws.ToTime(u.UserLastModifiedDate),
+	}
+}
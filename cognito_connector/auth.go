@@ -0,0 +1,230 @@
+package cognito_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrChallengeRequired = awserrors.New(awserrors.ErrUnauthorized, "auth challenge required")
+	ErrNotAuthorized     = awserrors.New(awserrors.ErrUnauthorized, "not authorized")
+)
+
+// Tokens are the tokens issued by a sign-in. RefreshToken is only issued
+// on sign-in; refreshing keeps the previous one.
+type Tokens struct {
+	AccessToken  string
+	IDToken      string
+	RefreshToken string
+	TokenType    string
+	ExpiresAt    time.Time
+}
+
+// ChallengeError is returned when Cognito needs another response, e.g.
+// NEW_PASSWORD_REQUIRED or SOFTWARE_TOKEN_MFA, before issuing tokens.
+// Answer it with RespondToAuthChallenge. It matches ErrChallengeRequired
+// with errors.Is.
+type ChallengeError struct {
+	Name       string
+	Username   string
+	Session    string
+	Parameters map[string]string
+}
+
+func (e *ChallengeError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrChallengeRequired, e.Name)
+}
+
+func (e *ChallengeError) Unwrap() error {
+	return ErrChallengeRequired
+}
+
+// SignIn authenticates with USER_SRP_AUTH, so the password never leaves
+// the process, and answers the PASSWORD_VERIFIER challenge.
+func (c *CognitoConnector) SignIn(ctx context.Context, username string, password string) (_ *Tokens, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "SignIn")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	srp, err := newSRPClient(c.userPoolID())
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{
+		"USERNAME": username,
+		"SRP_A":    srp.srpA(),
+	}
+	c.addSecretHash(params, username)
+
+	result, err := c.client.InitiateAuth(ctx, &cognitoidentityprovider.InitiateAuthInput{
+		AuthFlow:       types.AuthFlowTypeUserSrpAuth,
+		ClientId:       aws.String(c.clientID()),
+		AuthParameters: params,
+	})
+	if err != nil {
+		return nil, c.authError("Initiate auth error", username, err)
+	}
+
+	if result.ChallengeName != types.ChallengeNameTypePasswordVerifier {
+		return c.tokens(result.AuthenticationResult, result.ChallengeName, result.ChallengeParameters, result.Session, username)
+	}
+
+	// Cognito identifies the user by USER_ID_FOR_SRP from here on, which
+	// differs from username when signing in with an alias
+	challenge := result.ChallengeParameters
+	userID := challenge["USER_ID_FOR_SRP"]
+
+	signature, timestamp, err := srp.passwordClaim(userID, password, challenge["SRP_B"], challenge["SALT"], challenge["SECRET_BLOCK"], time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	responses := map[string]string{
+		"USERNAME":                    userID,
+		"PASSWORD_CLAIM_SECRET_BLOCK": challenge["SECRET_BLOCK"],
+		"PASSWORD_CLAIM_SIGNATURE":    signature,
+		"TIMESTAMP":                   timestamp,
+	}
+	c.addSecretHash(responses, userID)
+
+	return c.respond(ctx, &ChallengeError{
+		Name:     string(types.ChallengeNameTypePasswordVerifier),
+		Username: userID,
+		Session:  aws.ToString(result.Session),
+	}, responses)
+}
+
+// RespondToAuthChallenge answers a challenge returned by SignIn, e.g.
+// {"NEW_PASSWORD": "..."} or {"SOFTWARE_TOKEN_MFA_CODE": "123456"}.
+// USERNAME and SECRET_HASH are added.
+func (c *CognitoConnector) RespondToAuthChallenge(ctx context.Context, challenge *ChallengeError, responses map[string]string) (_ *Tokens, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "RespondToAuthChallenge")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	answers := make(map[string]string, len(responses)+2)
+	for name, value := range responses {
+		answers[name] = value
+	}
+
+	answers["USERNAME"] = challenge.Username
+	c.addSecretHash(answers, challenge.Username)
+
+	return c.respond(ctx, challenge, answers)
+}
+
+// RefreshTokens exchanges a refresh token for new access and ID tokens.
+// With a client secret, username must be the user's username, not an
+// alias.
+func (c *CognitoConnector) RefreshTokens(ctx context.Context, username string, refreshToken string) (_ *Tokens, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "RefreshTokens")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	params := map[string]string{
+		"REFRESH_TOKEN": refreshToken,
+	}
+	c.addSecretHash(params, username)
+
+	result, err := c.client.InitiateAuth(ctx, &cognitoidentityprovider.InitiateAuthInput{
+		AuthFlow:       types.AuthFlowTypeRefreshTokenAuth,
+		ClientId:       aws.String(c.clientID()),
+		AuthParameters: params,
+	})
+	if err != nil {
+		return nil, c.authError("Refresh tokens error", username, err)
+	}
+
+	tokens, err := c.tokens(result.AuthenticationResult, result.ChallengeName, result.ChallengeParameters, result.Session, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokens.RefreshToken == "" {
+		tokens.RefreshToken = refreshToken
+	}
+
+	return tokens, nil
+}
+
+// SecretHash computes SECRET_HASH for the configured client, or returns
+// "" when it has no client_secret.
+func (c *CognitoConnector) SecretHash(username string) string {
+	secret := c.config.ClientSecret
+	if secret == "" {
+		return ""
+	}
+
+	return SecretHash(c.clientID(), secret, username)
+}
+
+func (c *CognitoConnector) respond(ctx context.Context, challenge *ChallengeError, responses map[string]string) (*Tokens, error) {
+	result, err := c.client.RespondToAuthChallenge(ctx, &cognitoidentityprovider.RespondToAuthChallengeInput{
+		ChallengeName:      types.ChallengeNameType(challenge.Name),
+		ClientId:           aws.String(c.clientID()),
+		ChallengeResponses: responses,
+		Session:            aws.String(challenge.Session),
+	})
+	if err != nil {
+		return nil, c.authError("Respond to auth challenge error", challenge.Username, err)
+	}
+
+	return c.tokens(result.AuthenticationResult, result.ChallengeName, result.ChallengeParameters, result.Session, challenge.Username)
+}
+
+func (c *CognitoConnector) tokens(result *types.AuthenticationResultType, name types.ChallengeNameType, parameters map[string]string, session *string, username string) (*Tokens, error) {
+	if result == nil {
+		if userID := parameters["USER_ID_FOR_SRP"]; userID != "" {
+			username = userID
+		}
+
+		return nil, &ChallengeError{
+			Name:       string(name),
+			Username:   username,
+			Session:    aws.ToString(session),
+			Parameters: parameters,
+		}
+	}
+
+	return &Tokens{
+		AccessToken:  aws.ToString(result.AccessToken),
+		IDToken:      aws.ToString(result.IdToken),
+		RefreshToken: aws.ToString(result.RefreshToken),
+		TokenType:    aws.ToString(result.TokenType),
+		ExpiresAt:    time.Now().Add(time.Duration(result.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func (c *CognitoConnector) addSecretHash(params map[string]string, username string) {
+	if hash := c.SecretHash(username); hash != "" {
+		params["SECRET_HASH"] = hash
+	}
+}
+
+func (c *CognitoConnector) clientID() string {
+	return c.config.ClientID
+}
+
+// authError maps rejected credentials to ErrNotAuthorized and logs other
+// errors.
+func (c *CognitoConnector) authError(msg string, username string, err error) error {
+	var notAuthorized *types.NotAuthorizedException
+	if errors.As(err, &notAuthorized) {
+		return fmt.Errorf("%w: %w", ErrNotAuthorized, err)
+	}
+
+	var userNotFound *types.UserNotFoundException
+	if errors.As(err, &userNotFound) {
+		return fmt.Errorf("%w: %w", ErrUserNotFound, err)
+	}
+
+	c.logger.Error(msg, zap.String("username", username), zap.Error(err))
+	return err
+}
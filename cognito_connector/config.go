@@ -0,0 +1,33 @@
+package cognito_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. COGNITO_USER_POOL_ID for the cognito
+// scope.
+type Config struct {
+	UserPoolID          string   `mapstructure:"user_pool_id" default:""`
+	ClientIDs           []string `mapstructure:"client_ids" default:""`
+	ClientID            string   `mapstructure:"client_id" default:""`
+	ClientSecret        string   `mapstructure:"client_secret" default:""`
+	TokenUse            string   `mapstructure:"token_use" default:""`
+	JWKSRefreshInterval int      `mapstructure:"jwks_refresh_interval" default:"300"`
+	Leeway              int      `mapstructure:"leeway" default:"0"`
+	CognitoKey          string   `mapstructure:"cognito_key" default:"ABCDE"`
+	CognitoSecret       string   `mapstructure:"cognito_secret" default:"example_secret"`
+	CognitoToken        string   `mapstructure:"cognito_token" default:""`
+	CognitoRegion       string   `mapstructure:"cognito_region" default:"us-west-1"`
+	VerifyCredentials   bool     `mapstructure:"verify_credentials" default:"false"`
+	Preflight           bool     `mapstructure:"preflight" default:"false"`
+}
+
+func (c *CognitoConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *CognitoConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,277 @@
+package cognito_connector
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultUserPoolID          = ""
+	DefaultClientID            = ""
+	DefaultClientSecret        = ""
+	DefaultTokenUse            = ""
+	DefaultJWKSRefreshInterval = 300
+	DefaultLeeway              = 0
+	DefaultCognitoKey          = "ABCDE"
+	DefaultCognitoSecret       = "example_secret"
+	DefaultCognitoToken        = ""
+	DefaultCognitoRegion       = "us-west-1"
+)
+
+var preflightActions = []string{
+	"cognito-idp:AdminGetUser",
+	"cognito-idp:AdminCreateUser",
+	"cognito-idp:AdminUpdateUserAttributes",
+	"cognito-idp:AdminAddUserToGroup",
+	"cognito-idp:AdminRemoveUserFromGroup",
+}
+
+type CognitoConnector struct {
+	params   Params
+	logger   *zap.Logger
+	client   *cognitoidentityprovider.Client
+	scope    string
+	config   Config
+	tracker  *inflight.Tracker
+	verifier *Verifier
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider         `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig            `optional:"true"`
+	CredentialChain *awscredentials.Chain           `optional:"true"`
+	Tracer          *xray.Tracer                    `optional:"true"`
+	Breaker         *circuitbreaker.Breaker         `optional:"true"`
+	Prometheus      *metrics.Metrics                `optional:"true"`
+	Telemetry       *telemetry.Telemetry            `optional:"true"`
+	Health          *health.Health                  `optional:"true"`
+	Faults          *faults.Injector                `optional:"true"`
+	Client          *cognitoidentityprovider.Client `optional:"true"`
+}
+
+// Module provides the CognitoConnector of scope. It uses the
+// *cognitoidentityprovider.Client in the graph, if any, instead of
+// building one, and adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the CognitoConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *cognitoidentityprovider.Client named
+// after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *CognitoConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*cognitoidentityprovider.Client](scope, named),
+		instance.Provide[*CognitoConnector](scope, named, func(p Params) *CognitoConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &CognitoConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *CognitoConnector) Authenticator {
+			return c
+		}),
+		instance.Export(scope, named, func(c *CognitoConnector) UserPool {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *CognitoConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting CognitoConnector",
+		zap.String("user_pool_id", c.config.UserPoolID),
+		zap.String("cognito_region", c.config.CognitoRegion),
+		zap.Strings("client_ids", c.config.ClientIDs),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions,
+			"arn:{partition}:cognito-idp:{region}:{account}:userpool/"+c.config.UserPoolID,
+		)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = cognitoidentityprovider.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = cognitoidentityprovider.New(c.client.Options(), func(o *cognitoidentityprovider.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+	c.verifier = NewVerifier(
+		c.config.CognitoRegion,
+		c.config.UserPoolID,
+		c.config.ClientIDs...,
+	)
+	c.verifier.TokenUse = c.config.TokenUse
+	c.verifier.RefreshInterval = time.Duration(c.config.JWKSRefreshInterval) * time.Second
+	c.verifier.Leeway = time.Duration(c.config.Leeway) * time.Second
+
+	if len(c.verifier.clientIDs) == 0 {
+		c.logger.Warn("client_ids is empty; Verify rejects every token")
+	}
+
+	return nil
+}
+
+func (c *CognitoConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped CognitoConnector")
+
+	return nil
+}
+
+func (c *CognitoConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.CognitoRegion,
+		Key:         c.config.CognitoKey,
+		Secret:      c.config.CognitoSecret,
+		Token:       c.config.CognitoToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *CognitoConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("cognito_region")
+
+	v.Required("user_pool_id")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("cognito_key", DefaultCognitoKey)
+		v.NotPlaceholder("cognito_secret", DefaultCognitoSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the user pool.
+func (c *CognitoConnector) probe(ctx context.Context) error {
+	_, err := c.client.DescribeUserPool(ctx, &cognitoidentityprovider.DescribeUserPoolInput{
+		UserPoolId: aws.String(c.config.UserPoolID),
+	})
+
+	return err
+}
+
+func (c *CognitoConnector) GetClient() *cognitoidentityprovider.Client {
+	return c.client
+}
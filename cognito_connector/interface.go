@@ -0,0 +1,32 @@
+package cognito_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Authenticator,UserPool
+
+// Authenticator signs users in and verifies their tokens.
+type Authenticator interface {
+	Verify(ctx context.Context, token string) (*Claims, error)
+	SignIn(ctx context.Context, username string, password string) (*Tokens, error)
+	RespondToAuthChallenge(ctx context.Context, challenge *ChallengeError, responses map[string]string) (*Tokens, error)
+	RefreshTokens(ctx context.Context, username string, refreshToken string) (*Tokens, error)
+}
+
+// UserPool manages users and their groups.
+type UserPool interface {
+	AdminCreateUser(ctx context.Context, u NewUser) (*User, error)
+	AdminGetUser(ctx context.Context, username string) (*User, error)
+	AdminDeleteUser(ctx context.Context, username string) error
+	AdminSetUserPassword(ctx context.Context, username string, password string, permanent bool) error
+	AdminEnableUser(ctx context.Context, username string) error
+	AdminDisableUser(ctx context.Context, username string) error
+	AdminUpdateUserAttributes(ctx context.Context, username string, attributes map[string]string) error
+	AdminAddUserToGroup(ctx context.Context, username string, group string) error
+	AdminRemoveUserFromGroup(ctx context.Context, username string, group string) error
+	AdminListGroupsForUser(ctx context.Context, username string) ([]string, error)
+}
+
+var (
+	_ Authenticator = (*CognitoConnector)(nil)
+	_ UserPool      = (*CognitoConnector)(nil)
+)
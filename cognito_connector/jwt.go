@@ -0,0 +1,344 @@
+package cognito_connector
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/partition"
+)
+
+var (
+	ErrInvalidToken  = awserrors.New(awserrors.ErrUnauthorized, "invalid Cognito token")
+	ErrTokenExpired  = awserrors.New(awserrors.ErrUnauthorized, "Cognito token is expired")
+	ErrUnknownKey    = awserrors.New(awserrors.ErrUnauthorized, "unknown Cognito signing key")
+	ErrInvalidClaims = awserrors.New(awserrors.ErrUnauthorized, "unexpected Cognito token claims")
+	ErrNoClientIDs   = awserrors.New(awserrors.ErrUnauthorized, "no Cognito app clients to accept tokens of")
+)
+
+const (
+	TokenUseAccess = "access"
+	TokenUseID     = "id"
+)
+
+// Claims are the verified claims of an access or ID token. Raw holds
+// every claim, including custom attributes.
+type Claims struct {
+	Subject       string
+	Issuer        string
+	TokenUse      string
+	ClientID      string
+	Username      string
+	Email         string
+	EmailVerified bool
+	Groups        []string
+	Scopes        []string
+	ExpiresAt     time.Time
+	IssuedAt      time.Time
+	AuthTime      time.Time
+	Raw           map[string]interface{}
+}
+
+// HasGroup reports whether the user is in group.
+func (c *Claims) HasGroup(group string) bool {
+	return slices.Contains(c.Groups, group)
+}
+
+// HasScope reports whether an access token was granted scope.
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+type tokenClaims struct {
+	Sub           string      `json:"sub"`
+	Iss           string      `json:"iss"`
+	TokenUse      string      `json:"token_use"`
+	ClientID      string      `json:"client_id"`
+	Aud           string      `json:"aud"`
+	Username      string      `json:"username"`
+	CognitoUser   string      `json:"cognito:username"`
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"`
+	Groups        []string    `json:"cognito:groups"`
+	Scope         string      `json:"scope"`
+	Exp           int64       `json:"exp"`
+	Iat           int64       `json:"iat"`
+	AuthTime      int64       `json:"auth_time"`
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// Verifier checks tokens issued by a user pool against its JWKS. Keys are
+// cached and refetched, at most once per RefreshInterval, when a token
+// names an unknown key.
+type Verifier struct {
+	// TokenUse restricts tokens to TokenUseAccess or TokenUseID; empty
+	// accepts both.
+	TokenUse        string
+	RefreshInterval time.Duration
+	Leeway          time.Duration
+
+	client    *http.Client
+	issuer    string
+	clientIDs []string
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewVerifier returns a Verifier for the user pool accepting tokens of
+// clientIDs. Without any it rejects every token with ErrNoClientIDs, as
+// a token of another app client of the pool must not pass.
+func NewVerifier(region string, userPoolID string, clientIDs ...string) *Verifier {
+	return &Verifier{
+		RefreshInterval: DefaultJWKSRefreshInterval * time.Second,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		issuer:          fmt.Sprintf("https://%s/%s", partition.ForRegion(region).Host("cognito-idp", region), userPoolID),
+		clientIDs:       slices.DeleteFunc(clientIDs, func(id string) bool { return id == "" }),
+		keys:            make(map[string]*rsa.PublicKey),
+	}
+}
+
+// Issuer is the iss claim expected of tokens.
+func (v *Verifier) Issuer() string {
+	return v.issuer
+}
+
+// Verify checks the signature, issuer, expiry, token_use and client of
+// token and returns its claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	if len(v.clientIDs) == 0 {
+		return nil, ErrNoClientIDs
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Alg)
+	}
+
+	key, err := v.getKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var tc tokenClaims
+	if err := decodeSegment(parts[1], &tc); err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, err
+	}
+
+	if tc.Iss != v.issuer {
+		return nil, fmt.Errorf("%w: issuer %q", ErrInvalidClaims, tc.Iss)
+	}
+
+	if time.Now().After(time.Unix(tc.Exp, 0).Add(v.Leeway)) {
+		return nil, ErrTokenExpired
+	}
+
+	clientID := tc.ClientID
+	switch tc.TokenUse {
+	case TokenUseAccess:
+	case TokenUseID:
+		clientID = tc.Aud
+	default:
+		return nil, fmt.Errorf("%w: token_use %q", ErrInvalidClaims, tc.TokenUse)
+	}
+
+	if v.TokenUse != "" && tc.TokenUse != v.TokenUse {
+		return nil, fmt.Errorf("%w: token_use %q", ErrInvalidClaims, tc.TokenUse)
+	}
+
+	if !slices.Contains(v.clientIDs, clientID) {
+		return nil, fmt.Errorf("%w: client %q", ErrInvalidClaims, clientID)
+	}
+
+	claims := &Claims{
+		Subject:   tc.Sub,
+		Issuer:    tc.Iss,
+		TokenUse:  tc.TokenUse,
+		ClientID:  clientID,
+		Username:  tc.Username,
+		Email:     tc.Email,
+		Groups:    tc.Groups,
+		ExpiresAt: time.Unix(tc.Exp, 0),
+		IssuedAt:  time.Unix(tc.Iat, 0),
+		AuthTime:  time.Unix(tc.AuthTime, 0),
+		Raw:       raw,
+	}
+
+	if tc.CognitoUser != "" {
+		claims.Username = tc.CognitoUser
+	}
+
+	// ID tokens carry email_verified as a bool or, for some pools, a string
+	switch verified := tc.EmailVerified.(type) {
+	case bool:
+		claims.EmailVerified = verified
+	case string:
+		claims.EmailVerified = verified == "true"
+	}
+
+	if tc.Scope != "" {
+		claims.Scopes = strings.Fields(tc.Scope)
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	fetchedAt := v.fetchedAt
+	v.mu.RUnlock()
+
+	if ok {
+		return key, nil
+	}
+
+	// Keys rotate rarely; don't let unknown kids trigger a fetch per token
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) < v.RefreshInterval {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, kid)
+	}
+
+	if err := v.fetchKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	v.mu.RLock()
+	key, ok = v.keys[kid]
+	v.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, kid)
+	}
+
+	return key, nil
+}
+
+func (v *Verifier) fetchKeys(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.issuer+"/.well-known/jwks.json", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch Cognito JWKS: %s", resp.Status)
+	}
+
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return err
+		}
+
+		keys[k.Kid] = key
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+
+	return nil
+}
+
+func (k jwk) publicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWK %s: %w", k.Kid, err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWK %s: %w", k.Kid, err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return nil
+}
+
+// Verify checks token against the configured user_pool_id, client_ids and
+// token_use.
+func (c *CognitoConnector) Verify(ctx context.Context, token string) (_ *Claims, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "CognitoConnector", "Verify")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.verifier.Verify(ctx, token)
+}
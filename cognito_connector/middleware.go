@@ -0,0 +1,82 @@
+package cognito_connector
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const claimsKey = "cognito_claims"
+
+type claimsContextKey struct{}
+
+// Middleware rejects requests without a valid bearer token and stores the
+// claims for ClaimsFromGin and ClaimsFromContext. Groups, if given, are
+// required of the user; one of them is enough.
+func (c *CognitoConnector) Middleware(groups ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+
+		token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "missing bearer token",
+			})
+
+			return
+		}
+
+		claims, err := c.Verify(ctx.Request.Context(), token)
+		if err != nil {
+			c.logger.Debug("Rejected Cognito token", zap.Error(err))
+
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "invalid token",
+			})
+
+			return
+		}
+
+		if len(groups) > 0 && !hasAnyGroup(claims, groups) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "forbidden",
+			})
+
+			return
+		}
+
+		ctx.Set(claimsKey, claims)
+		ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), claimsContextKey{}, claims))
+
+		ctx.Next()
+	}
+}
+
+// ClaimsFromGin returns the claims stored by Middleware.
+func ClaimsFromGin(ctx *gin.Context) (*Claims, bool) {
+	claims, ok := ctx.Get(claimsKey)
+	if !ok {
+		return nil, false
+	}
+
+	return claims.(*Claims), true
+}
+
+// ClaimsFromContext returns the claims stored by Middleware in the request
+// context.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*Claims)
+	return claims, ok
+}
+
+func hasAnyGroup(claims *Claims, groups []string) bool {
+	for _, group := range groups {
+		if claims.HasGroup(group) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,267 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/cognito_connector (interfaces: Authenticator,UserPool)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . Authenticator,UserPool
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	cognito_connector "github.com/elmntri/zeitgeber-aws-modules/cognito_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuthenticator is a mock of Authenticator interface.
+type MockAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthenticatorMockRecorder
+}
+
+// MockAuthenticatorMockRecorder is the mock recorder for MockAuthenticator.
+type MockAuthenticatorMockRecorder struct {
+	mock *MockAuthenticator
+}
+
+// NewMockAuthenticator creates a new mock instance.
+func NewMockAuthenticator(ctrl *gomock.Controller) *MockAuthenticator {
+	mock := &MockAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthenticator) EXPECT() *MockAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// RefreshTokens mocks base method.
+func (m *MockAuthenticator) RefreshTokens(arg0 context.Context, arg1, arg2 string) (*cognito_connector.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokens", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*cognito_connector.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTokens indicates an expected call of RefreshTokens.
+func (mr *MockAuthenticatorMockRecorder) RefreshTokens(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockAuthenticator)(nil).RefreshTokens), arg0, arg1, arg2)
+}
+
+// RespondToAuthChallenge mocks base method.
+func (m *MockAuthenticator) RespondToAuthChallenge(arg0 context.Context, arg1 *cognito_connector.ChallengeError, arg2 map[string]string) (*cognito_connector.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToAuthChallenge", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*cognito_connector.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondToAuthChallenge indicates an expected call of RespondToAuthChallenge.
+func (mr *MockAuthenticatorMockRecorder) RespondToAuthChallenge(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToAuthChallenge", reflect.TypeOf((*MockAuthenticator)(nil).RespondToAuthChallenge), arg0, arg1, arg2)
+}
+
+// SignIn mocks base method.
+func (m *MockAuthenticator) SignIn(arg0 context.Context, arg1, arg2 string) (*cognito_connector.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignIn", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*cognito_connector.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignIn indicates an expected call of SignIn.
+func (mr *MockAuthenticatorMockRecorder) SignIn(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockAuthenticator)(nil).SignIn), arg0, arg1, arg2)
+}
+
+// Verify mocks base method.
+func (m *MockAuthenticator) Verify(arg0 context.Context, arg1 string) (*cognito_connector.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0, arg1)
+	ret0, _ := ret[0].(*cognito_connector.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockAuthenticatorMockRecorder) Verify(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockAuthenticator)(nil).Verify), arg0, arg1)
+}
+
+// MockUserPool is a mock of UserPool interface.
+type MockUserPool struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserPoolMockRecorder
+}
+
+// MockUserPoolMockRecorder is the mock recorder for MockUserPool.
+type MockUserPoolMockRecorder struct {
+	mock *MockUserPool
+}
+
+// NewMockUserPool creates a new mock instance.
+func NewMockUserPool(ctrl *gomock.Controller) *MockUserPool {
+	mock := &MockUserPool{ctrl: ctrl}
+	mock.recorder = &MockUserPoolMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserPool) EXPECT() *MockUserPoolMockRecorder {
+	return m.recorder
+}
+
+// AdminAddUserToGroup mocks base method.
+func (m *MockUserPool) AdminAddUserToGroup(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminAddUserToGroup", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminAddUserToGroup indicates an expected call of AdminAddUserToGroup.
+func (mr *MockUserPoolMockRecorder) AdminAddUserToGroup(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminAddUserToGroup", reflect.TypeOf((*MockUserPool)(nil).AdminAddUserToGroup), arg0, arg1, arg2)
+}
+
+// AdminCreateUser mocks base method.
+func (m *MockUserPool) AdminCreateUser(arg0 context.Context, arg1 cognito_connector.NewUser) (*cognito_connector.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminCreateUser", arg0, arg1)
+	ret0, _ := ret[0].(*cognito_connector.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminCreateUser indicates an expected call of AdminCreateUser.
+func (mr *MockUserPoolMockRecorder) AdminCreateUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminCreateUser", reflect.TypeOf((*MockUserPool)(nil).AdminCreateUser), arg0, arg1)
+}
+
+// AdminDeleteUser mocks base method.
+func (m *MockUserPool) AdminDeleteUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminDeleteUser indicates an expected call of AdminDeleteUser.
+func (mr *MockUserPoolMockRecorder) AdminDeleteUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDeleteUser", reflect.TypeOf((*MockUserPool)(nil).AdminDeleteUser), arg0, arg1)
+}
+
+// AdminDisableUser mocks base method.
+func (m *MockUserPool) AdminDisableUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDisableUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminDisableUser indicates an expected call of AdminDisableUser.
+func (mr *MockUserPoolMockRecorder) AdminDisableUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDisableUser", reflect.TypeOf((*MockUserPool)(nil).AdminDisableUser), arg0, arg1)
+}
+
+// AdminEnableUser mocks base method.
+func (m *MockUserPool) AdminEnableUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminEnableUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminEnableUser indicates an expected call of AdminEnableUser.
+func (mr *MockUserPoolMockRecorder) AdminEnableUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminEnableUser", reflect.TypeOf((*MockUserPool)(nil).AdminEnableUser), arg0, arg1)
+}
+
+// AdminGetUser mocks base method.
+func (m *MockUserPool) AdminGetUser(arg0 context.Context, arg1 string) (*cognito_connector.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminGetUser", arg0, arg1)
+	ret0, _ := ret[0].(*cognito_connector.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminGetUser indicates an expected call of AdminGetUser.
+func (mr *MockUserPoolMockRecorder) AdminGetUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminGetUser", reflect.TypeOf((*MockUserPool)(nil).AdminGetUser), arg0, arg1)
+}
+
+// AdminListGroupsForUser mocks base method.
+func (m *MockUserPool) AdminListGroupsForUser(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminListGroupsForUser", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminListGroupsForUser indicates an expected call of AdminListGroupsForUser.
+func (mr *MockUserPoolMockRecorder) AdminListGroupsForUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminListGroupsForUser", reflect.TypeOf((*MockUserPool)(nil).AdminListGroupsForUser), arg0, arg1)
+}
+
+// AdminRemoveUserFromGroup mocks base method.
+func (m *MockUserPool) AdminRemoveUserFromGroup(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminRemoveUserFromGroup", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminRemoveUserFromGroup indicates an expected call of AdminRemoveUserFromGroup.
+func (mr *MockUserPoolMockRecorder) AdminRemoveUserFromGroup(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminRemoveUserFromGroup", reflect.TypeOf((*MockUserPool)(nil).AdminRemoveUserFromGroup), arg0, arg1, arg2)
+}
+
+// AdminSetUserPassword mocks base method.
+func (m *MockUserPool) AdminSetUserPassword(arg0 context.Context, arg1, arg2 string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminSetUserPassword", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminSetUserPassword indicates an expected call of AdminSetUserPassword.
+func (mr *MockUserPoolMockRecorder) AdminSetUserPassword(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminSetUserPassword", reflect.TypeOf((*MockUserPool)(nil).AdminSetUserPassword), arg0, arg1, arg2, arg3)
+}
+
+// AdminUpdateUserAttributes mocks base method.
+func (m *MockUserPool) AdminUpdateUserAttributes(arg0 context.Context, arg1 string, arg2 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminUpdateUserAttributes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminUpdateUserAttributes indicates an expected call of AdminUpdateUserAttributes.
+func (mr *MockUserPoolMockRecorder) AdminUpdateUserAttributes(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminUpdateUserAttributes", reflect.TypeOf((*MockUserPool)(nil).AdminUpdateUserAttributes), arg0, arg1, arg2)
+}
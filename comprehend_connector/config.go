@@ -0,0 +1,31 @@
+package comprehend_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. COMPREHEND_LANGUAGE_CODE for the
+// comprehend scope.
+type Config struct {
+	LanguageCode      string   `mapstructure:"language_code" default:"en"`
+	PIIEntityTypes    []string `mapstructure:"pii_entity_types" default:""`
+	PIIMinScore       float64  `mapstructure:"pii_min_score" default:"0.5"`
+	PIIMaskChar       string   `mapstructure:"pii_mask_char" default:"*"`
+	PIIMaskByType     bool     `mapstructure:"pii_mask_by_type" default:"false"`
+	ComprehendKey     string   `mapstructure:"comprehend_key" default:"ABCDE"`
+	ComprehendSecret  string   `mapstructure:"comprehend_secret" default:"example_secret"`
+	ComprehendToken   string   `mapstructure:"comprehend_token" default:""`
+	ComprehendRegion  string   `mapstructure:"comprehend_region" default:"us-west-1"`
+	VerifyCredentials bool     `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool     `mapstructure:"preflight" default:"false"`
+}
+
+func (c *ComprehendConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *ComprehendConnector) Config() Config {
+	return c.config
+}
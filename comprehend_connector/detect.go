@@ -0,0 +1,196 @@
+package comprehend_connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/comprehend"
+	"github.com/aws/aws-sdk-go-v2/service/comprehend/types"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// MaxBatchSize is the number of documents a batch call accepts.
+const MaxBatchSize = 25
+
+var ErrBatchFailed = awserrors.New(awserrors.ErrValidation, "batch documents failed")
+
+type Sentiment struct {
+	Sentiment types.SentimentType
+	Positive  float32
+	Negative  float32
+	Neutral   float32
+	Mixed     float32
+}
+
+func (c *ComprehendConnector) DetectSentiment(ctx context.Context, text string) (_ *Sentiment, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ComprehendConnector", "DetectSentiment")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.DetectSentiment(ctx, &comprehend.DetectSentimentInput{
+		Text:         aws.String(text),
+		LanguageCode: c.languageCode(),
+	})
+	if err != nil {
+		c.logger.Error("Detect sentiment error", zap.Error(err))
+		return nil, err
+	}
+
+	return toSentiment(result.Sentiment, result.SentimentScore), nil
+}
+
+// BatchDetectSentiment detects the sentiment of texts in batches of
+// MaxBatchSize. Results line up with texts; documents that failed are
+// nil and reported together in an ErrBatchFailed error.
+func (c *ComprehendConnector) BatchDetectSentiment(ctx context.Context, texts []string) (_ []*Sentiment, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ComprehendConnector", "BatchDetectSentiment")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	results := make([]*Sentiment, len(texts))
+	var failed []string
+
+	for offset := 0; offset < len(texts); offset += MaxBatchSize {
+		batch := texts[offset:min(offset+MaxBatchSize, len(texts))]
+
+		result, err := c.client.BatchDetectSentiment(ctx, &comprehend.BatchDetectSentimentInput{
+			TextList:     batch,
+			LanguageCode: c.languageCode(),
+		})
+		if err != nil {
+			c.logger.Error("Batch detect sentiment error", zap.Error(err))
+			return nil, err
+		}
+
+		for _, item := range result.ResultList {
+			results[offset+int(aws.ToInt32(item.Index))] = toSentiment(item.Sentiment, item.SentimentScore)
+		}
+
+		failed = append(failed, batchErrors(offset, result.ErrorList)...)
+	}
+
+	return results, batchError(failed, len(texts))
+}
+
+// DetectEntities returns the named entities in text.
+func (c *ComprehendConnector) DetectEntities(ctx context.Context, text string) (_ []types.Entity, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ComprehendConnector", "DetectEntities")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.DetectEntities(ctx, &comprehend.DetectEntitiesInput{
+		Text:         aws.String(text),
+		LanguageCode: c.languageCode(),
+	})
+	if err != nil {
+		c.logger.Error("Detect entities error", zap.Error(err))
+		return nil, err
+	}
+
+	return result.Entities, nil
+}
+
+// BatchDetectEntities is DetectEntities for many texts, batched like
+// BatchDetectSentiment.
+func (c *ComprehendConnector) BatchDetectEntities(ctx context.Context, texts []string) (_ [][]types.Entity, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ComprehendConnector", "BatchDetectEntities")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	results := make([][]types.Entity, len(texts))
+	var failed []string
+
+	for offset := 0; offset < len(texts); offset += MaxBatchSize {
+		batch := texts[offset:min(offset+MaxBatchSize, len(texts))]
+
+		result, err := c.client.BatchDetectEntities(ctx, &comprehend.BatchDetectEntitiesInput{
+			TextList:     batch,
+			LanguageCode: c.languageCode(),
+		})
+		if err != nil {
+			c.logger.Error("Batch detect entities error", zap.Error(err))
+			return nil, err
+		}
+
+		for _, item := range result.ResultList {
+			results[offset+int(aws.ToInt32(item.Index))] = item.Entities
+		}
+
+		failed = append(failed, batchErrors(offset, result.ErrorList)...)
+	}
+
+	return results, batchError(failed, len(texts))
+}
+
+// DetectPIIEntities returns the PII in text with at least pii_min_score,
+// limited to pii_entity_types when set. Comprehend has no batch PII call.
+func (c *ComprehendConnector) DetectPIIEntities(ctx context.Context, text string) (_ []types.PiiEntity, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ComprehendConnector", "DetectPIIEntities")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.DetectPiiEntities(ctx, &comprehend.DetectPiiEntitiesInput{
+		Text:         aws.String(text),
+		LanguageCode: c.languageCode(),
+	})
+	if err != nil {
+		c.logger.Error("Detect PII entities error", zap.Error(err))
+		return nil, err
+	}
+
+	minScore := float32(c.config.PIIMinScore)
+
+	allowed := map[string]bool{}
+	for _, entityType := range c.config.PIIEntityTypes {
+		allowed[strings.ToUpper(entityType)] = true
+	}
+
+	entities := make([]types.PiiEntity, 0, len(result.Entities))
+	for _, entity := range result.Entities {
+		if aws.ToFloat32(entity.Score) < minScore {
+			continue
+		}
+
+		if len(allowed) > 0 && !allowed[string(entity.Type)] {
+			continue
+		}
+
+		entities = append(entities, entity)
+	}
+
+	return entities, nil
+}
+
+func (c *ComprehendConnector) languageCode() types.LanguageCode {
+	return types.LanguageCode(c.config.LanguageCode)
+}
+
+func toSentiment(sentiment types.SentimentType, score *types.SentimentScore) *Sentiment {
+	s := &Sentiment{Sentiment: sentiment}
+
+	if score != nil {
+		s.Positive = aws.ToFloat32(score.Positive)
+		s.Negative = aws.ToFloat32(score.Negative)
+		s.Neutral = aws.ToFloat32(score.Neutral)
+		s.Mixed = aws.ToFloat32(score.Mixed)
+	}
+
+	return s
+}
+
+func batchErrors(offset int, errs []types.BatchItemError) []string {
+	failed := make([]string, 0, len(errs))
+	for _, e := range errs {
+		failed = append(failed, fmt.Sprintf("%d: %s", offset+int(aws.ToInt32(e.Index)), aws.ToString(e.ErrorCode)))
+	}
+
+	return failed
+}
+
+func batchError(failed []string, total int) error {
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d of %d: %s", ErrBatchFailed, len(failed), total, strings.Join(failed, ", "))
+}
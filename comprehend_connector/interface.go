@@ -0,0 +1,21 @@
+package comprehend_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/comprehend/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . TextAnalyzer
+
+// TextAnalyzer detects sentiment, entities and PII in text.
+type TextAnalyzer interface {
+	DetectSentiment(ctx context.Context, text string) (*Sentiment, error)
+	BatchDetectSentiment(ctx context.Context, texts []string) ([]*Sentiment, error)
+	DetectEntities(ctx context.Context, text string) ([]types.Entity, error)
+	BatchDetectEntities(ctx context.Context, texts []string) ([][]types.Entity, error)
+	DetectPIIEntities(ctx context.Context, text string) ([]types.PiiEntity, error)
+	RedactPII(ctx context.Context, text string) (string, error)
+}
+
+var _ TextAnalyzer = (*ComprehendConnector)(nil)
@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/comprehend_connector (interfaces: TextAnalyzer)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . TextAnalyzer
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/comprehend/types"
+	comprehend_connector "github.com/elmntri/zeitgeber-aws-modules/comprehend_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTextAnalyzer is a mock of TextAnalyzer interface.
+type MockTextAnalyzer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTextAnalyzerMockRecorder
+}
+
+// MockTextAnalyzerMockRecorder is the mock recorder for MockTextAnalyzer.
+type MockTextAnalyzerMockRecorder struct {
+	mock *MockTextAnalyzer
+}
+
+// NewMockTextAnalyzer creates a new mock instance.
+func NewMockTextAnalyzer(ctrl *gomock.Controller) *MockTextAnalyzer {
+	mock := &MockTextAnalyzer{ctrl: ctrl}
+	mock.recorder = &MockTextAnalyzerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTextAnalyzer) EXPECT() *MockTextAnalyzerMockRecorder {
+	return m.recorder
+}
+
+// BatchDetectEntities mocks base method.
+func (m *MockTextAnalyzer) BatchDetectEntities(arg0 context.Context, arg1 []string) ([][]types.Entity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDetectEntities", arg0, arg1)
+	ret0, _ := ret[0].([][]types.Entity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDetectEntities indicates an expected call of BatchDetectEntities.
+func (mr *MockTextAnalyzerMockRecorder) BatchDetectEntities(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDetectEntities", reflect.TypeOf((*MockTextAnalyzer)(nil).BatchDetectEntities), arg0, arg1)
+}
+
+// BatchDetectSentiment mocks base method.
+func (m *MockTextAnalyzer) BatchDetectSentiment(arg0 context.Context, arg1 []string) ([]*comprehend_connector.Sentiment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDetectSentiment", arg0, arg1)
+	ret0, _ := ret[0].([]*comprehend_connector.Sentiment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDetectSentiment indicates an expected call of BatchDetectSentiment.
+func (mr *MockTextAnalyzerMockRecorder) BatchDetectSentiment(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDetectSentiment", reflect.TypeOf((*MockTextAnalyzer)(nil).BatchDetectSentiment), arg0, arg1)
+}
+
+// DetectEntities mocks base method.
+func (m *MockTextAnalyzer) DetectEntities(arg0 context.Context, arg1 string) ([]types.Entity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectEntities", arg0, arg1)
+	ret0, _ := ret[0].([]types.Entity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectEntities indicates an expected call of DetectEntities.
+func (mr *MockTextAnalyzerMockRecorder) DetectEntities(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectEntities", reflect.TypeOf((*MockTextAnalyzer)(nil).DetectEntities), arg0, arg1)
+}
+
+// DetectPIIEntities mocks base method.
+func (m *MockTextAnalyzer) DetectPIIEntities(arg0 context.Context, arg1 string) ([]types.PiiEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectPIIEntities", arg0, arg1)
+	ret0, _ := ret[0].([]types.PiiEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectPIIEntities indicates an expected call of DetectPIIEntities.
+func (mr *MockTextAnalyzerMockRecorder) DetectPIIEntities(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectPIIEntities", reflect.TypeOf((*MockTextAnalyzer)(nil).DetectPIIEntities), arg0, arg1)
+}
+
+// DetectSentiment mocks base method.
+func (m *MockTextAnalyzer) DetectSentiment(arg0 context.Context, arg1 string) (*comprehend_connector.Sentiment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectSentiment", arg0, arg1)
+	ret0, _ := ret[0].(*comprehend_connector.Sentiment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectSentiment indicates an expected call of DetectSentiment.
+func (mr *MockTextAnalyzerMockRecorder) DetectSentiment(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectSentiment", reflect.TypeOf((*MockTextAnalyzer)(nil).DetectSentiment), arg0, arg1)
+}
+
+// RedactPII mocks base method.
+func (m *MockTextAnalyzer) RedactPII(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedactPII", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedactPII indicates an expected call of RedactPII.
+func (mr *MockTextAnalyzerMockRecorder) RedactPII(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedactPII", reflect.TypeOf((*MockTextAnalyzer)(nil).RedactPII), arg0, arg1)
+}
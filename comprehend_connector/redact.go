@@ -0,0 +1,67 @@
+package comprehend_connector
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/comprehend/types"
+)
+
+// RedactPII masks the PII detected in text, e.g. before storing user
+// content. Each character of a span is replaced with pii_mask_char, or
+// the whole span with its type such as [EMAIL] when pii_mask_by_type.
+func (c *ComprehendConnector) RedactPII(ctx context.Context, text string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ComprehendConnector", "RedactPII")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	entities, err := c.DetectPIIEntities(ctx, text)
+	if err != nil {
+		return "", err
+	}
+
+	return Redact(text, entities, c.config.PIIMaskChar, c.config.PIIMaskByType), nil
+}
+
+// Redact masks the entity spans of text. Offsets count characters, not
+// bytes, as Comprehend reports them.
+func Redact(text string, entities []types.PiiEntity, maskChar string, byType bool) string {
+	if len(entities) == 0 {
+		return text
+	}
+
+	spans := make([]types.PiiEntity, len(entities))
+	copy(spans, entities)
+	sort.Slice(spans, func(i, j int) bool {
+		return aws.ToInt32(spans[i].BeginOffset) < aws.ToInt32(spans[j].BeginOffset)
+	})
+
+	runes := []rune(text)
+
+	var b strings.Builder
+	pos := 0
+	for _, span := range spans {
+		begin := max(int(aws.ToInt32(span.BeginOffset)), pos)
+		end := min(int(aws.ToInt32(span.EndOffset)), len(runes))
+
+		// Overlapping spans were masked by the previous one
+		if begin >= end {
+			continue
+		}
+
+		b.WriteString(string(runes[pos:begin]))
+
+		if byType {
+			b.WriteString("[" + string(span.Type) + "]")
+		} else {
+			b.WriteString(strings.Repeat(maskChar, end-begin))
+		}
+
+		pos = end
+	}
+
+	b.WriteString(string(runes[pos:]))
+
+	return b.String()
+}
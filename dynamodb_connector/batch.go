@@ -0,0 +1,225 @@
+package dynamodb_connector
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+const (
+	maxBatchWriteItems = 25
+	maxBatchGetKeys    = 100
+)
+
+const (
+	DefaultBatchMaxRetries = 5
+	DefaultBatchBackoffMs  = 50
+	DefaultBatchMaxBackoff = 2000
+)
+
+var ErrUnprocessed = awserrors.New(awserrors.ErrThrottled, "unprocessed batch items")
+
+// BatchWriteError reports the puts (items) and deletes (keys) that were
+// still unprocessed after all retries.
+type BatchWriteError struct {
+	UnprocessedPuts    []Item
+	UnprocessedDeletes []Item
+	Err                error
+}
+
+func (e *BatchWriteError) Error() string {
+	return fmt.Sprintf("batch write: %d puts and %d deletes unprocessed: %v",
+		len(e.UnprocessedPuts), len(e.UnprocessedDeletes), e.Err)
+}
+
+func (e *BatchWriteError) Unwrap() error {
+	return e.Err
+}
+
+// BatchGetError reports the keys that were still unprocessed after all
+// retries. Items that were read are still unmarshaled into out.
+type BatchGetError struct {
+	UnprocessedKeys []Item
+	Err             error
+}
+
+func (e *BatchGetError) Error() string {
+	return fmt.Sprintf("batch get: %d keys unprocessed: %v", len(e.UnprocessedKeys), e.Err)
+}
+
+func (e *BatchGetError) Unwrap() error {
+	return e.Err
+}
+
+// BatchWrite puts items and deletes keys in chunks of 25, retrying
+// unprocessed requests with exponential backoff.
+func (c *DynamoDBConnector) BatchWrite(ctx context.Context, puts []interface{}, deletes []interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "BatchWrite")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	requests := make([]types.WriteRequest, 0, len(puts)+len(deletes))
+
+	for _, item := range puts {
+		av, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			return err
+		}
+
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+	}
+
+	for _, key := range deletes {
+		k, err := attributevalue.MarshalMap(key)
+		if err != nil {
+			return err
+		}
+
+		requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: k}})
+	}
+
+	table := c.GetTableName()
+	failed := &BatchWriteError{Err: ErrUnprocessed}
+
+	for start := 0; start < len(requests); start += maxBatchWriteItems {
+		end := min(start+maxBatchWriteItems, len(requests))
+		pending := requests[start:end]
+
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt > 0 {
+				if attempt > c.config.BatchMaxRetries {
+					break
+				}
+
+				if err := c.batchBackoff(ctx, attempt); err != nil {
+					failed.Err = err
+					break
+				}
+			}
+
+			result, err := c.writes.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]types.WriteRequest{table: pending},
+			})
+			if err != nil {
+				c.logger.Error("Batch write error", zap.Error(err))
+				failed.Err = err
+				break
+			}
+
+			pending = result.UnprocessedItems[table]
+		}
+
+		for _, r := range pending {
+			if r.PutRequest != nil {
+				failed.UnprocessedPuts = append(failed.UnprocessedPuts, r.PutRequest.Item)
+			}
+			if r.DeleteRequest != nil {
+				failed.UnprocessedDeletes = append(failed.UnprocessedDeletes, r.DeleteRequest.Key)
+			}
+		}
+	}
+
+	if len(failed.UnprocessedPuts) > 0 || len(failed.UnprocessedDeletes) > 0 {
+		return failed
+	}
+
+	return nil
+}
+
+// BatchGet reads keys in chunks of 100, retrying unprocessed keys with
+// exponential backoff, and unmarshals the items into out (a pointer to a
+// slice). Result order is not guaranteed.
+func (c *DynamoDBConnector) BatchGet(ctx context.Context, keys []interface{}, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "BatchGet")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	marshaled := make([]map[string]types.AttributeValue, 0, len(keys))
+	for _, key := range keys {
+		k, err := attributevalue.MarshalMap(key)
+		if err != nil {
+			return err
+		}
+
+		marshaled = append(marshaled, k)
+	}
+
+	table := c.GetTableName()
+	failed := &BatchGetError{Err: ErrUnprocessed}
+
+	err = collect(out, func(fn ItemFunc) error {
+		for start := 0; start < len(marshaled); start += maxBatchGetKeys {
+			end := min(start+maxBatchGetKeys, len(marshaled))
+			pending := marshaled[start:end]
+
+			for attempt := 0; len(pending) > 0; attempt++ {
+				if attempt > 0 {
+					if attempt > c.config.BatchMaxRetries {
+						break
+					}
+
+					if err := c.batchBackoff(ctx, attempt); err != nil {
+						failed.Err = err
+						break
+					}
+				}
+
+				result, err := c.reads.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+					RequestItems: map[string]types.KeysAndAttributes{
+						table: {Keys: pending},
+					},
+				})
+				if err != nil {
+					c.logger.Error("Batch get error", zap.Error(err))
+					failed.Err = err
+					break
+				}
+
+				if err := each(result.Responses[table], fn); err != nil {
+					return err
+				}
+
+				pending = result.UnprocessedKeys[table].Keys
+			}
+
+			for _, k := range pending {
+				failed.UnprocessedKeys = append(failed.UnprocessedKeys, k)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(failed.UnprocessedKeys) > 0 {
+		return failed
+	}
+
+	return nil
+}
+
+func (c *DynamoDBConnector) batchBackoff(ctx context.Context, attempt int) error {
+	backoff := time.Duration(c.config.BatchBackoffMs) * time.Millisecond << (attempt - 1)
+	maxBackoff := time.Duration(c.config.BatchMaxBackoffMs) * time.Millisecond
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	// Full jitter keeps concurrent writers from retrying in lockstep
+	backoff = time.Duration(rand.Int63n(int64(backoff) + 1))
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(backoff):
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package dynamodb_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. DYNAMODB_TABLE_NAME for the dynamodb
+// scope.
+type Config struct {
+	TableName         string `mapstructure:"table_name" default:"example-table"`
+	TableKey          string `mapstructure:"table_key" default:"ABCDE"`
+	TableSecret       string `mapstructure:"table_secret" default:"example_secret"`
+	TableToken        string `mapstructure:"table_token" default:""`
+	TableRegion       string `mapstructure:"table_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+
+	BatchMaxRetries   int `mapstructure:"batch_max_retries" default:"5"`
+	BatchBackoffMs    int `mapstructure:"batch_backoff_ms" default:"50"`
+	BatchMaxBackoffMs int `mapstructure:"batch_max_backoff_ms" default:"2000"`
+
+	Versioned        bool   `mapstructure:"versioned" default:"false"`
+	VersionAttribute string `mapstructure:"version_attribute" default:"version"`
+
+	EnsureTable        bool        `mapstructure:"ensure_table" default:"false"`
+	TableHashKey       string      `mapstructure:"table_hash_key" default:"pk"`
+	TableHashKeyType   string      `mapstructure:"table_hash_key_type" default:"S"`
+	TableRangeKey      string      `mapstructure:"table_range_key" default:""`
+	TableRangeKeyType  string      `mapstructure:"table_range_key_type" default:"S"`
+	TableBillingMode   string      `mapstructure:"table_billing_mode" default:"PAY_PER_REQUEST"`
+	TableReadCapacity  int64       `mapstructure:"table_read_capacity" default:"5"`
+	TableWriteCapacity int64       `mapstructure:"table_write_capacity" default:"5"`
+	TableTTLAttribute  string      `mapstructure:"table_ttl_attribute" default:""`
+	TableActiveTimeout int         `mapstructure:"table_active_timeout" default:"120"`
+	TableGSIs          []GSIConfig `mapstructure:"table_gsis"`
+
+	DAXMode     string `mapstructure:"dax_mode" default:"off"`
+	DAXEndpoint string `mapstructure:"dax_endpoint" default:""`
+}
+
+func (c *DynamoDBConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *DynamoDBConnector) Config() Config {
+	return c.config
+}
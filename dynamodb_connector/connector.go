@@ -0,0 +1,439 @@
+package dynamodb_connector
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+var ErrNotFound = awserrors.New(awserrors.ErrNotFound, "item not found")
+
+const (
+	DefaultTableName   = "example-table"
+	DefaultTableKey    = "ABCDE"
+	DefaultTableSecret = "example_secret"
+	DefaultTableToken  = ""
+	DefaultTableRegion = "us-west-1"
+)
+
+var preflightActions = []string{
+	"dynamodb:GetItem",
+	"dynamodb:PutItem",
+	"dynamodb:UpdateItem",
+	"dynamodb:DeleteItem",
+	"dynamodb:Query",
+	"dynamodb:Scan",
+	"dynamodb:BatchGetItem",
+	"dynamodb:BatchWriteItem",
+	"dynamodb:DescribeTable",
+}
+
+type DynamoDBConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *dynamodb.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	reads  DataPlaneAPI
+	writes DataPlaneAPI
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	DAX             DAXClientFunc           `optional:"true"`
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *dynamodb.Client        `optional:"true"`
+}
+
+// Module provides the DynamoDBConnector of scope. It uses the
+// *dynamodb.Client in the graph, if any, instead of building one, and
+// adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the DynamoDBConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *dynamodb.Client named after scope, if
+// any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *DynamoDBConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*dynamodb.Client](scope, named),
+		instance.Provide[*DynamoDBConnector](scope, named, func(p Params) *DynamoDBConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &DynamoDBConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *DynamoDBConnector) Table {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *DynamoDBConnector) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting DynamoDBConnector",
+		zap.String("table_name", c.config.TableName),
+		zap.String("table_region", c.config.TableRegion),
+		zap.String("dax_mode", c.config.DAXMode),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions,
+			"arn:{partition}:dynamodb:{region}:{account}:table/"+c.config.TableName,
+			"arn:{partition}:dynamodb:{region}:{account}:table/"+c.config.TableName+"/index/*",
+		)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = dynamodb.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = dynamodb.New(c.client.Options(), func(o *dynamodb.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	if err := c.setupDataPlane(ctx, cfg); err != nil {
+		return err
+	}
+
+	if c.config.EnsureTable {
+		if err := c.EnsureTable(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *DynamoDBConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped DynamoDBConnector")
+
+	return nil
+}
+
+func (c *DynamoDBConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.TableRegion,
+		Key:         c.config.TableKey,
+		Secret:      c.config.TableSecret,
+		Token:       c.config.TableToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *DynamoDBConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("table_region")
+
+	v.Required("table_name")
+	v.NotPlaceholder("table_name", DefaultTableName)
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("table_key", DefaultTableKey)
+		v.NotPlaceholder("table_secret", DefaultTableSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the table description.
+func (c *DynamoDBConnector) probe(ctx context.Context) error {
+	_, err := c.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(c.config.TableName),
+	})
+
+	return err
+}
+
+func (c *DynamoDBConnector) GetTableName() string {
+	return c.config.TableName
+}
+
+// PutItem marshals item with attributevalue and writes it to the table.
+// In versioned mode the write is rejected with ErrConditionFailed when the
+// stored version differs, and item (if a pointer) receives the new version.
+func (c *DynamoDBConnector) PutItem(ctx context.Context, item interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "PutItem")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	return c.putItem(ctx, av, item)
+}
+
+func (c *DynamoDBConnector) putItem(ctx context.Context, av map[string]types.AttributeValue, item interface{}) error {
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(c.GetTableName()),
+		Item:      av,
+	}
+
+	if c.versioned() {
+		expr, err := c.versionedPut(av)
+		if err != nil {
+			return err
+		}
+
+		input.ConditionExpression = expr.Condition()
+		input.ExpressionAttributeNames = expr.Names()
+		input.ExpressionAttributeValues = expr.Values()
+	}
+
+	_, err := c.writes.PutItem(ctx, input)
+	if err != nil {
+		c.logger.Error("Put item error", zap.Error(err))
+		return conditionError(err)
+	}
+
+	if c.versioned() {
+		return writeBack(av, item)
+	}
+
+	return nil
+}
+
+// GetItem loads the item identified by key into out, returning
+// ErrNotFound when it does not exist.
+func (c *DynamoDBConnector) GetItem(ctx context.Context, key interface{}, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "GetItem")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	k, err := attributevalue.MarshalMap(key)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.reads.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(c.GetTableName()),
+		Key:       k,
+	})
+	if err != nil {
+		c.logger.Error("Get item error", zap.Error(err))
+		return err
+	}
+
+	if result.Item == nil {
+		return ErrNotFound
+	}
+
+	return attributevalue.UnmarshalMap(result.Item, out)
+}
+
+// UpdateItem sets the given attributes on the item identified by key. When
+// out is not nil it receives the item as it is after the update. In
+// versioned mode updates must carry the expected current version, which
+// is incremented on success.
+func (c *DynamoDBConnector) UpdateItem(ctx context.Context, key interface{}, updates map[string]interface{}, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "UpdateItem")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if len(updates) == 0 {
+		return fmt.Errorf("no attributes to update")
+	}
+
+	k, err := attributevalue.MarshalMap(key)
+	if err != nil {
+		return err
+	}
+
+	var update expression.UpdateBuilder
+	for name, value := range updates {
+		if c.versioned() && name == c.versionAttribute() {
+			continue
+		}
+
+		update = update.Set(expression.Name(name), expression.Value(value))
+	}
+
+	var expr expression.Expression
+	if c.versioned() {
+		expr, err = c.versionedUpdate(update, updates)
+	} else {
+		expr, err = expression.NewBuilder().WithUpdate(update).Build()
+	}
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(c.GetTableName()),
+		Key:                       k,
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+
+	if out != nil {
+		input.ReturnValues = types.ReturnValueAllNew
+	}
+
+	result, err := c.writes.UpdateItem(ctx, input)
+	if err != nil {
+		c.logger.Error("Update item error", zap.Error(err))
+		return conditionError(err)
+	}
+
+	if out != nil {
+		return attributevalue.UnmarshalMap(result.Attributes, out)
+	}
+
+	return nil
+}
+
+func (c *DynamoDBConnector) DeleteItem(ctx context.Context, key interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "DeleteItem")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	k, err := attributevalue.MarshalMap(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.writes.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(c.GetTableName()),
+		Key:       k,
+	})
+	if err != nil {
+		c.logger.Error("Delete item error", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *DynamoDBConnector) GetClient() *dynamodb.Client {
+	return c.client
+}
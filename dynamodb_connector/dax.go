@@ -0,0 +1,94 @@
+package dynamodb_connector
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// DataPlaneAPI is the subset of DynamoDB operations the connector uses to
+// read and write items. Both *dynamodb.Client and DAX clients implement it.
+type DataPlaneAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+}
+
+// DAXClientFunc builds a DAX client for the cluster at endpoint, from the
+// connector's AWS config. Apps using DAX provide one, wrapping the DAX
+// SDK, and each connector calls it with its own dax_endpoint, so named
+// modules use their own clusters:
+//
+//	fx.Provide(func() dynamodb_connector.DAXClientFunc {
+//		return func(ctx context.Context, cfg aws.Config, endpoint string) (dynamodb_connector.DataPlaneAPI, error) {
+//			daxCfg := dax.NewConfig(cfg, endpoint)
+//			return dax.New(daxCfg)
+//		}
+//	})
+type DAXClientFunc func(ctx context.Context, cfg aws.Config, endpoint string) (DataPlaneAPI, error)
+
+const (
+	DAXModeOff   = "off"
+	DAXModeReads = "reads"
+	DAXModeAll   = "all"
+)
+
+const (
+	DefaultDAXMode     = DAXModeOff
+	DefaultDAXEndpoint = ""
+)
+
+// setupDataPlane routes item operations through a DAX client for the
+// cluster at dax_endpoint according to dax_mode: "all" sends reads and
+// writes to DAX, "reads" sends only reads. DAX updates its item cache
+// only on writes through it, so in "reads" mode reads return stale items
+// until the cache TTL expires; use it only for tables that tolerate
+// that. Table management always uses the standard client.
+func (c *DynamoDBConnector) setupDataPlane(ctx context.Context, cfg aws.Config) error {
+	c.reads = c.client
+	c.writes = c.client
+
+	mode := c.config.DAXMode
+	switch mode {
+	case DAXModeOff:
+		return nil
+	case DAXModeReads, DAXModeAll:
+	default:
+		return fmt.Errorf("%s: unknown dax_mode %q", c.scope, mode)
+	}
+
+	endpoint := c.config.DAXEndpoint
+	if endpoint == "" {
+		return fmt.Errorf("%s: dax_mode %q requires dax_endpoint", c.scope, mode)
+	}
+
+	if c.params.DAX == nil {
+		return fmt.Errorf("%s: dax_mode %q requires a DAXClientFunc in the fx graph", c.scope, mode)
+	}
+
+	dax, err := c.params.DAX(ctx, cfg, endpoint)
+	if err != nil {
+		c.logger.Error("Create DAX client error", zap.String("dax_endpoint", endpoint), zap.Error(err))
+		return err
+	}
+
+	c.reads = dax
+	if mode == DAXModeAll {
+		c.writes = dax
+	} else {
+		c.logger.Warn("DAX serves reads only; items written here stay stale in its cache until the TTL expires",
+			zap.String("dax_endpoint", endpoint),
+		)
+	}
+
+	return nil
+}
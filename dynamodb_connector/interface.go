@@ -0,0 +1,21 @@
+package dynamodb_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Table
+
+// Table reads and writes items of the table.
+type Table interface {
+	PutItem(ctx context.Context, item interface{}) error
+	GetItem(ctx context.Context, key interface{}, out interface{}) error
+	UpdateItem(ctx context.Context, key interface{}, updates map[string]interface{}, out interface{}) error
+	DeleteItem(ctx context.Context, key interface{}) error
+	QueryPrefix(ctx context.Context, pk string, skPrefix string, out interface{}) error
+	BatchGet(ctx context.Context, keys []interface{}, out interface{}) error
+	BatchWrite(ctx context.Context, puts []interface{}, deletes []interface{}) error
+}
+
+var (
+	_ Table = (*DynamoDBConnector)(nil)
+	_ Table = (*MemoryTable)(nil)
+)
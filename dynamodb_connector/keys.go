@@ -0,0 +1,118 @@
+package dynamodb_connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+)
+
+// Single-table design conventions: items carry generic pk/sk keys,
+// overloaded gsiNpk/gsiNsk index keys, and an entity_type discriminator.
+const (
+	KeySeparator = "#"
+
+	AttrPK         = "pk"
+	AttrSK         = "sk"
+	AttrEntityType = "entity_type"
+)
+
+// Keys can be embedded in entity structs to carry the generic key
+// attributes. Unused GSI keys are omitted so items stay out of sparse
+// indexes.
+type Keys struct {
+	PK         string `dynamodbav:"pk"`
+	SK         string `dynamodbav:"sk"`
+	EntityType string `dynamodbav:"entity_type,omitempty"`
+	GSI1PK     string `dynamodbav:"gsi1pk,omitempty"`
+	GSI1SK     string `dynamodbav:"gsi1sk,omitempty"`
+	GSI2PK     string `dynamodbav:"gsi2pk,omitempty"`
+	GSI2SK     string `dynamodbav:"gsi2sk,omitempty"`
+}
+
+// Key joins parts with KeySeparator, e.g. Key("USER", id) is "USER#<id>".
+func Key(parts ...string) string {
+	return strings.Join(parts, KeySeparator)
+}
+
+// SplitKey is the inverse of Key.
+func SplitKey(key string) []string {
+	return strings.Split(key, KeySeparator)
+}
+
+// KeyPrefix returns Key(parts...) followed by the separator, for use
+// with begins_with so "ORDER#1" does not match "ORDER#10".
+func KeyPrefix(parts ...string) string {
+	return Key(parts...) + KeySeparator
+}
+
+// PrimaryKey returns the pk/sk key map for GetItem/DeleteItem.
+func PrimaryKey(pk string, sk string) map[string]string {
+	return map[string]string{
+		AttrPK: pk,
+		AttrSK: sk,
+	}
+}
+
+func GSIName(n int) string {
+	return fmt.Sprintf("gsi%d", n)
+}
+
+func GSIKeyAttributes(n int) (string, string) {
+	return fmt.Sprintf("gsi%dpk", n), fmt.Sprintf("gsi%dsk", n)
+}
+
+// KeyBeginsWith matches pkAttr = pk and, when skPrefix is not empty,
+// begins_with(skAttr, skPrefix).
+func KeyBeginsWith(pkAttr string, pk string, skAttr string, skPrefix string) expression.KeyConditionBuilder {
+	cond := expression.Key(pkAttr).Equal(expression.Value(pk))
+	if skPrefix == "" {
+		return cond
+	}
+
+	return cond.And(expression.Key(skAttr).BeginsWith(skPrefix))
+}
+
+func EntityTypeIs(entityType string) expression.ConditionBuilder {
+	return expression.Name(AttrEntityType).Equal(expression.Value(entityType))
+}
+
+// QueryPrefix returns the items of partition pk whose sort key starts
+// with skPrefix.
+func (c *DynamoDBConnector) QueryPrefix(ctx context.Context, pk string, skPrefix string, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "QueryPrefix")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.Query(ctx, QueryInput{
+		KeyCondition: KeyBeginsWith(AttrPK, pk, AttrSK, skPrefix),
+	}, out)
+}
+
+// QueryEntities returns the items of partition pk with the given
+// entity_type.
+func (c *DynamoDBConnector) QueryEntities(ctx context.Context, pk string, entityType string, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "QueryEntities")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	filter := EntityTypeIs(entityType)
+
+	return c.Query(ctx, QueryInput{
+		KeyCondition: KeyBeginsWith(AttrPK, pk, AttrSK, ""),
+		Filter:       &filter,
+	}, out)
+}
+
+// QueryGSIPrefix queries the overloaded index gsiN by its pk and sort key
+// prefix.
+func (c *DynamoDBConnector) QueryGSIPrefix(ctx context.Context, n int, pk string, skPrefix string, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "QueryGSIPrefix")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	pkAttr, skAttr := GSIKeyAttributes(n)
+
+	return c.Query(ctx, QueryInput{
+		IndexName:    GSIName(n),
+		KeyCondition: KeyBeginsWith(pkAttr, pk, skAttr, skPrefix),
+	}, out)
+}
@@ -0,0 +1,217 @@
+package dynamodb_connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type memoryKey struct {
+	pk string
+	sk string
+}
+
+// MemoryTable is an in-process Table for tests, keyed by the string pk
+// and optional sk attributes of the single-table design. It has no
+// versioning or TTL.
+type MemoryTable struct {
+	mu    sync.RWMutex
+	items map[memoryKey]map[string]types.AttributeValue
+}
+
+func NewMemoryTable() *MemoryTable {
+	return &MemoryTable{
+		items: make(map[memoryKey]map[string]types.AttributeValue),
+	}
+}
+
+func (t *MemoryTable) PutItem(ctx context.Context, item interface{}) error {
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	k, err := memoryKeyOf(av)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.items[k] = av
+	t.mu.Unlock()
+
+	return nil
+}
+
+func (t *MemoryTable) GetItem(ctx context.Context, key interface{}, out interface{}) error {
+	k, err := marshalMemoryKey(key)
+	if err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	av, ok := t.items[k]
+	t.mu.RUnlock()
+
+	if !ok {
+		return ErrNotFound
+	}
+
+	return attributevalue.UnmarshalMap(av, out)
+}
+
+// UpdateItem sets the given attributes, creating the item when it does
+// not exist, as DynamoDB does.
+func (t *MemoryTable) UpdateItem(ctx context.Context, key interface{}, updates map[string]interface{}, out interface{}) error {
+	if len(updates) == 0 {
+		return fmt.Errorf("no attributes to update")
+	}
+
+	keyAV, err := attributevalue.MarshalMap(key)
+	if err != nil {
+		return err
+	}
+
+	k, err := memoryKeyOf(keyAV)
+	if err != nil {
+		return err
+	}
+
+	values, err := attributevalue.MarshalMap(updates)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	item := make(map[string]types.AttributeValue, len(keyAV)+len(values))
+	for name, value := range t.items[k] {
+		item[name] = value
+	}
+	for name, value := range keyAV {
+		item[name] = value
+	}
+	for name, value := range values {
+		item[name] = value
+	}
+	t.items[k] = item
+	t.mu.Unlock()
+
+	if out != nil {
+		return attributevalue.UnmarshalMap(item, out)
+	}
+
+	return nil
+}
+
+func (t *MemoryTable) DeleteItem(ctx context.Context, key interface{}) error {
+	k, err := marshalMemoryKey(key)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	delete(t.items, k)
+	t.mu.Unlock()
+
+	return nil
+}
+
+// QueryPrefix returns the items of pk whose sk begins with skPrefix, in
+// sk order.
+func (t *MemoryTable) QueryPrefix(ctx context.Context, pk string, skPrefix string, out interface{}) error {
+	t.mu.RLock()
+	var keys []memoryKey
+	for k := range t.items {
+		if k.pk == pk && strings.HasPrefix(k.sk, skPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].sk < keys[j].sk
+	})
+
+	items := make([]map[string]types.AttributeValue, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, t.items[k])
+	}
+	t.mu.RUnlock()
+
+	return attributevalue.UnmarshalListOfMaps(items, out)
+}
+
+// BatchGet loads the items of keys that exist into out, a pointer to a
+// slice.
+func (t *MemoryTable) BatchGet(ctx context.Context, keys []interface{}, out interface{}) error {
+	items := make([]map[string]types.AttributeValue, 0, len(keys))
+
+	for _, key := range keys {
+		k, err := marshalMemoryKey(key)
+		if err != nil {
+			return err
+		}
+
+		t.mu.RLock()
+		av, ok := t.items[k]
+		t.mu.RUnlock()
+
+		if ok {
+			items = append(items, av)
+		}
+	}
+
+	return attributevalue.UnmarshalListOfMaps(items, out)
+}
+
+func (t *MemoryTable) BatchWrite(ctx context.Context, puts []interface{}, deletes []interface{}) error {
+	for _, item := range puts {
+		if err := t.PutItem(ctx, item); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range deletes {
+		if err := t.DeleteItem(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of items in the table.
+func (t *MemoryTable) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.items)
+}
+
+func marshalMemoryKey(key interface{}) (memoryKey, error) {
+	av, err := attributevalue.MarshalMap(key)
+	if err != nil {
+		return memoryKey{}, err
+	}
+
+	return memoryKeyOf(av)
+}
+
+func memoryKeyOf(av map[string]types.AttributeValue) (memoryKey, error) {
+	var k memoryKey
+
+	pk, ok := av[AttrPK].(*types.AttributeValueMemberS)
+	if !ok {
+		return k, fmt.Errorf("item has no string %s attribute", AttrPK)
+	}
+	k.pk = pk.Value
+
+	if sk, ok := av[AttrSK].(*types.AttributeValueMemberS); ok {
+		k.sk = sk.Value
+	}
+
+	return k, nil
+}
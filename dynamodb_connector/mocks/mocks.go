@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/dynamodb_connector (interfaces: Table)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . Table
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTable is a mock of Table interface.
+type MockTable struct {
+	ctrl     *gomock.Controller
+	recorder *MockTableMockRecorder
+}
+
+// MockTableMockRecorder is the mock recorder for MockTable.
+type MockTableMockRecorder struct {
+	mock *MockTable
+}
+
+// NewMockTable creates a new mock instance.
+func NewMockTable(ctrl *gomock.Controller) *MockTable {
+	mock := &MockTable{ctrl: ctrl}
+	mock.recorder = &MockTableMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTable) EXPECT() *MockTableMockRecorder {
+	return m.recorder
+}
+
+// BatchGet mocks base method.
+func (m *MockTable) BatchGet(arg0 context.Context, arg1 []any, arg2 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGet", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchGet indicates an expected call of BatchGet.
+func (mr *MockTableMockRecorder) BatchGet(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGet", reflect.TypeOf((*MockTable)(nil).BatchGet), arg0, arg1, arg2)
+}
+
+// BatchWrite mocks base method.
+func (m *MockTable) BatchWrite(arg0 context.Context, arg1, arg2 []any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchWrite", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchWrite indicates an expected call of BatchWrite.
+func (mr *MockTableMockRecorder) BatchWrite(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchWrite", reflect.TypeOf((*MockTable)(nil).BatchWrite), arg0, arg1, arg2)
+}
+
+// DeleteItem mocks base method.
+func (m *MockTable) DeleteItem(arg0 context.Context, arg1 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItem", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockTableMockRecorder) DeleteItem(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockTable)(nil).DeleteItem), arg0, arg1)
+}
+
+// GetItem mocks base method.
+func (m *MockTable) GetItem(arg0 context.Context, arg1, arg2 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockTableMockRecorder) GetItem(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockTable)(nil).GetItem), arg0, arg1, arg2)
+}
+
+// PutItem mocks base method.
+func (m *MockTable) PutItem(arg0 context.Context, arg1 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutItem", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutItem indicates an expected call of PutItem.
+func (mr *MockTableMockRecorder) PutItem(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutItem", reflect.TypeOf((*MockTable)(nil).PutItem), arg0, arg1)
+}
+
+// QueryPrefix mocks base method.
+func (m *MockTable) QueryPrefix(arg0 context.Context, arg1, arg2 string, arg3 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryPrefix", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueryPrefix indicates an expected call of QueryPrefix.
+func (mr *MockTableMockRecorder) QueryPrefix(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPrefix", reflect.TypeOf((*MockTable)(nil).QueryPrefix), arg0, arg1, arg2, arg3)
+}
+
+// UpdateItem mocks base method.
+func (m *MockTable) UpdateItem(arg0 context.Context, arg1 any, arg2 map[string]any, arg3 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItem", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockTableMockRecorder) UpdateItem(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockTable)(nil).UpdateItem), arg0, arg1, arg2, arg3)
+}
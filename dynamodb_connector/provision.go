@@ -0,0 +1,202 @@
+package dynamodb_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	DefaultEnsureTable        = false
+	DefaultTableHashKey       = "pk"
+	DefaultTableHashKeyType   = "S"
+	DefaultTableRangeKey      = ""
+	DefaultTableRangeKeyType  = "S"
+	DefaultTableBillingMode   = string(types.BillingModePayPerRequest)
+	DefaultTableReadCapacity  = 5
+	DefaultTableWriteCapacity = 5
+	DefaultTableTTLAttribute  = ""
+	DefaultTableActiveTimeout = 120
+)
+
+// GSIConfig describes a global secondary index under table_gsis.
+type GSIConfig struct {
+	Name           string `mapstructure:"name"`
+	HashKey        string `mapstructure:"hash_key"`
+	HashKeyType    string `mapstructure:"hash_key_type"`
+	RangeKey       string `mapstructure:"range_key"`
+	RangeKeyType   string `mapstructure:"range_key_type"`
+	ProjectionType string `mapstructure:"projection_type"`
+}
+
+// EnsureTable creates the configured table when it does not exist, or
+// validates the key schema and indexes of an existing one, then enables
+// TTL if table_ttl_attribute is set.
+func (c *DynamoDBConnector) EnsureTable(ctx context.Context) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "EnsureTable")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	tableName := c.GetTableName()
+
+	gsis := c.config.TableGSIs
+
+	result, err := c.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err == nil {
+		c.logger.Info("Table exists", zap.String("table_name", tableName))
+
+		if err := c.validateTable(result.Table, gsis); err != nil {
+			return err
+		}
+
+		return c.ensureTTL(ctx)
+	}
+
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		c.logger.Error("Describe table error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Creating table", zap.String("table_name", tableName))
+
+	if _, err := c.client.CreateTable(ctx, c.createTableInput(gsis)); err != nil {
+		c.logger.Error("Create table error", zap.Error(err))
+		return err
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(c.client)
+	timeout := time.Duration(c.config.TableActiveTimeout) * time.Second
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}, timeout); err != nil {
+		return err
+	}
+
+	return c.ensureTTL(ctx)
+}
+
+func (c *DynamoDBConnector) createTableInput(gsis []GSIConfig) *dynamodb.CreateTableInput {
+	definitions := map[string]types.ScalarAttributeType{}
+
+	keySchema := func(hashKey string, hashKeyType string, rangeKey string, rangeKeyType string) []types.KeySchemaElement {
+		definitions[hashKey] = types.ScalarAttributeType(hashKeyType)
+		schema := []types.KeySchemaElement{
+			{AttributeName: aws.String(hashKey), KeyType: types.KeyTypeHash},
+		}
+
+		if rangeKey != "" {
+			definitions[rangeKey] = types.ScalarAttributeType(rangeKeyType)
+			schema = append(schema, types.KeySchemaElement{AttributeName: aws.String(rangeKey), KeyType: types.KeyTypeRange})
+		}
+
+		return schema
+	}
+
+	billingMode := types.BillingMode(c.config.TableBillingMode)
+
+	var throughput *types.ProvisionedThroughput
+	if billingMode == types.BillingModeProvisioned {
+		throughput = &types.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(c.config.TableReadCapacity),
+			WriteCapacityUnits: aws.Int64(c.config.TableWriteCapacity),
+		}
+	}
+
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(c.GetTableName()),
+		KeySchema: keySchema(
+			c.config.TableHashKey,
+			c.config.TableHashKeyType,
+			c.config.TableRangeKey,
+			c.config.TableRangeKeyType,
+		),
+		BillingMode:           billingMode,
+		ProvisionedThroughput: throughput,
+	}
+
+	for _, gsi := range gsis {
+		projection := types.ProjectionTypeAll
+		if gsi.ProjectionType != "" {
+			projection = types.ProjectionType(gsi.ProjectionType)
+		}
+
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, types.GlobalSecondaryIndex{
+			IndexName:             aws.String(gsi.Name),
+			KeySchema:             keySchema(gsi.HashKey, keyType(gsi.HashKeyType), gsi.RangeKey, keyType(gsi.RangeKeyType)),
+			Projection:            &types.Projection{ProjectionType: projection},
+			ProvisionedThroughput: throughput,
+		})
+	}
+
+	for name, attrType := range definitions {
+		input.AttributeDefinitions = append(input.AttributeDefinitions, types.AttributeDefinition{
+			AttributeName: aws.String(name),
+			AttributeType: attrType,
+		})
+	}
+
+	return input
+}
+
+func (c *DynamoDBConnector) validateTable(table *types.TableDescription, gsis []GSIConfig) error {
+	var problems []error
+
+	expected := map[types.KeyType]string{
+		types.KeyTypeHash: c.config.TableHashKey,
+	}
+	if rangeKey := c.config.TableRangeKey; rangeKey != "" {
+		expected[types.KeyTypeRange] = rangeKey
+	}
+
+	actual := map[types.KeyType]string{}
+	for _, k := range table.KeySchema {
+		actual[k.KeyType] = aws.ToString(k.AttributeName)
+	}
+
+	for keyType, name := range expected {
+		if actual[keyType] != name {
+			problems = append(problems, fmt.Errorf("%s key is %q, expected %q", keyType, actual[keyType], name))
+		}
+	}
+
+	existing := map[string]bool{}
+	for _, gsi := range table.GlobalSecondaryIndexes {
+		existing[aws.ToString(gsi.IndexName)] = true
+	}
+
+	for _, gsi := range gsis {
+		if !existing[gsi.Name] {
+			problems = append(problems, fmt.Errorf("global secondary index %q is missing", gsi.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("table %s does not match configuration: %w", aws.ToString(table.TableName), errors.Join(problems...))
+	}
+
+	return nil
+}
+
+func (c *DynamoDBConnector) ensureTTL(ctx context.Context) error {
+	attr := c.ttlAttribute()
+	if attr == "" {
+		return nil
+	}
+
+	return c.EnableTTL(ctx, attr)
+}
+
+func keyType(t string) string {
+	if t == "" {
+		return "S"
+	}
+
+	return t
+}
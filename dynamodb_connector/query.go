@@ -0,0 +1,238 @@
+package dynamodb_connector
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrStopIteration can be returned from an ItemFunc to end a Query or
+// Scan early without reporting an error.
+var ErrStopIteration = errors.New("stop iteration")
+
+// Item is a raw DynamoDB item as streamed to an ItemFunc.
+type Item map[string]types.AttributeValue
+
+func (i Item) Unmarshal(out interface{}) error {
+	return attributevalue.UnmarshalMap(i, out)
+}
+
+type ItemFunc func(item Item) error
+
+type QueryInput struct {
+	IndexName      string
+	KeyCondition   expression.KeyConditionBuilder
+	Filter         *expression.ConditionBuilder
+	Projection     *expression.ProjectionBuilder
+	PageSize       int32
+	Descending     bool
+	ConsistentRead bool
+
+	// ExcludeExpired filters out items past table_ttl_attribute that
+	// DynamoDB has not deleted yet.
+	ExcludeExpired bool
+}
+
+type ScanInput struct {
+	IndexName      string
+	Filter         *expression.ConditionBuilder
+	Projection     *expression.ProjectionBuilder
+	PageSize       int32
+	ConsistentRead bool
+	ExcludeExpired bool
+}
+
+// Query runs q across all pages and unmarshals every item into out, which
+// must be a pointer to a slice.
+func (c *DynamoDBConnector) Query(ctx context.Context, q QueryInput, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "Query")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return collect(out, func(fn ItemFunc) error {
+		return c.QueryEach(ctx, q, fn)
+	})
+}
+
+// QueryEach runs q across all pages and calls fn for every item.
+func (c *DynamoDBConnector) QueryEach(ctx context.Context, q QueryInput, fn ItemFunc) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "QueryEach")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if q.ExcludeExpired {
+		filter, err := c.withNotExpired(q.Filter)
+		if err != nil {
+			return err
+		}
+
+		q.Filter = filter
+	}
+
+	builder := expression.NewBuilder().WithKeyCondition(q.KeyCondition)
+	if q.Filter != nil {
+		builder = builder.WithFilter(*q.Filter)
+	}
+	if q.Projection != nil {
+		builder = builder.WithProjection(*q.Projection)
+	}
+
+	expr, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(c.GetTableName()),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(!q.Descending),
+		ConsistentRead:            aws.Bool(q.ConsistentRead),
+	}
+
+	if q.IndexName != "" {
+		input.IndexName = aws.String(q.IndexName)
+	}
+
+	if q.PageSize > 0 {
+		input.Limit = aws.Int32(q.PageSize)
+	}
+
+	paginator := dynamodb.NewQueryPaginator(c.reads, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("Query error", zap.Error(err))
+			return err
+		}
+
+		if err := each(page.Items, fn); err != nil {
+			return stopped(err)
+		}
+	}
+
+	return nil
+}
+
+// Scan runs s across all pages and unmarshals every item into out, which
+// must be a pointer to a slice.
+func (c *DynamoDBConnector) Scan(ctx context.Context, s ScanInput, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "Scan")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return collect(out, func(fn ItemFunc) error {
+		return c.ScanEach(ctx, s, fn)
+	})
+}
+
+// ScanEach runs s across all pages and calls fn for every item.
+func (c *DynamoDBConnector) ScanEach(ctx context.Context, s ScanInput, fn ItemFunc) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "ScanEach")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if s.ExcludeExpired {
+		filter, err := c.withNotExpired(s.Filter)
+		if err != nil {
+			return err
+		}
+
+		s.Filter = filter
+	}
+
+	input := &dynamodb.ScanInput{
+		TableName:      aws.String(c.GetTableName()),
+		ConsistentRead: aws.Bool(s.ConsistentRead),
+	}
+
+	if s.Filter != nil || s.Projection != nil {
+		builder := expression.NewBuilder()
+		if s.Filter != nil {
+			builder = builder.WithFilter(*s.Filter)
+		}
+		if s.Projection != nil {
+			builder = builder.WithProjection(*s.Projection)
+		}
+
+		expr, err := builder.Build()
+		if err != nil {
+			return err
+		}
+
+		input.FilterExpression = expr.Filter()
+		input.ProjectionExpression = expr.Projection()
+		input.ExpressionAttributeNames = expr.Names()
+		input.ExpressionAttributeValues = expr.Values()
+	}
+
+	if s.IndexName != "" {
+		input.IndexName = aws.String(s.IndexName)
+	}
+
+	if s.PageSize > 0 {
+		input.Limit = aws.Int32(s.PageSize)
+	}
+
+	paginator := dynamodb.NewScanPaginator(c.reads, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("Scan error", zap.Error(err))
+			return err
+		}
+
+		if err := each(page.Items, fn); err != nil {
+			return stopped(err)
+		}
+	}
+
+	return nil
+}
+
+func each(items []map[string]types.AttributeValue, fn ItemFunc) error {
+	for _, item := range items {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func stopped(err error) error {
+	if errors.Is(err, ErrStopIteration) {
+		return nil
+	}
+
+	return err
+}
+
+// collect appends every streamed item to the slice out points to.
+func collect(out interface{}, run func(fn ItemFunc) error) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return errors.New("out must be a pointer to a slice")
+	}
+
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+
+	return run(func(item Item) error {
+		elem := reflect.New(elemType)
+		if err := item.Unmarshal(elem.Interface()); err != nil {
+			return err
+		}
+
+		slice.Set(reflect.Append(slice, elem.Elem()))
+
+		return nil
+	})
+}
@@ -0,0 +1,127 @@
+package dynamodb_connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrTTLNotConfigured = awserrors.New(awserrors.ErrValidation, "table_ttl_attribute is not configured")
+
+var ErrTTLAttributeMismatch = awserrors.New(awserrors.ErrConflict, "ttl is enabled on another attribute")
+
+func (c *DynamoDBConnector) ttlAttribute() string {
+	return c.config.TableTTLAttribute
+}
+
+func (c *DynamoDBConnector) DescribeTTL(ctx context.Context) (_ *types.TimeToLiveDescription, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "DescribeTTL")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(c.GetTableName()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.TimeToLiveDescription, nil
+}
+
+// EnableTTL turns on TTL for attribute unless it is already enabled. A
+// table has one TTL attribute, so TTL enabled on another one fails with
+// ErrTTLAttributeMismatch.
+func (c *DynamoDBConnector) EnableTTL(ctx context.Context, attribute string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "EnableTTL")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	d, err := c.DescribeTTL(ctx)
+	if err != nil {
+		return err
+	}
+
+	if d != nil && (d.TimeToLiveStatus == types.TimeToLiveStatusEnabled || d.TimeToLiveStatus == types.TimeToLiveStatusEnabling) {
+		if current := aws.ToString(d.AttributeName); current != attribute {
+			return fmt.Errorf("%w: %s, not %s", ErrTTLAttributeMismatch, current, attribute)
+		}
+
+		return nil
+	}
+
+	c.logger.Info("Enabling TTL", zap.String("attribute", attribute))
+
+	_, err = c.client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(c.GetTableName()),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(attribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		c.logger.Error("Enable TTL error", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ExpiresAt returns the epoch seconds value DynamoDB TTL expects for an
+// item expiring after ttl.
+func ExpiresAt(ttl time.Duration) int64 {
+	return time.Now().Add(ttl).Unix()
+}
+
+// PutItemWithTTL writes item like PutItem with table_ttl_attribute set to
+// expire after ttl.
+func (c *DynamoDBConnector) PutItemWithTTL(ctx context.Context, item interface{}, ttl time.Duration) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "DynamoDBConnector", "PutItemWithTTL")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	attr := c.ttlAttribute()
+	if attr == "" {
+		return ErrTTLNotConfigured
+	}
+
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	av[attr] = &types.AttributeValueMemberN{Value: strconv.FormatInt(ExpiresAt(ttl), 10)}
+
+	return c.putItem(ctx, av, item)
+}
+
+// NotExpired matches items whose TTL attribute is unset or still in the
+// future. DynamoDB deletes expired items lazily, so reads should filter
+// them out explicitly.
+func NotExpired(attribute string) expression.ConditionBuilder {
+	return expression.AttributeNotExists(expression.Name(attribute)).
+		Or(expression.Name(attribute).GreaterThan(expression.Value(time.Now().Unix())))
+}
+
+// withNotExpired adds the NotExpired condition on table_ttl_attribute to
+// filter.
+func (c *DynamoDBConnector) withNotExpired(filter *expression.ConditionBuilder) (*expression.ConditionBuilder, error) {
+	attr := c.ttlAttribute()
+	if attr == "" {
+		return nil, ErrTTLNotConfigured
+	}
+
+	cond := NotExpired(attr)
+	if filter != nil {
+		cond = filter.And(cond)
+	}
+
+	return &cond, nil
+}
@@ -0,0 +1,111 @@
+package dynamodb_connector
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// ErrConditionFailed is returned when a conditional write is rejected,
+// e.g. because a versioned item was modified concurrently. Callers should
+// reload the item and retry.
+var ErrConditionFailed = awserrors.New(awserrors.ErrConflict, "condition check failed")
+
+const (
+	DefaultVersioned        = false
+	DefaultVersionAttribute = "version"
+)
+
+func (c *DynamoDBConnector) versioned() bool {
+	return c.config.Versioned
+}
+
+func (c *DynamoDBConnector) versionAttribute() string {
+	return c.config.VersionAttribute
+}
+
+// versionedPut bumps the version in item and returns the condition that
+// the stored item still has the previous version (or does not exist yet).
+func (c *DynamoDBConnector) versionedPut(item map[string]types.AttributeValue) (expression.Expression, error) {
+	attr := c.versionAttribute()
+
+	current, err := versionOf(item[attr])
+	if err != nil {
+		return expression.Expression{}, fmt.Errorf("%s: %w", attr, err)
+	}
+
+	item[attr] = &types.AttributeValueMemberN{Value: strconv.FormatInt(current+1, 10)}
+
+	cond := expression.AttributeNotExists(expression.Name(attr))
+	if current > 0 {
+		cond = expression.Name(attr).Equal(expression.Value(current))
+	}
+
+	return expression.NewBuilder().WithCondition(cond).Build()
+}
+
+// versionedUpdate replaces the expected version in updates with the next
+// one and adds the matching condition.
+func (c *DynamoDBConnector) versionedUpdate(update expression.UpdateBuilder, updates map[string]interface{}) (expression.Expression, error) {
+	attr := c.versionAttribute()
+
+	value, ok := updates[attr]
+	if !ok {
+		return expression.Expression{}, fmt.Errorf("versioned update requires the current %s value", attr)
+	}
+
+	av, err := attributevalue.Marshal(value)
+	if err != nil {
+		return expression.Expression{}, err
+	}
+
+	current, err := versionOf(av)
+	if err != nil {
+		return expression.Expression{}, fmt.Errorf("%s: %w", attr, err)
+	}
+
+	update = update.Set(expression.Name(attr), expression.Value(current+1))
+
+	return expression.NewBuilder().
+		WithUpdate(update).
+		WithCondition(expression.Name(attr).Equal(expression.Value(current))).
+		Build()
+}
+
+func versionOf(av types.AttributeValue) (int64, error) {
+	switch v := av.(type) {
+	case nil:
+		return 0, nil
+	case *types.AttributeValueMemberNULL:
+		return 0, nil
+	case *types.AttributeValueMemberN:
+		return strconv.ParseInt(v.Value, 10, 64)
+	default:
+		return 0, fmt.Errorf("version must be a number, got %T", av)
+	}
+}
+
+// writeBack copies the stored item back into the caller's value so it
+// carries the new version.
+func writeBack(item map[string]types.AttributeValue, out interface{}) error {
+	if v := reflect.ValueOf(out); v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+
+	return attributevalue.UnmarshalMap(item, out)
+}
+
+func conditionError(err error) error {
+	var ccf *types.ConditionalCheckFailedException
+	if errors.As(err, &ccf) {
+		return fmt.Errorf("%w: %v", ErrConditionFailed, err)
+	}
+
+	return err
+}
@@ -0,0 +1,34 @@
+package dynamodb_lock
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. DYNAMODB_LOCK_TABLE_NAME for the
+// dynamodb_lock scope.
+type Config struct {
+	TableName         string `mapstructure:"table_name" default:"locks"`
+	LeaseDuration     int    `mapstructure:"lease_duration" default:"30"`
+	HeartbeatInterval int    `mapstructure:"heartbeat_interval" default:"10"`
+	AutoHeartbeat     bool   `mapstructure:"auto_heartbeat" default:"true"`
+	Owner             string `mapstructure:"owner"`
+}
+
+func (c *LockClient) initDefaultConfigs() {
+	hostname, _ := os.Hostname()
+
+	moduleconfig.Register(c.scope, &Config{
+		Owner: fmt.Sprintf("%s-%s", hostname, uuid.New().String()),
+	})
+}
+
+// Config returns the configuration the module started with.
+func (c *LockClient) Config() Config {
+	return c.config
+}
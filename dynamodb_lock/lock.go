@@ -0,0 +1,382 @@
+package dynamodb_lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/dynamodb_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+)
+
+var logger *zap.Logger
+
+var (
+	ErrLockHeld = awserrors.New(awserrors.ErrConflict, "lock is held by another owner")
+	ErrLockLost = awserrors.New(awserrors.ErrConflict, "lock lease was lost")
+)
+
+const (
+	DefaultTableName         = "locks"
+	DefaultLeaseDuration     = 30
+	DefaultHeartbeatInterval = 10
+	DefaultAutoHeartbeat     = true
+)
+
+// The lock table needs a string hash key named lock_key. Fencing tokens
+// survive releases, so they keep increasing for the lifetime of the item.
+const (
+	attrLockKey      = "lock_key"
+	attrOwner        = "owner"
+	attrLeaseExpires = "lease_expires_at"
+	attrFencingToken = "fencing_token"
+)
+
+// Lock is a held lease. FencingToken strictly increases with every
+// acquisition of the same key and should be passed to the protected
+// resource so stale holders can be rejected.
+type Lock struct {
+	Key          string
+	Owner        string
+	FencingToken int64
+
+	mu        sync.Mutex
+	expiresAt time.Time
+
+	lost chan struct{}
+	once sync.Once
+}
+
+// ExpiresAt returns when the lease runs out unless it is extended. The
+// heartbeat extends it as the lock is held.
+func (l *Lock) ExpiresAt() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.expiresAt
+}
+
+func (l *Lock) setExpiresAt(expiresAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expiresAt = expiresAt
+}
+
+// Lost is closed when the lease is released or could not be renewed.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *Lock) markLost() {
+	l.once.Do(func() {
+		close(l.lost)
+	})
+}
+
+type LockClient struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+	owner  string
+
+	mu     sync.Mutex
+	held   map[string]*Lock
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+	DynamoDB  *dynamodb_connector.DynamoDBConnector
+}
+
+func Module(scope string) fx.Option {
+
+	var c *LockClient
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) *LockClient {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &LockClient{
+				params: p,
+				logger: logger,
+				scope:  scope,
+				held:   make(map[string]*Lock),
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *LockClient) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.owner = c.config.Owner
+
+	logger.Info("Starting LockClient",
+		zap.String("table_name", c.tableName()),
+		zap.String("owner", c.owner),
+	)
+
+	if c.config.AutoHeartbeat {
+		loopCtx, cancel := context.WithCancel(context.Background())
+		c.cancel = cancel
+		c.done = make(chan struct{})
+
+		go c.heartbeatLoop(loopCtx)
+	}
+
+	return nil
+}
+
+func (c *LockClient) onStop(ctx context.Context) error {
+
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+
+	c.mu.Lock()
+	locks := make([]*Lock, 0, len(c.held))
+	for _, l := range c.held {
+		locks = append(locks, l)
+	}
+	c.mu.Unlock()
+
+	for _, l := range locks {
+		if err := c.Release(ctx, l); err != nil {
+			c.logger.Warn("Release lock on shutdown error", zap.String("key", l.Key), zap.Error(err))
+		}
+	}
+
+	c.logger.Info("Stopped LockClient")
+
+	return nil
+}
+
+func (c *LockClient) tableName() string {
+	return c.config.TableName
+}
+
+func (c *LockClient) leaseDuration() time.Duration {
+	return time.Duration(c.config.LeaseDuration) * time.Second
+}
+
+func (c *LockClient) lockKey(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		attrLockKey: &types.AttributeValueMemberS{Value: key},
+	}
+}
+
+// AcquireLock takes the lease on key if it is free, expired, or already
+// held by this client. It returns ErrLockHeld otherwise.
+func (c *LockClient) AcquireLock(ctx context.Context, key string) (*Lock, error) {
+	now := time.Now()
+	expiresAt := now.Add(c.leaseDuration())
+
+	update := expression.
+		Set(expression.Name(attrOwner), expression.Value(c.owner)).
+		Set(expression.Name(attrLeaseExpires), expression.Value(expiresAt.UnixMilli())).
+		Add(expression.Name(attrFencingToken), expression.Value(1))
+
+	cond := expression.AttributeNotExists(expression.Name(attrLockKey)).
+		Or(expression.Name(attrLeaseExpires).LessThan(expression.Value(now.UnixMilli()))).
+		Or(expression.Name(attrOwner).Equal(expression.Value(c.owner)))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.params.DynamoDB.GetClient().UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(c.tableName()),
+		Key:                       c.lockKey(key),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return nil, ErrLockHeld
+		}
+
+		c.logger.Error("Acquire lock error", zap.String("key", key), zap.Error(err))
+		return nil, err
+	}
+
+	var token int64
+	if err := attributevalue.Unmarshal(result.Attributes[attrFencingToken], &token); err != nil {
+		return nil, err
+	}
+
+	l := &Lock{
+		Key:          key,
+		Owner:        c.owner,
+		FencingToken: token,
+		expiresAt:    expiresAt,
+		lost:         make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	if previous, ok := c.held[key]; ok {
+		previous.markLost()
+	}
+	c.held[key] = l
+	c.mu.Unlock()
+
+	c.logger.Info("Acquired lock", zap.String("key", key), zap.Int64("fencing_token", token))
+
+	return l, nil
+}
+
+// Heartbeat extends the lease of l. It returns ErrLockLost when another
+// owner has taken the lock in the meantime.
+func (c *LockClient) Heartbeat(ctx context.Context, l *Lock) error {
+	expiresAt := time.Now().Add(c.leaseDuration())
+
+	err := c.conditionalUpdate(ctx, l, expression.Set(expression.Name(attrLeaseExpires), expression.Value(expiresAt.UnixMilli())))
+	if err != nil {
+		if errors.Is(err, ErrLockLost) {
+			c.forget(l)
+		}
+
+		return err
+	}
+
+	l.setExpiresAt(expiresAt)
+
+	return nil
+}
+
+// Release gives up the lease of l. The fencing token is kept so the next
+// holder receives a higher one.
+func (c *LockClient) Release(ctx context.Context, l *Lock) error {
+	defer c.forget(l)
+
+	err := c.conditionalUpdate(ctx, l, expression.
+		Set(expression.Name(attrLeaseExpires), expression.Value(0)).
+		Remove(expression.Name(attrOwner)))
+	if err != nil && !errors.Is(err, ErrLockLost) {
+		return err
+	}
+
+	c.logger.Info("Released lock", zap.String("key", l.Key), zap.Int64("fencing_token", l.FencingToken))
+
+	return nil
+}
+
+func (c *LockClient) conditionalUpdate(ctx context.Context, l *Lock, update expression.UpdateBuilder) error {
+	cond := expression.Name(attrOwner).Equal(expression.Value(l.Owner)).
+		And(expression.Name(attrFencingToken).Equal(expression.Value(l.FencingToken)))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.params.DynamoDB.GetClient().UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(c.tableName()),
+		Key:                       c.lockKey(l.Key),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var ccf *types.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return ErrLockLost
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *LockClient) forget(l *Lock) {
+	l.markLost()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.held[l.Key] == l {
+		delete(c.held, l.Key)
+	}
+}
+
+func (c *LockClient) heartbeatLoop(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(time.Duration(c.config.HeartbeatInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+		locks := make([]*Lock, 0, len(c.held))
+		for _, l := range c.held {
+			locks = append(locks, l)
+		}
+		c.mu.Unlock()
+
+		for _, l := range locks {
+			if err := c.Heartbeat(ctx, l); err != nil {
+				c.logger.Warn("Lock heartbeat error",
+					zap.String("key", l.Key),
+					zap.Int64("fencing_token", l.FencingToken),
+					zap.Error(err),
+				)
+
+				// Transient errors are retried until the lease runs out
+				if time.Now().After(l.ExpiresAt()) {
+					c.forget(l)
+				}
+			}
+		}
+	}
+}
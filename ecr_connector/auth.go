@@ -0,0 +1,114 @@
+package ecr_connector
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrInvalidToken = awserrors.New(awserrors.ErrUnauthorized, "invalid authorization token")
+
+// Credentials log in to the registry, e.g. with docker login. Their JSON
+// is what a docker credential helper prints for get.
+type Credentials struct {
+	ServerURL string    `json:"ServerURL"`
+	Username  string    `json:"Username"`
+	Secret    string    `json:"Secret"`
+	ExpiresAt time.Time `json:"-"`
+}
+
+// GetCredentials returns the registry credentials. ECR tokens are valid
+// for 12 hours; the cached ones are renewed refresh_ahead seconds before
+// they expire.
+func (c *ECRConnector) GetCredentials(ctx context.Context) (_ *Credentials, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECRConnector", "GetCredentials")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	refreshAhead := time.Duration(c.config.RefreshAhead) * time.Second
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.credentials != nil && time.Until(c.credentials.ExpiresAt) > refreshAhead {
+		return c.credentials, nil
+	}
+
+	input := &ecr.GetAuthorizationTokenInput{}
+	if registryID := c.config.RegistryID; registryID != "" {
+		input.RegistryIds = []string{registryID}
+	}
+
+	result, err := c.client.GetAuthorizationToken(ctx, input)
+	if err != nil {
+		c.logger.Error("Get authorization token error", zap.Error(err))
+		return nil, err
+	}
+
+	if len(result.AuthorizationData) == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	data := result.AuthorizationData[0]
+
+	// The token is base64 of "AWS:<password>"
+	decoded, err := base64.StdEncoding.DecodeString(aws.ToString(data.AuthorizationToken))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	username, secret, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	c.credentials = &Credentials{
+		ServerURL: aws.ToString(data.ProxyEndpoint),
+		Username:  username,
+		Secret:    secret,
+		ExpiresAt: aws.ToTime(data.ExpiresAt),
+	}
+
+	c.logger.Info("Refreshed registry credentials",
+		zap.String("server_url", c.credentials.ServerURL),
+		zap.Time("expires_at", c.credentials.ExpiresAt),
+	)
+
+	return c.credentials, nil
+}
+
+// DockerCredential returns the credentials in the docker credential helper
+// format, for a docker-credential-* binary to print.
+func (c *ECRConnector) DockerCredential(ctx context.Context) (_ []byte, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECRConnector", "DockerCredential")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	credentials, err := c.GetCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(credentials)
+}
+
+// RegistryHost returns the registry host name images are tagged with,
+// e.g. 123456789012.dkr.ecr.us-west-1.amazonaws.com.
+func (c *ECRConnector) RegistryHost(ctx context.Context) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECRConnector", "RegistryHost")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	credentials, err := c.GetCredentials(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(credentials.ServerURL, "https://"), nil
+}
@@ -0,0 +1,27 @@
+package ecr_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. ECR_REGISTRY_ID for the ecr scope.
+type Config struct {
+	RegistryID        string `mapstructure:"registry_id" default:""`
+	RefreshAhead      int    `mapstructure:"refresh_ahead" default:"600"`
+	ECRKey            string `mapstructure:"ecr_key" default:"ABCDE"`
+	ECRSecret         string `mapstructure:"ecr_secret" default:"example_secret"`
+	ECRToken          string `mapstructure:"ecr_token" default:""`
+	ECRRegion         string `mapstructure:"ecr_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *ECRConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *ECRConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,257 @@
+package ecr_connector
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultRegistryID   = ""
+	DefaultRefreshAhead = 600
+	DefaultECRKey       = "ABCDE"
+	DefaultECRSecret    = "example_secret"
+	DefaultECRToken     = ""
+	DefaultECRRegion    = "us-west-1"
+)
+
+var preflightActions = []string{
+	"ecr:GetAuthorizationToken",
+	"ecr:DescribeImages",
+}
+
+type ECRConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *ecr.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	mu          sync.Mutex
+	credentials *Credentials
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *ecr.Client             `optional:"true"`
+}
+
+// Module provides the ECRConnector of scope. It uses the *ecr.Client in
+// the graph, if any, instead of building one, and adds the connector's
+// middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the ECRConnector of scope, and its interfaces,
+// named after scope, for apps with more than one; see package instance.
+// It uses the *ecr.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *ECRConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*ecr.Client](scope, named),
+		instance.Provide[*ECRConnector](scope, named, func(p Params) *ECRConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &ECRConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *ECRConnector) Registry {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *ECRConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting ECRConnector",
+		zap.String("registry_id", c.config.RegistryID),
+		zap.String("ecr_region", c.config.ECRRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = ecr.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = ecr.New(c.client.Options(), func(o *ecr.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *ECRConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped ECRConnector")
+
+	return nil
+}
+
+func (c *ECRConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.ECRRegion,
+		Key:         c.config.ECRKey,
+		Secret:      c.config.ECRSecret,
+		Token:       c.config.ECRToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *ECRConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("ecr_region")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("ecr_key", DefaultECRKey)
+		v.NotPlaceholder("ecr_secret", DefaultECRSecret)
+	}
+
+	return v.Err()
+}
+
+// probe lists a repository of the registry.
+func (c *ECRConnector) probe(ctx context.Context) error {
+	input := &ecr.DescribeRepositoriesInput{
+		MaxResults: aws.Int32(1),
+	}
+
+	if registryID := c.config.RegistryID; registryID != "" {
+		input.RegistryId = aws.String(registryID)
+	}
+
+	_, err := c.client.DescribeRepositories(ctx, input)
+
+	return err
+}
+
+func (c *ECRConnector) GetClient() *ecr.Client {
+	return c.client
+}
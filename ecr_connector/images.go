@@ -0,0 +1,114 @@
+package ecr_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrRepositoryNotFound = awserrors.New(awserrors.ErrNotFound, "repository not found")
+	ErrImageNotFound      = awserrors.New(awserrors.ErrNotFound, "image not found")
+)
+
+// ListImages returns the images of a repository, newest first.
+func (c *ECRConnector) ListImages(ctx context.Context, repository string) (_ []types.ImageDetail, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECRConnector", "ListImages")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	paginator := ecr.NewDescribeImagesPaginator(c.client, &ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repository),
+		RegistryId:     c.registryID(),
+	})
+
+	images := []types.ImageDetail{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, c.imageError("Describe images error", repository, "", err)
+		}
+
+		images = append(images, page.ImageDetails...)
+	}
+
+	sort.SliceStable(images, func(i, j int) bool {
+		return aws.ToTime(images[i].ImagePushedAt).After(aws.ToTime(images[j].ImagePushedAt))
+	})
+
+	return images, nil
+}
+
+// GetImage looks up the image tagged tag, e.g. to resolve "latest" to a
+// digest, or returns ErrImageNotFound.
+func (c *ECRConnector) GetImage(ctx context.Context, repository string, tag string) (_ *types.ImageDetail, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECRConnector", "GetImage")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.DescribeImages(ctx, &ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repository),
+		RegistryId:     c.registryID(),
+		ImageIds: []types.ImageIdentifier{
+			{ImageTag: aws.String(tag)},
+		},
+	})
+	if err != nil {
+		return nil, c.imageError("Describe images error", repository, tag, err)
+	}
+
+	if len(result.ImageDetails) == 0 {
+		return nil, fmt.Errorf("%w: %s:%s", ErrImageNotFound, repository, tag)
+	}
+
+	return &result.ImageDetails[0], nil
+}
+
+// ImageURI returns the reference of an image pinned to its digest, such
+// as 123456789012.dkr.ecr.us-west-1.amazonaws.com/app@sha256:..., for
+// deployments that must not follow a moving tag.
+func (c *ECRConnector) ImageURI(ctx context.Context, repository string, tag string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECRConnector", "ImageURI")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	image, err := c.GetImage(ctx, repository, tag)
+	if err != nil {
+		return "", err
+	}
+
+	host, err := c.RegistryHost(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return host + "/" + repository + "@" + aws.ToString(image.ImageDigest), nil
+}
+
+func (c *ECRConnector) registryID() *string {
+	if registryID := c.config.RegistryID; registryID != "" {
+		return aws.String(registryID)
+	}
+
+	return nil
+}
+
+func (c *ECRConnector) imageError(msg string, repository string, tag string, err error) error {
+	var repositoryNotFound *types.RepositoryNotFoundException
+	if errors.As(err, &repositoryNotFound) {
+		return fmt.Errorf("%w: %s: %w", ErrRepositoryNotFound, repository, err)
+	}
+
+	var imageNotFound *types.ImageNotFoundException
+	if errors.As(err, &imageNotFound) {
+		return fmt.Errorf("%w: %s:%s: %w", ErrImageNotFound, repository, tag, err)
+	}
+
+	c.logger.Error(msg, zap.String("repository", repository), zap.Error(err))
+	return err
+}
@@ -0,0 +1,18 @@
+package ecr_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Registry
+
+// Registry looks up images of repositories.
+type Registry interface {
+	ListImages(ctx context.Context, repository string) ([]types.ImageDetail, error)
+	GetImage(ctx context.Context, repository string, tag string) (*types.ImageDetail, error)
+	ImageURI(ctx context.Context, repository string, tag string) (string, error)
+}
+
+var _ Registry = (*ECRConnector)(nil)
@@ -0,0 +1,33 @@
+package ecs_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. ECS_CLUSTER for the ecs scope.
+type Config struct {
+	Cluster           string   `mapstructure:"cluster" default:"default"`
+	TaskDefinition    string   `mapstructure:"task_definition" default:""`
+	ContainerName     string   `mapstructure:"container_name" default:""`
+	LaunchType        string   `mapstructure:"launch_type" default:"FARGATE"`
+	Subnets           []string `mapstructure:"subnets" default:""`
+	SecurityGroups    []string `mapstructure:"security_groups" default:""`
+	AssignPublicIP    bool     `mapstructure:"assign_public_ip" default:"false"`
+	PollInterval      int      `mapstructure:"poll_interval" default:"6"`
+	ECSKey            string   `mapstructure:"ecs_key" default:"ABCDE"`
+	ECSSecret         string   `mapstructure:"ecs_secret" default:"example_secret"`
+	ECSToken          string   `mapstructure:"ecs_token" default:""`
+	ECSRegion         string   `mapstructure:"ecs_region" default:"us-west-1"`
+	VerifyCredentials bool     `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool     `mapstructure:"preflight" default:"false"`
+}
+
+func (c *ECSConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *ECSConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,264 @@
+package ecs_connector
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/cloudwatchlogs_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultCluster        = "default"
+	DefaultTaskDefinition = ""
+	DefaultContainerName  = ""
+	DefaultLaunchType     = "FARGATE"
+	DefaultAssignPublicIP = false
+	DefaultPollInterval   = 6
+	DefaultECSKey         = "ABCDE"
+	DefaultECSSecret      = "example_secret"
+	DefaultECSToken       = ""
+	DefaultECSRegion      = "us-west-1"
+)
+
+var preflightActions = []string{
+	"ecs:RunTask",
+	"ecs:DescribeTasks",
+	"ecs:DescribeTaskDefinition",
+	"iam:PassRole",
+}
+
+type ECSConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *ecs.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider                           `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig                              `optional:"true"`
+	CredentialChain *awscredentials.Chain                             `optional:"true"`
+	Logs            *cloudwatchlogs_connector.CloudWatchLogsConnector `optional:"true"`
+	Tracer          *xray.Tracer                                      `optional:"true"`
+	Breaker         *circuitbreaker.Breaker                           `optional:"true"`
+	Prometheus      *metrics.Metrics                                  `optional:"true"`
+	Telemetry       *telemetry.Telemetry                              `optional:"true"`
+	Health          *health.Health                                    `optional:"true"`
+	Faults          *faults.Injector                                  `optional:"true"`
+	Client          *ecs.Client                                       `optional:"true"`
+}
+
+// Module provides the ECSConnector of scope. It uses the *ecs.Client in
+// the graph, if any, instead of building one, and adds the connector's
+// middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the ECSConnector of scope, and its interfaces,
+// named after scope, for apps with more than one; see package instance.
+// It uses the *ecs.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *ECSConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*ecs.Client](scope, named),
+		instance.Provide[*ECSConnector](scope, named, func(p Params) *ECSConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &ECSConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *ECSConnector) TaskRunner {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *ECSConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting ECSConnector",
+		zap.String("cluster", c.config.Cluster),
+		zap.String("task_definition", c.config.TaskDefinition),
+		zap.String("ecs_region", c.config.ECSRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = ecs.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = ecs.New(c.client.Options(), func(o *ecs.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *ECSConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped ECSConnector")
+
+	return nil
+}
+
+func (c *ECSConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.ECSRegion,
+		Key:         c.config.ECSKey,
+		Secret:      c.config.ECSSecret,
+		Token:       c.config.ECSToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *ECSConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("ecs_region")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("ecs_key", DefaultECSKey)
+		v.NotPlaceholder("ecs_secret", DefaultECSSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the cluster.
+func (c *ECSConnector) probe(ctx context.Context) error {
+	result, err := c.client.DescribeClusters(ctx, &ecs.DescribeClustersInput{
+		Clusters: []string{c.config.Cluster},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(result.Failures) > 0 {
+		return fmt.Errorf("cluster %s: %s", c.config.Cluster, aws.ToString(result.Failures[0].Reason))
+	}
+
+	return nil
+}
+
+func (c *ECSConnector) GetClient() *ecs.Client {
+	return c.client
+}
@@ -0,0 +1,19 @@
+package ecs_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . TaskRunner
+
+// TaskRunner runs tasks and waits for them.
+type TaskRunner interface {
+	Run(ctx context.Context, req RunTaskRequest) (*types.Task, error)
+	RunTask(ctx context.Context, req RunTaskRequest) (string, error)
+	WaitForTask(ctx context.Context, taskArn string) (*types.Task, error)
+	DescribeTask(ctx context.Context, taskArn string) (*types.Task, error)
+}
+
+var _ TaskRunner = (*ECSConnector)(nil)
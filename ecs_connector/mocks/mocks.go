@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/ecs_connector (interfaces: TaskRunner)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . TaskRunner
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	ecs_connector "github.com/elmntri/zeitgeber-aws-modules/ecs_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTaskRunner is a mock of TaskRunner interface.
+type MockTaskRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskRunnerMockRecorder
+}
+
+// MockTaskRunnerMockRecorder is the mock recorder for MockTaskRunner.
+type MockTaskRunnerMockRecorder struct {
+	mock *MockTaskRunner
+}
+
+// NewMockTaskRunner creates a new mock instance.
+func NewMockTaskRunner(ctrl *gomock.Controller) *MockTaskRunner {
+	mock := &MockTaskRunner{ctrl: ctrl}
+	mock.recorder = &MockTaskRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskRunner) EXPECT() *MockTaskRunnerMockRecorder {
+	return m.recorder
+}
+
+// DescribeTask mocks base method.
+func (m *MockTaskRunner) DescribeTask(arg0 context.Context, arg1 string) (*types.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeTask", arg0, arg1)
+	ret0, _ := ret[0].(*types.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeTask indicates an expected call of DescribeTask.
+func (mr *MockTaskRunnerMockRecorder) DescribeTask(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTask", reflect.TypeOf((*MockTaskRunner)(nil).DescribeTask), arg0, arg1)
+}
+
+// Run mocks base method.
+func (m *MockTaskRunner) Run(arg0 context.Context, arg1 ecs_connector.RunTaskRequest) (*types.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", arg0, arg1)
+	ret0, _ := ret[0].(*types.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockTaskRunnerMockRecorder) Run(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockTaskRunner)(nil).Run), arg0, arg1)
+}
+
+// RunTask mocks base method.
+func (m *MockTaskRunner) RunTask(arg0 context.Context, arg1 ecs_connector.RunTaskRequest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunTask", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunTask indicates an expected call of RunTask.
+func (mr *MockTaskRunnerMockRecorder) RunTask(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunTask", reflect.TypeOf((*MockTaskRunner)(nil).RunTask), arg0, arg1)
+}
+
+// WaitForTask mocks base method.
+func (m *MockTaskRunner) WaitForTask(arg0 context.Context, arg1 string) (*types.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForTask", arg0, arg1)
+	ret0, _ := ret[0].(*types.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForTask indicates an expected call of WaitForTask.
+func (mr *MockTaskRunnerMockRecorder) WaitForTask(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForTask", reflect.TypeOf((*MockTaskRunner)(nil).WaitForTask), arg0, arg1)
+}
@@ -0,0 +1,302 @@
+package ecs_connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrTaskFailed = awserrors.New(awserrors.ErrTransient, "task failed")
+	ErrNoLogs     = awserrors.New(awserrors.ErrValidation, "no cloudwatch logs connector")
+)
+
+// RunTaskRequest describes a one-off task. Empty fields keep the task
+// definition's values; TaskDefinition and Container default to
+// task_definition and container_name.
+type RunTaskRequest struct {
+	TaskDefinition string
+	Container      string
+	Command        []string
+	Environment    map[string]string
+
+	// Task size, e.g. "1024" CPU units and "2048" MiB
+	CPU    string
+	Memory string
+
+	TaskRoleArn string
+	StartedBy   string
+}
+
+// TaskFailedError reports a stopped task whose container did not exit
+// with 0.
+type TaskFailedError struct {
+	TaskArn   string
+	Container string
+	ExitCode  *int32
+	Reason    string
+}
+
+func (e *TaskFailedError) Error() string {
+	if e.ExitCode == nil {
+		return fmt.Sprintf("task %s: container %s: %s", e.TaskArn, e.Container, e.Reason)
+	}
+
+	return fmt.Sprintf("task %s: container %s exited with %d: %s", e.TaskArn, e.Container, *e.ExitCode, e.Reason)
+}
+
+func (e *TaskFailedError) Unwrap() error {
+	return ErrTaskFailed
+}
+
+// Run starts a task and waits until it stopped, see WaitForTask.
+func (c *ECSConnector) Run(ctx context.Context, req RunTaskRequest) (_ *types.Task, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECSConnector", "Run")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	taskArn, err := c.RunTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.WaitForTask(ctx, taskArn)
+}
+
+// RunTask starts a task in the configured cluster and returns its ARN.
+// Fargate tasks run in subnets with security_groups.
+func (c *ECSConnector) RunTask(ctx context.Context, req RunTaskRequest) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECSConnector", "RunTask")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	taskDefinition := req.TaskDefinition
+	if taskDefinition == "" {
+		taskDefinition = c.config.TaskDefinition
+	}
+
+	input := &ecs.RunTaskInput{
+		Cluster:        aws.String(c.cluster()),
+		TaskDefinition: aws.String(taskDefinition),
+		LaunchType:     types.LaunchType(c.config.LaunchType),
+		Overrides:      c.taskOverride(req),
+	}
+
+	if req.StartedBy != "" {
+		input.StartedBy = aws.String(req.StartedBy)
+	}
+
+	if subnets := c.config.Subnets; len(subnets) > 0 {
+		assignPublicIP := types.AssignPublicIpDisabled
+		if c.config.AssignPublicIP {
+			assignPublicIP = types.AssignPublicIpEnabled
+		}
+
+		input.NetworkConfiguration = &types.NetworkConfiguration{
+			AwsvpcConfiguration: &types.AwsVpcConfiguration{
+				Subnets:        subnets,
+				SecurityGroups: c.config.SecurityGroups,
+				AssignPublicIp: assignPublicIP,
+			},
+		}
+	}
+
+	result, err := c.client.RunTask(ctx, input)
+	if err != nil {
+		c.logger.Error("Run task error", zap.String("task_definition", taskDefinition), zap.Error(err))
+		return "", err
+	}
+
+	// Placement problems come back as failures rather than an error
+	if len(result.Tasks) == 0 {
+		reason := "no task started"
+		if len(result.Failures) > 0 {
+			reason = aws.ToString(result.Failures[0].Reason) + ": " + aws.ToString(result.Failures[0].Detail)
+		}
+
+		return "", fmt.Errorf("%s: run task %s: %s", c.scope, taskDefinition, reason)
+	}
+
+	return aws.ToString(result.Tasks[0].TaskArn), nil
+}
+
+// WaitForTask polls a task every poll_interval seconds until it stopped.
+// It returns a *TaskFailedError when a container exited with another
+// code than 0 or never ran, together with the task so its logs can
+// still be read.
+func (c *ECSConnector) WaitForTask(ctx context.Context, taskArn string) (_ *types.Task, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECSConnector", "WaitForTask")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Second
+
+	for {
+		task, err := c.DescribeTask(ctx, taskArn)
+		if err != nil {
+			return nil, err
+		}
+
+		if aws.ToString(task.LastStatus) == "STOPPED" {
+			return task, taskError(task)
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// DescribeTask returns a task of the configured cluster.
+func (c *ECSConnector) DescribeTask(ctx context.Context, taskArn string) (_ *types.Task, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECSConnector", "DescribeTask")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+		Cluster: aws.String(c.cluster()),
+		Tasks:   []string{taskArn},
+	})
+	if err != nil {
+		c.logger.Error("Describe tasks error", zap.String("task_arn", taskArn), zap.Error(err))
+		return nil, err
+	}
+
+	if len(result.Tasks) == 0 {
+		return nil, fmt.Errorf("%s: task not found: %s", c.scope, taskArn)
+	}
+
+	return &result.Tasks[0], nil
+}
+
+// TaskLogs returns the log messages of a container of a task using the
+// awslogs driver, read through the CloudWatch Logs connector. An empty
+// container means container_name.
+func (c *ECSConnector) TaskLogs(ctx context.Context, task *types.Task, container string) (_ []string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "ECSConnector", "TaskLogs")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if c.params.Logs == nil {
+		return nil, ErrNoLogs
+	}
+
+	if container == "" {
+		container = c.config.ContainerName
+	}
+
+	result, err := c.client.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: task.TaskDefinitionArn,
+	})
+	if err != nil {
+		c.logger.Error("Describe task definition error", zap.String("task_definition", aws.ToString(task.TaskDefinitionArn)), zap.Error(err))
+		return nil, err
+	}
+
+	var logConfiguration *types.LogConfiguration
+	for _, definition := range result.TaskDefinition.ContainerDefinitions {
+		if aws.ToString(definition.Name) == container {
+			logConfiguration = definition.LogConfiguration
+		}
+	}
+
+	if logConfiguration == nil || logConfiguration.LogDriver != types.LogDriverAwslogs {
+		return nil, fmt.Errorf("%s: container %s does not log to CloudWatch Logs", c.scope, container)
+	}
+
+	// awslogs names streams prefix/container/task-id
+	taskArn := aws.ToString(task.TaskArn)
+	stream := logConfiguration.Options["awslogs-stream-prefix"] + "/" + container + "/" + taskArn[strings.LastIndex(taskArn, "/")+1:]
+
+	events, err := c.params.Logs.GetLogEvents(ctx, logConfiguration.Options["awslogs-group"], stream)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]string, len(events))
+	for i, event := range events {
+		messages[i] = aws.ToString(event.Message)
+	}
+
+	return messages, nil
+}
+
+func (c *ECSConnector) taskOverride(req RunTaskRequest) *types.TaskOverride {
+	override := &types.TaskOverride{}
+
+	if req.CPU != "" {
+		override.Cpu = aws.String(req.CPU)
+	}
+
+	if req.Memory != "" {
+		override.Memory = aws.String(req.Memory)
+	}
+
+	if req.TaskRoleArn != "" {
+		override.TaskRoleArn = aws.String(req.TaskRoleArn)
+	}
+
+	if len(req.Command) > 0 || len(req.Environment) > 0 {
+		container := req.Container
+		if container == "" {
+			container = c.config.ContainerName
+		}
+
+		names := make([]string, 0, len(req.Environment))
+		for name := range req.Environment {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		environment := make([]types.KeyValuePair, len(names))
+		for i, name := range names {
+			environment[i] = types.KeyValuePair{
+				Name:  aws.String(name),
+				Value: aws.String(req.Environment[name]),
+			}
+		}
+
+		override.ContainerOverrides = []types.ContainerOverride{
+			{
+				Name:        aws.String(container),
+				Command:     req.Command,
+				Environment: environment,
+			},
+		}
+	}
+
+	return override
+}
+
+func (c *ECSConnector) cluster() string {
+	return c.config.Cluster
+}
+
+func taskError(task *types.Task) error {
+	for _, container := range task.Containers {
+		if container.ExitCode != nil && *container.ExitCode == 0 {
+			continue
+		}
+
+		reason := aws.ToString(container.Reason)
+		if reason == "" {
+			reason = aws.ToString(task.StoppedReason)
+		}
+
+		return &TaskFailedError{
+			TaskArn:   aws.ToString(task.TaskArn),
+			Container: aws.ToString(container.Name),
+			ExitCode:  container.ExitCode,
+			Reason:    reason,
+		}
+	}
+
+	return nil
+}
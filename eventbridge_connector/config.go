@@ -0,0 +1,32 @@
+package eventbridge_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. EVENTBRIDGE_EVENT_BUS for the
+// eventbridge scope.
+type Config struct {
+	EventBus          string `mapstructure:"event_bus" default:"default"`
+	Source            string `mapstructure:"source" default:""`
+	DetailType        string `mapstructure:"detail_type" default:""`
+	MaxRetries        int    `mapstructure:"max_retries" default:"3"`
+	EventBridgeKey    string `mapstructure:"eventbridge_key" default:"ABCDE"`
+	EventBridgeSecret string `mapstructure:"eventbridge_secret" default:"example_secret"`
+	EventBridgeToken  string `mapstructure:"eventbridge_token" default:""`
+	EventBridgeRegion string `mapstructure:"eventbridge_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+
+	Rules []RuleConfig `mapstructure:"rules"`
+}
+
+func (c *EventBridgeConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *EventBridgeConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,226 @@
+package eventbridge_connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrEventTooLarge = awserrors.New(awserrors.ErrValidation, "event exceeds the eventbridge entry size limit")
+
+// PutEvents limits
+const (
+	maxBatchEntries = 10
+	maxBatchBytes   = 256 * 1024
+)
+
+// Event is one entry to publish. Empty Source, DetailType and EventBus
+// fall back to the configured defaults. Detail is marshaled to JSON
+// unless it already is JSON ([]byte or json.RawMessage).
+type Event struct {
+	Source     string
+	DetailType string
+	Detail     interface{}
+	Resources  []string
+	Time       time.Time
+	EventBus   string
+}
+
+// Publish sends one event with the configured source and event bus.
+func (c *EventBridgeConnector) Publish(ctx context.Context, detailType string, detail interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "EventBridgeConnector", "Publish")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.PutEvents(ctx, Event{DetailType: detailType, Detail: detail})
+}
+
+// PutEvents sends events in as few PutEvents calls as the entry limits
+// allow. Entries the service rejects are retried up to max_retries times;
+// the error reports those that never went through.
+func (c *EventBridgeConnector) PutEvents(ctx context.Context, events ...Event) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "EventBridgeConnector", "PutEvents")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	entries := make([]types.PutEventsRequestEntry, 0, len(events))
+	for _, e := range events {
+		entry, err := c.entry(e)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	var errs []error
+	for len(entries) > 0 {
+		n, size := 0, 0
+		for n < len(entries) && n < maxBatchEntries {
+			entrySize := entrySize(entries[n])
+			if size+entrySize > maxBatchBytes {
+				break
+			}
+
+			size += entrySize
+			n++
+		}
+
+		if err := c.putEntries(ctx, entries[:n]); err != nil {
+			errs = append(errs, err)
+		}
+
+		entries = entries[n:]
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *EventBridgeConnector) entry(e Event) (types.PutEventsRequestEntry, error) {
+	if e.Source == "" {
+		e.Source = c.config.Source
+	}
+	if e.DetailType == "" {
+		e.DetailType = c.config.DetailType
+	}
+	if e.EventBus == "" {
+		e.EventBus = c.config.EventBus
+	}
+
+	if e.Source == "" || e.DetailType == "" {
+		return types.PutEventsRequestEntry{}, fmt.Errorf("%s: events need a source and a detail type", c.scope)
+	}
+
+	var detail []byte
+	switch d := e.Detail.(type) {
+	case []byte:
+		detail = d
+	case json.RawMessage:
+		detail = d
+	case nil:
+		detail = []byte("{}")
+	default:
+		var err error
+		if detail, err = json.Marshal(d); err != nil {
+			return types.PutEventsRequestEntry{}, err
+		}
+	}
+
+	entry := types.PutEventsRequestEntry{
+		Source:       aws.String(e.Source),
+		DetailType:   aws.String(e.DetailType),
+		Detail:       aws.String(string(detail)),
+		EventBusName: aws.String(e.EventBus),
+		Resources:    e.Resources,
+	}
+	if !e.Time.IsZero() {
+		entry.Time = aws.Time(e.Time)
+	}
+
+	if entrySize(entry) > maxBatchBytes {
+		return types.PutEventsRequestEntry{}, ErrEventTooLarge
+	}
+
+	return entry, nil
+}
+
+// entrySize follows the documented PutEvents entry size calculation.
+func entrySize(e types.PutEventsRequestEntry) int {
+	size := len(aws.ToString(e.Source)) + len(aws.ToString(e.DetailType)) + len(aws.ToString(e.Detail))
+	if e.Time != nil {
+		size += 14
+	}
+	for _, r := range e.Resources {
+		size += len(r)
+	}
+
+	return size
+}
+
+// retryableCodes are the entry error codes of PutEvents worth resending;
+// the others, such as MalformedDetail or AccessDeniedException, fail the
+// same way every time.
+var retryableCodes = map[string]bool{
+	"ThrottlingException": true,
+	"InternalFailure":     true,
+	"InternalException":   true,
+	"ServiceUnavailable":  true,
+}
+
+// putEntries sends one batch, resending only the entries failed with a
+// retryable error code with an exponential backoff. Entries rejected
+// otherwise are reported in the returned error.
+func (c *EventBridgeConnector) putEntries(ctx context.Context, entries []types.PutEventsRequestEntry) error {
+	maxRetries := c.config.MaxRetries
+	backoff := 100 * time.Millisecond
+
+	var errs []error
+
+	for attempt := 0; ; attempt++ {
+		result, err := c.client.PutEvents(ctx, &eventbridge.PutEventsInput{
+			Entries: entries,
+		})
+		if err != nil {
+			c.logger.Error("Put events error", zap.Int("count", len(entries)), zap.Error(err))
+			return errors.Join(append(errs, err)...)
+		}
+
+		if result.FailedEntryCount == 0 {
+			return errors.Join(errs...)
+		}
+
+		var failed []types.PutEventsRequestEntry
+		var rejected int
+		var lastError, lastRejected string
+		for i, r := range result.Entries {
+			if r.ErrorCode == nil {
+				continue
+			}
+
+			message := fmt.Sprintf("%s: %s", aws.ToString(r.ErrorCode), aws.ToString(r.ErrorMessage))
+			if retryableCodes[aws.ToString(r.ErrorCode)] {
+				failed = append(failed, entries[i])
+				lastError = message
+			} else {
+				rejected++
+				lastRejected = message
+			}
+		}
+
+		if rejected > 0 {
+			c.logger.Error("Events rejected",
+				zap.Int("count", rejected),
+				zap.String("last_error", lastRejected),
+			)
+			errs = append(errs, fmt.Errorf("%s: %d events rejected: %s", c.scope, rejected, lastRejected))
+		}
+
+		if len(failed) == 0 {
+			return errors.Join(errs...)
+		}
+
+		if attempt >= maxRetries {
+			c.logger.Error("Events dropped after retries",
+				zap.Int("count", len(failed)),
+				zap.String("last_error", lastError),
+			)
+			return errors.Join(append(errs, fmt.Errorf("%s: %d events failed: %s", c.scope, len(failed), lastError))...)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+
+		entries = failed
+		backoff *= 2
+	}
+}
@@ -0,0 +1,16 @@
+package eventbridge_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . EventPublisher
+
+// EventPublisher puts events on the bus.
+type EventPublisher interface {
+	Publish(ctx context.Context, detailType string, detail interface{}) error
+	PutEvents(ctx context.Context, events ...Event) error
+}
+
+var (
+	_ EventPublisher = (*EventBridgeConnector)(nil)
+	_ EventPublisher = (*MemoryEventPublisher)(nil)
+)
@@ -0,0 +1,37 @@
+package eventbridge_connector
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryEventPublisher is an in-process EventPublisher for tests. It
+// records the events that would have been put on the bus.
+type MemoryEventPublisher struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func NewMemoryEventPublisher() *MemoryEventPublisher {
+	return &MemoryEventPublisher{}
+}
+
+func (p *MemoryEventPublisher) Publish(ctx context.Context, detailType string, detail interface{}) error {
+	return p.PutEvents(ctx, Event{DetailType: detailType, Detail: detail})
+}
+
+func (p *MemoryEventPublisher) PutEvents(ctx context.Context, events ...Event) error {
+	p.mu.Lock()
+	p.events = append(p.events, events...)
+	p.mu.Unlock()
+
+	return nil
+}
+
+// Events returns the recorded events in the order they were put.
+func (p *MemoryEventPublisher) Events() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Event(nil), p.events...)
+}
@@ -0,0 +1,182 @@
+package eventbridge_connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+)
+
+// TargetConfig is one target of a rule. RoleArn is needed for targets
+// that are not invoked through resource policies, e.g. other event buses.
+type TargetConfig struct {
+	ID      string `mapstructure:"id"`
+	Arn     string `mapstructure:"arn"`
+	RoleArn string `mapstructure:"role_arn"`
+	Input   string `mapstructure:"input"`
+}
+
+// RuleConfig declares a rule and its complete set of targets. Pattern is
+// best given as a JSON string: viper lowercases the keys of nested maps,
+// and event patterns are case sensitive.
+type RuleConfig struct {
+	Name        string         `mapstructure:"name"`
+	Description string         `mapstructure:"description"`
+	EventBus    string         `mapstructure:"event_bus"`
+	Pattern     interface{}    `mapstructure:"pattern"`
+	Targets     []TargetConfig `mapstructure:"targets"`
+}
+
+// reconcileRules brings every configured rule to its declared state.
+func (c *EventBridgeConnector) reconcileRules(ctx context.Context) error {
+	for _, rule := range c.config.Rules {
+		if _, err := c.EnsureRule(ctx, rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EnsureRule creates or updates the rule and makes its targets exactly
+// the declared ones, removing any others. It returns the rule ARN.
+func (c *EventBridgeConnector) EnsureRule(ctx context.Context, rule RuleConfig) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "EventBridgeConnector", "EnsureRule")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if rule.Name == "" || rule.Pattern == nil {
+		return "", fmt.Errorf("%s: rules need a name and a pattern", c.scope)
+	}
+
+	if rule.EventBus == "" {
+		rule.EventBus = c.config.EventBus
+	}
+
+	var pattern string
+	switch p := rule.Pattern.(type) {
+	case string:
+		pattern = p
+	default:
+		data, err := json.Marshal(p)
+		if err != nil {
+			return "", err
+		}
+		pattern = string(data)
+	}
+
+	input := &eventbridge.PutRuleInput{
+		Name:         aws.String(rule.Name),
+		EventBusName: aws.String(rule.EventBus),
+		EventPattern: aws.String(pattern),
+		State:        types.RuleStateEnabled,
+	}
+	if rule.Description != "" {
+		input.Description = aws.String(rule.Description)
+	}
+
+	// PutRule creates the rule or overwrites its definition
+	result, err := c.client.PutRule(ctx, input)
+	if err != nil {
+		c.logger.Error("Put rule error", zap.String("rule", rule.Name), zap.Error(err))
+		return "", err
+	}
+
+	if err := c.reconcileTargets(ctx, rule); err != nil {
+		return "", err
+	}
+
+	c.logger.Info("Reconciled rule", zap.String("rule", rule.Name), zap.Int("targets", len(rule.Targets)))
+
+	return aws.ToString(result.RuleArn), nil
+}
+
+func (c *EventBridgeConnector) reconcileTargets(ctx context.Context, rule RuleConfig) error {
+	desired := make(map[string]bool, len(rule.Targets))
+	targets := make([]types.Target, 0, len(rule.Targets))
+	for _, t := range rule.Targets {
+		if t.ID == "" || t.Arn == "" {
+			return fmt.Errorf("%s: targets of rule %q need an id and an arn", c.scope, rule.Name)
+		}
+
+		desired[t.ID] = true
+
+		target := types.Target{Id: aws.String(t.ID), Arn: aws.String(t.Arn)}
+		if t.RoleArn != "" {
+			target.RoleArn = aws.String(t.RoleArn)
+		}
+		if t.Input != "" {
+			target.Input = aws.String(t.Input)
+		}
+		targets = append(targets, target)
+	}
+
+	var stale []string
+	input := &eventbridge.ListTargetsByRuleInput{
+		Rule:         aws.String(rule.Name),
+		EventBusName: aws.String(rule.EventBus),
+	}
+	for {
+		result, err := c.client.ListTargetsByRule(ctx, input)
+		if err != nil {
+			c.logger.Error("List targets error", zap.String("rule", rule.Name), zap.Error(err))
+			return err
+		}
+
+		for _, t := range result.Targets {
+			if !desired[aws.ToString(t.Id)] {
+				stale = append(stale, aws.ToString(t.Id))
+			}
+		}
+
+		if result.NextToken == nil {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+
+	// Both calls take at most 10 targets at a time
+	for len(stale) > 0 {
+		n := min(len(stale), 10)
+
+		result, err := c.client.RemoveTargets(ctx, &eventbridge.RemoveTargetsInput{
+			Rule:         aws.String(rule.Name),
+			EventBusName: aws.String(rule.EventBus),
+			Ids:          stale[:n],
+		})
+		if err != nil {
+			c.logger.Error("Remove targets error", zap.String("rule", rule.Name), zap.Error(err))
+			return err
+		}
+		if result.FailedEntryCount > 0 {
+			return fmt.Errorf("%s: removing targets of rule %q: %s", c.scope, rule.Name, aws.ToString(result.FailedEntries[0].ErrorMessage))
+		}
+
+		stale = stale[n:]
+	}
+
+	for len(targets) > 0 {
+		n := min(len(targets), 10)
+
+		result, err := c.client.PutTargets(ctx, &eventbridge.PutTargetsInput{
+			Rule:         aws.String(rule.Name),
+			EventBusName: aws.String(rule.EventBus),
+			Targets:      targets[:n],
+		})
+		if err != nil {
+			c.logger.Error("Put targets error", zap.String("rule", rule.Name), zap.Error(err))
+			return err
+		}
+		if result.FailedEntryCount > 0 {
+			return fmt.Errorf("%s: putting targets of rule %q: %s", c.scope, rule.Name, aws.ToString(result.FailedEntries[0].ErrorMessage))
+		}
+
+		targets = targets[n:]
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package eventbridge_events
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. EVENTBRIDGE_EVENTS_RULE_NAME for the
+// eventbridge_events scope.
+type Config struct {
+	RuleName          string `mapstructure:"rule_name" default:""`
+	Pattern           string `mapstructure:"pattern" default:""`
+	ManageQueuePolicy bool   `mapstructure:"manage_queue_policy" default:"false"`
+	MaxMessages       int32  `mapstructure:"max_messages" default:"10"`
+	WaitSeconds       int32  `mapstructure:"wait_seconds" default:"20"`
+	RetryInterval     int    `mapstructure:"retry_interval" default:"5"`
+}
+
+func (c *Consumer) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *Consumer) Config() Config {
+	return c.config
+}
@@ -0,0 +1,210 @@
+package eventbridge_events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/elmntri/zeitgeber-aws-modules/eventbridge_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/sqs_connector"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultRuleName          = ""
+	DefaultPattern           = ""
+	DefaultManageQueuePolicy = false
+	DefaultMaxMessages       = 10
+	DefaultWaitSeconds       = 20
+	DefaultRetryInterval     = 5
+)
+
+const targetID = "sqs"
+
+// Consumer routes events from an EventBridge rule to handlers by
+// detail-type. On start it ensures the rule (rule_name, pattern) targets
+// the SQS connector's queue, then consumes that queue.
+type Consumer struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	tracker  *inflight.Tracker
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle   fx.Lifecycle
+	Logger      *zap.Logger
+	SQS         *sqs_connector.SQSConnector
+	EventBridge *eventbridge_connector.EventBridgeConnector
+}
+
+func Module(scope string) fx.Option {
+
+	var c *Consumer
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) *Consumer {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &Consumer{
+				params:   p,
+				logger:   logger,
+				scope:    scope,
+				handlers: make(map[string][]Handler),
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *Consumer) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	ruleName := c.config.RuleName
+
+	logger.Info("Starting EventBridge consumer",
+		zap.String("rule_name", ruleName),
+	)
+
+	if ruleName == "" || c.config.Pattern == "" {
+		return fmt.Errorf("%s: rule_name and pattern are required", c.scope)
+	}
+
+	if err := c.ensureRule(ctx, ruleName); err != nil {
+		return err
+	}
+
+	tracker, err := inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+	c.tracker = tracker
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go c.receiveLoop(loopCtx)
+
+	return nil
+}
+
+func (c *Consumer) onStop(ctx context.Context) error {
+
+	// Let the messages being handled finish, then interrupt the loop
+	c.tracker.Shutdown(ctx)
+
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+
+	c.logger.Info("Stopped EventBridge consumer")
+
+	return nil
+}
+
+// Handle registers a handler for a detail-type. Register handlers before
+// the app starts.
+func (c *Consumer) Handle(detailType string, handler Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers[detailType] = append(c.handlers[detailType], handler)
+}
+
+// ensureRule points the rule at the queue and, with manage_queue_policy,
+// adds a statement allowing the rule to send to it to the queue's policy.
+// The policy's other statements are kept.
+func (c *Consumer) ensureRule(ctx context.Context, ruleName string) error {
+	queueArn, err := c.params.SQS.GetQueueArn(ctx)
+	if err != nil {
+		c.logger.Error("Get queue ARN error", zap.Error(err))
+		return err
+	}
+
+	ruleArn, err := c.params.EventBridge.EnsureRule(ctx, eventbridge_connector.RuleConfig{
+		Name:    ruleName,
+		Pattern: c.config.Pattern,
+		Targets: []eventbridge_connector.TargetConfig{{ID: targetID, Arn: queueArn}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if !c.config.ManageQueuePolicy {
+		return nil
+	}
+
+	return c.params.SQS.AllowService(ctx, "events.amazonaws.com", ruleArn)
+}
+
+func (c *Consumer) receiveLoop(ctx context.Context) {
+	defer close(c.done)
+
+	c.params.SQS.Consume(ctx, sqs_connector.ConsumeOptions{
+		MaxMessages:   c.config.MaxMessages,
+		WaitSeconds:   c.config.WaitSeconds,
+		RetryInterval: time.Duration(c.config.RetryInterval) * time.Second,
+		Logger:        c.logger,
+		Tracker:       c.tracker,
+	}, c.process)
+}
+
+func (c *Consumer) process(ctx context.Context, body string) error {
+	event, err := ParseEvent([]byte(body))
+	if err != nil {
+		return sqs_connector.Malformed(err)
+	}
+
+	c.mu.RLock()
+	handlers := c.handlers[event.DetailType]
+	c.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		c.logger.Debug("No handler for event", zap.String("detail_type", event.DetailType), zap.String("id", event.ID))
+	}
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
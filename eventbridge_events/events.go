@@ -0,0 +1,48 @@
+package eventbridge_events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Event is the EventBridge envelope as delivered to an SQS target.
+type Event struct {
+	Version    string          `json:"version"`
+	ID         string          `json:"id"`
+	DetailType string          `json:"detail-type"`
+	Source     string          `json:"source"`
+	Account    string          `json:"account"`
+	Time       time.Time       `json:"time"`
+	Region     string          `json:"region"`
+	Resources  []string        `json:"resources"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// ParseEvent decodes an EventBridge envelope from a message body.
+func ParseEvent(body []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// Handler processes an event. Returning an error leaves the message on
+// the queue so it is redelivered.
+type Handler func(ctx context.Context, event *Event) error
+
+// Typed adapts a handler taking the decoded detail, e.g.
+//
+//	consumer.Handle("Object Created", eventbridge_events.Typed(onObjectCreated))
+func Typed[T any](handler func(ctx context.Context, event *Event, detail T) error) Handler {
+	return func(ctx context.Context, event *Event) error {
+		var detail T
+		if err := json.Unmarshal(event.Detail, &detail); err != nil {
+			return err
+		}
+
+		return handler(ctx, event, detail)
+	}
+}
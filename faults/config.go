@@ -0,0 +1,47 @@
+package faults
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. A connector's
+// faults key takes the same keys.
+type Config struct {
+	Enabled            bool     `mapstructure:"enabled" default:"false"`
+	Latency            int      `mapstructure:"latency" default:"0"`
+	LatencyJitter      int      `mapstructure:"latency_jitter" default:"0"`
+	ThrottleRate       float64  `mapstructure:"throttle_rate" default:"0"`
+	ErrorRate          float64  `mapstructure:"error_rate" default:"0"`
+	PartialFailureRate float64  `mapstructure:"partial_failure_rate" default:"0"`
+	Operations         []string `mapstructure:"operations" default:""`
+}
+
+func (i *Injector) initDefaultConfigs() {
+	moduleconfig.Register(i.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (i *Injector) Config() Config {
+	return i.config
+}
+
+// connectorConfig is the module's config with the overrides under the
+// connector's faults key applied.
+func (i *Injector) connectorConfig(connectorScope string) Config {
+	cfg := i.config
+
+	if err := moduleconfig.Load(fmt.Sprintf("%s.faults", connectorScope), &cfg); err != nil {
+		i.logger.Warn("Invalid faults override, using the module's settings",
+			zap.String("connector", connectorScope),
+			zap.Error(err),
+		)
+
+		return i.config
+	}
+
+	return cfg
+}
@@ -0,0 +1,280 @@
+package faults
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultEnabled            = false
+	DefaultLatency            = 0
+	DefaultLatencyJitter      = 0
+	DefaultThrottleRate       = 0.0
+	DefaultErrorRate          = 0.0
+	DefaultPartialFailureRate = 0.0
+)
+
+// InjectedRequestID is the request ID of the errors the Injector makes
+// up, telling them apart from real ones in logs.
+const InjectedRequestID = "injected-fault"
+
+// Injector injects faults into the AWS calls of connectors given it, for
+// resilience tests against real resources. It does nothing unless
+// enabled under the module's scope:
+//
+//	faults:
+//	  enabled: true
+//	  latency: 200
+//	  latency_jitter: 100
+//	  throttle_rate: 0.1
+//	  error_rate: 0.05
+//	  partial_failure_rate: 0.2
+//	  operations: [SQS.ReceiveMessage, SQS.SendMessage]
+//
+// and each setting may be overridden under a connector's faults key.
+// Latency is in milliseconds and added to every attempt. The rates are
+// the fractions of attempts failing with a throttling error or a 500,
+// which the SDK retries as it would real ones. partial_failure_rate is
+// the fraction of the entries of batch writes (Kinesis PutRecords,
+// Firehose PutRecordBatch, EventBridge PutEvents and DynamoDB
+// BatchWriteItem) reported as failed, although AWS processed them, so
+// callers retry them. operations, as Service.Operation, limits the
+// faults to those operations.
+type Injector struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+}
+
+type settings struct {
+	latency            time.Duration
+	latencyJitter      time.Duration
+	throttleRate       float64
+	errorRate          float64
+	partialFailureRate float64
+	operations         map[string]bool
+}
+
+func Module(scope string) fx.Option {
+
+	var i *Injector
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) (*Injector, error) {
+
+			logger = p.Logger.Named(scope)
+
+			i := &Injector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			i.initDefaultConfigs()
+
+			// Loaded here, as connectors may start before the module
+			if err := moduleconfig.Load(scope, &i.config); err != nil {
+				return nil, err
+			}
+
+			return i, nil
+		}),
+		fx.Populate(&i),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: i.onStart,
+					OnStop:  i.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (i *Injector) onStart(ctx context.Context) error {
+
+	if i.config.Enabled {
+		logger.Warn("Starting fault injection",
+			zap.Int("latency", i.config.Latency),
+			zap.Float64("throttle_rate", i.config.ThrottleRate),
+			zap.Float64("error_rate", i.config.ErrorRate),
+			zap.Float64("partial_failure_rate", i.config.PartialFailureRate),
+		)
+	}
+
+	return nil
+}
+
+func (i *Injector) onStop(ctx context.Context) error {
+
+	i.logger.Info("Stopped fault injection")
+
+	return nil
+}
+
+func (i *Injector) settings(cfg Config) settings {
+	s := settings{
+		latency:            time.Duration(cfg.Latency) * time.Millisecond,
+		latencyJitter:      time.Duration(cfg.LatencyJitter) * time.Millisecond,
+		throttleRate:       cfg.ThrottleRate,
+		errorRate:          cfg.ErrorRate,
+		partialFailureRate: cfg.PartialFailureRate,
+	}
+
+	if len(cfg.Operations) > 0 {
+		s.operations = map[string]bool{}
+		for _, operation := range cfg.Operations {
+			s.operations[operation] = true
+		}
+	}
+
+	return s
+}
+
+func (s settings) applies(ctx context.Context) bool {
+	return s.operations == nil || s.operations[operationOf(ctx)]
+}
+
+// Instrument adds the faults to the clients created from cfg by the
+// connector of scope. Call it before creating them.
+func (i *Injector) Instrument(cfg *aws.Config, scope string) {
+	connectorConfig := i.connectorConfig(scope)
+	if !connectorConfig.Enabled {
+		return
+	}
+
+	s := i.settings(connectorConfig)
+
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		// Latency and errors are injected into every attempt, inside the
+		// retries, and partial failures into the final result.
+		err := stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("FaultInjection",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				if !s.applies(ctx) {
+					return next.HandleFinalize(ctx, in)
+				}
+
+				if err := s.delay(ctx); err != nil {
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+				}
+
+				if err := i.fault(ctx, s); err != nil {
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+				}
+
+				return next.HandleFinalize(ctx, in)
+			},
+		), middleware.After)
+		if err != nil {
+			return err
+		}
+
+		if s.partialFailureRate <= 0 {
+			return nil
+		}
+
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("PartialFailureInjection",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				if err != nil || !s.applies(ctx) {
+					return out, metadata, err
+				}
+
+				if failed := failEntries(in.Parameters, out.Result, s.partialFailureRate); failed > 0 {
+					i.logger.Debug("Injected partial failure",
+						zap.String("connector", scope),
+						zap.String("operation", operationOf(ctx)),
+						zap.Int("entries", failed),
+					)
+				}
+
+				return out, metadata, nil
+			},
+		), middleware.After)
+	})
+}
+
+func (s settings) delay(ctx context.Context) error {
+	latency := s.latency
+	if s.latencyJitter > 0 {
+		latency += time.Duration(rand.Int63n(int64(s.latencyJitter)))
+	}
+
+	if latency <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fault returns the error an attempt fails with, if any, shaped as the
+// SDK returns service errors so retries, breakers and error kinds treat
+// it as a real one.
+func (i *Injector) fault(ctx context.Context, s settings) error {
+	roll := rand.Float64()
+
+	var status int
+	var apiErr *smithy.GenericAPIError
+
+	switch {
+	case roll < s.throttleRate:
+		status = http.StatusBadRequest
+		apiErr = &smithy.GenericAPIError{Code: "ThrottlingException", Message: "injected fault", Fault: smithy.FaultClient}
+	case roll < s.throttleRate+s.errorRate:
+		status = http.StatusInternalServerError
+		apiErr = &smithy.GenericAPIError{Code: "InternalFailure", Message: "injected fault", Fault: smithy.FaultServer}
+	default:
+		return nil
+	}
+
+	i.logger.Debug("Injected fault",
+		zap.String("operation", operationOf(ctx)),
+		zap.String("code", apiErr.Code),
+	)
+
+	return &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status, Header: http.Header{}}},
+			Err:      apiErr,
+		},
+		RequestID: InjectedRequestID,
+	}
+}
+
+func operationOf(ctx context.Context) string {
+	return awsmiddleware.GetServiceID(ctx) + "." + awsmiddleware.GetOperationName(ctx)
+}
@@ -0,0 +1,94 @@
+package faults
+
+import (
+	"math/rand"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/firehose"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+)
+
+const partialFailureMessage = "injected fault"
+
+// failEntries marks a fraction rate of the successful entries of a batch
+// write result as failed, the way each service reports them, and returns
+// how many it marked. Other results are left alone.
+func failEntries(params interface{}, result interface{}, rate float64) int {
+	failed := 0
+
+	switch out := result.(type) {
+	case *kinesis.PutRecordsOutput:
+		for j := range out.Records {
+			entry := &out.Records[j]
+			if entry.ErrorCode != nil || rand.Float64() >= rate {
+				continue
+			}
+
+			entry.ErrorCode = aws.String("ProvisionedThroughputExceededException")
+			entry.ErrorMessage = aws.String(partialFailureMessage)
+			entry.SequenceNumber = nil
+			entry.ShardId = nil
+			failed++
+		}
+
+		out.FailedRecordCount = aws.Int32(aws.ToInt32(out.FailedRecordCount) + int32(failed))
+
+	case *firehose.PutRecordBatchOutput:
+		for j := range out.RequestResponses {
+			entry := &out.RequestResponses[j]
+			if entry.ErrorCode != nil || rand.Float64() >= rate {
+				continue
+			}
+
+			entry.ErrorCode = aws.String("ServiceUnavailableException")
+			entry.ErrorMessage = aws.String(partialFailureMessage)
+			entry.RecordId = nil
+			failed++
+		}
+
+		out.FailedPutCount = aws.Int32(aws.ToInt32(out.FailedPutCount) + int32(failed))
+
+	case *eventbridge.PutEventsOutput:
+		for j := range out.Entries {
+			entry := &out.Entries[j]
+			if entry.ErrorCode != nil || rand.Float64() >= rate {
+				continue
+			}
+
+			entry.ErrorCode = aws.String("ThrottlingException")
+			entry.ErrorMessage = aws.String(partialFailureMessage)
+			entry.EventId = nil
+			failed++
+		}
+
+		out.FailedEntryCount += int32(failed)
+
+	case *dynamodb.BatchWriteItemOutput:
+		in, ok := params.(*dynamodb.BatchWriteItemInput)
+		// Items AWS left unprocessed are already among the requests, so
+		// only results without any are changed
+		if !ok || len(out.UnprocessedItems) > 0 {
+			return 0
+		}
+
+		for table, requests := range in.RequestItems {
+			for _, request := range requests {
+				if rand.Float64() >= rate {
+					continue
+				}
+
+				if out.UnprocessedItems == nil {
+					out.UnprocessedItems = map[string][]dynamodbtypes.WriteRequest{}
+				}
+
+				out.UnprocessedItems[table] = append(out.UnprocessedItems[table], request)
+				failed++
+			}
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,155 @@
+package firehose_connector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/firehose"
+	"github.com/aws/aws-sdk-go-v2/service/firehose/types"
+)
+
+// PutRecordBatch limits
+const (
+	maxBatchRecords = 500
+	maxBatchBytes   = 4 * 1024 * 1024
+	maxRecordBytes  = 1000 * 1024
+)
+
+// Put buffers a record for the next batch. Firehose concatenates records
+// as they are, so records of line based formats need their own newline.
+// When buffer_size records are waiting, Put blocks until a batch has been
+// sent or ctx is done.
+func (c *FirehoseConnector) Put(ctx context.Context, data []byte) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "FirehoseConnector", "Put")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if len(data) > maxRecordBytes {
+		return ErrRecordTooLarge
+	}
+
+	return c.batcher.Add(ctx, data)
+}
+
+// PutJSON buffers v as one newline-terminated JSON line.
+func (c *FirehoseConnector) PutJSON(ctx context.Context, v interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "FirehoseConnector", "PutJSON")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	data, err := JSONLines(v)
+	if err != nil {
+		return err
+	}
+
+	return c.Put(ctx, data)
+}
+
+// JSONLines encodes values as newline-delimited JSON. Several small
+// events packed into one record lower the per-record cost.
+func JSONLines(values ...interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	// Encode appends the newline after every value
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Flush sends all buffered records. Records still failing after
+// max_retries are dropped and reported in the returned error.
+func (c *FirehoseConnector) Flush(ctx context.Context) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "FirehoseConnector", "Flush")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.batcher.Flush(ctx)
+}
+
+// send splits records into PutRecordBatch calls.
+func (c *FirehoseConnector) send(ctx context.Context, records [][]byte) error {
+	if c.client == nil {
+		return fmt.Errorf("%s: connector is not started", c.scope)
+	}
+
+	var errs []error
+	for len(records) > 0 {
+		n, size := 0, 0
+		for n < len(records) && n < maxBatchRecords && size+len(records[n]) <= maxBatchBytes {
+			size += len(records[n])
+			n++
+		}
+
+		batch := make([]types.Record, n)
+		for i, data := range records[:n] {
+			batch[i] = types.Record{Data: data}
+		}
+
+		if err := c.putRecordBatch(ctx, batch); err != nil {
+			errs = append(errs, err)
+		}
+
+		records = records[n:]
+	}
+
+	return errors.Join(errs...)
+}
+
+// putRecordBatch sends one batch, resending only the records that failed
+// with an exponential backoff.
+func (c *FirehoseConnector) putRecordBatch(ctx context.Context, records []types.Record) error {
+	maxRetries := c.config.MaxRetries
+	backoff := 100 * time.Millisecond
+
+	for attempt := 0; ; attempt++ {
+		result, err := c.client.PutRecordBatch(ctx, &firehose.PutRecordBatchInput{
+			DeliveryStreamName: aws.String(c.stream),
+			Records:            records,
+		})
+		if err != nil {
+			c.logger.Error("Put record batch error", zap.Int("count", len(records)), zap.Error(err))
+			return err
+		}
+
+		if aws.ToInt32(result.FailedPutCount) == 0 {
+			return nil
+		}
+
+		var failed []types.Record
+		var lastError string
+		for i, r := range result.RequestResponses {
+			if r.ErrorCode != nil {
+				failed = append(failed, records[i])
+				lastError = aws.ToString(r.ErrorMessage)
+			}
+		}
+
+		if attempt >= maxRetries {
+			c.logger.Error("Records dropped after retries",
+				zap.Int("count", len(failed)),
+				zap.String("last_error", lastError),
+			)
+			return fmt.Errorf("%s: %d records failed: %s", c.scope, len(failed), lastError)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		records = failed
+		backoff *= 2
+	}
+}
@@ -0,0 +1,30 @@
+package firehose_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. FIREHOSE_DELIVERY_STREAM for the
+// firehose scope.
+type Config struct {
+	DeliveryStream    string `mapstructure:"delivery_stream" default:""`
+	BufferSize        int    `mapstructure:"buffer_size" default:"10000"`
+	FlushInterval     int    `mapstructure:"flush_interval" default:"1"`
+	MaxRetries        int    `mapstructure:"max_retries" default:"3"`
+	FirehoseKey       string `mapstructure:"firehose_key" default:"ABCDE"`
+	FirehoseSecret    string `mapstructure:"firehose_secret" default:"example_secret"`
+	FirehoseToken     string `mapstructure:"firehose_token" default:""`
+	FirehoseRegion    string `mapstructure:"firehose_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *FirehoseConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *FirehoseConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,281 @@
+package firehose_connector
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/firehose"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/batcher"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+var ErrRecordTooLarge = awserrors.New(awserrors.ErrValidation, "record exceeds the firehose record size limit")
+
+const (
+	DefaultDeliveryStream = ""
+	DefaultBufferSize     = 10000
+	DefaultFlushInterval  = 1
+	DefaultMaxRetries     = 3
+	DefaultFirehoseKey    = "ABCDE"
+	DefaultFirehoseSecret = "example_secret"
+	DefaultFirehoseToken  = ""
+	DefaultFirehoseRegion = "us-west-1"
+)
+
+var preflightActions = []string{
+	"firehose:PutRecordBatch",
+}
+
+type FirehoseConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *firehose.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	stream  string
+	batcher *batcher.Batcher[[]byte]
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *firehose.Client        `optional:"true"`
+}
+
+// Module provides the FirehoseConnector of scope. It uses the
+// *firehose.Client in the graph, if any, instead of building one, and
+// adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the FirehoseConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *firehose.Client named after scope, if
+// any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *FirehoseConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*firehose.Client](scope, named),
+		instance.Provide[*FirehoseConnector](scope, named, func(p Params) (*FirehoseConnector, error) {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &FirehoseConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			// Loaded here, as the buffer is sized before start
+			if err := moduleconfig.Load(scope, &c.config); err != nil {
+				return nil, err
+			}
+
+			c.batcher = batcher.New(batcher.Options[[]byte]{
+				BufferSize: c.config.BufferSize,
+				BatchSize:  maxBatchRecords,
+				Interval:   time.Duration(c.config.FlushInterval) * time.Second,
+				Block:      true,
+				Send:       c.send,
+				OnError: func(err error) {
+					c.logger.Error("Flush records error", zap.Error(err))
+				},
+			})
+
+			return c, nil
+		}),
+		instance.Export(scope, named, func(c *FirehoseConnector) DeliveryStream {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *FirehoseConnector) onStart(ctx context.Context) error {
+	c.stream = c.config.DeliveryStream
+
+	c.logger.Info("Starting FirehoseConnector",
+		zap.String("delivery_stream", c.stream),
+		zap.String("firehose_region", c.config.FirehoseRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions,
+			"arn:{partition}:firehose:{region}:{account}:deliverystream/"+c.config.DeliveryStream,
+		)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = firehose.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = firehose.New(c.client.Options(), func(o *firehose.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	c.batcher.Start(inflight.Internal(context.Background()))
+
+	return nil
+}
+
+func (c *FirehoseConnector) onStop(ctx context.Context) error {
+
+	c.batcher.Stop()
+
+	c.tracker.Shutdown(ctx)
+
+	// Deliver whatever is left before the process exits
+	if err := c.Flush(inflight.Internal(ctx)); err != nil {
+		c.logger.Warn("Flush records on shutdown error", zap.Error(err))
+	}
+
+	c.logger.Info("Stopped FirehoseConnector")
+
+	return nil
+}
+
+func (c *FirehoseConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.FirehoseRegion,
+		Key:         c.config.FirehoseKey,
+		Secret:      c.config.FirehoseSecret,
+		Token:       c.config.FirehoseToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *FirehoseConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("firehose_region")
+
+	v.Required("delivery_stream")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("firehose_key", DefaultFirehoseKey)
+		v.NotPlaceholder("firehose_secret", DefaultFirehoseSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the delivery stream.
+func (c *FirehoseConnector) probe(ctx context.Context) error {
+	_, err := c.client.DescribeDeliveryStream(ctx, &firehose.DescribeDeliveryStreamInput{
+		DeliveryStreamName: aws.String(c.config.DeliveryStream),
+	})
+
+	return err
+}
+
+func (c *FirehoseConnector) GetClient() *firehose.Client {
+	return c.client
+}
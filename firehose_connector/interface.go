@@ -0,0 +1,14 @@
+package firehose_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . DeliveryStream
+
+// DeliveryStream buffers records for the delivery stream.
+type DeliveryStream interface {
+	Put(ctx context.Context, data []byte) error
+	PutJSON(ctx context.Context, v interface{}) error
+	Flush(ctx context.Context) error
+}
+
+var _ DeliveryStream = (*FirehoseConnector)(nil)
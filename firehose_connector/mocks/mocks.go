@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/firehose_connector (interfaces: DeliveryStream)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . DeliveryStream
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeliveryStream is a mock of DeliveryStream interface.
+type MockDeliveryStream struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryStreamMockRecorder
+}
+
+// MockDeliveryStreamMockRecorder is the mock recorder for MockDeliveryStream.
+type MockDeliveryStreamMockRecorder struct {
+	mock *MockDeliveryStream
+}
+
+// NewMockDeliveryStream creates a new mock instance.
+func NewMockDeliveryStream(ctrl *gomock.Controller) *MockDeliveryStream {
+	mock := &MockDeliveryStream{ctrl: ctrl}
+	mock.recorder = &MockDeliveryStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryStream) EXPECT() *MockDeliveryStreamMockRecorder {
+	return m.recorder
+}
+
+// Flush mocks base method.
+func (m *MockDeliveryStream) Flush(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockDeliveryStreamMockRecorder) Flush(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockDeliveryStream)(nil).Flush), arg0)
+}
+
+// Put mocks base method.
+func (m *MockDeliveryStream) Put(arg0 context.Context, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockDeliveryStreamMockRecorder) Put(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockDeliveryStream)(nil).Put), arg0, arg1)
+}
+
+// PutJSON mocks base method.
+func (m *MockDeliveryStream) PutJSON(arg0 context.Context, arg1 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutJSON", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutJSON indicates an expected call of PutJSON.
+func (mr *MockDeliveryStreamMockRecorder) PutJSON(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutJSON", reflect.TypeOf((*MockDeliveryStream)(nil).PutJSON), arg0, arg1)
+}
@@ -0,0 +1,226 @@
+package glue_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/glue"
+	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// maxPartitionBatch is the number of partitions BatchCreatePartition
+// accepts per call.
+const maxPartitionBatch = 100
+
+var (
+	ErrTableNotFound    = awserrors.New(awserrors.ErrNotFound, "table not found")
+	ErrPartitionsFailed = awserrors.New(awserrors.ErrValidation, "partitions failed")
+)
+
+// GetTable returns a table of the configured database or
+// ErrTableNotFound.
+func (c *GlueConnector) GetTable(ctx context.Context, table string) (_ *types.Table, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "GlueConnector", "GetTable")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.GetTable(ctx, &glue.GetTableInput{
+		CatalogId:    c.catalogID(),
+		DatabaseName: aws.String(c.database()),
+		Name:         aws.String(table),
+	})
+	if err != nil {
+		return nil, c.tableError("Get table error", table, err)
+	}
+
+	return result.Table, nil
+}
+
+// GetPartitions returns the partitions of a table matching expression,
+// e.g. "dt >= '2024-01-01'", or all of them when it is empty.
+func (c *GlueConnector) GetPartitions(ctx context.Context, table string, expression string) (_ []types.Partition, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "GlueConnector", "GetPartitions")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	params := &glue.GetPartitionsInput{
+		CatalogId:    c.catalogID(),
+		DatabaseName: aws.String(c.database()),
+		TableName:    aws.String(table),
+	}
+	if expression != "" {
+		params.Expression = aws.String(expression)
+	}
+
+	paginator := glue.NewGetPartitionsPaginator(c.client, params)
+
+	partitions := []types.Partition{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, c.tableError("Get partitions error", table, err)
+		}
+
+		partitions = append(partitions, page.Partitions...)
+	}
+
+	return partitions, nil
+}
+
+// UpdateTable reads a table, lets update change it and writes it back.
+// The write fails if the table changed in between.
+func (c *GlueConnector) UpdateTable(ctx context.Context, table string, update func(input *types.TableInput) error) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "GlueConnector", "UpdateTable")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	current, err := c.GetTable(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	input := &types.TableInput{
+		Name:              current.Name,
+		Description:       current.Description,
+		Owner:             current.Owner,
+		Parameters:        current.Parameters,
+		PartitionKeys:     current.PartitionKeys,
+		Retention:         current.Retention,
+		StorageDescriptor: current.StorageDescriptor,
+		TableType:         current.TableType,
+		TargetTable:       current.TargetTable,
+		ViewExpandedText:  current.ViewExpandedText,
+		ViewOriginalText:  current.ViewOriginalText,
+		LastAccessTime:    current.LastAccessTime,
+		LastAnalyzedTime:  current.LastAnalyzedTime,
+	}
+
+	if err := update(input); err != nil {
+		return err
+	}
+
+	_, err = c.client.UpdateTable(ctx, &glue.UpdateTableInput{
+		CatalogId:    c.catalogID(),
+		DatabaseName: aws.String(c.database()),
+		TableInput:   input,
+		VersionId:    current.VersionId,
+	})
+	if err != nil {
+		return c.tableError("Update table error", table, err)
+	}
+
+	return nil
+}
+
+// AddPartitions registers partitions of a table, one slice of values per
+// partition in partition key order, e.g. []string{"2024-01-02"} for a
+// table partitioned by dt. Each partition copies the table's storage
+// descriptor with a Hive-style location under the table's, such as
+// s3://bucket/events/dt=2024-01-02/. Existing partitions are skipped.
+func (c *GlueConnector) AddPartitions(ctx context.Context, table string, partitions ...[]string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "GlueConnector", "AddPartitions")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	current, err := c.GetTable(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	if current.StorageDescriptor == nil {
+		return fmt.Errorf("%s: table has no storage descriptor", table)
+	}
+
+	inputs := make([]types.PartitionInput, 0, len(partitions))
+	for _, values := range partitions {
+		if len(values) != len(current.PartitionKeys) {
+			return fmt.Errorf("%s: %d partition values for %d partition keys", table, len(values), len(current.PartitionKeys))
+		}
+
+		descriptor := *current.StorageDescriptor
+		descriptor.Location = aws.String(PartitionLocation(aws.ToString(current.StorageDescriptor.Location), current.PartitionKeys, values))
+
+		inputs = append(inputs, types.PartitionInput{
+			Values:            values,
+			StorageDescriptor: &descriptor,
+		})
+	}
+
+	var failed []string
+	for offset := 0; offset < len(inputs); offset += maxPartitionBatch {
+		result, err := c.client.BatchCreatePartition(ctx, &glue.BatchCreatePartitionInput{
+			CatalogId:          c.catalogID(),
+			DatabaseName:       aws.String(c.database()),
+			TableName:          aws.String(table),
+			PartitionInputList: inputs[offset:min(offset+maxPartitionBatch, len(inputs))],
+		})
+		if err != nil {
+			return c.tableError("Batch create partition error", table, err)
+		}
+
+		for _, e := range result.Errors {
+			if e.ErrorDetail != nil && aws.ToString(e.ErrorDetail.ErrorCode) == "AlreadyExistsException" {
+				continue
+			}
+
+			failed = append(failed, fmt.Sprintf("%s: %s", strings.Join(e.PartitionValues, "/"), errorMessage(e.ErrorDetail)))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s: %s", ErrPartitionsFailed, table, strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// PartitionLocation appends key=value directories for the partition to a
+// table location.
+func PartitionLocation(tableLocation string, keys []types.Column, values []string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSuffix(tableLocation, "/"))
+
+	for i, key := range keys {
+		if i >= len(values) {
+			break
+		}
+
+		b.WriteString("/" + aws.ToString(key.Name) + "=" + values[i])
+	}
+
+	b.WriteString("/")
+
+	return b.String()
+}
+
+func (c *GlueConnector) database() string {
+	return c.config.Database
+}
+
+func (c *GlueConnector) catalogID() *string {
+	if catalogID := c.config.CatalogID; catalogID != "" {
+		return aws.String(catalogID)
+	}
+
+	return nil
+}
+
+func (c *GlueConnector) tableError(msg string, table string, err error) error {
+	var notFound *types.EntityNotFoundException
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("%w: %s: %w", ErrTableNotFound, table, err)
+	}
+
+	c.logger.Error(msg, zap.String("table", table), zap.Error(err))
+	return err
+}
+
+func errorMessage(detail *types.ErrorDetail) string {
+	if detail == nil {
+		return "unknown error"
+	}
+
+	return aws.ToString(detail.ErrorCode) + ": " + aws.ToString(detail.ErrorMessage)
+}
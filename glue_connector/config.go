@@ -0,0 +1,27 @@
+package glue_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. GLUE_DATABASE for the glue scope.
+type Config struct {
+	Database          string `mapstructure:"database" default:"default"`
+	CatalogID         string `mapstructure:"catalog_id" default:""`
+	GlueKey           string `mapstructure:"glue_key" default:"ABCDE"`
+	GlueSecret        string `mapstructure:"glue_secret" default:"example_secret"`
+	GlueToken         string `mapstructure:"glue_token" default:""`
+	GlueRegion        string `mapstructure:"glue_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *GlueConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *GlueConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,19 @@
+package glue_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Catalog
+
+// Catalog reads and updates tables of the database.
+type Catalog interface {
+	GetTable(ctx context.Context, table string) (*types.Table, error)
+	GetPartitions(ctx context.Context, table string, expression string) ([]types.Partition, error)
+	UpdateTable(ctx context.Context, table string, update func(input *types.TableInput) error) error
+	AddPartitions(ctx context.Context, table string, partitions ...[]string) error
+}
+
+var _ Catalog = (*GlueConnector)(nil)
@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/glue_connector (interfaces: Catalog)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . Catalog
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/glue/types"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCatalog is a mock of Catalog interface.
+type MockCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockCatalogMockRecorder
+}
+
+// MockCatalogMockRecorder is the mock recorder for MockCatalog.
+type MockCatalogMockRecorder struct {
+	mock *MockCatalog
+}
+
+// NewMockCatalog creates a new mock instance.
+func NewMockCatalog(ctrl *gomock.Controller) *MockCatalog {
+	mock := &MockCatalog{ctrl: ctrl}
+	mock.recorder = &MockCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCatalog) EXPECT() *MockCatalogMockRecorder {
+	return m.recorder
+}
+
+// AddPartitions mocks base method.
+func (m *MockCatalog) AddPartitions(arg0 context.Context, arg1 string, arg2 ...[]string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddPartitions", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPartitions indicates an expected call of AddPartitions.
+func (mr *MockCatalogMockRecorder) AddPartitions(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPartitions", reflect.TypeOf((*MockCatalog)(nil).AddPartitions), varargs...)
+}
+
+// GetPartitions mocks base method.
+func (m *MockCatalog) GetPartitions(arg0 context.Context, arg1, arg2 string) ([]types.Partition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPartitions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]types.Partition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPartitions indicates an expected call of GetPartitions.
+func (mr *MockCatalogMockRecorder) GetPartitions(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPartitions", reflect.TypeOf((*MockCatalog)(nil).GetPartitions), arg0, arg1, arg2)
+}
+
+// GetTable mocks base method.
+func (m *MockCatalog) GetTable(arg0 context.Context, arg1 string) (*types.Table, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTable", arg0, arg1)
+	ret0, _ := ret[0].(*types.Table)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTable indicates an expected call of GetTable.
+func (mr *MockCatalogMockRecorder) GetTable(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTable", reflect.TypeOf((*MockCatalog)(nil).GetTable), arg0, arg1)
+}
+
+// UpdateTable mocks base method.
+func (m *MockCatalog) UpdateTable(arg0 context.Context, arg1 string, arg2 func(*types.TableInput) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTable", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTable indicates an expected call of UpdateTable.
+func (mr *MockCatalogMockRecorder) UpdateTable(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTable", reflect.TypeOf((*MockCatalog)(nil).UpdateTable), arg0, arg1, arg2)
+}
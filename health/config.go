@@ -0,0 +1,22 @@
+package health
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. HEALTH_LIVE_PATH for the health scope.
+type Config struct {
+	LivePath  string `mapstructure:"live_path" default:"/health/live"`
+	ReadyPath string `mapstructure:"ready_path" default:"/health/ready"`
+	Timeout   int    `mapstructure:"timeout" default:"5"`
+}
+
+func (h *Health) initDefaultConfigs() {
+	moduleconfig.Register(h.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (h *Health) Config() Config {
+	return h.config
+}
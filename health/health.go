@@ -0,0 +1,298 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/elmntri/zeitgeber-common-modules/http_server"
+	"github.com/gin-gonic/gin"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultLivePath  = "/health/live"
+	DefaultReadyPath = "/health/ready"
+	DefaultTimeout   = 5
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Kind says which results a check counts towards. Liveness checks should
+// only fail when the process has to be restarted; readiness checks fail
+// while a dependency, such as an AWS resource, is unreachable.
+type Kind string
+
+const (
+	Liveness  Kind = "liveness"
+	Readiness Kind = "readiness"
+)
+
+// Probe checks one dependency, returning an error when it is unhealthy.
+type Probe func(ctx context.Context) error
+
+// Result is the outcome of one check.
+type Result struct {
+	Name    string        `json:"name"`
+	Status  Status        `json:"status"`
+	Latency time.Duration `json:"-"`
+	Error   string        `json:"error,omitempty"`
+
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// Report is the aggregate of the checks of a kind: up when all of them
+// are.
+type Report struct {
+	Status Status   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Health aggregates the checks of the modules. Connectors given Health
+// register a readiness check named after their scope, probing their
+// resource with a cheap read such as HeadBucket or DescribeTable. The
+// reports are served as JSON, with a 503 when down, at live_path and
+// ready_path when an HTTPServer is provided:
+//
+//	health:
+//	  live_path: /health/live
+//	  ready_path: /health/ready
+//	  timeout: 5
+//
+// timeout is in seconds and applies to each check.
+type Health struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	mu     sync.RWMutex
+	checks map[string]check
+}
+
+type check struct {
+	kind  Kind
+	probe Probe
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle  fx.Lifecycle
+	Logger     *zap.Logger
+	HTTPServer *http_server.HTTPServer `optional:"true"`
+}
+
+func Module(scope string) fx.Option {
+
+	var h *Health
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) *Health {
+
+			logger = p.Logger.Named(scope)
+
+			h := &Health{
+				params: p,
+				logger: logger,
+				scope:  scope,
+				checks: map[string]check{},
+			}
+
+			h.initDefaultConfigs()
+
+			return h
+		}),
+		fx.Populate(&h),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: h.onStart,
+					OnStop:  h.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (h *Health) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(h.scope, &h.config); err != nil {
+		h.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	livePath := h.config.LivePath
+	readyPath := h.config.ReadyPath
+
+	logger.Info("Starting health checks",
+		zap.String("live_path", livePath),
+		zap.String("ready_path", readyPath),
+		zap.Bool("http_server", h.params.HTTPServer != nil),
+	)
+
+	if h.params.HTTPServer != nil {
+		router := h.params.HTTPServer.GetRouter()
+
+		if livePath != "" {
+			router.GET(livePath, h.handle(Liveness))
+		}
+
+		if readyPath != "" {
+			router.GET(readyPath, h.handle(Readiness))
+		}
+	}
+
+	return nil
+}
+
+func (h *Health) onStop(ctx context.Context) error {
+
+	h.logger.Info("Stopped health checks")
+
+	return nil
+}
+
+// Register adds a check of kind named name, replacing any check of the
+// same name.
+func (h *Health) Register(name string, kind Kind, probe Probe) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks[name] = check{kind: kind, probe: probe}
+}
+
+// Unregister removes the check named name.
+func (h *Health) Unregister(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.checks, name)
+}
+
+// Live runs the liveness checks. It is up when there are none.
+func (h *Health) Live(ctx context.Context) Report {
+	return h.Check(ctx, Liveness)
+}
+
+// Ready runs the liveness and readiness checks, since a process that is
+// not live is not ready either.
+func (h *Health) Ready(ctx context.Context) Report {
+	return h.Check(ctx, Liveness, Readiness)
+}
+
+// Check runs the checks of the given kinds concurrently, each within
+// the configured timeout, and reports them ordered by name.
+func (h *Health) Check(ctx context.Context, kinds ...Kind) Report {
+	h.mu.RLock()
+	names := make([]string, 0, len(h.checks))
+	probes := make([]Probe, 0, len(h.checks))
+	for name, c := range h.checks {
+		for _, kind := range kinds {
+			if c.kind == kind {
+				names = append(names, name)
+				probes = append(probes, c.probe)
+				break
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	timeout := time.Duration(h.config.Timeout) * time.Second
+
+	results := make([]Result, len(names))
+
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			results[i] = run(ctx, names[i], probes[i], timeout)
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		if result.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func run(ctx context.Context, name string, probe Probe, timeout time.Duration) Result {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := probe(ctx)
+	latency := time.Since(start)
+
+	result := Result{
+		Name:      name,
+		Status:    StatusUp,
+		Latency:   latency,
+		LatencyMS: float64(latency.Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func (h *Health) handle(kind Kind) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var report Report
+		if kind == Liveness {
+			report = h.Live(ctx.Request.Context())
+		} else {
+			report = h.Ready(ctx.Request.Context())
+		}
+
+		status := http.StatusOK
+		if report.Status == StatusDown {
+			status = http.StatusServiceUnavailable
+
+			for _, result := range report.Checks {
+				if result.Status == StatusDown {
+					h.logger.Warn("Health check failed",
+						zap.String("check", result.Name),
+						zap.String("kind", string(kind)),
+						zap.String("error", result.Error),
+					)
+				}
+			}
+		}
+
+		ctx.JSON(status, report)
+	}
+}
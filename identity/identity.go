@@ -0,0 +1,48 @@
+package identity
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// Connectors read this as the default of their verify_credentials key.
+const DefaultVerifyCredentials = false
+
+var ErrInvalidCredentials = awserrors.New(awserrors.ErrUnauthorized, "invalid AWS credentials")
+
+type Identity struct {
+	Account string
+	Arn     string
+	UserID  string
+}
+
+// Verify calls sts:GetCallerIdentity with cfg and logs who the
+// credentials belong to. Any failure is returned wrapped in
+// ErrInvalidCredentials, so a misconfigured connector fails at startup
+// instead of at its first operation.
+func Verify(ctx context.Context, cfg aws.Config, logger *zap.Logger) (*Identity, error) {
+	result, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		logger.Error("Verify credentials error", zap.String("region", cfg.Region), zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	id := &Identity{
+		Account: aws.ToString(result.Account),
+		Arn:     aws.ToString(result.Arn),
+		UserID:  aws.ToString(result.UserId),
+	}
+
+	logger.Info("Verified credentials",
+		zap.String("account", id.Account),
+		zap.String("arn", id.Arn),
+	)
+
+	return id, nil
+}
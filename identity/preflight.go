@@ -0,0 +1,127 @@
+package identity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Connectors read this as the default of their preflight key.
+const DefaultPreflight = false
+
+// Preflight simulates the caller's IAM policies for actions on resources
+// and logs a report of the actions that would be denied, so a missing
+// permission shows up at startup rather than on first use. Resources may
+// contain {partition}, {region} and {account}, filled in from the
+// caller; without any, actions are simulated on "*". An action counts as
+// allowed when it is allowed on any of the resources.
+//
+// Preflight never fails: it returns the denied actions, and only warns
+// when the simulation cannot run, e.g. without iam:SimulatePrincipalPolicy.
+func Preflight(ctx context.Context, cfg aws.Config, logger *zap.Logger, actions []string, resources ...string) []string {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	caller, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		logger.Warn("Preflight skipped", zap.Error(err))
+		return nil
+	}
+
+	callerArn, err := arn.Parse(aws.ToString(caller.Arn))
+	if err != nil {
+		logger.Warn("Preflight skipped", zap.Error(err))
+		return nil
+	}
+
+	principalArn, err := principal(callerArn)
+	if err != nil {
+		logger.Warn("Preflight skipped", zap.Error(err))
+		return nil
+	}
+
+	replacer := strings.NewReplacer(
+		"{partition}", callerArn.Partition,
+		"{region}", cfg.Region,
+		"{account}", callerArn.AccountID,
+	)
+
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalArn),
+		ActionNames:     actions,
+	}
+	for _, resource := range resources {
+		input.ResourceArns = append(input.ResourceArns, replacer.Replace(resource))
+	}
+
+	allowed := make(map[string]bool, len(actions))
+
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(iam.NewFromConfig(cfg), input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			logger.Warn("Preflight skipped", zap.String("principal", principalArn), zap.Error(err))
+			return nil
+		}
+
+		for _, result := range page.EvaluationResults {
+			if result.EvalDecision == types.PolicyEvaluationDecisionTypeAllowed {
+				allowed[aws.ToString(result.EvalActionName)] = true
+			}
+		}
+	}
+
+	var denied []string
+	for _, action := range actions {
+		if !allowed[action] {
+			denied = append(denied, action)
+		}
+	}
+	sort.Strings(denied)
+
+	if len(denied) > 0 {
+		logger.Warn("Missing permissions",
+			zap.String("principal", principalArn),
+			zap.Strings("actions", denied),
+			zap.Strings("resources", input.ResourceArns),
+		)
+		return denied
+	}
+
+	logger.Info("Verified permissions",
+		zap.String("principal", principalArn),
+		zap.Int("actions", len(actions)),
+	)
+
+	return nil
+}
+
+// principal turns the caller into a principal IAM can simulate. An
+// assumed role maps to its role, assuming the role has no path.
+func principal(caller arn.ARN) (string, error) {
+	switch {
+	case caller.Service == "iam" && strings.HasPrefix(caller.Resource, "user/"):
+		return caller.String(), nil
+
+	case caller.Service == "sts" && strings.HasPrefix(caller.Resource, "assumed-role/"):
+		parts := strings.Split(caller.Resource, "/")
+		return arn.ARN{
+			Partition: caller.Partition,
+			Service:   "iam",
+			AccountID: caller.AccountID,
+			Resource:  "role/" + parts[1],
+		}.String(), nil
+	}
+
+	return "", fmt.Errorf("cannot simulate policies of %s", caller.String())
+}
@@ -0,0 +1,36 @@
+package inflight
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+const topLevelScope = "aws"
+
+// Config holds the shutdown keys of a connector. They are read from the
+// top-level aws key and then from the connector's scope, so its
+// overrides win. The timeout is in seconds, and shutdown never waits
+// past the deadline of the stop context either:
+//
+//	aws:
+//	  shutdown_timeout: 30
+//	sqs:
+//	  shutdown_timeout: 60
+type Config struct {
+	// No default, so that an unset key under the connector's scope
+	// keeps the top-level value
+	ShutdownTimeout int `mapstructure:"shutdown_timeout"`
+}
+
+func loadConfig(scope string) (Config, error) {
+	cfg := Config{ShutdownTimeout: DefaultShutdownTimeout}
+
+	for _, key := range []string{topLevelScope, scope} {
+		moduleconfig.Register(key, &Config{})
+
+		if err := moduleconfig.Load(key, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	return cfg, nil
+}
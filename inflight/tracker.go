@@ -0,0 +1,184 @@
+package inflight
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+const DefaultShutdownTimeout = 30
+
+var ErrShuttingDown = awserrors.New(awserrors.ErrTransient, "shutting down")
+
+// Operation is an AWS call, or other unit of work, in flight.
+type Operation struct {
+	Name    string
+	Started time.Time
+}
+
+// Tracker follows the operations of a connector, its AWS calls and the
+// handlers of the messages it consumes, so stopping it waits for
+// them to complete, and rejects operations started after it began
+// stopping with ErrShuttingDown. Calls made with an Internal context,
+// such as those of background loops and final flushes, are tracked but
+// never rejected.
+type Tracker struct {
+	scope  string
+	logger *zap.Logger
+	config Config
+
+	mu      sync.Mutex
+	closed  bool
+	next    uint64
+	ops     map[uint64]Operation
+	drained chan struct{}
+}
+
+// NewTracker returns the tracker of the connector of scope, with its
+// shutdown timeout loaded.
+func NewTracker(scope string, logger *zap.Logger) (*Tracker, error) {
+	config, err := loadConfig(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tracker{
+		scope:  scope,
+		logger: logger,
+		config: config,
+		ops:    map[uint64]Operation{},
+	}, nil
+}
+
+type internalKey struct{}
+
+// Internal marks ctx as belonging to the connector itself, so its calls
+// go through while the connector is stopping.
+func Internal(ctx context.Context) context.Context {
+	return context.WithValue(ctx, internalKey{}, true)
+}
+
+func isInternal(ctx context.Context) bool {
+	internal, _ := ctx.Value(internalKey{}).(bool)
+	return internal
+}
+
+// Begin records the start of the operation name. Call done when it
+// completes. It tracks nothing on a nil Tracker.
+func (t *Tracker) Begin(ctx context.Context, name string) (done func(), err error) {
+	if t == nil {
+		return func() {}, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed && !isInternal(ctx) {
+		return nil, fmt.Errorf("%s: %s: %w", t.scope, name, ErrShuttingDown)
+	}
+
+	id := t.next
+	t.next++
+	t.ops[id] = Operation{Name: name, Started: time.Now()}
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		delete(t.ops, id)
+
+		if len(t.ops) == 0 && t.drained != nil {
+			close(t.drained)
+			t.drained = nil
+		}
+	}, nil
+}
+
+// Instrument tracks the operations of the clients created from cfg.
+// Call it before creating them.
+func (t *Tracker) Instrument(cfg *aws.Config) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("InflightTracker",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				done, err := t.Begin(ctx, awsmiddleware.GetServiceID(ctx)+"."+awsmiddleware.GetOperationName(ctx))
+				if err != nil {
+					return middleware.InitializeOutput{}, middleware.Metadata{}, err
+				}
+				defer done()
+
+				return next.HandleInitialize(ctx, in)
+			},
+		), middleware.After)
+	})
+}
+
+// Shutdown rejects new operations and waits for those in flight, until
+// the shutdown timeout or the deadline of ctx. It logs and returns the
+// operations it gave up on. It does nothing on a nil Tracker, as when
+// the connector failed to start.
+func (t *Tracker) Shutdown(ctx context.Context) []Operation {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	t.closed = true
+
+	if len(t.ops) == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+
+	drained := make(chan struct{})
+	t.drained = drained
+	pending := len(t.ops)
+	t.mu.Unlock()
+
+	timeout := time.Duration(t.config.ShutdownTimeout) * time.Second
+
+	t.logger.Info("Waiting for operations in flight",
+		zap.Int("operations", pending),
+		zap.Duration("timeout", timeout),
+	)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-drained:
+		return nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	abandoned := t.InFlight()
+	for _, op := range abandoned {
+		t.logger.Warn("Abandoned operation in flight",
+			zap.String("operation", op.Name),
+			zap.Duration("running", time.Since(op.Started)),
+		)
+	}
+
+	return abandoned
+}
+
+// InFlight returns the operations in flight.
+func (t *Tracker) InFlight() []Operation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ops := make([]Operation, 0, len(t.ops))
+	for _, op := range t.ops {
+		ops = append(ops, op)
+	}
+
+	return ops
+}
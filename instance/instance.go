@@ -0,0 +1,69 @@
+// Package instance lets an app run several instances of a connector, each
+// added with its package's NamedModule instead of Module. fx keys values
+// by type, so the instances are provided named after their scope and
+// injected with a name tag:
+//
+//	fx.New(
+//		bucket_connector.NamedModule("uploads"),
+//		bucket_connector.NamedModule("archive"),
+//		fx.Invoke(func(p struct {
+//			fx.In
+//
+//			Uploads bucket_connector.ObjectStore `name:"uploads"`
+//			Archive bucket_connector.ObjectStore `name:"archive"`
+//		}) {
+//			...
+//		}),
+//	)
+//
+// Within its own fx.Module an instance stays unnamed, so the module's
+// hooks find it as with Module. An instance uses the SDK client named
+// after its scope, if any, so each may be given its own:
+//
+//	fx.Provide(fx.Annotate(newArchiveClient, fx.ResultTags(instance.Tag("archive"))))
+package instance
+
+import (
+	"fmt"
+
+	"go.uber.org/fx"
+)
+
+// Tag is the fx tag of the values of the instance of scope.
+func Tag(scope string) string {
+	return fmt.Sprintf(`name:"%s"`, scope)
+}
+
+// Provide provides the T built by constructor. When named, the app gets
+// it named after scope and the unnamed one is private to the module.
+func Provide[T any](scope string, named bool, constructor interface{}) fx.Option {
+	if !named {
+		return fx.Provide(constructor)
+	}
+
+	return fx.Options(
+		fx.Provide(constructor, fx.Private),
+		fx.Provide(fx.Annotate(func(v T) T { return v }, fx.ResultTags(Tag(scope)))),
+	)
+}
+
+// Export provides what fn derives from the module's own values, such as
+// the interfaces the connector implements, named after scope when named.
+func Export(scope string, named bool, fn interface{}) fx.Option {
+	if !named {
+		return fx.Provide(fn)
+	}
+
+	return fx.Provide(fx.Annotate(fn, fx.ResultTags(Tag(scope))))
+}
+
+// Client makes the module's Params get the T, usually an SDK client,
+// named after scope when named, instead of the unnamed one, which every
+// instance would otherwise share.
+func Client[T any](scope string, named bool) fx.Option {
+	if !named {
+		return fx.Options()
+	}
+
+	return fx.Decorate(fx.Annotate(func(v T) T { return v }, fx.ParamTags(Tag(scope)+` optional:"true"`)))
+}
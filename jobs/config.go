@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. JOBS_CONSUME for the jobs scope.
+type Config struct {
+	Consume           bool   `mapstructure:"consume" default:"true"`
+	Concurrency       int    `mapstructure:"concurrency" default:"1"`
+	MaxMessages       int    `mapstructure:"max_messages" default:"10"`
+	WaitSeconds       int    `mapstructure:"wait_seconds" default:"20"`
+	VisibilityTimeout int    `mapstructure:"visibility_timeout" default:"30"`
+	JobTimeout        int    `mapstructure:"job_timeout" default:"300"`
+	RetryInterval     int    `mapstructure:"retry_interval" default:"5"`
+	MaxAttempts       int    `mapstructure:"max_attempts" default:"5"`
+	BackoffBase       int    `mapstructure:"backoff_base" default:"10"`
+	BackoffMax        int    `mapstructure:"backoff_max" default:"900"`
+	DLQQueueURL       string `mapstructure:"dlq_queue_url" default:""`
+	PriorityQueueURL  string `mapstructure:"priority_queue_url" default:""`
+	Idempotent        bool   `mapstructure:"idempotent" default:"false"`
+	DedupTable        string `mapstructure:"dedup_table" default:"job_dedup"`
+	DedupTTL          int    `mapstructure:"dedup_ttl" default:"86400"`
+	DedupLease        int    `mapstructure:"dedup_lease" default:"900"`
+}
+
+func (q *Queue) initDefaultConfigs() {
+	moduleconfig.Register(q.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (q *Queue) Config() Config {
+	return q.config
+}
@@ -0,0 +1,153 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// The dedup table needs a string hash key named dedup_key, with TTL
+// enabled on expires_at so records of old jobs are removed.
+const (
+	attrDedupKey     = "dedup_key"
+	attrStatus       = "status"
+	attrLeaseExpires = "lease_expires_at"
+	attrExpires      = "expires_at"
+)
+
+const (
+	statusRunning = "running"
+	statusDone    = "done"
+)
+
+type claimResult int
+
+const (
+	claimAcquired claimResult = iota
+	claimDone
+	claimRunning
+)
+
+func (q *Queue) idempotent() bool {
+	return q.config.Idempotent
+}
+
+func (q *Queue) dedupTable() string {
+	return q.config.DedupTable
+}
+
+func (q *Queue) dedupLease() time.Duration {
+	return time.Duration(q.config.DedupLease) * time.Second
+}
+
+func (q *Queue) dedupTTL() time.Duration {
+	return time.Duration(q.config.DedupTTL) * time.Second
+}
+
+// dedupKey identifies a job in the dedup table: its business dedup key
+// when it was enqueued with one, its ID otherwise. Keys are scoped to the
+// job type.
+func dedupKey(job *Job) map[string]dynamodbtypes.AttributeValue {
+	key := job.DedupKey
+	if key == "" {
+		key = job.ID
+	}
+
+	return map[string]dynamodbtypes.AttributeValue{
+		attrDedupKey: &dynamodbtypes.AttributeValueMemberS{Value: job.Type + "#" + key},
+	}
+}
+
+// claim records that a job is running unless it already completed or
+// another worker holds an unexpired claim on it. Claims last dedup_lease
+// seconds, so jobs of crashed workers run again.
+func (q *Queue) claim(ctx context.Context, job *Job) (claimResult, error) {
+	now := time.Now()
+
+	update := expression.
+		Set(expression.Name(attrStatus), expression.Value(statusRunning)).
+		Set(expression.Name(attrLeaseExpires), expression.Value(now.Add(q.dedupLease()).Unix())).
+		Set(expression.Name(attrExpires), expression.Value(now.Add(q.dedupTTL()).Unix()))
+
+	cond := expression.AttributeNotExists(expression.Name(attrDedupKey)).
+		Or(expression.And(
+			expression.Name(attrStatus).Equal(expression.Value(statusRunning)),
+			expression.Name(attrLeaseExpires).LessThan(expression.Value(now.Unix())),
+		))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = q.params.DynamoDB.GetClient().UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                           aws.String(q.dedupTable()),
+		Key:                                 dedupKey(job),
+		UpdateExpression:                    expr.Update(),
+		ConditionExpression:                 expr.Condition(),
+		ExpressionAttributeNames:            expr.Names(),
+		ExpressionAttributeValues:           expr.Values(),
+		ReturnValuesOnConditionCheckFailure: dynamodbtypes.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err != nil {
+		var ccf *dynamodbtypes.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			if status, ok := ccf.Item[attrStatus].(*dynamodbtypes.AttributeValueMemberS); ok && status.Value == statusDone {
+				return claimDone, nil
+			}
+
+			return claimRunning, nil
+		}
+
+		q.logger.Error("Claim job error", zap.String("job_id", job.ID), zap.Error(err))
+		return 0, err
+	}
+
+	return claimAcquired, nil
+}
+
+// complete marks a claimed job as done for dedup_ttl seconds.
+func (q *Queue) complete(job *Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := expression.
+		Set(expression.Name(attrStatus), expression.Value(statusDone)).
+		Set(expression.Name(attrExpires), expression.Value(time.Now().Add(q.dedupTTL()).Unix())).
+		Remove(expression.Name(attrLeaseExpires))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err == nil {
+		_, err = q.params.DynamoDB.GetClient().UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(q.dedupTable()),
+			Key:                       dedupKey(job),
+			UpdateExpression:          expr.Update(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		})
+	}
+	if err != nil {
+		q.logger.Error("Complete job claim error", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+// release drops the claim of a failed job so its retry can run.
+func (q *Queue) release(job *Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := q.params.DynamoDB.GetClient().DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(q.dedupTable()),
+		Key:       dedupKey(job),
+	})
+	if err != nil {
+		q.logger.Error("Release job claim error", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
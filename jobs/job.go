@@ -0,0 +1,189 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/google/uuid"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// maxDelay is the longest delay SQS accepts for a message.
+const maxDelay = 15 * time.Minute
+
+// jobTypeAttribute carries the job type so queues can be inspected
+// without decoding bodies.
+const jobTypeAttribute = "JobType"
+
+var (
+	ErrUnknownJobType  = awserrors.New(awserrors.ErrValidation, "no handler for job type")
+	ErrPermanent       = awserrors.New(awserrors.ErrValidation, "permanent job failure")
+	ErrNoPriorityQueue = awserrors.New(awserrors.ErrValidation, "no priority queue configured")
+)
+
+type Priority int
+
+const (
+	PriorityNormal Priority = iota
+	PriorityHigh
+)
+
+// Job is a job as its handler receives it. Attempt counts deliveries,
+// starting at 1.
+type Job struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+	DedupKey   string          `json:"dedup_key,omitempty"`
+	Attempt    int             `json:"-"`
+}
+
+// Handler runs a job. Returning an error retries the job after a
+// backoff; wrap it with Permanent to give up right away.
+type Handler func(ctx context.Context, job *Job) error
+
+// Typed adapts a handler taking the decoded payload, e.g.
+//
+//	queue.Handle("send-invoice", jobs.Typed(sendInvoice))
+//
+// Payloads that do not decode fail permanently.
+func Typed[T any](handler func(ctx context.Context, job *Job, payload T) error) Handler {
+	return func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return Permanent(err)
+		}
+
+		return handler(ctx, job, payload)
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() []error {
+	return []error{ErrPermanent, e.err}
+}
+
+// Permanent marks a job error as not worth retrying; the job goes to the
+// dead letter queue at once.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// EnqueueOptions overrides how a job is sent. Delay postpones the first
+// delivery by up to 15 minutes; standard queues only. PriorityHigh jobs
+// go to priority_queue_url, which workers drain first. On FIFO queues,
+// DedupKey drops duplicates sent within five minutes and Group orders
+// jobs sharing it; the group defaults to the job type. With idempotent
+// set, DedupKey also keeps duplicates from running for dedup_ttl, on any
+// queue.
+type EnqueueOptions struct {
+	Delay    time.Duration
+	Priority Priority
+	DedupKey string
+	Group    string
+}
+
+// Enqueue sends a job of jobType with payload encoded as JSON and
+// returns its ID.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload interface{}) (string, error) {
+	return q.EnqueueWithOptions(ctx, jobType, payload, EnqueueOptions{})
+}
+
+func (q *Queue) EnqueueWithOptions(ctx context.Context, jobType string, payload interface{}, opts EnqueueOptions) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	job := Job{
+		ID:         uuid.New().String(),
+		Type:       jobType,
+		Payload:    data,
+		EnqueuedAt: time.Now().UTC(),
+		DedupKey:   opts.DedupKey,
+	}
+
+	body, err := json.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+
+	queueURL, err := q.queueURL(ctx, opts.Priority)
+	if err != nil {
+		return "", err
+	}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(queueURL),
+		MessageBody: aws.String(string(body)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			jobTypeAttribute: {DataType: aws.String("String"), StringValue: aws.String(jobType)},
+		},
+	}
+
+	if strings.HasSuffix(queueURL, ".fifo") {
+		if opts.Delay > 0 {
+			return "", fmt.Errorf("%s: FIFO queues do not delay single jobs", q.scope)
+		}
+
+		group := opts.Group
+		if group == "" {
+			group = jobType
+		}
+
+		dedupKey := opts.DedupKey
+		if dedupKey == "" {
+			dedupKey = job.ID
+		}
+
+		input.MessageGroupId = aws.String(group)
+		input.MessageDeduplicationId = aws.String(dedupKey)
+	} else {
+		if opts.Group != "" {
+			return "", fmt.Errorf("%s: groups need a FIFO queue", q.scope)
+		}
+
+		if opts.DedupKey != "" && !q.idempotent() {
+			return "", fmt.Errorf("%s: dedup keys need a FIFO queue or idempotent", q.scope)
+		}
+
+		input.DelaySeconds = int32(min(opts.Delay, maxDelay) / time.Second)
+	}
+
+	if _, err := q.params.SQS.GetClient().SendMessage(ctx, input); err != nil {
+		q.logger.Error("Enqueue job error", zap.String("job_type", jobType), zap.Error(err))
+		return "", err
+	}
+
+	return job.ID, nil
+}
+
+func (q *Queue) queueURL(ctx context.Context, priority Priority) (string, error) {
+	if priority == PriorityHigh {
+		queueURL := q.config.PriorityQueueURL
+		if queueURL == "" {
+			return "", ErrNoPriorityQueue
+		}
+
+		return queueURL, nil
+	}
+
+	return q.params.SQS.GetQueueURL(ctx)
+}
@@ -0,0 +1,190 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/elmntri/zeitgeber-aws-modules/cloudwatch_metrics_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/dynamodb_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/sqs_connector"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultConsume           = true
+	DefaultConcurrency       = 1
+	DefaultMaxMessages       = 10
+	DefaultWaitSeconds       = 20
+	DefaultVisibilityTimeout = 30
+	DefaultJobTimeout        = 300
+	DefaultRetryInterval     = 5
+	DefaultMaxAttempts       = 5
+	DefaultBackoffBase       = 10
+	DefaultBackoffMax        = 900
+	DefaultDLQQueueURL       = ""
+	DefaultPriorityQueueURL  = ""
+	DefaultIdempotent        = false
+	DefaultDedupTable        = "job_dedup"
+	DefaultDedupTTL          = 86400
+	DefaultDedupLease        = 900
+)
+
+// Queue runs background jobs on the SQS connector's queue. Enqueue sends
+// a job; with consume set, concurrency workers receive jobs and run the
+// handler registered for their type. Each worker runs the jobs of a
+// batch, up to max_messages, at the same time. A running job's message
+// is kept hidden by extending it visibility_timeout seconds at a time,
+// and the job is cancelled after job_timeout seconds. Failed jobs are
+// retried after an exponential backoff and moved to dlq_queue_url after
+// max_attempts.
+//
+// With idempotent set, jobs are claimed in dedup_table of the DynamoDB
+// connector before they run and stay recorded for dedup_ttl seconds once
+// done, so redelivered or re-enqueued duplicates are deleted without
+// running the handler again.
+type Queue struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+
+	tracker    *inflight.Tracker
+	cancel     context.CancelFunc
+	cancelJobs context.CancelFunc
+	workers    sync.WaitGroup
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+	SQS       *sqs_connector.SQSConnector
+	DynamoDB  *dynamodb_connector.DynamoDBConnector                    `optional:"true"`
+	Metrics   *cloudwatch_metrics_connector.CloudWatchMetricsConnector `optional:"true"`
+}
+
+func Module(scope string) fx.Option {
+
+	var q *Queue
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) *Queue {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			q := &Queue{
+				params:   p,
+				logger:   logger,
+				scope:    scope,
+				handlers: make(map[string]Handler),
+			}
+
+			q.initDefaultConfigs()
+
+			return q
+		}),
+		fx.Populate(&q),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: q.onStart,
+					OnStop:  q.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (q *Queue) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(q.scope, &q.config); err != nil {
+		q.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	consume := q.config.Consume
+	concurrency := max(q.config.Concurrency, 1)
+
+	q.mu.RLock()
+	jobTypes := len(q.handlers)
+	q.mu.RUnlock()
+
+	logger.Info("Starting job queue",
+		zap.Bool("consume", consume),
+		zap.Int("job_types", jobTypes),
+		zap.Int("concurrency", concurrency),
+		zap.Bool("idempotent", q.idempotent()),
+	)
+
+	if q.idempotent() && q.params.DynamoDB == nil {
+		return fmt.Errorf("%s: idempotent needs the DynamoDB connector", q.scope)
+	}
+
+	if !consume {
+		return nil
+	}
+
+	tracker, err := inflight.NewTracker(q.scope, q.logger)
+	if err != nil {
+		q.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+	q.tracker = tracker
+
+	pollCtx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+
+	// Jobs outlive the workers' polling so they can finish during shutdown
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	q.cancelJobs = cancelJobs
+
+	for i := 0; i < concurrency; i++ {
+		q.workers.Add(1)
+		go q.workLoop(pollCtx, jobCtx)
+	}
+
+	return nil
+}
+
+// onStop stops receiving and waits for running jobs until the shutdown
+// timeout or ctx is done, then cancels them. Cancelled jobs are retried
+// like failed ones.
+func (q *Queue) onStop(ctx context.Context) error {
+
+	if q.cancel != nil {
+		q.cancel()
+
+		if abandoned := q.tracker.Shutdown(ctx); len(abandoned) > 0 {
+			q.logger.Warn("Cancelling running jobs", zap.Int("jobs", len(abandoned)))
+		}
+
+		q.cancelJobs()
+		q.workers.Wait()
+	}
+
+	q.logger.Info("Stopped job queue")
+
+	return nil
+}
+
+// Handle registers the handler of a job type. Register handlers before
+// the app starts.
+func (q *Queue) Handle(jobType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.handlers[jobType] = handler
+}
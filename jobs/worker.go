@@ -0,0 +1,370 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/elmntri/zeitgeber-aws-modules/cloudwatch_metrics_connector"
+)
+
+// errorAttribute carries the last error of a job moved to the dead
+// letter queue.
+const errorAttribute = "JobError"
+
+func (q *Queue) workLoop(ctx context.Context, jobCtx context.Context) {
+	defer q.workers.Done()
+
+	retryInterval := time.Duration(q.config.RetryInterval) * time.Second
+	priorityQueueURL := q.config.PriorityQueueURL
+
+	for ctx.Err() == nil {
+		queueURL, err := q.params.SQS.GetQueueURL(ctx)
+		if err == nil {
+			err = q.receive(ctx, jobCtx, priorityQueueURL, queueURL)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			q.logger.Error("Receive jobs error", zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+		}
+	}
+}
+
+// receive runs a batch of jobs, taking them from the priority queue when
+// it has any and long polling the queue otherwise.
+func (q *Queue) receive(ctx context.Context, jobCtx context.Context, priorityQueueURL string, queueURL string) error {
+	if priorityQueueURL != "" {
+		messages, err := q.receiveMessages(ctx, priorityQueueURL, 0)
+		if err != nil {
+			return err
+		}
+
+		if len(messages) > 0 {
+			q.runAll(jobCtx, priorityQueueURL, messages)
+			return nil
+		}
+	}
+
+	messages, err := q.receiveMessages(ctx, queueURL, int32(q.config.WaitSeconds))
+	if err != nil {
+		return err
+	}
+
+	q.runAll(jobCtx, queueURL, messages)
+
+	return nil
+}
+
+func (q *Queue) receiveMessages(ctx context.Context, queueURL string, waitSeconds int32) ([]types.Message, error) {
+	result, err := q.params.SQS.GetClient().ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:              aws.String(queueURL),
+		MaxNumberOfMessages:   int32(min(max(q.config.MaxMessages, 1), 10)),
+		WaitTimeSeconds:       waitSeconds,
+		VisibilityTimeout:     q.visibilityTimeout(),
+		MessageAttributeNames: []string{"All"},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+			types.MessageSystemAttributeNameApproximateReceiveCount,
+			types.MessageSystemAttributeNameMessageGroupId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Messages, nil
+}
+
+// runAll runs the jobs of a batch at the same time and waits for them,
+// so a slow job does not hold the others back past their visibility
+// timeout.
+func (q *Queue) runAll(ctx context.Context, queueURL string, messages []types.Message) {
+	var wg sync.WaitGroup
+
+	for _, msg := range messages {
+		wg.Add(1)
+		go func(msg types.Message) {
+			defer wg.Done()
+			q.run(ctx, queueURL, msg)
+		}(msg)
+	}
+
+	wg.Wait()
+}
+
+func (q *Queue) run(ctx context.Context, queueURL string, msg types.Message) {
+	done, err := q.tracker.Begin(ctx, "job "+aws.ToString(msg.MessageId))
+	if err != nil {
+		// Stopping; the message comes back after its visibility timeout
+		return
+	}
+	defer done()
+
+	var job Job
+	if err := json.Unmarshal([]byte(aws.ToString(msg.Body)), &job); err != nil {
+		q.logger.Error("Decode job error", zap.String("message_id", aws.ToString(msg.MessageId)), zap.Error(err))
+		q.fail(queueURL, msg, &job, Permanent(err))
+		return
+	}
+
+	job.Attempt, _ = strconv.Atoi(msg.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+	job.Attempt = max(job.Attempt, 1)
+
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+
+	if !ok {
+		q.fail(queueURL, msg, &job, Permanent(fmt.Errorf("%w: %s", ErrUnknownJobType, job.Type)))
+		return
+	}
+
+	if q.idempotent() && !q.claimOrSkip(ctx, queueURL, msg, &job) {
+		return
+	}
+
+	stop := q.heartbeat(queueURL, msg, &job)
+
+	start := time.Now()
+	err = q.call(ctx, handler, &job)
+	q.record(&job, err, time.Since(start))
+
+	stop()
+
+	if err != nil {
+		if q.idempotent() {
+			q.release(&job)
+		}
+
+		q.fail(queueURL, msg, &job, err)
+		return
+	}
+
+	if q.idempotent() {
+		q.complete(&job)
+	}
+
+	q.delete(queueURL, msg, &job)
+}
+
+// claimOrSkip claims a job before it runs. Duplicates of completed jobs
+// are deleted; duplicates of jobs another worker is running come back
+// once its claim expires.
+func (q *Queue) claimOrSkip(ctx context.Context, queueURL string, msg types.Message, job *Job) bool {
+	claim, err := q.claim(ctx, job)
+	if err != nil {
+		q.fail(queueURL, msg, job, err)
+		return false
+	}
+
+	switch claim {
+	case claimDone:
+		q.logger.Info("Skipping duplicate job", zap.String("job_id", job.ID), zap.String("job_type", job.Type))
+		q.delete(queueURL, msg, job)
+		return false
+
+	case claimRunning:
+		q.logger.Info("Deferring job running elsewhere", zap.String("job_id", job.ID), zap.String("job_type", job.Type))
+		q.changeVisibility(queueURL, msg, job, int32(min(q.dedupLease()/time.Second, 43200)))
+		return false
+	}
+
+	return true
+}
+
+// call runs handler for at most job_timeout seconds, turning a panic
+// into a job error.
+func (q *Queue) call(ctx context.Context, handler Handler, job *Job) (err error) {
+	if timeout := q.config.JobTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// visibilityTimeout is how long a received message stays hidden, zero
+// for the queue's own timeout.
+func (q *Queue) visibilityTimeout() int32 {
+	return int32(min(max(q.config.VisibilityTimeout, 0), 43200))
+}
+
+// heartbeat keeps the job's message hidden while it runs, extending its
+// visibility every half visibility_timeout. The returned func stops it,
+// and returns once no extension is in flight, so it cannot undo the
+// visibility fail sets.
+func (q *Queue) heartbeat(queueURL string, msg types.Message, job *Job) func() {
+	timeout := q.visibilityTimeout()
+	if timeout == 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(time.Duration(timeout) * time.Second / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				q.changeVisibility(queueURL, msg, job, timeout)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// fail retries the job after a backoff of backoff_base seconds doubling
+// per attempt up to backoff_max, or moves it to the dead letter queue
+// once it is out of attempts. Without dlq_queue_url, exhausted jobs stay
+// on the queue for its redrive policy to move.
+func (q *Queue) fail(queueURL string, msg types.Message, job *Job, err error) {
+	maxAttempts := q.config.MaxAttempts
+
+	if errors.Is(err, ErrPermanent) || job.Attempt >= maxAttempts {
+		q.logger.Error("Job failed",
+			zap.String("job_id", job.ID),
+			zap.String("job_type", job.Type),
+			zap.Int("attempt", job.Attempt),
+			zap.Error(err),
+		)
+
+		if dlqQueueURL := q.config.DLQQueueURL; dlqQueueURL != "" {
+			if q.deadLetter(dlqQueueURL, msg, err) {
+				q.delete(queueURL, msg, job)
+			}
+			return
+		}
+
+		// Let the message become visible again right away so the
+		// redrive policy can move it
+		if errors.Is(err, ErrPermanent) {
+			q.changeVisibility(queueURL, msg, job, 0)
+		}
+		return
+	}
+
+	backoff := q.config.BackoffBase << min(job.Attempt-1, 30)
+	backoff = min(backoff, q.config.BackoffMax, 43200)
+
+	q.logger.Warn("Job failed, retrying",
+		zap.String("job_id", job.ID),
+		zap.String("job_type", job.Type),
+		zap.Int("attempt", job.Attempt),
+		zap.Int("backoff", backoff),
+		zap.Error(err),
+	)
+
+	q.changeVisibility(queueURL, msg, job, int32(backoff))
+}
+
+// deadLetter copies a message to the dead letter queue with the job's
+// error. Like the other calls settling a job, it uses its own context so
+// cancelled jobs are still settled.
+func (q *Queue) deadLetter(dlqQueueURL string, msg types.Message, jobErr error) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	attributes := make(map[string]types.MessageAttributeValue, len(msg.MessageAttributes)+1)
+	for name, value := range msg.MessageAttributes {
+		attributes[name] = value
+	}
+	attributes[errorAttribute] = types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(jobErr.Error()),
+	}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(dlqQueueURL),
+		MessageBody:       msg.Body,
+		MessageAttributes: attributes,
+	}
+	if groupID, ok := msg.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)]; ok {
+		input.MessageGroupId = aws.String(groupID)
+		input.MessageDeduplicationId = msg.MessageId
+	}
+
+	if _, err := q.params.SQS.GetClient().SendMessage(ctx, input); err != nil {
+		q.logger.Error("Send to dead letter queue error", zap.String("message_id", aws.ToString(msg.MessageId)), zap.Error(err))
+		return false
+	}
+
+	return true
+}
+
+func (q *Queue) delete(queueURL string, msg types.Message, job *Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := q.params.SQS.GetClient().DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(queueURL),
+		ReceiptHandle: msg.ReceiptHandle,
+	})
+	if err != nil {
+		q.logger.Error("Delete job error", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+func (q *Queue) changeVisibility(queueURL string, msg types.Message, job *Job, timeout int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := q.params.SQS.GetClient().ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(queueURL),
+		ReceiptHandle:     msg.ReceiptHandle,
+		VisibilityTimeout: timeout,
+	})
+	if err != nil {
+		q.logger.Error("Change job visibility error", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
+
+func (q *Queue) record(job *Job, err error, d time.Duration) {
+	if q.params.Metrics == nil {
+		return
+	}
+
+	dims := cloudwatch_metrics_connector.Dimensions{"JobType": job.Type}
+
+	if err != nil {
+		q.params.Metrics.Count("JobsFailed", 1, dims)
+	} else {
+		q.params.Metrics.Count("JobsSucceeded", 1, dims)
+	}
+
+	q.params.Metrics.Timing("JobDuration", d, dims)
+}
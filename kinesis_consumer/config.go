@@ -0,0 +1,46 @@
+package kinesis_consumer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. KINESIS_CONSUMER_STREAM_NAME for the
+// kinesis_consumer scope.
+type Config struct {
+	StreamName        string `mapstructure:"stream_name" default:""`
+	LeaseTable        string `mapstructure:"lease_table" default:"kinesis_leases"`
+	LeaseDuration     int    `mapstructure:"lease_duration" default:"30"`
+	ShardSyncInterval int    `mapstructure:"shard_sync_interval" default:"60"`
+	InitialPosition   string `mapstructure:"initial_position" default:"TRIM_HORIZON"`
+	MaxRecords        int32  `mapstructure:"max_records" default:"1000"`
+	PollInterval      int    `mapstructure:"poll_interval" default:"1000"`
+	RetryInterval     int    `mapstructure:"retry_interval" default:"5"`
+	EnhancedFanOut    bool   `mapstructure:"enhanced_fan_out" default:"false"`
+	ConsumerName      string `mapstructure:"consumer_name" default:""`
+	Owner             string `mapstructure:"owner"`
+	KinesisKey        string `mapstructure:"kinesis_key" default:"ABCDE"`
+	KinesisSecret     string `mapstructure:"kinesis_secret" default:"example_secret"`
+	KinesisToken      string `mapstructure:"kinesis_token" default:""`
+	KinesisRegion     string `mapstructure:"kinesis_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *KinesisConsumer) initDefaultConfigs() {
+	hostname, _ := os.Hostname()
+
+	moduleconfig.Register(c.scope, &Config{
+		Owner: fmt.Sprintf("%s-%s", hostname, uuid.New().String()),
+	})
+}
+
+// Config returns the configuration the module started with.
+func (c *KinesisConsumer) Config() Config {
+	return c.config
+}
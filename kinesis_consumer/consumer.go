@@ -0,0 +1,397 @@
+package kinesis_consumer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/dynamodb_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultStreamName        = ""
+	DefaultLeaseTable        = "kinesis_leases"
+	DefaultLeaseDuration     = 30
+	DefaultShardSyncInterval = 60
+	DefaultInitialPosition   = "TRIM_HORIZON"
+	DefaultMaxRecords        = 1000
+	DefaultPollInterval      = 1000
+	DefaultRetryInterval     = 5
+	DefaultEnhancedFanOut    = false
+	DefaultConsumerName      = ""
+	DefaultKinesisKey        = "ABCDE"
+	DefaultKinesisSecret     = "example_secret"
+	DefaultKinesisToken      = ""
+	DefaultKinesisRegion     = "us-west-1"
+)
+
+var preflightActions = []string{
+	"kinesis:ListShards",
+	"kinesis:GetShardIterator",
+	"kinesis:GetRecords",
+	"kinesis:DescribeStreamSummary",
+}
+
+// Record is one user record. Records the producer aggregated are
+// delivered individually, distinguished by SubSequenceNumber.
+type Record struct {
+	ShardID           string
+	SequenceNumber    string
+	SubSequenceNumber int
+	PartitionKey      string
+	Data              []byte
+	ArrivalTime       time.Time
+}
+
+// Handler processes a batch of records from one shard, in order.
+// Returning an error retries the same batch; the shard checkpoint only
+// advances once every handler succeeded.
+type Handler func(ctx context.Context, records []Record) error
+
+// KinesisConsumer reads a stream across instances. Shards are leased
+// through a DynamoDB table (string hash key lease_key), so each shard is
+// processed by one worker at a time; children of a split or merge are
+// only picked up once their parents are finished.
+type KinesisConsumer struct {
+	params  Params
+	logger  *zap.Logger
+	client  *kinesis.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	stream      string
+	owner       string
+	consumerARN string
+
+	mu       sync.Mutex
+	handlers []Handler
+	workers  map[string]*shardWorker
+	lastSync time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	DynamoDB        *dynamodb_connector.DynamoDBConnector
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *kinesis.Client         `optional:"true"`
+}
+
+// Module provides the KinesisConsumer of scope. It uses the
+// *kinesis.Client in the graph, if any, instead of building one, and
+// adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the KinesisConsumer of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *kinesis.Client named after scope, if
+// any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *KinesisConsumer
+
+	return fx.Module(
+		scope,
+		instance.Client[*kinesis.Client](scope, named),
+		instance.Provide[*KinesisConsumer](scope, named, func(p Params) *KinesisConsumer {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &KinesisConsumer{
+				params:  p,
+				logger:  logger,
+				scope:   scope,
+				workers: make(map[string]*shardWorker),
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *KinesisConsumer) RecordConsumer {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *KinesisConsumer) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.stream = c.config.StreamName
+	c.owner = c.config.Owner
+
+	c.logger.Info("Starting KinesisConsumer",
+		zap.String("stream_name", c.stream),
+		zap.String("lease_table", c.leaseTable()),
+		zap.String("owner", c.owner),
+		zap.Bool("enhanced_fan_out", c.config.EnhancedFanOut),
+		zap.String("kinesis_region", c.config.KinesisRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	switch types.ShardIteratorType(c.config.InitialPosition) {
+	case types.ShardIteratorTypeTrimHorizon, types.ShardIteratorTypeLatest:
+	default:
+		return fmt.Errorf("%s: initial_position must be TRIM_HORIZON or LATEST", c.scope)
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions,
+			"arn:{partition}:kinesis:{region}:{account}:stream/"+c.config.StreamName,
+		)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = kinesis.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = kinesis.New(c.client.Options(), func(o *kinesis.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	if c.config.EnhancedFanOut {
+		if err := c.registerConsumer(ctx); err != nil {
+			return err
+		}
+	}
+
+	loopCtx, cancel := context.WithCancel(inflight.Internal(context.Background()))
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go c.leaseLoop(loopCtx)
+
+	return nil
+}
+
+func (c *KinesisConsumer) onStop(ctx context.Context) error {
+
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+
+	c.tracker.Shutdown(ctx)
+
+	c.stopWorkers(inflight.Internal(ctx))
+
+	c.logger.Info("Stopped KinesisConsumer")
+
+	return nil
+}
+
+func (c *KinesisConsumer) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.KinesisRegion,
+		Key:         c.config.KinesisKey,
+		Secret:      c.config.KinesisSecret,
+		Token:       c.config.KinesisToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *KinesisConsumer) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("kinesis_region")
+
+	v.Required("stream_name")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("kinesis_key", DefaultKinesisKey)
+		v.NotPlaceholder("kinesis_secret", DefaultKinesisSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the stream summary.
+func (c *KinesisConsumer) probe(ctx context.Context) error {
+	_, err := c.client.DescribeStreamSummary(ctx, &kinesis.DescribeStreamSummaryInput{
+		StreamName: aws.String(c.config.StreamName),
+	})
+
+	return err
+}
+
+// Handle registers a handler for every record batch. Register handlers
+// before the app starts.
+func (c *KinesisConsumer) Handle(handler Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers = append(c.handlers, handler)
+}
+
+func (c *KinesisConsumer) dispatch(ctx context.Context, records []Record) error {
+	c.mu.Lock()
+	handlers := c.handlers
+	c.mu.Unlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, records); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// leaseLoop renews held leases, takes free ones and starts their
+// workers, and re-lists shards every shard_sync_interval.
+func (c *KinesisConsumer) leaseLoop(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.leaseDuration() / 3)
+	defer ticker.Stop()
+
+	for {
+		if err := c.balance(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Error("Balance leases error", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *KinesisConsumer) balance(ctx context.Context) error {
+	syncInterval := time.Duration(c.config.ShardSyncInterval) * time.Second
+	if time.Since(c.lastSync) >= syncInterval {
+		if err := c.syncShards(ctx); err != nil {
+			return err
+		}
+		c.lastSync = time.Now()
+	}
+
+	c.reapWorkers()
+
+	if err := c.renewLeases(ctx); err != nil {
+		return err
+	}
+
+	return c.takeLeases(ctx)
+}
+
+func (c *KinesisConsumer) leaseDuration() time.Duration {
+	return time.Duration(c.config.LeaseDuration) * time.Second
+}
+
+func (c *KinesisConsumer) GetClient() *kinesis.Client {
+	return c.client
+}
@@ -0,0 +1,159 @@
+package kinesis_consumer
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+// KPL aggregated record framing, see kinesis_producer
+var aggregationMagic = []byte{0xf3, 0x89, 0x9a, 0xc2}
+
+var errMalformedProtobuf = errors.New("malformed aggregated record")
+
+// deaggregate expands KPL aggregated records into their user records and
+// passes other records through. A record with the magic prefix but a
+// bad checksum is treated as plain data, as the KCL does.
+func deaggregate(shardID string, records []types.Record) ([]Record, error) {
+	result := make([]Record, 0, len(records))
+
+	for _, r := range records {
+		base := Record{
+			ShardID:        shardID,
+			SequenceNumber: aws.ToString(r.SequenceNumber),
+			PartitionKey:   aws.ToString(r.PartitionKey),
+			Data:           r.Data,
+			ArrivalTime:    aws.ToTime(r.ApproximateArrivalTimestamp),
+		}
+
+		body, ok := aggregatedBody(r.Data)
+		if !ok {
+			result = append(result, base)
+			continue
+		}
+
+		keys, members, err := decodeAggregate(body)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, m := range members {
+			if m.keyIndex >= uint64(len(keys)) {
+				return nil, errMalformedProtobuf
+			}
+
+			record := base
+			record.SubSequenceNumber = i
+			record.PartitionKey = keys[m.keyIndex]
+			record.Data = m.data
+			result = append(result, record)
+		}
+	}
+
+	return result, nil
+}
+
+func aggregatedBody(data []byte) ([]byte, bool) {
+	if len(data) < len(aggregationMagic)+md5.Size || !bytes.HasPrefix(data, aggregationMagic) {
+		return nil, false
+	}
+
+	body := data[len(aggregationMagic) : len(data)-md5.Size]
+	sum := md5.Sum(body)
+	if !bytes.Equal(sum[:], data[len(data)-md5.Size:]) {
+		return nil, false
+	}
+
+	return body, true
+}
+
+type aggregatedMember struct {
+	keyIndex uint64
+	data     []byte
+}
+
+// decodeAggregate reads the partition key table (field 1) and records
+// (field 3) of an AggregatedRecord, skipping the fields it does not use.
+func decodeAggregate(b []byte) ([]string, []aggregatedMember, error) {
+	var keys []string
+	var members []aggregatedMember
+
+	err := walkFields(b, func(field uint64, value []byte) error {
+		switch field {
+		case 1:
+			keys = append(keys, string(value))
+		case 3:
+			var m aggregatedMember
+			err := walkFields(value, func(field uint64, value []byte) error {
+				switch field {
+				case 1:
+					index, n := binary.Uvarint(value)
+					if n <= 0 {
+						return errMalformedProtobuf
+					}
+					m.keyIndex = index
+				case 3:
+					m.data = value
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			members = append(members, m)
+		}
+		return nil
+	})
+
+	return keys, members, err
+}
+
+// walkFields calls fn with each field's number and raw value: the varint
+// bytes for wire type 0, the payload for wire type 2.
+func walkFields(b []byte, fn func(field uint64, value []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errMalformedProtobuf
+		}
+		b = b[n:]
+
+		var value []byte
+		switch tag & 7 {
+		case 0:
+			_, n := binary.Uvarint(b)
+			if n <= 0 {
+				return errMalformedProtobuf
+			}
+			value, b = b[:n], b[n:]
+		case 1:
+			if len(b) < 8 {
+				return errMalformedProtobuf
+			}
+			value, b = b[:8], b[8:]
+		case 2:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return errMalformedProtobuf
+			}
+			value, b = b[n:n+int(length)], b[n+int(length):]
+		case 5:
+			if len(b) < 4 {
+				return errMalformedProtobuf
+			}
+			value, b = b[:4], b[4:]
+		default:
+			return errMalformedProtobuf
+		}
+
+		if err := fn(tag>>3, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package kinesis_consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+// registerConsumer registers consumer_name for enhanced fan-out, or looks
+// it up when another instance already did, and waits until it is active.
+// The consumer is shared by all instances and left registered on stop.
+func (c *KinesisConsumer) registerConsumer(ctx context.Context) error {
+	name := c.config.ConsumerName
+	if name == "" {
+		return fmt.Errorf("%s: consumer_name is required for enhanced_fan_out", c.scope)
+	}
+
+	summary, err := c.client.DescribeStreamSummary(ctx, &kinesis.DescribeStreamSummaryInput{
+		StreamName: aws.String(c.stream),
+	})
+	if err != nil {
+		c.logger.Error("Describe stream error", zap.Error(err))
+		return err
+	}
+	streamARN := summary.StreamDescriptionSummary.StreamARN
+
+	_, err = c.client.RegisterStreamConsumer(ctx, &kinesis.RegisterStreamConsumerInput{
+		StreamARN:    streamARN,
+		ConsumerName: aws.String(name),
+	})
+	if err != nil {
+		var inUse *types.ResourceInUseException
+		if !errors.As(err, &inUse) {
+			c.logger.Error("Register stream consumer error", zap.String("consumer_name", name), zap.Error(err))
+			return err
+		}
+	}
+
+	for {
+		described, err := c.client.DescribeStreamConsumer(ctx, &kinesis.DescribeStreamConsumerInput{
+			StreamARN:    streamARN,
+			ConsumerName: aws.String(name),
+		})
+		if err != nil {
+			c.logger.Error("Describe stream consumer error", zap.String("consumer_name", name), zap.Error(err))
+			return err
+		}
+
+		consumer := described.ConsumerDescription
+		if consumer.ConsumerStatus == types.ConsumerStatusActive {
+			c.consumerARN = aws.ToString(consumer.ConsumerARN)
+
+			c.logger.Info("Stream consumer active", zap.String("consumer_arn", c.consumerARN))
+
+			return nil
+		}
+
+		if !sleep(ctx, time.Second) {
+			return ctx.Err()
+		}
+	}
+}
+
+// subscribeShard is consumeShard for enhanced fan-out: records are pushed
+// over SubscribeToShard, which ends every five minutes and is resumed from
+// the last continuation sequence number.
+func (c *KinesisConsumer) subscribeShard(ctx context.Context, shardID string, checkpoint string) error {
+	if checkpoint == checkpointShardEnd {
+		return nil
+	}
+
+	c.logger.Info("Subscribing to shard", zap.String("shard_id", shardID), zap.String("checkpoint", checkpoint))
+
+	retryInterval := time.Duration(c.config.RetryInterval) * time.Second
+	position := checkpoint
+
+	for {
+		result, err := c.client.SubscribeToShard(ctx, &kinesis.SubscribeToShardInput{
+			ConsumerARN:      aws.String(c.consumerARN),
+			ShardId:          aws.String(shardID),
+			StartingPosition: startingPosition(position),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			// Also returned while the previous subscription winds down
+			c.logger.Warn("Subscribe to shard error", zap.String("shard_id", shardID), zap.Error(err))
+			if !sleep(ctx, retryInterval) {
+				return nil
+			}
+			continue
+		}
+
+		finished, err := c.readSubscription(ctx, shardID, result.GetStream(), &position, retryInterval)
+		if err != nil || finished || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// readSubscription handles the events of one subscription until it ends.
+// It reports whether the shard has been read completely.
+func (c *KinesisConsumer) readSubscription(ctx context.Context, shardID string, stream *kinesis.SubscribeToShardEventStream, position *string, retryInterval time.Duration) (bool, error) {
+	defer stream.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+
+		case event, ok := <-stream.Events():
+			if !ok {
+				if err := stream.Err(); err != nil && ctx.Err() == nil {
+					c.logger.Warn("Shard subscription error", zap.String("shard_id", shardID), zap.Error(err))
+				}
+				return false, nil
+			}
+
+			e, ok := event.(*types.SubscribeToShardEventStreamMemberSubscribeToShardEvent)
+			if !ok {
+				continue
+			}
+
+			if len(e.Value.Records) > 0 {
+				if _, err := c.commit(ctx, shardID, e.Value.Records, retryInterval); err != nil {
+					return false, err
+				}
+			}
+
+			// No continuation means the shard is closed and fully read
+			if e.Value.ContinuationSequenceNumber == nil {
+				return true, c.finishShard(ctx, shardID)
+			}
+
+			*position = aws.ToString(e.Value.ContinuationSequenceNumber)
+		}
+	}
+}
+
+func startingPosition(position string) *types.StartingPosition {
+	switch position {
+	case checkpointTrimHorizon, checkpointLatest:
+		return &types.StartingPosition{Type: types.ShardIteratorType(position)}
+	}
+
+	return &types.StartingPosition{
+		Type:           types.ShardIteratorTypeAfterSequenceNumber,
+		SequenceNumber: aws.String(position),
+	}
+}
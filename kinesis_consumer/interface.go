@@ -0,0 +1,10 @@
+package kinesis_consumer
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . RecordConsumer
+
+// RecordConsumer hands records of the stream to a handler.
+type RecordConsumer interface {
+	Handle(handler Handler)
+}
+
+var _ RecordConsumer = (*KinesisConsumer)(nil)
@@ -0,0 +1,378 @@
+package kinesis_consumer
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+var errLeaseLost = errors.New("shard lease was lost")
+
+// Besides sequence numbers a checkpoint holds where to start a shard
+// nobody has read yet, or that the shard is finished.
+const (
+	checkpointTrimHorizon = string(types.ShardIteratorTypeTrimHorizon)
+	checkpointLatest      = string(types.ShardIteratorTypeLatest)
+	checkpointShardEnd    = "SHARD_END"
+)
+
+const (
+	attrLeaseKey     = "lease_key"
+	attrOwner        = "owner"
+	attrLeaseExpires = "lease_expires_at"
+	attrCheckpoint   = "checkpoint"
+)
+
+type lease struct {
+	ShardID    string   `dynamodbav:"lease_key"`
+	Owner      string   `dynamodbav:"owner"`
+	ExpiresAt  int64    `dynamodbav:"lease_expires_at"`
+	Checkpoint string   `dynamodbav:"checkpoint"`
+	Parents    []string `dynamodbav:"parent_shards,omitempty"`
+}
+
+func (c *KinesisConsumer) leaseTable() string {
+	return c.config.LeaseTable
+}
+
+func (c *KinesisConsumer) leaseKey(shardID string) map[string]dynamodbtypes.AttributeValue {
+	return map[string]dynamodbtypes.AttributeValue{
+		attrLeaseKey: &dynamodbtypes.AttributeValueMemberS{Value: shardID},
+	}
+}
+
+func (c *KinesisConsumer) listShards(ctx context.Context) ([]types.Shard, error) {
+	var shards []types.Shard
+
+	input := &kinesis.ListShardsInput{StreamName: aws.String(c.stream)}
+	for {
+		result, err := c.client.ListShards(ctx, input)
+		if err != nil {
+			c.logger.Error("List shards error", zap.Error(err))
+			return nil, err
+		}
+
+		shards = append(shards, result.Shards...)
+
+		if result.NextToken == nil {
+			return shards, nil
+		}
+
+		// The stream name must not be repeated alongside a token
+		input = &kinesis.ListShardsInput{NextToken: result.NextToken}
+	}
+}
+
+func (c *KinesisConsumer) scanLeases(ctx context.Context) ([]lease, error) {
+	var leases []lease
+
+	paginator := dynamodb.NewScanPaginator(c.params.DynamoDB.GetClient(), &dynamodb.ScanInput{
+		TableName:      aws.String(c.leaseTable()),
+		ConsistentRead: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("Scan leases error", zap.Error(err))
+			return nil, err
+		}
+
+		var batch []lease
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &batch); err != nil {
+			return nil, err
+		}
+
+		leases = append(leases, batch...)
+	}
+
+	return leases, nil
+}
+
+// syncShards creates a lease for every new shard and deletes finished
+// leases of shards that aged out of the stream. On the very first sync
+// open shards start at initial_position and closed ones are skipped;
+// shards appearing later are always read from their start.
+func (c *KinesisConsumer) syncShards(ctx context.Context) error {
+	shards, err := c.listShards(ctx)
+	if err != nil {
+		return err
+	}
+
+	leases, err := c.scanLeases(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]lease, len(leases))
+	for _, l := range leases {
+		existing[l.ShardID] = l
+	}
+
+	initial := len(leases) == 0
+	listed := make(map[string]bool, len(shards))
+
+	for _, shard := range shards {
+		shardID := aws.ToString(shard.ShardId)
+		listed[shardID] = true
+
+		if _, ok := existing[shardID]; ok {
+			continue
+		}
+
+		l := lease{ShardID: shardID, Checkpoint: checkpointTrimHorizon}
+		for _, parent := range []*string{shard.ParentShardId, shard.AdjacentParentShardId} {
+			if parent != nil {
+				l.Parents = append(l.Parents, *parent)
+			}
+		}
+
+		if initial && c.config.InitialPosition == checkpointLatest {
+			if shard.SequenceNumberRange != nil && shard.SequenceNumberRange.EndingSequenceNumber != nil {
+				l.Checkpoint = checkpointShardEnd
+			} else {
+				l.Checkpoint = checkpointLatest
+			}
+		}
+
+		if err := c.createLease(ctx, l); err != nil {
+			return err
+		}
+
+		c.logger.Info("Created shard lease", zap.String("shard_id", shardID), zap.Strings("parents", l.Parents))
+	}
+
+	for _, l := range leases {
+		if !listed[l.ShardID] && l.Checkpoint == checkpointShardEnd {
+			if err := c.deleteLease(ctx, l.ShardID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *KinesisConsumer) createLease(ctx context.Context, l lease) error {
+	item, err := attributevalue.MarshalMap(l)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.params.DynamoDB.GetClient().PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(c.leaseTable()),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(" + attrLeaseKey + ")"),
+	})
+	if err != nil {
+		// Another instance created it first
+		var ccf *dynamodbtypes.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return nil
+		}
+
+		c.logger.Error("Create lease error", zap.String("shard_id", l.ShardID), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *KinesisConsumer) deleteLease(ctx context.Context, shardID string) error {
+	_, err := c.params.DynamoDB.GetClient().DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(c.leaseTable()),
+		Key:       c.leaseKey(shardID),
+	})
+	if err != nil {
+		c.logger.Error("Delete lease error", zap.String("shard_id", shardID), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// renewLeases extends the leases of running workers and stops the
+// workers whose lease was taken over.
+func (c *KinesisConsumer) renewLeases(ctx context.Context) error {
+	c.mu.Lock()
+	workers := make([]*shardWorker, 0, len(c.workers))
+	for _, w := range c.workers {
+		workers = append(workers, w)
+	}
+	c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.leaseDuration()).UnixMilli()
+
+	for _, w := range workers {
+		err := c.updateOwnedLease(ctx, w.shardID, expression.Set(expression.Name(attrLeaseExpires), expression.Value(expiresAt)))
+		if errors.Is(err, errLeaseLost) {
+			c.logger.Warn("Shard lease lost", zap.String("shard_id", w.shardID))
+			w.cancel()
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// takeLeases takes free or expired leases up to an even share of the
+// unfinished shards among the live owners. A shard is only taken once
+// all its parents are finished, which keeps records of a key in order
+// across splits and merges.
+func (c *KinesisConsumer) takeLeases(ctx context.Context) error {
+	leases, err := c.scanLeases(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+
+	checkpoints := make(map[string]string, len(leases))
+	owners := map[string]bool{c.owner: true}
+	active := 0
+	for _, l := range leases {
+		checkpoints[l.ShardID] = l.Checkpoint
+		if l.Checkpoint == checkpointShardEnd {
+			continue
+		}
+
+		active++
+		if l.Owner != "" && l.ExpiresAt > now {
+			owners[l.Owner] = true
+		}
+	}
+
+	target := (active + len(owners) - 1) / len(owners)
+
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].ShardID < leases[j].ShardID
+	})
+
+	for _, l := range leases {
+		c.mu.Lock()
+		held := len(c.workers)
+		_, running := c.workers[l.ShardID]
+		c.mu.Unlock()
+
+		if held >= target {
+			return nil
+		}
+
+		if running || l.Checkpoint == checkpointShardEnd {
+			continue
+		}
+		if l.Owner != "" && l.Owner != c.owner && l.ExpiresAt > now {
+			continue
+		}
+
+		ready := true
+		for _, parent := range l.Parents {
+			// Parents without a lease have aged out of the stream
+			if checkpoint, ok := checkpoints[parent]; ok && checkpoint != checkpointShardEnd {
+				ready = false
+			}
+		}
+		if !ready {
+			continue
+		}
+
+		taken, err := c.takeLease(ctx, l, now)
+		if errors.Is(err, errLeaseLost) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		c.logger.Info("Took shard lease", zap.String("shard_id", l.ShardID), zap.String("previous_owner", l.Owner))
+
+		c.startWorker(taken)
+	}
+
+	return nil
+}
+
+// takeLease returns the lease as stored after taking it, so the worker
+// starts from the latest checkpoint of the previous owner.
+func (c *KinesisConsumer) takeLease(ctx context.Context, l lease, now int64) (lease, error) {
+	update := expression.
+		Set(expression.Name(attrOwner), expression.Value(c.owner)).
+		Set(expression.Name(attrLeaseExpires), expression.Value(time.Now().Add(c.leaseDuration()).UnixMilli()))
+
+	cond := expression.AttributeNotExists(expression.Name(attrOwner)).
+		Or(expression.Name(attrOwner).Equal(expression.Value(""))).
+		Or(expression.Name(attrOwner).Equal(expression.Value(c.owner))).
+		Or(expression.Name(attrLeaseExpires).LessThan(expression.Value(now)))
+
+	attributes, err := c.conditionalUpdate(ctx, l.ShardID, update, cond)
+	if err != nil {
+		return lease{}, err
+	}
+
+	var taken lease
+	if err := attributevalue.UnmarshalMap(attributes, &taken); err != nil {
+		return lease{}, err
+	}
+
+	return taken, nil
+}
+
+// checkpoint records the last processed sequence number, as long as
+// this instance still holds the lease.
+func (c *KinesisConsumer) checkpoint(ctx context.Context, shardID string, checkpoint string) error {
+	return c.updateOwnedLease(ctx, shardID, expression.Set(expression.Name(attrCheckpoint), expression.Value(checkpoint)))
+}
+
+func (c *KinesisConsumer) releaseLease(ctx context.Context, shardID string) error {
+	update := expression.
+		Set(expression.Name(attrOwner), expression.Value("")).
+		Set(expression.Name(attrLeaseExpires), expression.Value(0))
+
+	return c.updateOwnedLease(ctx, shardID, update)
+}
+
+func (c *KinesisConsumer) updateOwnedLease(ctx context.Context, shardID string, update expression.UpdateBuilder) error {
+	_, err := c.conditionalUpdate(ctx, shardID, update, expression.Name(attrOwner).Equal(expression.Value(c.owner)))
+	return err
+}
+
+func (c *KinesisConsumer) conditionalUpdate(ctx context.Context, shardID string, update expression.UpdateBuilder, cond expression.ConditionBuilder) (map[string]dynamodbtypes.AttributeValue, error) {
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.params.DynamoDB.GetClient().UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(c.leaseTable()),
+		Key:                       c.leaseKey(shardID),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              dynamodbtypes.ReturnValueAllNew,
+	})
+	if err != nil {
+		var ccf *dynamodbtypes.ConditionalCheckFailedException
+		if errors.As(err, &ccf) {
+			return nil, errLeaseLost
+		}
+
+		c.logger.Error("Update lease error", zap.String("shard_id", shardID), zap.Error(err))
+		return nil, err
+	}
+
+	return result.Attributes, nil
+}
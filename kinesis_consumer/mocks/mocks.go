@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/kinesis_consumer (interfaces: RecordConsumer)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . RecordConsumer
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	kinesis_consumer "github.com/elmntri/zeitgeber-aws-modules/kinesis_consumer"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecordConsumer is a mock of RecordConsumer interface.
+type MockRecordConsumer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecordConsumerMockRecorder
+}
+
+// MockRecordConsumerMockRecorder is the mock recorder for MockRecordConsumer.
+type MockRecordConsumerMockRecorder struct {
+	mock *MockRecordConsumer
+}
+
+// NewMockRecordConsumer creates a new mock instance.
+func NewMockRecordConsumer(ctrl *gomock.Controller) *MockRecordConsumer {
+	mock := &MockRecordConsumer{ctrl: ctrl}
+	mock.recorder = &MockRecordConsumerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecordConsumer) EXPECT() *MockRecordConsumerMockRecorder {
+	return m.recorder
+}
+
+// Handle mocks base method.
+func (m *MockRecordConsumer) Handle(arg0 kinesis_consumer.Handler) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Handle", arg0)
+}
+
+// Handle indicates an expected call of Handle.
+func (mr *MockRecordConsumerMockRecorder) Handle(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handle", reflect.TypeOf((*MockRecordConsumer)(nil).Handle), arg0)
+}
@@ -0,0 +1,238 @@
+package kinesis_consumer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+)
+
+type shardWorker struct {
+	shardID string
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func (c *KinesisConsumer) startWorker(l lease) {
+	ctx, cancel := context.WithCancel(inflight.Internal(context.Background()))
+
+	w := &shardWorker{
+		shardID: l.ShardID,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	c.workers[l.ShardID] = w
+	c.mu.Unlock()
+
+	go func() {
+		defer close(w.done)
+
+		consume := c.consumeShard
+		if c.consumerARN != "" {
+			consume = c.subscribeShard
+		}
+
+		err := consume(ctx, l.ShardID, l.Checkpoint)
+		if err != nil && ctx.Err() == nil && !errors.Is(err, inflight.ErrShuttingDown) {
+			c.logger.Error("Consume shard error", zap.String("shard_id", l.ShardID), zap.Error(err))
+		}
+	}()
+}
+
+// reapWorkers forgets workers that have exited, so their shards can be
+// taken again on the next pass.
+func (c *KinesisConsumer) reapWorkers() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for shardID, w := range c.workers {
+		select {
+		case <-w.done:
+			delete(c.workers, shardID)
+		default:
+		}
+	}
+}
+
+// stopWorkers stops all workers and hands their leases back.
+func (c *KinesisConsumer) stopWorkers(ctx context.Context) {
+	c.mu.Lock()
+	workers := c.workers
+	c.workers = make(map[string]*shardWorker)
+	c.mu.Unlock()
+
+	for _, w := range workers {
+		w.cancel()
+	}
+
+	for _, w := range workers {
+		<-w.done
+
+		if err := c.releaseLease(ctx, w.shardID); err != nil && !errors.Is(err, errLeaseLost) {
+			c.logger.Warn("Release lease on shutdown error", zap.String("shard_id", w.shardID), zap.Error(err))
+		}
+	}
+}
+
+// consumeShard reads a shard from checkpoint until it is closed or ctx is
+// cancelled, checkpointing after every batch the handlers accepted.
+func (c *KinesisConsumer) consumeShard(ctx context.Context, shardID string, checkpoint string) error {
+	if checkpoint == checkpointShardEnd {
+		return nil
+	}
+
+	c.logger.Info("Consuming shard", zap.String("shard_id", shardID), zap.String("checkpoint", checkpoint))
+
+	pollInterval := time.Duration(c.config.PollInterval) * time.Millisecond
+	retryInterval := time.Duration(c.config.RetryInterval) * time.Second
+
+	iterator, err := c.shardIterator(ctx, shardID, checkpoint)
+	if err != nil {
+		return err
+	}
+
+	for {
+		result, err := c.client.GetRecords(ctx, &kinesis.GetRecordsInput{
+			ShardIterator: iterator,
+			Limit:         aws.Int32(c.config.MaxRecords),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			var expired *types.ExpiredIteratorException
+			if errors.As(err, &expired) {
+				if iterator, err = c.shardIterator(ctx, shardID, checkpoint); err != nil {
+					return err
+				}
+				continue
+			}
+
+			c.logger.Warn("Get records error", zap.String("shard_id", shardID), zap.Error(err))
+			if !sleep(ctx, retryInterval) {
+				return nil
+			}
+			continue
+		}
+
+		if len(result.Records) > 0 {
+			if checkpoint, err = c.commit(ctx, shardID, result.Records, retryInterval); err != nil {
+				return err
+			}
+		}
+
+		// A closed shard has been read completely; its children can start
+		if result.NextShardIterator == nil {
+			return c.finishShard(ctx, shardID)
+		}
+
+		iterator = result.NextShardIterator
+
+		if !sleep(ctx, pollInterval) {
+			return nil
+		}
+	}
+}
+
+func (c *KinesisConsumer) shardIterator(ctx context.Context, shardID string, checkpoint string) (*string, error) {
+	input := &kinesis.GetShardIteratorInput{
+		StreamName: aws.String(c.stream),
+		ShardId:    aws.String(shardID),
+	}
+
+	switch checkpoint {
+	case checkpointTrimHorizon, checkpointLatest:
+		input.ShardIteratorType = types.ShardIteratorType(checkpoint)
+	default:
+		input.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		input.StartingSequenceNumber = aws.String(checkpoint)
+	}
+
+	result, err := c.client.GetShardIterator(ctx, input)
+	if err != nil {
+		c.logger.Error("Get shard iterator error", zap.String("shard_id", shardID), zap.Error(err))
+		return nil, err
+	}
+
+	return result.ShardIterator, nil
+}
+
+// commit processes a batch and checkpoints its last sequence number.
+func (c *KinesisConsumer) commit(ctx context.Context, shardID string, batch []types.Record, retryInterval time.Duration) (string, error) {
+	records, err := deaggregate(shardID, batch)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.process(ctx, shardID, records, retryInterval); err != nil {
+		return "", err
+	}
+
+	checkpoint := aws.ToString(batch[len(batch)-1].SequenceNumber)
+	if err := c.checkpoint(ctx, shardID, checkpoint); err != nil {
+		return "", err
+	}
+
+	return checkpoint, nil
+}
+
+// finishShard marks a closed shard as read completely, so its children
+// can be taken, and gives up its lease.
+func (c *KinesisConsumer) finishShard(ctx context.Context, shardID string) error {
+	if err := c.checkpoint(ctx, shardID, checkpointShardEnd); err != nil {
+		return err
+	}
+
+	c.logger.Info("Finished shard", zap.String("shard_id", shardID))
+
+	return c.releaseLease(ctx, shardID)
+}
+
+// process hands records to the handlers until they accept them. Once
+// the consumer is stopping, new batches are left to be read again from
+// the checkpoint, and those being handled are waited for.
+func (c *KinesisConsumer) process(ctx context.Context, shardID string, records []Record, retryInterval time.Duration) error {
+	// Not the worker's Internal ctx, so new batches are rejected
+	done, err := c.tracker.Begin(context.Background(), "records of "+shardID)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	for {
+		err := c.dispatch(ctx, records)
+		if err == nil {
+			return nil
+		}
+
+		c.logger.Error("Handle records error",
+			zap.String("shard_id", shardID),
+			zap.String("sequence_number", records[0].SequenceNumber),
+			zap.Error(err),
+		)
+
+		if !sleep(ctx, retryInterval) {
+			return ctx.Err()
+		}
+	}
+}
+
+// sleep waits for d and reports false when ctx was cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
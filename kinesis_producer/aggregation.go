@@ -0,0 +1,124 @@
+package kinesis_producer
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+// Aggregated records use the KPL format, which the KCL and Lambda event
+// source mappings de-aggregate: magic | AggregatedRecord protobuf | md5.
+var aggregationMagic = []byte{0xf3, 0x89, 0x9a, 0xc2}
+
+// maxAggregateBytes matches the KPL default, which keeps aggregated
+// records small enough to spread across shards.
+const maxAggregateBytes = 51200
+
+// perRecordOverhead bounds the protobuf framing of one user record and
+// its partition key table entry.
+const perRecordOverhead = 24
+
+// aggregate packs the records sharing a partition key into KPL
+// aggregated records under that key, so each lands on the shard its key
+// maps to and keeps its order there. Records too large to share an
+// aggregate, or alone under their key, are sent as they are.
+func aggregate(records []record) []types.PutRecordsRequestEntry {
+	type group struct {
+		records []record
+		size    int
+	}
+
+	var entries []types.PutRecordsRequestEntry
+	var keys []string
+	groups := make(map[string]*group)
+
+	emit := func(g *group) {
+		switch len(g.records) {
+		case 0:
+		case 1:
+			entries = append(entries, types.PutRecordsRequestEntry{
+				PartitionKey: aws.String(g.records[0].partitionKey),
+				Data:         g.records[0].data,
+			})
+		default:
+			entries = append(entries, types.PutRecordsRequestEntry{
+				PartitionKey: aws.String(g.records[0].partitionKey),
+				Data:         encodeAggregate(g.records),
+			})
+		}
+
+		g.records = nil
+		g.size = len(aggregationMagic) + md5.Size
+	}
+
+	for _, r := range records {
+		g, ok := groups[r.partitionKey]
+		if !ok {
+			g = &group{size: len(aggregationMagic) + md5.Size}
+			groups[r.partitionKey] = g
+			keys = append(keys, r.partitionKey)
+		}
+
+		recordSize := len(r.partitionKey) + len(r.data) + perRecordOverhead
+		if g.size+recordSize > maxAggregateBytes {
+			emit(g)
+		}
+
+		g.records = append(g.records, r)
+		g.size += recordSize
+	}
+
+	for _, key := range keys {
+		emit(groups[key])
+	}
+
+	return entries
+}
+
+func encodeAggregate(records []record) []byte {
+	keyIndex := make(map[string]uint64)
+	var keys []string
+	for _, r := range records {
+		if _, ok := keyIndex[r.partitionKey]; !ok {
+			keyIndex[r.partitionKey] = uint64(len(keys))
+			keys = append(keys, r.partitionKey)
+		}
+	}
+
+	var body []byte
+
+	// repeated string partition_key_table = 1
+	for _, key := range keys {
+		body = appendBytesField(body, 1, []byte(key))
+	}
+
+	// repeated Record records = 3
+	for _, r := range records {
+		var msg []byte
+		// required uint64 partition_key_index = 1
+		msg = binary.AppendUvarint(msg, 1<<3|0)
+		msg = binary.AppendUvarint(msg, keyIndex[r.partitionKey])
+		// required bytes data = 3
+		msg = appendBytesField(msg, 3, r.data)
+
+		body = appendBytesField(body, 3, msg)
+	}
+
+	sum := md5.Sum(body)
+
+	out := make([]byte, 0, len(aggregationMagic)+len(body)+len(sum))
+	out = append(out, aggregationMagic...)
+	out = append(out, body...)
+	out = append(out, sum[:]...)
+
+	return out
+}
+
+// appendBytesField appends a length-delimited protobuf field.
+func appendBytesField(b []byte, field uint64, value []byte) []byte {
+	b = binary.AppendUvarint(b, field<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
@@ -0,0 +1,31 @@
+package kinesis_producer
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. KINESIS_PRODUCER_STREAM_NAME for the
+// kinesis_producer scope.
+type Config struct {
+	StreamName        string `mapstructure:"stream_name" default:""`
+	BufferSize        int    `mapstructure:"buffer_size" default:"10000"`
+	LingerMs          int    `mapstructure:"linger_ms" default:"100"`
+	MaxRetries        int    `mapstructure:"max_retries" default:"3"`
+	Aggregate         bool   `mapstructure:"aggregate" default:"false"`
+	KinesisKey        string `mapstructure:"kinesis_key" default:"ABCDE"`
+	KinesisSecret     string `mapstructure:"kinesis_secret" default:"example_secret"`
+	KinesisToken      string `mapstructure:"kinesis_token" default:""`
+	KinesisRegion     string `mapstructure:"kinesis_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *KinesisProducer) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *KinesisProducer) Config() Config {
+	return c.config
+}
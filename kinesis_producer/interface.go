@@ -0,0 +1,14 @@
+package kinesis_producer
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . StreamProducer
+
+// StreamProducer puts records on the stream.
+type StreamProducer interface {
+	Put(ctx context.Context, partitionKey string, data []byte) error
+	PutJSON(ctx context.Context, partitionKey string, v interface{}) error
+	Flush(ctx context.Context) error
+}
+
+var _ StreamProducer = (*KinesisProducer)(nil)
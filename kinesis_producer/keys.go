@@ -0,0 +1,25 @@
+package kinesis_producer
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/google/uuid"
+)
+
+// RandomPartitionKey spreads records evenly across shards when ordering
+// does not matter.
+func RandomPartitionKey() string {
+	return uuid.New().String()
+}
+
+// SpreadPartitionKey splits a hot key over n sub-keys. Records of the same
+// key are no longer ordered relative to each other, only within a
+// sub-key.
+func SpreadPartitionKey(key string, n int) string {
+	if n <= 1 {
+		return key
+	}
+
+	return fmt.Sprintf("%s-%d", key, rand.Intn(n))
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/kinesis_producer (interfaces: StreamProducer)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . StreamProducer
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStreamProducer is a mock of StreamProducer interface.
+type MockStreamProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamProducerMockRecorder
+}
+
+// MockStreamProducerMockRecorder is the mock recorder for MockStreamProducer.
+type MockStreamProducerMockRecorder struct {
+	mock *MockStreamProducer
+}
+
+// NewMockStreamProducer creates a new mock instance.
+func NewMockStreamProducer(ctrl *gomock.Controller) *MockStreamProducer {
+	mock := &MockStreamProducer{ctrl: ctrl}
+	mock.recorder = &MockStreamProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamProducer) EXPECT() *MockStreamProducerMockRecorder {
+	return m.recorder
+}
+
+// Flush mocks base method.
+func (m *MockStreamProducer) Flush(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockStreamProducerMockRecorder) Flush(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockStreamProducer)(nil).Flush), arg0)
+}
+
+// Put mocks base method.
+func (m *MockStreamProducer) Put(arg0 context.Context, arg1 string, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockStreamProducerMockRecorder) Put(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStreamProducer)(nil).Put), arg0, arg1, arg2)
+}
+
+// PutJSON mocks base method.
+func (m *MockStreamProducer) PutJSON(arg0 context.Context, arg1 string, arg2 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutJSON", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutJSON indicates an expected call of PutJSON.
+func (mr *MockStreamProducerMockRecorder) PutJSON(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutJSON", reflect.TypeOf((*MockStreamProducer)(nil).PutJSON), arg0, arg1, arg2)
+}
@@ -0,0 +1,424 @@
+package kinesis_producer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/batcher"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+var ErrRecordTooLarge = awserrors.New(awserrors.ErrValidation, "record exceeds the kinesis record size limit")
+
+const (
+	DefaultStreamName    = ""
+	DefaultBufferSize    = 10000
+	DefaultLingerMs      = 100
+	DefaultMaxRetries    = 3
+	DefaultAggregate     = false
+	DefaultKinesisKey    = "ABCDE"
+	DefaultKinesisSecret = "example_secret"
+	DefaultKinesisToken  = ""
+	DefaultKinesisRegion = "us-west-1"
+)
+
+// PutRecords limits
+const (
+	maxBatchRecords = 500
+	maxBatchBytes   = 5 * 1024 * 1024
+	maxRecordBytes  = 1024 * 1024
+)
+
+var preflightActions = []string{
+	"kinesis:PutRecords",
+}
+
+type record struct {
+	partitionKey string
+	data         []byte
+}
+
+type KinesisProducer struct {
+	params  Params
+	logger  *zap.Logger
+	client  *kinesis.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	stream  string
+	batcher *batcher.Batcher[record]
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *kinesis.Client         `optional:"true"`
+}
+
+// Module provides the KinesisProducer of scope. It uses the
+// *kinesis.Client in the graph, if any, instead of building one, and
+// adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the KinesisProducer of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *kinesis.Client named after scope, if
+// any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *KinesisProducer
+
+	return fx.Module(
+		scope,
+		instance.Client[*kinesis.Client](scope, named),
+		instance.Provide[*KinesisProducer](scope, named, func(p Params) (*KinesisProducer, error) {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &KinesisProducer{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			// Loaded here, as the buffer is sized before start
+			if err := moduleconfig.Load(scope, &c.config); err != nil {
+				return nil, err
+			}
+
+			c.batcher = batcher.New(batcher.Options[record]{
+				BufferSize: c.config.BufferSize,
+				BatchSize:  maxBatchRecords,
+				Interval:   time.Duration(c.config.LingerMs) * time.Millisecond,
+				Block:      true,
+				Send:       c.send,
+				OnError: func(err error) {
+					c.logger.Error("Flush records error", zap.Error(err))
+				},
+			})
+
+			return c, nil
+		}),
+		instance.Export(scope, named, func(c *KinesisProducer) StreamProducer {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *KinesisProducer) onStart(ctx context.Context) error {
+	c.stream = c.config.StreamName
+
+	c.logger.Info("Starting KinesisProducer",
+		zap.String("stream_name", c.stream),
+		zap.Bool("aggregate", c.config.Aggregate),
+		zap.String("kinesis_region", c.config.KinesisRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions,
+			"arn:{partition}:kinesis:{region}:{account}:stream/"+c.config.StreamName,
+		)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = kinesis.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = kinesis.New(c.client.Options(), func(o *kinesis.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	c.batcher.Start(inflight.Internal(context.Background()))
+
+	return nil
+}
+
+func (c *KinesisProducer) onStop(ctx context.Context) error {
+
+	c.batcher.Stop()
+
+	c.tracker.Shutdown(ctx)
+
+	// Send whatever is left before the process exits
+	if err := c.Flush(inflight.Internal(ctx)); err != nil {
+		c.logger.Warn("Flush records on shutdown error", zap.Error(err))
+	}
+
+	c.logger.Info("Stopped KinesisProducer")
+
+	return nil
+}
+
+func (c *KinesisProducer) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.KinesisRegion,
+		Key:         c.config.KinesisKey,
+		Secret:      c.config.KinesisSecret,
+		Token:       c.config.KinesisToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *KinesisProducer) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("kinesis_region")
+
+	v.Required("stream_name")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("kinesis_key", DefaultKinesisKey)
+		v.NotPlaceholder("kinesis_secret", DefaultKinesisSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the stream summary.
+func (c *KinesisProducer) probe(ctx context.Context) error {
+	_, err := c.client.DescribeStreamSummary(ctx, &kinesis.DescribeStreamSummaryInput{
+		StreamName: aws.String(c.config.StreamName),
+	})
+
+	return err
+}
+
+// Put buffers a record for the next batch. When buffer_size records are
+// already waiting, Put blocks until a batch has been sent or ctx is done.
+func (c *KinesisProducer) Put(ctx context.Context, partitionKey string, data []byte) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "KinesisProducer", "Put")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if len(partitionKey)+len(data) > maxRecordBytes {
+		return ErrRecordTooLarge
+	}
+
+	return c.batcher.Add(ctx, record{partitionKey: partitionKey, data: data})
+}
+
+// PutJSON buffers v encoded as JSON.
+func (c *KinesisProducer) PutJSON(ctx context.Context, partitionKey string, v interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "KinesisProducer", "PutJSON")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return c.Put(ctx, partitionKey, data)
+}
+
+// Flush sends all buffered records. Records still failing after
+// max_retries are dropped and reported in the returned error.
+func (c *KinesisProducer) Flush(ctx context.Context) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "KinesisProducer", "Flush")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.batcher.Flush(ctx)
+}
+
+// send aggregates records, when configured, and splits them into
+// PutRecords calls.
+func (c *KinesisProducer) send(ctx context.Context, records []record) error {
+	if c.client == nil {
+		return fmt.Errorf("%s: producer is not started", c.scope)
+	}
+
+	entries := make([]types.PutRecordsRequestEntry, 0, len(records))
+	if c.config.Aggregate {
+		entries = aggregate(records)
+	} else {
+		for _, r := range records {
+			entries = append(entries, types.PutRecordsRequestEntry{
+				PartitionKey: aws.String(r.partitionKey),
+				Data:         r.data,
+			})
+		}
+	}
+
+	var errs []error
+	for len(entries) > 0 {
+		n, bytes := 0, 0
+		for n < len(entries) && n < maxBatchRecords {
+			size := len(*entries[n].PartitionKey) + len(entries[n].Data)
+			if bytes+size > maxBatchBytes {
+				break
+			}
+
+			bytes += size
+			n++
+		}
+
+		if err := c.putRecords(ctx, entries[:n]); err != nil {
+			errs = append(errs, err)
+		}
+
+		entries = entries[n:]
+	}
+
+	return errors.Join(errs...)
+}
+
+// putRecords sends one batch, resending only the records that failed
+// (throttling or internal errors) with an exponential backoff.
+func (c *KinesisProducer) putRecords(ctx context.Context, entries []types.PutRecordsRequestEntry) error {
+	maxRetries := c.config.MaxRetries
+	backoff := 100 * time.Millisecond
+
+	for attempt := 0; ; attempt++ {
+		result, err := c.client.PutRecords(ctx, &kinesis.PutRecordsInput{
+			StreamName: aws.String(c.stream),
+			Records:    entries,
+		})
+		if err != nil {
+			c.logger.Error("Put records error", zap.Int("count", len(entries)), zap.Error(err))
+			return err
+		}
+
+		if aws.ToInt32(result.FailedRecordCount) == 0 {
+			return nil
+		}
+
+		var failed []types.PutRecordsRequestEntry
+		var lastError string
+		for i, r := range result.Records {
+			if r.ErrorCode != nil {
+				failed = append(failed, entries[i])
+				lastError = aws.ToString(r.ErrorMessage)
+			}
+		}
+
+		if attempt >= maxRetries {
+			c.logger.Error("Records dropped after retries",
+				zap.Int("count", len(failed)),
+				zap.String("last_error", lastError),
+			)
+			return fmt.Errorf("%s: %d records failed: %s", c.scope, len(failed), lastError)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		entries = failed
+		backoff *= 2
+	}
+}
+
+func (c *KinesisProducer) GetClient() *kinesis.Client {
+	return c.client
+}
@@ -0,0 +1,29 @@
+package kms_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. KMS_KEY_ID for the kms scope.
+type Config struct {
+	KeyID             string `mapstructure:"key_id" default:""`
+	KMSKey            string `mapstructure:"kms_key" default:"ABCDE"`
+	KMSSecret         string `mapstructure:"kms_secret" default:"example_secret"`
+	KMSToken          string `mapstructure:"kms_token" default:""`
+	KMSRegion         string `mapstructure:"kms_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+
+	EnvelopeKeyTTL     int `mapstructure:"envelope_key_ttl" default:"300"`
+	EnvelopeKeyMaxUses int `mapstructure:"envelope_key_max_uses" default:"10000"`
+}
+
+func (c *KMSConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *KMSConnector) Config() Config {
+	return c.config
+}
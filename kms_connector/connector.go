@@ -0,0 +1,345 @@
+package kms_connector
+
+import (
+	"context"
+	"crypto"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultKeyID     = ""
+	DefaultKMSKey    = "ABCDE"
+	DefaultKMSSecret = "example_secret"
+	DefaultKMSToken  = ""
+	DefaultKMSRegion = "us-west-1"
+)
+
+var preflightActions = []string{
+	"kms:Encrypt",
+	"kms:Decrypt",
+	"kms:GenerateDataKey",
+}
+
+// DataKey is a data key generated under a KMS key. Plaintext should be
+// used and discarded; Ciphertext is stored next to the encrypted data.
+type DataKey struct {
+	KeyID      string
+	Plaintext  []byte
+	Ciphertext []byte
+}
+
+type KMSConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *kms.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	mu         sync.RWMutex
+	publicKeys map[string]crypto.PublicKey
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *kms.Client             `optional:"true"`
+}
+
+// Module provides the KMSConnector of scope. It uses the *kms.Client in
+// the graph, if any, instead of building one, and adds the connector's
+// middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the KMSConnector of scope, and its interfaces,
+// named after scope, for apps with more than one; see package instance.
+// It uses the *kms.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *KMSConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*kms.Client](scope, named),
+		instance.Provide[*KMSConnector](scope, named, func(p Params) *KMSConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &KMSConnector{
+				params:     p,
+				logger:     logger,
+				scope:      scope,
+				publicKeys: make(map[string]crypto.PublicKey),
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *KMSConnector) KeyService {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *KMSConnector) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting KMSConnector",
+		zap.String("key_id", c.GetKeyID()),
+		zap.String("kms_region", c.config.KMSRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = kms.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = kms.New(c.client.Options(), func(o *kms.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *KMSConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped KMSConnector")
+
+	return nil
+}
+
+func (c *KMSConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.KMSRegion,
+		Key:         c.config.KMSKey,
+		Secret:      c.config.KMSSecret,
+		Token:       c.config.KMSToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *KMSConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("kms_region")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("kms_key", DefaultKMSKey)
+		v.NotPlaceholder("kms_secret", DefaultKMSSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the key, or lists one when none is configured.
+func (c *KMSConnector) probe(ctx context.Context) error {
+	if keyID := c.config.KeyID; keyID != "" {
+		_, err := c.client.DescribeKey(ctx, &kms.DescribeKeyInput{
+			KeyId: aws.String(keyID),
+		})
+
+		return err
+	}
+
+	_, err := c.client.ListKeys(ctx, &kms.ListKeysInput{
+		Limit: aws.Int32(1),
+	})
+
+	return err
+}
+
+// GetKeyID returns the configured default key ID, ARN or alias.
+func (c *KMSConnector) GetKeyID() string {
+	return c.config.KeyID
+}
+
+// Encrypt encrypts up to 4 KB of plaintext under the default key. The same
+// encryption context must be passed to Decrypt.
+func (c *KMSConnector) Encrypt(ctx context.Context, plaintext []byte, encryptionContext map[string]string) (_ []byte, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "KMSConnector", "Encrypt")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.EncryptWithKey(ctx, c.GetKeyID(), plaintext, encryptionContext)
+}
+
+func (c *KMSConnector) EncryptWithKey(ctx context.Context, keyID string, plaintext []byte, encryptionContext map[string]string) (_ []byte, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "KMSConnector", "EncryptWithKey")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:             aws.String(keyID),
+		Plaintext:         plaintext,
+		EncryptionContext: encryptionContext,
+	})
+	if err != nil {
+		c.logger.Error("Encrypt error", zap.String("key_id", keyID), zap.Error(err))
+		return nil, err
+	}
+
+	return result.CiphertextBlob, nil
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt. When a default key is
+// configured, ciphertexts of other keys are rejected.
+func (c *KMSConnector) Decrypt(ctx context.Context, ciphertext []byte, encryptionContext map[string]string) (_ []byte, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "KMSConnector", "Decrypt")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	input := &kms.DecryptInput{
+		CiphertextBlob:    ciphertext,
+		EncryptionContext: encryptionContext,
+	}
+	if keyID := c.GetKeyID(); keyID != "" {
+		input.KeyId = aws.String(keyID)
+	}
+
+	result, err := c.client.Decrypt(ctx, input)
+	if err != nil {
+		c.logger.Error("Decrypt error", zap.Error(err))
+		return nil, err
+	}
+
+	return result.Plaintext, nil
+}
+
+// GenerateDataKey returns a new AES-256 data key under the default key.
+func (c *KMSConnector) GenerateDataKey(ctx context.Context, encryptionContext map[string]string) (_ *DataKey, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "KMSConnector", "GenerateDataKey")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:             aws.String(c.GetKeyID()),
+		KeySpec:           types.DataKeySpecAes256,
+		EncryptionContext: encryptionContext,
+	})
+	if err != nil {
+		c.logger.Error("Generate data key error", zap.String("key_id", c.GetKeyID()), zap.Error(err))
+		return nil, err
+	}
+
+	return &DataKey{
+		KeyID:      aws.ToString(result.KeyId),
+		Plaintext:  result.Plaintext,
+		Ciphertext: result.CiphertextBlob,
+	}, nil
+}
+
+func (c *KMSConnector) GetClient() *kms.Client {
+	return c.client
+}
@@ -0,0 +1,213 @@
+package kms_connector
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+const (
+	DefaultEnvelopeKeyTTL     = 300
+	DefaultEnvelopeKeyMaxUses = 10000
+)
+
+// Envelope layout: version (1 byte) | encrypted data key length (2 bytes,
+// big endian) | encrypted data key | nonce (12 bytes) | AES-GCM sealed
+// payload. The header up to the nonce is authenticated as additional data.
+const envelopeVersion = 1
+
+var ErrInvalidEnvelope = awserrors.New(awserrors.ErrValidation, "invalid envelope")
+
+type cachedDataKey struct {
+	key       *DataKey
+	aead      cipher.AEAD
+	expiresAt time.Time
+	uses      int
+}
+
+// EnvelopeCipher encrypts payloads locally with AES-256-GCM under data
+// keys from KMS. A data key is reused until it is envelope_key_ttl
+// seconds old or has sealed envelope_key_max_uses payloads; decrypted
+// data keys are cached for the same TTL.
+type EnvelopeCipher struct {
+	kms               *KMSConnector
+	encryptionContext map[string]string
+	ttl               time.Duration
+	maxUses           int
+
+	mu        sync.Mutex
+	current   *cachedDataKey
+	decrypted map[string]*cachedDataKey
+}
+
+// NewEnvelopeCipher returns a cipher whose data keys are bound to
+// encryptionContext, e.g. {"table": "users"}.
+func (c *KMSConnector) NewEnvelopeCipher(encryptionContext map[string]string) *EnvelopeCipher {
+	return &EnvelopeCipher{
+		kms:               c,
+		encryptionContext: encryptionContext,
+		ttl:               time.Duration(c.config.EnvelopeKeyTTL) * time.Second,
+		maxUses:           c.config.EnvelopeKeyMaxUses,
+		decrypted:         make(map[string]*cachedDataKey),
+	}
+}
+
+func newCachedDataKey(key *DataKey, ttl time.Duration) (*cachedDataKey, error) {
+	block, err := aes.NewCipher(key.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cachedDataKey{
+		key:       key,
+		aead:      aead,
+		expiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+func (e *EnvelopeCipher) encryptionKey(ctx context.Context) (*cachedDataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.current != nil && time.Now().Before(e.current.expiresAt) && e.current.uses < e.maxUses {
+		e.current.uses++
+		return e.current, nil
+	}
+
+	key, err := e.kms.GenerateDataKey(ctx, e.encryptionContext)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, err := newCachedDataKey(key, e.ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	cached.uses = 1
+	e.current = cached
+
+	return cached, nil
+}
+
+func (e *EnvelopeCipher) decryptionKey(ctx context.Context, encryptedKey []byte) (*cachedDataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+
+	if cached, ok := e.decrypted[string(encryptedKey)]; ok && now.Before(cached.expiresAt) {
+		return cached, nil
+	}
+
+	plaintext, err := e.kms.Decrypt(ctx, encryptedKey, e.encryptionContext)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, err := newCachedDataKey(&DataKey{Plaintext: plaintext, Ciphertext: encryptedKey}, e.ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range e.decrypted {
+		if now.After(v.expiresAt) {
+			delete(e.decrypted, k)
+		}
+	}
+	e.decrypted[string(encryptedKey)] = cached
+
+	return cached, nil
+}
+
+// Encrypt seals plaintext into an envelope.
+func (e *EnvelopeCipher) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	key, err := e.encryptionKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedKey := key.key.Ciphertext
+	nonceSize := key.aead.NonceSize()
+
+	header := make([]byte, 3, 3+len(encryptedKey)+nonceSize+len(plaintext)+key.aead.Overhead())
+	header[0] = envelopeVersion
+	binary.BigEndian.PutUint16(header[1:3], uint16(len(encryptedKey)))
+	header = append(header, encryptedKey...)
+
+	envelope := header
+	aad := envelope[:len(header)]
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	envelope = append(envelope, nonce...)
+
+	return key.aead.Seal(envelope, nonce, plaintext, aad), nil
+}
+
+// Decrypt opens an envelope produced by Encrypt.
+func (e *EnvelopeCipher) Decrypt(ctx context.Context, envelope []byte) ([]byte, error) {
+	if len(envelope) < 3 || envelope[0] != envelopeVersion {
+		return nil, ErrInvalidEnvelope
+	}
+
+	keyLen := int(binary.BigEndian.Uint16(envelope[1:3]))
+	if len(envelope) < 3+keyLen {
+		return nil, ErrInvalidEnvelope
+	}
+
+	aad := envelope[:3+keyLen]
+	encryptedKey := envelope[3 : 3+keyLen]
+
+	key, err := e.decryptionKey(ctx, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := envelope[3+keyLen:]
+	nonceSize := key.aead.NonceSize()
+	if len(rest) < nonceSize+key.aead.Overhead() {
+		return nil, ErrInvalidEnvelope
+	}
+
+	return key.aead.Open(nil, rest[:nonceSize], rest[nonceSize:], aad)
+}
+
+// EncryptString returns the envelope as unpadded URL-safe base64, for
+// string attributes and object metadata.
+func (e *EnvelopeCipher) EncryptString(ctx context.Context, plaintext string) (string, error) {
+	envelope, err := e.Encrypt(ctx, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(envelope), nil
+}
+
+func (e *EnvelopeCipher) DecryptString(ctx context.Context, encoded string) (string, error) {
+	envelope, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidEnvelope
+	}
+
+	plaintext, err := e.Decrypt(ctx, envelope)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
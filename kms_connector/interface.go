@@ -0,0 +1,16 @@
+package kms_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . KeyService
+
+// KeyService encrypts, decrypts and signs with KMS keys.
+type KeyService interface {
+	Encrypt(ctx context.Context, plaintext []byte, encryptionContext map[string]string) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte, encryptionContext map[string]string) ([]byte, error)
+	GenerateDataKey(ctx context.Context, encryptionContext map[string]string) (*DataKey, error)
+	Sign(ctx context.Context, keyID string, message []byte, alg SigningAlgorithm) ([]byte, error)
+	Verify(ctx context.Context, keyID string, message []byte, signature []byte, alg SigningAlgorithm) error
+}
+
+var _ KeyService = (*KMSConnector)(nil)
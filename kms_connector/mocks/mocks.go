@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/kms_connector (interfaces: KeyService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . KeyService
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	kms_connector "github.com/elmntri/zeitgeber-aws-modules/kms_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKeyService is a mock of KeyService interface.
+type MockKeyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyServiceMockRecorder
+}
+
+// MockKeyServiceMockRecorder is the mock recorder for MockKeyService.
+type MockKeyServiceMockRecorder struct {
+	mock *MockKeyService
+}
+
+// NewMockKeyService creates a new mock instance.
+func NewMockKeyService(ctrl *gomock.Controller) *MockKeyService {
+	mock := &MockKeyService{ctrl: ctrl}
+	mock.recorder = &MockKeyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyService) EXPECT() *MockKeyServiceMockRecorder {
+	return m.recorder
+}
+
+// Decrypt mocks base method.
+func (m *MockKeyService) Decrypt(arg0 context.Context, arg1 []byte, arg2 map[string]string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrypt", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrypt indicates an expected call of Decrypt.
+func (mr *MockKeyServiceMockRecorder) Decrypt(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockKeyService)(nil).Decrypt), arg0, arg1, arg2)
+}
+
+// Encrypt mocks base method.
+func (m *MockKeyService) Encrypt(arg0 context.Context, arg1 []byte, arg2 map[string]string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encrypt", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encrypt indicates an expected call of Encrypt.
+func (mr *MockKeyServiceMockRecorder) Encrypt(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockKeyService)(nil).Encrypt), arg0, arg1, arg2)
+}
+
+// GenerateDataKey mocks base method.
+func (m *MockKeyService) GenerateDataKey(arg0 context.Context, arg1 map[string]string) (*kms_connector.DataKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateDataKey", arg0, arg1)
+	ret0, _ := ret[0].(*kms_connector.DataKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateDataKey indicates an expected call of GenerateDataKey.
+func (mr *MockKeyServiceMockRecorder) GenerateDataKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateDataKey", reflect.TypeOf((*MockKeyService)(nil).GenerateDataKey), arg0, arg1)
+}
+
+// Sign mocks base method.
+func (m *MockKeyService) Sign(arg0 context.Context, arg1 string, arg2 []byte, arg3 types.SigningAlgorithmSpec) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockKeyServiceMockRecorder) Sign(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockKeyService)(nil).Sign), arg0, arg1, arg2, arg3)
+}
+
+// Verify mocks base method.
+func (m *MockKeyService) Verify(arg0 context.Context, arg1 string, arg2, arg3 []byte, arg4 types.SigningAlgorithmSpec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockKeyServiceMockRecorder) Verify(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockKeyService)(nil).Verify), arg0, arg1, arg2, arg3, arg4)
+}
@@ -0,0 +1,179 @@
+package kms_connector
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrInvalidSignature = awserrors.New(awserrors.ErrUnauthorized, "invalid signature")
+
+type SigningAlgorithm = types.SigningAlgorithmSpec
+
+func signingHash(alg SigningAlgorithm) (crypto.Hash, error) {
+	switch alg {
+	case types.SigningAlgorithmSpecRsassaPssSha256,
+		types.SigningAlgorithmSpecRsassaPkcs1V15Sha256,
+		types.SigningAlgorithmSpecEcdsaSha256:
+		return crypto.SHA256, nil
+	case types.SigningAlgorithmSpecRsassaPssSha384,
+		types.SigningAlgorithmSpecRsassaPkcs1V15Sha384,
+		types.SigningAlgorithmSpecEcdsaSha384:
+		return crypto.SHA384, nil
+	case types.SigningAlgorithmSpecRsassaPssSha512,
+		types.SigningAlgorithmSpecRsassaPkcs1V15Sha512,
+		types.SigningAlgorithmSpecEcdsaSha512:
+		return crypto.SHA512, nil
+	}
+
+	return 0, fmt.Errorf("unsupported signing algorithm %q", alg)
+}
+
+func digest(alg SigningAlgorithm, message []byte) ([]byte, error) {
+	hash, err := signingHash(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	h := hash.New()
+	h.Write(message)
+
+	return h.Sum(nil), nil
+}
+
+func (c *KMSConnector) signingKeyID(keyID string) string {
+	if keyID == "" {
+		return c.GetKeyID()
+	}
+
+	return keyID
+}
+
+// Sign signs message with an asymmetric key; an empty keyID uses the
+// default key. The message is hashed locally so it may be of any size.
+func (c *KMSConnector) Sign(ctx context.Context, keyID string, message []byte, alg SigningAlgorithm) (_ []byte, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "KMSConnector", "Sign")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	keyID = c.signingKeyID(keyID)
+
+	d, err := digest(alg, message)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.client.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(keyID),
+		Message:          d,
+		MessageType:      types.MessageTypeDigest,
+		SigningAlgorithm: alg,
+	})
+	if err != nil {
+		c.logger.Error("Sign error", zap.String("key_id", keyID), zap.Error(err))
+		return nil, err
+	}
+
+	return result.Signature, nil
+}
+
+// Verify checks signature locally against the cached public key and
+// returns ErrInvalidSignature when it does not match.
+func (c *KMSConnector) Verify(ctx context.Context, keyID string, message []byte, signature []byte, alg SigningAlgorithm) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "KMSConnector", "Verify")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	hash, err := signingHash(alg)
+	if err != nil {
+		return err
+	}
+
+	d, err := digest(alg, message)
+	if err != nil {
+		return err
+	}
+
+	pub, err := c.GetPublicKey(ctx, keyID)
+	if err != nil {
+		return err
+	}
+
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		switch alg {
+		case types.SigningAlgorithmSpecRsassaPssSha256,
+			types.SigningAlgorithmSpecRsassaPssSha384,
+			types.SigningAlgorithmSpecRsassaPssSha512:
+			// KMS uses a salt as long as the digest
+			err = rsa.VerifyPSS(key, hash, d, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		case types.SigningAlgorithmSpecRsassaPkcs1V15Sha256,
+			types.SigningAlgorithmSpecRsassaPkcs1V15Sha384,
+			types.SigningAlgorithmSpecRsassaPkcs1V15Sha512:
+			err = rsa.VerifyPKCS1v15(key, hash, d, signature)
+		default:
+			return fmt.Errorf("signing algorithm %q does not match an RSA key", alg)
+		}
+		if err != nil {
+			return ErrInvalidSignature
+		}
+
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, d, signature) {
+			return ErrInvalidSignature
+		}
+
+	default:
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+
+	return nil
+}
+
+// GetPublicKey returns the public key of an asymmetric key, fetching it
+// once per key ID.
+func (c *KMSConnector) GetPublicKey(ctx context.Context, keyID string) (_ crypto.PublicKey, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "KMSConnector", "GetPublicKey")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	keyID = c.signingKeyID(keyID)
+
+	c.mu.RLock()
+	pub, ok := c.publicKeys[keyID]
+	c.mu.RUnlock()
+
+	if ok {
+		return pub, nil
+	}
+
+	result, err := c.client.GetPublicKey(ctx, &kms.GetPublicKeyInput{
+		KeyId: aws.String(keyID),
+	})
+	if err != nil {
+		c.logger.Error("Get public key error", zap.String("key_id", keyID), zap.Error(err))
+		return nil, err
+	}
+
+	pub, err = x509.ParsePKIXPublicKey(result.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.publicKeys[keyID] = pub
+	c.mu.Unlock()
+
+	return pub, nil
+}
@@ -0,0 +1,24 @@
+package kvstore
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. KVSTORE_KEY_ATTRIBUTE for the kvstore
+// scope.
+type Config struct {
+	KeyAttribute   string `mapstructure:"key_attribute" default:"pk"`
+	ValueAttribute string `mapstructure:"value_attribute" default:"value"`
+	TTLAttribute   string `mapstructure:"ttl_attribute" default:"expires_at"`
+	KeyPrefix      string `mapstructure:"key_prefix" default:""`
+}
+
+func (s *DynamoDBStore) initDefaultConfigs() {
+	moduleconfig.Register(s.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (s *DynamoDBStore) Config() Config {
+	return s.config
+}
@@ -0,0 +1,139 @@
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/elmntri/zeitgeber-aws-modules/dynamodb_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+)
+
+const (
+	DefaultKeyAttribute   = "pk"
+	DefaultValueAttribute = "value"
+	DefaultTTLAttribute   = "expires_at"
+	DefaultKeyPrefix      = ""
+)
+
+// DynamoDBStore keeps each key as one item in the table of the
+// dynamodb_connector. Expired items are hidden on read even before
+// DynamoDB TTL deletes them.
+type DynamoDBStore struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+}
+
+type Params struct {
+	fx.In
+
+	Logger   *zap.Logger
+	DynamoDB *dynamodb_connector.DynamoDBConnector
+}
+
+// Module provides a KVStore backed by the dynamodb_connector table.
+func Module(scope string) fx.Option {
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) (KVStore, error) {
+
+			s := &DynamoDBStore{
+				params: p,
+				logger: requestid.Logger(p.Logger.Named(scope)),
+				scope:  scope,
+			}
+
+			s.initDefaultConfigs()
+
+			// The store has no lifecycle, so it loads when provided
+			if err := moduleconfig.Load(scope, &s.config); err != nil {
+				return nil, err
+			}
+
+			return s, nil
+		}),
+	)
+}
+
+func (s *DynamoDBStore) key(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		s.config.KeyAttribute: &types.AttributeValueMemberS{
+			Value: s.config.KeyPrefix + key,
+		},
+	}
+}
+
+func (s *DynamoDBStore) Get(ctx context.Context, key string) ([]byte, error) {
+	result, err := s.params.DynamoDB.GetClient().GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.params.DynamoDB.GetTableName()),
+		Key:       s.key(key),
+	})
+	if err != nil {
+		s.logger.Error("KV get error", zap.String("key", key), zap.Error(err))
+		return nil, err
+	}
+
+	if result.Item == nil {
+		return nil, ErrNotFound
+	}
+
+	if ttl, ok := result.Item[s.config.TTLAttribute].(*types.AttributeValueMemberN); ok {
+		expiresAt, err := strconv.ParseInt(ttl.Value, 10, 64)
+		if err == nil && expiresAt <= time.Now().Unix() {
+			return nil, ErrNotFound
+		}
+	}
+
+	value, ok := result.Item[s.config.ValueAttribute].(*types.AttributeValueMemberB)
+	if !ok {
+		return nil, fmt.Errorf("kv item %q has no binary value attribute", key)
+	}
+
+	return value.Value, nil
+}
+
+func (s *DynamoDBStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	item := s.key(key)
+	item[s.config.ValueAttribute] = &types.AttributeValueMemberB{Value: value}
+
+	if ttl > 0 {
+		item[s.config.TTLAttribute] = &types.AttributeValueMemberN{
+			Value: strconv.FormatInt(dynamodb_connector.ExpiresAt(ttl), 10),
+		}
+	}
+
+	_, err := s.params.DynamoDB.GetClient().PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.params.DynamoDB.GetTableName()),
+		Item:      item,
+	})
+	if err != nil {
+		s.logger.Error("KV set error", zap.String("key", key), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *DynamoDBStore) Delete(ctx context.Context, key string) error {
+	_, err := s.params.DynamoDB.GetClient().DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.params.DynamoDB.GetTableName()),
+		Key:       s.key(key),
+	})
+	if err != nil {
+		s.logger.Error("KV delete error", zap.String("key", key), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
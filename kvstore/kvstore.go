@@ -0,0 +1,37 @@
+package kvstore
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrNotFound = awserrors.New(awserrors.ErrNotFound, "key not found")
+
+// KVStore is a minimal key-value store with optional expiry. A ttl of 0
+// means the value never expires.
+type KVStore interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+func GetJSON(ctx context.Context, store KVStore, key string, out interface{}) error {
+	data, err := store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+func SetJSON(ctx context.Context, store KVStore, key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return store.Set(ctx, key, data, ttl)
+}
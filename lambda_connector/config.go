@@ -0,0 +1,28 @@
+package lambda_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. LAMBDA_FUNCTION_NAME for the lambda
+// scope.
+type Config struct {
+	FunctionName      string `mapstructure:"function_name" default:""`
+	FunctionQualifier string `mapstructure:"function_qualifier" default:""`
+	FunctionKey       string `mapstructure:"function_key" default:"ABCDE"`
+	FunctionSecret    string `mapstructure:"function_secret" default:"example_secret"`
+	FunctionToken     string `mapstructure:"function_token" default:""`
+	FunctionRegion    string `mapstructure:"function_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *LambdaConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *LambdaConnector) Config() Config {
+	return c.config
+}
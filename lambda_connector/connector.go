@@ -0,0 +1,378 @@
+package lambda_connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+var ErrFunctionError = awserrors.New(awserrors.ErrTransient, "function error")
+
+const (
+	DefaultFunctionName      = ""
+	DefaultFunctionQualifier = ""
+	DefaultFunctionKey       = "ABCDE"
+	DefaultFunctionSecret    = "example_secret"
+	DefaultFunctionToken     = ""
+	DefaultFunctionRegion    = "us-west-1"
+)
+
+const (
+	InvokeSync  = types.InvocationTypeRequestResponse
+	InvokeAsync = types.InvocationTypeEvent
+)
+
+var preflightActions = []string{
+	"lambda:InvokeFunction",
+}
+
+// FunctionError is returned when the function itself failed. Payload is
+// the raw error document returned by Lambda; it matches ErrFunctionError
+// with errors.Is.
+type FunctionError struct {
+	FunctionName string   `json:"-"`
+	Kind         string   `json:"-"` // Handled or Unhandled
+	ErrorMessage string   `json:"errorMessage"`
+	ErrorType    string   `json:"errorType"`
+	StackTrace   []string `json:"stackTrace"`
+	Payload      []byte   `json:"-"`
+}
+
+func (e *FunctionError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.FunctionName, e.ErrorType, e.ErrorMessage)
+}
+
+func (e *FunctionError) Unwrap() error {
+	return ErrFunctionError
+}
+
+// InvokeOptions overrides the defaults of Invoke. An empty Qualifier uses
+// function_qualifier; an empty InvocationType is a synchronous call.
+type InvokeOptions struct {
+	Qualifier      string
+	InvocationType types.InvocationType
+}
+
+type LambdaConnector struct {
+	params    Params
+	logger    *zap.Logger
+	client    *lambda.Client
+	awsConfig aws.Config
+	scope     string
+	config    Config
+	tracker   *inflight.Tracker
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *lambda.Client          `optional:"true"`
+}
+
+// Module provides the LambdaConnector of scope. It uses the
+// *lambda.Client in the graph, if any, instead of building one, and
+// adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the LambdaConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *lambda.Client named after scope, if
+// any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *LambdaConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*lambda.Client](scope, named),
+		instance.Provide[*LambdaConnector](scope, named, func(p Params) *LambdaConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &LambdaConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *LambdaConnector) Invoker {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *LambdaConnector) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting LambdaConnector",
+		zap.String("function_name", c.config.FunctionName),
+		zap.String("function_qualifier", c.config.FunctionQualifier),
+		zap.String("function_region", c.config.FunctionRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.awsConfig = cfg
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = lambda.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = lambda.New(c.client.Options(), func(o *lambda.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *LambdaConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped LambdaConnector")
+
+	return nil
+}
+
+func (c *LambdaConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.FunctionRegion,
+		Key:         c.config.FunctionKey,
+		Secret:      c.config.FunctionSecret,
+		Token:       c.config.FunctionToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *LambdaConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("function_region")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("function_key", DefaultFunctionKey)
+		v.NotPlaceholder("function_secret", DefaultFunctionSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the account settings.
+func (c *LambdaConnector) probe(ctx context.Context) error {
+	_, err := c.client.GetAccountSettings(ctx, &lambda.GetAccountSettingsInput{})
+
+	return err
+}
+
+// Invoke calls functionName synchronously and unmarshals the JSON
+// response into out, which may be nil. An empty functionName uses
+// function_name.
+func (c *LambdaConnector) Invoke(ctx context.Context, functionName string, payload interface{}, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "LambdaConnector", "Invoke")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.InvokeWithOptions(ctx, functionName, payload, out, InvokeOptions{})
+}
+
+// InvokeAsync queues an Event invocation of functionName and returns once
+// Lambda has accepted it.
+func (c *LambdaConnector) InvokeAsync(ctx context.Context, functionName string, payload interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "LambdaConnector", "InvokeAsync")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.InvokeWithOptions(ctx, functionName, payload, nil, InvokeOptions{
+		InvocationType: InvokeAsync,
+	})
+}
+
+// InvokeWithOptions invokes functionName with a JSON payload. A []byte or
+// json.RawMessage payload is sent as is. Failures inside the function are
+// returned as *FunctionError.
+func (c *LambdaConnector) InvokeWithOptions(ctx context.Context, functionName string, payload interface{}, out interface{}, opts InvokeOptions) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "LambdaConnector", "InvokeWithOptions")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if functionName == "" {
+		functionName = c.config.FunctionName
+	}
+
+	qualifier := opts.Qualifier
+	if qualifier == "" {
+		qualifier = c.config.FunctionQualifier
+	}
+
+	invocationType := opts.InvocationType
+	if invocationType == "" {
+		invocationType = InvokeSync
+	}
+
+	var body []byte
+	switch p := payload.(type) {
+	case nil:
+	case []byte:
+		body = p
+	case json.RawMessage:
+		body = p
+	default:
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+
+		body = data
+	}
+
+	input := &lambda.InvokeInput{
+		FunctionName:   aws.String(functionName),
+		InvocationType: invocationType,
+		Payload:        body,
+	}
+	if qualifier != "" {
+		input.Qualifier = aws.String(qualifier)
+	}
+
+	result, err := c.client.Invoke(ctx, input)
+	if err != nil {
+		c.logger.Error("Invoke function error", zap.String("function_name", functionName), zap.Error(err))
+		return err
+	}
+
+	if result.FunctionError != nil {
+		ferr := &FunctionError{
+			FunctionName: functionName,
+			Kind:         aws.ToString(result.FunctionError),
+			Payload:      result.Payload,
+		}
+
+		// Runtimes return {errorMessage, errorType, stackTrace}; keep the raw
+		// payload when the document has another shape.
+		json.Unmarshal(result.Payload, ferr)
+
+		return ferr
+	}
+
+	if out == nil || len(result.Payload) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(result.Payload, out)
+}
+
+func (c *LambdaConnector) GetClient() *lambda.Client {
+	return c.client
+}
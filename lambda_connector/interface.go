@@ -0,0 +1,14 @@
+package lambda_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Invoker
+
+// Invoker invokes functions.
+type Invoker interface {
+	Invoke(ctx context.Context, functionName string, payload interface{}, out interface{}) error
+	InvokeAsync(ctx context.Context, functionName string, payload interface{}) error
+	InvokeWithOptions(ctx context.Context, functionName string, payload interface{}, out interface{}, opts InvokeOptions) error
+}
+
+var _ Invoker = (*LambdaConnector)(nil)
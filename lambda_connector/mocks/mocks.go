@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/lambda_connector (interfaces: Invoker)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . Invoker
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	lambda_connector "github.com/elmntri/zeitgeber-aws-modules/lambda_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockInvoker is a mock of Invoker interface.
+type MockInvoker struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvokerMockRecorder
+}
+
+// MockInvokerMockRecorder is the mock recorder for MockInvoker.
+type MockInvokerMockRecorder struct {
+	mock *MockInvoker
+}
+
+// NewMockInvoker creates a new mock instance.
+func NewMockInvoker(ctrl *gomock.Controller) *MockInvoker {
+	mock := &MockInvoker{ctrl: ctrl}
+	mock.recorder = &MockInvokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvoker) EXPECT() *MockInvokerMockRecorder {
+	return m.recorder
+}
+
+// Invoke mocks base method.
+func (m *MockInvoker) Invoke(arg0 context.Context, arg1 string, arg2, arg3 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invoke", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Invoke indicates an expected call of Invoke.
+func (mr *MockInvokerMockRecorder) Invoke(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invoke", reflect.TypeOf((*MockInvoker)(nil).Invoke), arg0, arg1, arg2, arg3)
+}
+
+// InvokeAsync mocks base method.
+func (m *MockInvoker) InvokeAsync(arg0 context.Context, arg1 string, arg2 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvokeAsync", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvokeAsync indicates an expected call of InvokeAsync.
+func (mr *MockInvokerMockRecorder) InvokeAsync(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvokeAsync", reflect.TypeOf((*MockInvoker)(nil).InvokeAsync), arg0, arg1, arg2)
+}
+
+// InvokeWithOptions mocks base method.
+func (m *MockInvoker) InvokeWithOptions(arg0 context.Context, arg1 string, arg2, arg3 any, arg4 lambda_connector.InvokeOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvokeWithOptions", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvokeWithOptions indicates an expected call of InvokeWithOptions.
+func (mr *MockInvokerMockRecorder) InvokeWithOptions(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvokeWithOptions", reflect.TypeOf((*MockInvoker)(nil).InvokeWithOptions), arg0, arg1, arg2, arg3, arg4)
+}
@@ -0,0 +1,86 @@
+package lambda_connector
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// Signing names for IAM-authenticated endpoints.
+const (
+	SigningServiceLambda     = "lambda"      // function URLs
+	SigningServiceAPIGateway = "execute-api" // API Gateway with IAM auth
+)
+
+// SignRequest SigV4-signs req for service with the module's credentials
+// and region. The body is read to compute the payload hash and then
+// restored.
+func (c *LambdaConnector) SignRequest(ctx context.Context, req *http.Request, service string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "LambdaConnector", "SignRequest")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		body = data
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	hash := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(hash[:])
+
+	creds, err := c.awsConfig.Credentials.Retrieve(ctx)
+	if err != nil {
+		c.logger.Error("Retrieve credentials error", zap.Error(err))
+		return err
+	}
+
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	return v4.NewSigner().SignHTTP(ctx, creds, req, payloadHash, service, c.awsConfig.Region, time.Now())
+}
+
+type signingTransport struct {
+	c       *LambdaConnector
+	service string
+	base    http.RoundTripper
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+
+	if err := t.c.SignRequest(req.Context(), req, t.service); err != nil {
+		return nil, err
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// HTTPClient returns an http.Client that signs every request for service,
+// e.g. SigningServiceLambda for function URLs.
+func (c *LambdaConnector) HTTPClient(service string) *http.Client {
+	return &http.Client{
+		Transport: &signingTransport{
+			c:       c,
+			service: service,
+			base:    http.DefaultTransport,
+		},
+	}
+}
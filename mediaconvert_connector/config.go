@@ -0,0 +1,32 @@
+package mediaconvert_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. MEDIACONVERT_ROLE_ARN for the
+// mediaconvert scope.
+type Config struct {
+	RoleARN            string `mapstructure:"role_arn" default:""`
+	JobTemplate        string `mapstructure:"job_template" default:""`
+	Queue              string `mapstructure:"queue" default:""`
+	Endpoint           string `mapstructure:"endpoint" default:""`
+	OutputPrefix       string `mapstructure:"output_prefix" default:"transcoded/"`
+	PollInterval       int    `mapstructure:"poll_interval" default:"10"`
+	MediaConvertKey    string `mapstructure:"mediaconvert_key" default:"ABCDE"`
+	MediaConvertSecret string `mapstructure:"mediaconvert_secret" default:"example_secret"`
+	MediaConvertToken  string `mapstructure:"mediaconvert_token" default:""`
+	MediaConvertRegion string `mapstructure:"mediaconvert_region" default:"us-west-1"`
+	VerifyCredentials  bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight          bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *MediaConvertConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *MediaConvertConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,265 @@
+package mediaconvert_connector
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mediaconvert"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/bucket_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultRoleArn            = ""
+	DefaultJobTemplate        = ""
+	DefaultQueue              = ""
+	DefaultEndpoint           = ""
+	DefaultOutputPrefix       = "transcoded/"
+	DefaultPollInterval       = 10
+	DefaultMediaConvertKey    = "ABCDE"
+	DefaultMediaConvertSecret = "example_secret"
+	DefaultMediaConvertToken  = ""
+	DefaultMediaConvertRegion = "us-west-1"
+)
+
+var preflightActions = []string{
+	"mediaconvert:CreateJob",
+	"mediaconvert:GetJob",
+	"mediaconvert:GetJobTemplate",
+	"iam:PassRole",
+}
+
+// MediaConvertConnector transcodes videos stored in the bucket connector's
+// bucket and writes the outputs back to it under output_prefix.
+type MediaConvertConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *mediaconvert.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Bucket          *bucket_connector.BucketConnector
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *mediaconvert.Client    `optional:"true"`
+}
+
+// Module provides the MediaConvertConnector of scope. It uses the
+// *mediaconvert.Client in the graph, if any, instead of building one,
+// and adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the MediaConvertConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *mediaconvert.Client named after scope,
+// if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *MediaConvertConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*mediaconvert.Client](scope, named),
+		instance.Provide[*MediaConvertConnector](scope, named, func(p Params) *MediaConvertConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &MediaConvertConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *MediaConvertConnector) Transcoder {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *MediaConvertConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting MediaConvertConnector",
+		zap.String("mediaconvert_region", c.config.MediaConvertRegion),
+		zap.String("job_template", c.config.JobTemplate),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	// endpoint replaces the regional endpoint, e.g. with an account endpoint
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = mediaconvert.NewFromConfig(cfg, func(o *mediaconvert.Options) {
+			if endpoint := c.config.Endpoint; endpoint != "" {
+				o.BaseEndpoint = aws.String(endpoint)
+			}
+		})
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = mediaconvert.New(c.client.Options(), func(o *mediaconvert.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *MediaConvertConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped MediaConvertConnector")
+
+	return nil
+}
+
+func (c *MediaConvertConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.MediaConvertRegion,
+		Key:         c.config.MediaConvertKey,
+		Secret:      c.config.MediaConvertSecret,
+		Token:       c.config.MediaConvertToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *MediaConvertConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("mediaconvert_region")
+
+	v.Required("role_arn")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("mediaconvert_key", DefaultMediaConvertKey)
+		v.NotPlaceholder("mediaconvert_secret", DefaultMediaConvertSecret)
+	}
+
+	return v.Err()
+}
+
+// probe lists a queue.
+func (c *MediaConvertConnector) probe(ctx context.Context) error {
+	_, err := c.client.ListQueues(ctx, &mediaconvert.ListQueuesInput{
+		MaxResults: aws.Int32(1),
+	})
+
+	return err
+}
+
+func (c *MediaConvertConnector) GetClient() *mediaconvert.Client {
+	return c.client
+}
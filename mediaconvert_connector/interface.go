@@ -0,0 +1,19 @@
+package mediaconvert_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/mediaconvert/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Transcoder
+
+// Transcoder transcodes objects of the input bucket.
+type Transcoder interface {
+	Transcode(ctx context.Context, key string, template string) (*Output, error)
+	SubmitJob(ctx context.Context, key string, template string) (string, error)
+	WaitForJob(ctx context.Context, jobID string) (*types.Job, error)
+	GetOutput(ctx context.Context, job *types.Job) (*Output, error)
+}
+
+var _ Transcoder = (*MediaConvertConnector)(nil)
@@ -0,0 +1,206 @@
+package mediaconvert_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mediaconvert"
+	"github.com/aws/aws-sdk-go-v2/service/mediaconvert/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// outputPrefixKey is the job metadata holding where the outputs went.
+const outputPrefixKey = "output_prefix"
+
+var (
+	ErrJobFailed        = awserrors.New(awserrors.ErrTransient, "transcode job failed")
+	ErrTemplateNotFound = awserrors.New(awserrors.ErrNotFound, "job template not found")
+)
+
+// Output lists the objects a finished job wrote to the bucket.
+type Output struct {
+	JobID string
+	Keys  []string
+	URLs  []string
+}
+
+// Transcode submits a job for the video at key and waits for its
+// outputs. An empty template means job_template.
+func (c *MediaConvertConnector) Transcode(ctx context.Context, key string, template string) (_ *Output, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "MediaConvertConnector", "Transcode")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	jobID, err := c.SubmitJob(ctx, key, template)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := c.WaitForJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetOutput(ctx, job)
+}
+
+// SubmitJob starts transcoding the video at key with a job template and
+// returns the job ID. Every output group of the template writes to
+// output_prefix followed by key without its extension instead of its own
+// destination.
+func (c *MediaConvertConnector) SubmitJob(ctx context.Context, key string, template string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "MediaConvertConnector", "SubmitJob")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if template == "" {
+		template = c.config.JobTemplate
+	}
+
+	result, err := c.client.GetJobTemplate(ctx, &mediaconvert.GetJobTemplateInput{
+		Name: aws.String(template),
+	})
+	if err != nil {
+		var notFound *types.NotFoundException
+		if errors.As(err, &notFound) {
+			return "", fmt.Errorf("%w: %s: %w", ErrTemplateNotFound, template, err)
+		}
+
+		c.logger.Error("Get job template error", zap.String("job_template", template), zap.Error(err))
+		return "", err
+	}
+
+	outputPrefix := c.config.OutputPrefix + strings.TrimSuffix(key, path.Ext(key)) + "/"
+	destination := "s3://" + c.params.Bucket.GetBucketName() + "/" + outputPrefix
+
+	var outputGroups []types.OutputGroup
+	if result.JobTemplate.Settings != nil {
+		outputGroups = result.JobTemplate.Settings.OutputGroups
+	}
+
+	for _, group := range outputGroups {
+		setDestination(group.OutputGroupSettings, destination)
+	}
+
+	input := &mediaconvert.CreateJobInput{
+		Role:        aws.String(c.config.RoleARN),
+		JobTemplate: aws.String(template),
+		Settings: &types.JobSettings{
+			Inputs: []types.Input{
+				{FileInput: aws.String("s3://" + c.params.Bucket.GetBucketName() + "/" + key)},
+			},
+			OutputGroups: outputGroups,
+		},
+		UserMetadata: map[string]string{
+			outputPrefixKey: outputPrefix,
+		},
+	}
+
+	if queue := c.config.Queue; queue != "" {
+		input.Queue = aws.String(queue)
+	}
+
+	job, err := c.client.CreateJob(ctx, input)
+	if err != nil {
+		c.logger.Error("Create job error", zap.String("key", key), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(job.Job.Id), nil
+}
+
+// WaitForJob polls a job every poll_interval seconds until it completed.
+func (c *MediaConvertConnector) WaitForJob(ctx context.Context, jobID string) (_ *types.Job, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "MediaConvertConnector", "WaitForJob")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Second
+
+	for {
+		result, err := c.client.GetJob(ctx, &mediaconvert.GetJobInput{
+			Id: aws.String(jobID),
+		})
+		if err != nil {
+			c.logger.Error("Get job error", zap.String("job_id", jobID), zap.Error(err))
+			return nil, err
+		}
+
+		job := result.Job
+		switch job.Status {
+		case types.JobStatusComplete:
+			return job, nil
+
+		case types.JobStatusError, types.JobStatusCanceled:
+			return nil, fmt.Errorf("%w: %s: %s: %s", ErrJobFailed, jobID, job.Status, aws.ToString(job.ErrorMessage))
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// GetOutput lists the objects a completed job wrote with their bucket
+// URLs. GetJob does not report output paths, so the job's output prefix
+// is listed.
+func (c *MediaConvertConnector) GetOutput(ctx context.Context, job *types.Job) (_ *Output, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "MediaConvertConnector", "GetOutput")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	outputPrefix, ok := job.UserMetadata[outputPrefixKey]
+	if !ok {
+		return nil, fmt.Errorf("%s: job %s was not submitted by this connector", c.scope, aws.ToString(job.Id))
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(c.params.Bucket.GetClient(), &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.params.Bucket.GetBucketName()),
+		Prefix: aws.String(outputPrefix),
+	})
+
+	output := &Output{
+		JobID: aws.ToString(job.Id),
+	}
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("List objects error", zap.String("prefix", outputPrefix), zap.Error(err))
+			return nil, err
+		}
+
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			output.Keys = append(output.Keys, key)
+			output.URLs = append(output.URLs, c.params.Bucket.ObjectURL(key))
+		}
+	}
+
+	return output, nil
+}
+
+func setDestination(settings *types.OutputGroupSettings, destination string) {
+	if settings == nil {
+		return
+	}
+
+	switch {
+	case settings.FileGroupSettings != nil:
+		settings.FileGroupSettings.Destination = aws.String(destination)
+	case settings.HlsGroupSettings != nil:
+		settings.HlsGroupSettings.Destination = aws.String(destination)
+	case settings.DashIsoGroupSettings != nil:
+		settings.DashIsoGroupSettings.Destination = aws.String(destination)
+	case settings.CmafGroupSettings != nil:
+		settings.CmafGroupSettings.Destination = aws.String(destination)
+	case settings.MsSmoothGroupSettings != nil:
+		settings.MsSmoothGroupSettings.Destination = aws.String(destination)
+	}
+}
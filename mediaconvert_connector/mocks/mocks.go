@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/mediaconvert_connector (interfaces: Transcoder)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . Transcoder
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/mediaconvert/types"
+	mediaconvert_connector "github.com/elmntri/zeitgeber-aws-modules/mediaconvert_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTranscoder is a mock of Transcoder interface.
+type MockTranscoder struct {
+	ctrl     *gomock.Controller
+	recorder *MockTranscoderMockRecorder
+}
+
+// MockTranscoderMockRecorder is the mock recorder for MockTranscoder.
+type MockTranscoderMockRecorder struct {
+	mock *MockTranscoder
+}
+
+// NewMockTranscoder creates a new mock instance.
+func NewMockTranscoder(ctrl *gomock.Controller) *MockTranscoder {
+	mock := &MockTranscoder{ctrl: ctrl}
+	mock.recorder = &MockTranscoderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTranscoder) EXPECT() *MockTranscoderMockRecorder {
+	return m.recorder
+}
+
+// GetOutput mocks base method.
+func (m *MockTranscoder) GetOutput(arg0 context.Context, arg1 *types.Job) (*mediaconvert_connector.Output, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutput", arg0, arg1)
+	ret0, _ := ret[0].(*mediaconvert_connector.Output)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutput indicates an expected call of GetOutput.
+func (mr *MockTranscoderMockRecorder) GetOutput(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutput", reflect.TypeOf((*MockTranscoder)(nil).GetOutput), arg0, arg1)
+}
+
+// SubmitJob mocks base method.
+func (m *MockTranscoder) SubmitJob(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitJob indicates an expected call of SubmitJob.
+func (mr *MockTranscoderMockRecorder) SubmitJob(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitJob", reflect.TypeOf((*MockTranscoder)(nil).SubmitJob), arg0, arg1, arg2)
+}
+
+// Transcode mocks base method.
+func (m *MockTranscoder) Transcode(arg0 context.Context, arg1, arg2 string) (*mediaconvert_connector.Output, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transcode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*mediaconvert_connector.Output)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transcode indicates an expected call of Transcode.
+func (mr *MockTranscoderMockRecorder) Transcode(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transcode", reflect.TypeOf((*MockTranscoder)(nil).Transcode), arg0, arg1, arg2)
+}
+
+// WaitForJob mocks base method.
+func (m *MockTranscoder) WaitForJob(arg0 context.Context, arg1 string) (*types.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForJob", arg0, arg1)
+	ret0, _ := ret[0].(*types.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForJob indicates an expected call of WaitForJob.
+func (mr *MockTranscoderMockRecorder) WaitForJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForJob", reflect.TypeOf((*MockTranscoder)(nil).WaitForJob), arg0, arg1)
+}
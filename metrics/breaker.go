@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+)
+
+var circuitStates = []circuitbreaker.State{
+	circuitbreaker.StateClosed,
+	circuitbreaker.StateOpen,
+	circuitbreaker.StateHalfOpen,
+}
+
+// breakerCollector reports the circuits of a Breaker as they are when
+// scraped, one state series per circuit set to 1 for its current state.
+type breakerCollector struct {
+	breaker *circuitbreaker.Breaker
+
+	state    *prometheus.Desc
+	rejected *prometheus.Desc
+	opened   *prometheus.Desc
+}
+
+func newBreakerCollector(breaker *circuitbreaker.Breaker, namespace string, constLabels prometheus.Labels) *breakerCollector {
+	return &breakerCollector{
+		breaker: breaker,
+		state: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "circuit", "state"),
+			"Current state of the circuits, 1 for the state they are in.",
+			[]string{LabelConnector, LabelClass, LabelState}, constLabels,
+		),
+		rejected: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "circuit", "rejected_total"),
+			"AWS operations rejected by open circuits.",
+			[]string{LabelConnector, LabelClass}, constLabels,
+		),
+		opened: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "circuit", "opened_total"),
+			"Times the circuits opened.",
+			[]string{LabelConnector, LabelClass}, constLabels,
+		),
+	}
+}
+
+func (c *breakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.state
+	ch <- c.rejected
+	ch <- c.opened
+}
+
+func (c *breakerCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stats := range c.breaker.Stats() {
+		for _, state := range circuitStates {
+			value := 0.0
+			if stats.State == state {
+				value = 1
+			}
+
+			ch <- prometheus.MustNewConstMetric(c.state, prometheus.GaugeValue, value, stats.Scope, string(stats.Class), string(state))
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.rejected, prometheus.CounterValue, float64(stats.Rejected), stats.Scope, string(stats.Class))
+		ch <- prometheus.MustNewConstMetric(c.opened, prometheus.CounterValue, float64(stats.Opened), stats.Scope, string(stats.Class))
+	}
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. METRICS_NAMESPACE for the metrics
+// scope.
+type Config struct {
+	Namespace       string            `mapstructure:"namespace" default:"aws"`
+	Path            string            `mapstructure:"path" default:"/metrics"`
+	DefaultRegistry bool              `mapstructure:"default_registry" default:"false"`
+	ConstLabels     map[string]string `mapstructure:"const_labels"`
+
+	Buckets []string `mapstructure:"buckets"`
+}
+
+func (m *Metrics) initDefaultConfigs() {
+	moduleconfig.Register(m.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (m *Metrics) Config() Config {
+	return m.config
+}
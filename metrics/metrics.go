@@ -0,0 +1,303 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/elmntri/zeitgeber-common-modules/http_server"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultNamespace       = "aws"
+	DefaultPath            = "/metrics"
+	DefaultDefaultRegistry = false
+)
+
+// Labels of the metrics of every module. Connectors are labelled with
+// their scope, services with the SDK's service ID.
+const (
+	LabelConnector = "connector"
+	LabelService   = "service"
+	LabelOperation = "operation"
+	LabelCode      = "code"
+	LabelClass     = "class"
+	LabelState     = "state"
+)
+
+// CodeOK is the code label of operations that succeeded. Failed ones are
+// labelled with the AWS error code, or "error" when there is none.
+const CodeOK = "ok"
+
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics is the Prometheus registry shared by the modules. Connectors
+// given Metrics record every AWS operation as
+//
+//	<namespace>_operations_total{connector, service, operation, code}
+//	<namespace>_operation_duration_seconds{connector, service, operation}
+//	<namespace>_operation_retries_total{connector, service, operation}
+//
+// and, with a circuitbreaker.Breaker, the state of its circuits as
+// <namespace>_circuit_state{connector, class, state}. The metrics are
+// served at path when an HTTPServer is provided, or through Handler.
+type Metrics struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	registry   *prometheus.Registry
+	registerer prometheus.Registerer
+	gatherer   prometheus.Gatherer
+
+	operations *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+	retries    *prometheus.CounterVec
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle  fx.Lifecycle
+	Logger     *zap.Logger
+	HTTPServer *http_server.HTTPServer `optional:"true"`
+	Breaker    *circuitbreaker.Breaker `optional:"true"`
+}
+
+func Module(scope string) fx.Option {
+
+	var m *Metrics
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) (*Metrics, error) {
+
+			logger = p.Logger.Named(scope)
+
+			m := &Metrics{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			m.initDefaultConfigs()
+
+			// Loaded here, as the registry is set up and connectors may
+			// start before the module
+			if err := moduleconfig.Load(scope, &m.config); err != nil {
+				return nil, err
+			}
+
+			if err := m.register(); err != nil {
+				return nil, err
+			}
+
+			return m, nil
+		}),
+		fx.Provide(func(m *Metrics) prometheus.Registerer {
+			return m.registerer
+		}),
+		fx.Provide(func(m *Metrics) prometheus.Gatherer {
+			return m.gatherer
+		}),
+		fx.Populate(&m),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: m.onStart,
+					OnStop:  m.onStop,
+				},
+			)
+		}),
+	)
+}
+
+// register creates the operation metrics. With default_registry, they go
+// to the global Prometheus registry, next to those of other libraries;
+// otherwise to a registry of their own with the Go and process
+// collectors.
+func (m *Metrics) register() error {
+	if m.config.DefaultRegistry {
+		m.registerer = prometheus.DefaultRegisterer
+		m.gatherer = prometheus.DefaultGatherer
+	} else {
+		m.registry = prometheus.NewRegistry()
+		m.registerer = m.registry
+		m.gatherer = m.registry
+
+		m.registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	namespace := m.Namespace()
+	constLabels := prometheus.Labels(m.config.ConstLabels)
+
+	m.operations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "operations_total",
+		Help:        "AWS operations by result.",
+		ConstLabels: constLabels,
+	}, []string{LabelConnector, LabelService, LabelOperation, LabelCode})
+
+	m.duration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   namespace,
+		Name:        "operation_duration_seconds",
+		Help:        "Duration of AWS operations, retries included.",
+		ConstLabels: constLabels,
+		Buckets:     m.buckets(),
+	}, []string{LabelConnector, LabelService, LabelOperation})
+
+	m.retries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   namespace,
+		Name:        "operation_retries_total",
+		Help:        "Attempts of AWS operations after the first.",
+		ConstLabels: constLabels,
+	}, []string{LabelConnector, LabelService, LabelOperation})
+
+	for _, collector := range []prometheus.Collector{m.operations, m.duration, m.retries} {
+		if err := m.registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+
+	if m.params.Breaker != nil {
+		return m.registerer.Register(newBreakerCollector(m.params.Breaker, namespace, constLabels))
+	}
+
+	return nil
+}
+
+// buckets are the upper bounds of the duration histogram, DefaultBuckets
+// unless listed under buckets.
+func (m *Metrics) buckets() []float64 {
+	buckets := []float64{}
+	for _, bucket := range m.config.Buckets {
+		var value float64
+		if _, err := fmt.Sscan(bucket, &value); err == nil {
+			buckets = append(buckets, value)
+		}
+	}
+
+	if len(buckets) == 0 {
+		return DefaultBuckets
+	}
+
+	return buckets
+}
+
+func (m *Metrics) onStart(ctx context.Context) error {
+
+	path := m.config.Path
+
+	logger.Info("Starting metrics",
+		zap.String("namespace", m.Namespace()),
+		zap.String("path", path),
+		zap.Bool("http_server", m.params.HTTPServer != nil),
+	)
+
+	if m.params.HTTPServer != nil && path != "" {
+		m.params.HTTPServer.GetRouter().GET(path, gin.WrapH(m.Handler()))
+	}
+
+	return nil
+}
+
+func (m *Metrics) onStop(ctx context.Context) error {
+
+	m.logger.Info("Stopped metrics")
+
+	return nil
+}
+
+// Namespace prefixes the name of every metric of the modules.
+func (m *Metrics) Namespace() string {
+	return m.config.Namespace
+}
+
+// Registerer is where modules register metrics of their own, named with
+// Namespace and the Label constants.
+func (m *Metrics) Registerer() prometheus.Registerer {
+	return m.registerer
+}
+
+// Gatherer collects the registered metrics.
+func (m *Metrics) Gatherer() prometheus.Gatherer {
+	return m.gatherer
+}
+
+// Handler serves the registered metrics in the Prometheus exposition
+// format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.gatherer, promhttp.HandlerOpts{})
+}
+
+// Instrument records the operations of the clients created from cfg by
+// the connector of scope. Call it before creating them.
+func (m *Metrics) Instrument(cfg *aws.Config, scope string) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("Metrics",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				start := time.Now()
+
+				out, metadata, err := next.HandleInitialize(ctx, in)
+
+				labels := prometheus.Labels{
+					LabelConnector: scope,
+					LabelService:   awsmiddleware.GetServiceID(ctx),
+					LabelOperation: awsmiddleware.GetOperationName(ctx),
+				}
+
+				m.duration.With(labels).Observe(time.Since(start).Seconds())
+
+				if results, ok := retry.GetAttemptResults(metadata); ok && len(results.Results) > 1 {
+					m.retries.With(labels).Add(float64(len(results.Results) - 1))
+				}
+
+				labels[LabelCode] = codeOf(err)
+				m.operations.With(labels).Inc()
+
+				return out, metadata, err
+			},
+		), middleware.After)
+	})
+}
+
+func codeOf(err error) string {
+	if err == nil {
+		return CodeOK
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() != "" {
+		return apiErr.ErrorCode()
+	}
+
+	if errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+		return "circuit_open"
+	}
+
+	return "error"
+}
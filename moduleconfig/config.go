@@ -0,0 +1,134 @@
+package moduleconfig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// A module's Config is a flat struct of its keys under the scope:
+//
+//	type Config struct {
+//		BucketName   string `mapstructure:"bucket_name" default:"example.com"`
+//		BucketRegion string `mapstructure:"bucket_region" default:"us-west-1" env:"AWS_REGION"`
+//	}
+//
+// default is the value of an unset key; slices take a comma-separated
+// list. Defaults computed at run time, such as a host name, are given as
+// the field values of the cfg passed to Register instead. Every key can
+// be set from the environment as SCOPE_KEY, e.g. S3_BUCKET_NAME, and
+// from the variables listed in env, in that order.
+//
+// Fields may also be slices of structs, such as a list of rules, which
+// are decoded from the config file the same way.
+
+var envReplacer = regexp.MustCompile(`[^A-Z0-9]+`)
+
+type field struct {
+	key          string
+	env          []string
+	defaultValue interface{}
+}
+
+// Register declares the defaults of cfg, a pointer to a Config struct,
+// under scope and binds its keys to the environment. Modules call it
+// where they used to set viper defaults, so the defaults are visible to
+// viper.Get as well.
+func Register(scope string, cfg interface{}) {
+	for _, f := range fields(cfg) {
+		key := fmt.Sprintf("%s.%s", scope, f.key)
+
+		envs := append([]string{EnvName(key)}, f.env...)
+		_ = viper.BindEnv(append([]string{key}, envs...)...)
+
+		if f.defaultValue != nil {
+			viper.SetDefault(key, f.defaultValue)
+		}
+	}
+}
+
+// Load decodes the keys under scope into cfg. Values from config files,
+// the environment and viper.Set are converted to the field types, so
+// "30" loads into an int and "1m" into a time.Duration. Keys unset under
+// scope keep the value cfg has, so a connector's overrides of a shared
+// module can be loaded over a copy of the module's config.
+func Load(scope string, cfg interface{}) error {
+	settings := make(map[string]interface{})
+
+	for _, f := range fields(cfg) {
+		if value := viper.Get(fmt.Sprintf("%s.%s", scope, f.key)); value != nil {
+			settings[f.key] = value
+		}
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           cfg,
+		WeaklyTypedInput: true,
+		ZeroFields:       true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := decoder.Decode(settings); err != nil {
+		return fmt.Errorf("%s: %w", scope, err)
+	}
+
+	return nil
+}
+
+// EnvName is the environment variable bound to key, e.g. S3_BUCKET_NAME
+// for s3.bucket_name.
+func EnvName(key string) string {
+	return strings.Trim(envReplacer.ReplaceAllString(strings.ToUpper(key), "_"), "_")
+}
+
+func fields(cfg interface{}) []field {
+	t := reflect.TypeOf(cfg)
+	if t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("moduleconfig: %T is not a pointer to a struct", cfg))
+	}
+	t = t.Elem()
+	v := reflect.ValueOf(cfg).Elem()
+
+	var result []field
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		key, _, _ := strings.Cut(sf.Tag.Get("mapstructure"), ",")
+		if key == "" || key == "-" || !sf.IsExported() {
+			continue
+		}
+
+		f := field{key: key}
+
+		if env := sf.Tag.Get("env"); env != "" {
+			f.env = strings.Split(env, ",")
+		}
+
+		if value, ok := sf.Tag.Lookup("default"); ok {
+			f.defaultValue = value
+			if sf.Type.Kind() == reflect.Slice {
+				f.defaultValue = []string{}
+				if value != "" {
+					f.defaultValue = strings.Split(value, ",")
+				}
+			}
+		} else if fv := v.Field(i); !fv.IsZero() {
+			f.defaultValue = fv.Interface()
+		}
+
+		result = append(result, f)
+	}
+
+	return result
+}
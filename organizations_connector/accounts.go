@@ -0,0 +1,293 @@
+package organizations_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/partition"
+)
+
+var (
+	ErrAccountNotFound = awserrors.New(awserrors.ErrNotFound, "account not found")
+	ErrNoSTS           = awserrors.New(awserrors.ErrValidation, "no sts_connector module")
+)
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// cached returns the value stored under key, loading it when it is
+// missing or older than cache_ttl.
+func cached[T any](c *OrganizationsConnector, key string, load func() (T, error)) (T, error) {
+	c.mu.Lock()
+	entry, ok := c.cache[key]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expires) {
+		return entry.value.(T), nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	c.cache[key] = cacheEntry{
+		value:   value,
+		expires: time.Now().Add(time.Duration(c.config.CacheTTL) * time.Second),
+	}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// InvalidateCache drops every cached listing.
+func (c *OrganizationsConnector) InvalidateCache() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache = make(map[string]cacheEntry)
+}
+
+// ListAccounts returns every account of the organization, whatever its
+// status.
+func (c *OrganizationsConnector) ListAccounts(ctx context.Context) (_ []types.Account, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "OrganizationsConnector", "ListAccounts")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return cached(c, "accounts", func() ([]types.Account, error) {
+		paginator := organizations.NewListAccountsPaginator(c.client, &organizations.ListAccountsInput{})
+
+		accounts := []types.Account{}
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.Error("List accounts error", zap.Error(err))
+				return nil, err
+			}
+
+			accounts = append(accounts, page.Accounts...)
+		}
+
+		return accounts, nil
+	})
+}
+
+// ActiveAccounts returns the accounts that are neither suspended nor
+// leaving the organization.
+func (c *OrganizationsConnector) ActiveAccounts(ctx context.Context) (_ []types.Account, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "OrganizationsConnector", "ActiveAccounts")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	accounts, err := c.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return active(accounts), nil
+}
+
+// GetAccount returns an account of the organization or
+// ErrAccountNotFound.
+func (c *OrganizationsConnector) GetAccount(ctx context.Context, accountID string) (_ *types.Account, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "OrganizationsConnector", "GetAccount")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	accounts, err := c.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range accounts {
+		if aws.ToString(accounts[i].Id) == accountID {
+			return &accounts[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, accountID)
+}
+
+// ListRoots returns the roots of the organization; there is only ever
+// one.
+func (c *OrganizationsConnector) ListRoots(ctx context.Context) (_ []types.Root, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "OrganizationsConnector", "ListRoots")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return cached(c, "roots", func() ([]types.Root, error) {
+		paginator := organizations.NewListRootsPaginator(c.client, &organizations.ListRootsInput{})
+
+		roots := []types.Root{}
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.Error("List roots error", zap.Error(err))
+				return nil, err
+			}
+
+			roots = append(roots, page.Roots...)
+		}
+
+		return roots, nil
+	})
+}
+
+// ListOrganizationalUnits returns the OUs directly under parentID, a
+// root or OU ID.
+func (c *OrganizationsConnector) ListOrganizationalUnits(ctx context.Context, parentID string) (_ []types.OrganizationalUnit, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "OrganizationsConnector", "ListOrganizationalUnits")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return cached(c, "ous:"+parentID, func() ([]types.OrganizationalUnit, error) {
+		paginator := organizations.NewListOrganizationalUnitsForParentPaginator(c.client, &organizations.ListOrganizationalUnitsForParentInput{
+			ParentId: aws.String(parentID),
+		})
+
+		units := []types.OrganizationalUnit{}
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.Error("List organizational units error", zap.String("parent_id", parentID), zap.Error(err))
+				return nil, err
+			}
+
+			units = append(units, page.OrganizationalUnits...)
+		}
+
+		return units, nil
+	})
+}
+
+// ListAccountsForParent returns the accounts directly under parentID, a
+// root or OU ID.
+func (c *OrganizationsConnector) ListAccountsForParent(ctx context.Context, parentID string) (_ []types.Account, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "OrganizationsConnector", "ListAccountsForParent")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return cached(c, "accounts:"+parentID, func() ([]types.Account, error) {
+		paginator := organizations.NewListAccountsForParentPaginator(c.client, &organizations.ListAccountsForParentInput{
+			ParentId: aws.String(parentID),
+		})
+
+		accounts := []types.Account{}
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				c.logger.Error("List accounts for parent error", zap.String("parent_id", parentID), zap.Error(err))
+				return nil, err
+			}
+
+			accounts = append(accounts, page.Accounts...)
+		}
+
+		return accounts, nil
+	})
+}
+
+// AccountsInOU returns the active accounts under an OU, including those
+// of nested OUs.
+func (c *OrganizationsConnector) AccountsInOU(ctx context.Context, ouID string) (_ []types.Account, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "OrganizationsConnector", "AccountsInOU")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	accounts, err := c.ListAccountsForParent(ctx, ouID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := active(accounts)
+
+	units, err := c.ListOrganizationalUnits(ctx, ouID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, unit := range units {
+		nested, err := c.AccountsInOU(ctx, aws.ToString(unit.Id))
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, nested...)
+	}
+
+	return result, nil
+}
+
+// AccountCredentials returns credentials for the member_role_name role
+// of an account, assumed through the sts_connector module.
+func (c *OrganizationsConnector) AccountCredentials(ctx context.Context, accountID string) (_ aws.CredentialsProvider, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "OrganizationsConnector", "AccountCredentials")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	account, err := c.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.credentialsFor(account)
+}
+
+// ForEachAccount calls fn with credentials for each account in turn, as
+// AccountCredentials, e.g. over ActiveAccounts or AccountsInOU. An
+// account failing does not stop the others; the errors are joined.
+func (c *OrganizationsConnector) ForEachAccount(ctx context.Context, accounts []types.Account, fn func(ctx context.Context, account types.Account, creds aws.CredentialsProvider) error) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "OrganizationsConnector", "ForEachAccount")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	var errs []error
+
+	for _, account := range accounts {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		creds, err := c.credentialsFor(&account)
+		if err == nil {
+			err = fn(ctx, account, creds)
+		}
+		if err != nil {
+			c.logger.Error("Account error", zap.String("account_id", aws.ToString(account.Id)), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", aws.ToString(account.Id), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *OrganizationsConnector) credentialsFor(account *types.Account) (aws.CredentialsProvider, error) {
+	if c.params.STS == nil {
+		return nil, ErrNoSTS
+	}
+
+	// Account ARNs name the organization's partition, which is that of
+	// the connector's region otherwise
+	p, err := partition.ForARN(aws.ToString(account.Arn))
+	if err != nil {
+		p = partition.ForRegion(c.client.Options().Region)
+	}
+
+	roleArn := p.ARN("iam", "", aws.ToString(account.Id), "role/"+c.config.MemberRoleName)
+
+	return c.params.STS.RoleProvider(roleArn)
+}
+
+func active(accounts []types.Account) []types.Account {
+	result := make([]types.Account, 0, len(accounts))
+	for _, account := range accounts {
+		if account.Status == types.AccountStatusActive {
+			result = append(result, account)
+		}
+	}
+
+	return result
+}
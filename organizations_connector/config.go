@@ -0,0 +1,28 @@
+package organizations_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. ORGANIZATIONS_CACHE_TTL for the
+// organizations scope.
+type Config struct {
+	CacheTTL            int    `mapstructure:"cache_ttl" default:"300"`
+	MemberRoleName      string `mapstructure:"member_role_name" default:"OrganizationAccountAccessRole"`
+	OrganizationsKey    string `mapstructure:"organizations_key" default:"ABCDE"`
+	OrganizationsSecret string `mapstructure:"organizations_secret" default:"example_secret"`
+	OrganizationsToken  string `mapstructure:"organizations_token" default:""`
+	OrganizationsRegion string `mapstructure:"organizations_region" default:"us-east-1"`
+	VerifyCredentials   bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight           bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *OrganizationsConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *OrganizationsConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,263 @@
+package organizations_connector
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/sts_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultCacheTTL            = 300
+	DefaultMemberRoleName      = "OrganizationAccountAccessRole"
+	DefaultOrganizationsKey    = "ABCDE"
+	DefaultOrganizationsSecret = "example_secret"
+	DefaultOrganizationsToken  = ""
+	DefaultOrganizationsRegion = "us-east-1"
+)
+
+var preflightActions = []string{
+	"organizations:ListAccounts",
+	"organizations:ListAccountsForParent",
+	"organizations:ListOrganizationalUnitsForParent",
+	"organizations:ListRoots",
+}
+
+// OrganizationsConnector lists the accounts and organizational units of
+// an organization, caching them for cache_ttl seconds. With an
+// sts_connector module it also provides credentials for the accounts'
+// member_role_name role, so jobs can discover and reach their target
+// accounts at runtime.
+type OrganizationsConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *organizations.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	STS             *sts_connector.STSConnector `optional:"true"`
+	Credentials     aws.CredentialsProvider     `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig        `optional:"true"`
+	CredentialChain *awscredentials.Chain       `optional:"true"`
+	Tracer          *xray.Tracer                `optional:"true"`
+	Breaker         *circuitbreaker.Breaker     `optional:"true"`
+	Prometheus      *metrics.Metrics            `optional:"true"`
+	Telemetry       *telemetry.Telemetry        `optional:"true"`
+	Health          *health.Health              `optional:"true"`
+	Faults          *faults.Injector            `optional:"true"`
+	Client          *organizations.Client       `optional:"true"`
+}
+
+// Module provides the OrganizationsConnector of scope. It uses the
+// *organizations.Client in the graph, if any, instead of building one,
+// and adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the OrganizationsConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *organizations.Client named after
+// scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *OrganizationsConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*organizations.Client](scope, named),
+		instance.Provide[*OrganizationsConnector](scope, named, func(p Params) *OrganizationsConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &OrganizationsConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+				cache:  make(map[string]cacheEntry),
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *OrganizationsConnector) AccountDirectory {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *OrganizationsConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting OrganizationsConnector",
+		zap.Int("cache_ttl", c.config.CacheTTL),
+		zap.String("member_role_name", c.config.MemberRoleName),
+		zap.String("organizations_region", c.config.OrganizationsRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = organizations.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = organizations.New(c.client.Options(), func(o *organizations.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *OrganizationsConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped OrganizationsConnector")
+
+	return nil
+}
+
+func (c *OrganizationsConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.OrganizationsRegion,
+		Key:         c.config.OrganizationsKey,
+		Secret:      c.config.OrganizationsSecret,
+		Token:       c.config.OrganizationsToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *OrganizationsConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("organizations_region")
+
+	v.Required("member_role_name")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("organizations_key", DefaultOrganizationsKey)
+		v.NotPlaceholder("organizations_secret", DefaultOrganizationsSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the organization.
+func (c *OrganizationsConnector) probe(ctx context.Context) error {
+	_, err := c.client.DescribeOrganization(ctx, &organizations.DescribeOrganizationInput{})
+
+	return err
+}
+
+func (c *OrganizationsConnector) GetClient() *organizations.Client {
+	return c.client
+}
@@ -0,0 +1,22 @@
+package organizations_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . AccountDirectory
+
+// AccountDirectory lists the accounts of the organization and reaches into them.
+type AccountDirectory interface {
+	ListAccounts(ctx context.Context) ([]types.Account, error)
+	ActiveAccounts(ctx context.Context) ([]types.Account, error)
+	GetAccount(ctx context.Context, accountID string) (*types.Account, error)
+	AccountsInOU(ctx context.Context, ouID string) ([]types.Account, error)
+	AccountCredentials(ctx context.Context, accountID string) (aws.CredentialsProvider, error)
+	ForEachAccount(ctx context.Context, accounts []types.Account, fn func(ctx context.Context, account types.Account, creds aws.CredentialsProvider) error) error
+}
+
+var _ AccountDirectory = (*OrganizationsConnector)(nil)
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/organizations_connector (interfaces: AccountDirectory)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . AccountDirectory
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	aws "github.com/aws/aws-sdk-go-v2/aws"
+	types "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccountDirectory is a mock of AccountDirectory interface.
+type MockAccountDirectory struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDirectoryMockRecorder
+}
+
+// MockAccountDirectoryMockRecorder is the mock recorder for MockAccountDirectory.
+type MockAccountDirectoryMockRecorder struct {
+	mock *MockAccountDirectory
+}
+
+// NewMockAccountDirectory creates a new mock instance.
+func NewMockAccountDirectory(ctrl *gomock.Controller) *MockAccountDirectory {
+	mock := &MockAccountDirectory{ctrl: ctrl}
+	mock.recorder = &MockAccountDirectoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountDirectory) EXPECT() *MockAccountDirectoryMockRecorder {
+	return m.recorder
+}
+
+// AccountCredentials mocks base method.
+func (m *MockAccountDirectory) AccountCredentials(arg0 context.Context, arg1 string) (aws.CredentialsProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountCredentials", arg0, arg1)
+	ret0, _ := ret[0].(aws.CredentialsProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountCredentials indicates an expected call of AccountCredentials.
+func (mr *MockAccountDirectoryMockRecorder) AccountCredentials(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountCredentials", reflect.TypeOf((*MockAccountDirectory)(nil).AccountCredentials), arg0, arg1)
+}
+
+// AccountsInOU mocks base method.
+func (m *MockAccountDirectory) AccountsInOU(arg0 context.Context, arg1 string) ([]types.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountsInOU", arg0, arg1)
+	ret0, _ := ret[0].([]types.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountsInOU indicates an expected call of AccountsInOU.
+func (mr *MockAccountDirectoryMockRecorder) AccountsInOU(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountsInOU", reflect.TypeOf((*MockAccountDirectory)(nil).AccountsInOU), arg0, arg1)
+}
+
+// ActiveAccounts mocks base method.
+func (m *MockAccountDirectory) ActiveAccounts(arg0 context.Context) ([]types.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveAccounts", arg0)
+	ret0, _ := ret[0].([]types.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveAccounts indicates an expected call of ActiveAccounts.
+func (mr *MockAccountDirectoryMockRecorder) ActiveAccounts(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveAccounts", reflect.TypeOf((*MockAccountDirectory)(nil).ActiveAccounts), arg0)
+}
+
+// ForEachAccount mocks base method.
+func (m *MockAccountDirectory) ForEachAccount(arg0 context.Context, arg1 []types.Account, arg2 func(context.Context, types.Account, aws.CredentialsProvider) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEachAccount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEachAccount indicates an expected call of ForEachAccount.
+func (mr *MockAccountDirectoryMockRecorder) ForEachAccount(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachAccount", reflect.TypeOf((*MockAccountDirectory)(nil).ForEachAccount), arg0, arg1, arg2)
+}
+
+// GetAccount mocks base method.
+func (m *MockAccountDirectory) GetAccount(arg0 context.Context, arg1 string) (*types.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(*types.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockAccountDirectoryMockRecorder) GetAccount(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockAccountDirectory)(nil).GetAccount), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockAccountDirectory) ListAccounts(arg0 context.Context) ([]types.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0)
+	ret0, _ := ret[0].([]types.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockAccountDirectoryMockRecorder) ListAccounts(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockAccountDirectory)(nil).ListAccounts), arg0)
+}
@@ -0,0 +1,101 @@
+// Package partition maps regions to the AWS partition they belong to,
+// for the URLs and ARNs built outside the SDK's endpoint resolution. The
+// regions of aws-cn, aws-us-gov and the isolated partitions have their
+// own ARN prefix, and most have their own domain:
+//
+//	p := partition.ForRegion("cn-north-1")
+//	p.Host("s3", "cn-north-1")                        // s3.cn-north-1.amazonaws.com.cn
+//	p.ARN("sqs", "cn-north-1", "123456789012", "jobs") // arn:aws-cn:sqs:cn-north-1:123456789012:jobs
+package partition
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+// Partition is a group of regions sharing an ARN prefix and a domain.
+type Partition struct {
+	ID        string
+	DNSSuffix string
+}
+
+var (
+	AWS      = Partition{ID: "aws", DNSSuffix: "amazonaws.com"}
+	China    = Partition{ID: "aws-cn", DNSSuffix: "amazonaws.com.cn"}
+	GovCloud = Partition{ID: "aws-us-gov", DNSSuffix: "amazonaws.com"}
+	ISO      = Partition{ID: "aws-iso", DNSSuffix: "c2s.ic.gov"}
+	ISOB     = Partition{ID: "aws-iso-b", DNSSuffix: "sc2s.sgov.gov"}
+	ISOE     = Partition{ID: "aws-iso-e", DNSSuffix: "cloud.adc-e.uk"}
+	ISOF     = Partition{ID: "aws-iso-f", DNSSuffix: "csp.hci.ic.gov"}
+)
+
+// The longer prefixes come first, as us-iso- is a prefix of us-isob-.
+var regionPrefixes = []struct {
+	prefix    string
+	partition Partition
+}{
+	{"us-isob-", ISOB},
+	{"us-isof-", ISOF},
+	{"us-iso-", ISO},
+	{"eu-isoe-", ISOE},
+	{"us-gov-", GovCloud},
+	{"cn-", China},
+}
+
+// ForRegion returns the partition of region, the aws partition for the
+// regions of no other.
+func ForRegion(region string) Partition {
+	for _, p := range regionPrefixes {
+		if strings.HasPrefix(region, p.prefix) {
+			return p.partition
+		}
+	}
+
+	return AWS
+}
+
+// ForID returns the partition named id, as in the partition of an ARN.
+func ForID(id string) (Partition, bool) {
+	for _, p := range []Partition{AWS, China, GovCloud, ISO, ISOB, ISOE, ISOF} {
+		if p.ID == id {
+			return p, true
+		}
+	}
+
+	return Partition{}, false
+}
+
+// ForARN returns the partition of the ARN s.
+func ForARN(s string) (Partition, error) {
+	parsed, err := arn.Parse(s)
+	if err != nil {
+		return Partition{}, err
+	}
+
+	p, ok := ForID(parsed.Partition)
+	if !ok {
+		return Partition{}, fmt.Errorf("arn %q: unknown partition %q", s, parsed.Partition)
+	}
+
+	return p, nil
+}
+
+// Host returns the regional host name of service, e.g.
+// cognito-idp.us-west-1.amazonaws.com.
+func (p Partition) Host(service string, region string) string {
+	return fmt.Sprintf("%s.%s.%s", service, region, p.DNSSuffix)
+}
+
+// ARN returns the ARN of resource. Global services, such as IAM, leave
+// region empty, and S3 leaves account empty too.
+func (p Partition) ARN(service string, region string, account string, resource string) string {
+	return arn.ARN{
+		Partition: p.ID,
+		Service:   service,
+		Region:    region,
+		AccountID: account,
+		Resource:  resource,
+	}.String()
+}
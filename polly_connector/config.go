@@ -0,0 +1,31 @@
+package polly_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. POLLY_VOICE_ID for the polly scope.
+type Config struct {
+	VoiceID           string `mapstructure:"voice_id" default:"Joanna"`
+	Engine            string `mapstructure:"engine" default:"neural"`
+	OutputFormat      string `mapstructure:"output_format" default:"mp3"`
+	SampleRate        string `mapstructure:"sample_rate" default:""`
+	LanguageCode      string `mapstructure:"language_code" default:""`
+	KeyPrefix         string `mapstructure:"key_prefix" default:"speech/"`
+	PollyKey          string `mapstructure:"polly_key" default:"ABCDE"`
+	PollySecret       string `mapstructure:"polly_secret" default:"example_secret"`
+	PollyToken        string `mapstructure:"polly_token" default:""`
+	PollyRegion       string `mapstructure:"polly_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *PollyConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *PollyConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,13 @@
+package polly_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . SpeechSynthesizer
+
+// SpeechSynthesizer turns text into speech.
+type SpeechSynthesizer interface {
+	SynthesizeSpeech(ctx context.Context, text string) (*Speech, error)
+	SynthesizeToBucket(ctx context.Context, key string, text string) (string, error)
+}
+
+var _ SpeechSynthesizer = (*PollyConnector)(nil)
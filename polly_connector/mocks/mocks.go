@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/polly_connector (interfaces: SpeechSynthesizer)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . SpeechSynthesizer
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	polly_connector "github.com/elmntri/zeitgeber-aws-modules/polly_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSpeechSynthesizer is a mock of SpeechSynthesizer interface.
+type MockSpeechSynthesizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpeechSynthesizerMockRecorder
+}
+
+// MockSpeechSynthesizerMockRecorder is the mock recorder for MockSpeechSynthesizer.
+type MockSpeechSynthesizerMockRecorder struct {
+	mock *MockSpeechSynthesizer
+}
+
+// NewMockSpeechSynthesizer creates a new mock instance.
+func NewMockSpeechSynthesizer(ctrl *gomock.Controller) *MockSpeechSynthesizer {
+	mock := &MockSpeechSynthesizer{ctrl: ctrl}
+	mock.recorder = &MockSpeechSynthesizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpeechSynthesizer) EXPECT() *MockSpeechSynthesizerMockRecorder {
+	return m.recorder
+}
+
+// SynthesizeSpeech mocks base method.
+func (m *MockSpeechSynthesizer) SynthesizeSpeech(arg0 context.Context, arg1 string) (*polly_connector.Speech, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SynthesizeSpeech", arg0, arg1)
+	ret0, _ := ret[0].(*polly_connector.Speech)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SynthesizeSpeech indicates an expected call of SynthesizeSpeech.
+func (mr *MockSpeechSynthesizerMockRecorder) SynthesizeSpeech(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SynthesizeSpeech", reflect.TypeOf((*MockSpeechSynthesizer)(nil).SynthesizeSpeech), arg0, arg1)
+}
+
+// SynthesizeToBucket mocks base method.
+func (m *MockSpeechSynthesizer) SynthesizeToBucket(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SynthesizeToBucket", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SynthesizeToBucket indicates an expected call of SynthesizeToBucket.
+func (mr *MockSpeechSynthesizerMockRecorder) SynthesizeToBucket(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SynthesizeToBucket", reflect.TypeOf((*MockSpeechSynthesizer)(nil).SynthesizeToBucket), arg0, arg1, arg2)
+}
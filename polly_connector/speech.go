@@ -0,0 +1,107 @@
+package polly_connector
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/polly"
+	"github.com/aws/aws-sdk-go-v2/service/polly/types"
+	"github.com/google/uuid"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrNoBucket = awserrors.New(awserrors.ErrValidation, "no bucket connector")
+
+var extensions = map[types.OutputFormat]string{
+	types.OutputFormatMp3:       ".mp3",
+	types.OutputFormatOggVorbis: ".ogg",
+	types.OutputFormatPcm:       ".pcm",
+	types.OutputFormatJson:      ".json",
+}
+
+// Speech is synthesized audio streamed from Polly. Close Audio when done.
+type Speech struct {
+	Audio       io.ReadCloser
+	ContentType string
+	Characters  int32
+}
+
+// SynthesizeSpeech streams text read with the configured voice. Text
+// wrapped in <speak> is read as SSML.
+func (c *PollyConnector) SynthesizeSpeech(ctx context.Context, text string) (_ *Speech, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "PollyConnector", "SynthesizeSpeech")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	params := &polly.SynthesizeSpeechInput{
+		Text:         aws.String(text),
+		VoiceId:      types.VoiceId(c.config.VoiceID),
+		Engine:       types.Engine(c.config.Engine),
+		OutputFormat: c.outputFormat(),
+		TextType:     types.TextTypeText,
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(text), "<speak>") {
+		params.TextType = types.TextTypeSsml
+	}
+
+	if sampleRate := c.config.SampleRate; sampleRate != "" {
+		params.SampleRate = aws.String(sampleRate)
+	}
+
+	if languageCode := c.config.LanguageCode; languageCode != "" {
+		params.LanguageCode = types.LanguageCode(languageCode)
+	}
+
+	result, err := c.client.SynthesizeSpeech(ctx, params)
+	if err != nil {
+		c.logger.Error("Synthesize speech error", zap.Error(err))
+		return nil, err
+	}
+
+	return &Speech{
+		Audio:       result.AudioStream,
+		ContentType: aws.ToString(result.ContentType),
+		Characters:  result.RequestCharacters,
+	}, nil
+}
+
+// SynthesizeToBucket writes the speech to key in the bucket and returns
+// its URL. An empty key is generated under key_prefix.
+func (c *PollyConnector) SynthesizeToBucket(ctx context.Context, key string, text string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "PollyConnector", "SynthesizeToBucket")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if c.params.Bucket == nil {
+		return "", ErrNoBucket
+	}
+
+	speech, err := c.SynthesizeSpeech(ctx, text)
+	if err != nil {
+		return "", err
+	}
+	defer speech.Audio.Close()
+
+	data, err := io.ReadAll(speech.Audio)
+	if err != nil {
+		return "", err
+	}
+
+	if key == "" {
+		key = c.config.KeyPrefix + uuid.New().String() + extensions[c.outputFormat()]
+	}
+
+	if err := c.params.Bucket.PutObject(ctx, key, data, speech.ContentType); err != nil {
+		return "", err
+	}
+
+	return c.params.Bucket.ObjectURL(key), nil
+}
+
+func (c *PollyConnector) outputFormat() types.OutputFormat {
+	return types.OutputFormat(c.config.OutputFormat)
+}
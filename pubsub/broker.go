@@ -0,0 +1,257 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/sns_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/sqs_connector"
+	"github.com/google/uuid"
+)
+
+var logger *zap.Logger
+
+// eventAttribute carries the event name for subscription filter
+// policies.
+const eventAttribute = "event"
+
+const (
+	DefaultProvision     = true
+	DefaultTopicArn      = ""
+	DefaultSource        = ""
+	DefaultMaxMessages   = 10
+	DefaultWaitSeconds   = 20
+	DefaultRetryInterval = 5
+)
+
+// Broker publishes events to one SNS topic per scope and delivers the
+// subscribed ones to handlers through the SQS connector's queue. With
+// provision set, the topic (named topic_name, the scope by default), the
+// queue and the queue's subscription are created on start, and the
+// subscription's filter policy is set to the subscribed events.
+type Broker struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	topicArn string
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	tracker  *inflight.Tracker
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+	SNS       *sns_connector.SNSConnector
+	SQS       *sqs_connector.SQSConnector `optional:"true"`
+}
+
+func Module(scope string) fx.Option {
+
+	var b *Broker
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) *Broker {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			b := &Broker{
+				params:   p,
+				logger:   logger,
+				scope:    scope,
+				handlers: make(map[string][]Handler),
+			}
+
+			b.initDefaultConfigs()
+
+			return b
+		}),
+		fx.Populate(&b),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: b.onStart,
+					OnStop:  b.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (b *Broker) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(b.scope, &b.config); err != nil {
+		b.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	provision := b.config.Provision
+	events := b.events()
+
+	logger.Info("Starting pub/sub broker",
+		zap.Bool("provision", provision),
+		zap.Strings("events", events),
+	)
+
+	if len(events) > 0 && b.params.SQS == nil {
+		return fmt.Errorf("%s: subscribing requires an sqs_connector module", b.scope)
+	}
+
+	if provision {
+		if err := b.ensureTopic(ctx); err != nil {
+			return err
+		}
+
+		if len(events) > 0 {
+			if err := b.ensureSubscription(ctx, events); err != nil {
+				return err
+			}
+		}
+	} else {
+		b.topicArn = b.config.TopicARN
+		if b.topicArn == "" {
+			return fmt.Errorf("%s: topic_arn is required without provision", b.scope)
+		}
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	tracker, err := inflight.NewTracker(b.scope, b.logger)
+	if err != nil {
+		b.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+	b.tracker = tracker
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	b.cancel = cancel
+	b.done = make(chan struct{})
+
+	go b.receiveLoop(loopCtx)
+
+	return nil
+}
+
+func (b *Broker) onStop(ctx context.Context) error {
+
+	// Let the messages being handled finish, then interrupt the loop
+	b.tracker.Shutdown(ctx)
+
+	if b.cancel != nil {
+		b.cancel()
+		<-b.done
+	}
+
+	b.logger.Info("Stopped pub/sub broker")
+
+	return nil
+}
+
+// Publish sends payload, encoded as JSON, as an event named eventName
+// and returns the event ID.
+func (b *Broker) Publish(ctx context.Context, eventName string, payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	event := Event{
+		Version:     EnvelopeVersion,
+		ID:          uuid.New().String(),
+		Name:        eventName,
+		Source:      b.config.Source,
+		PublishedAt: time.Now().UTC(),
+		Payload:     data,
+	}
+
+	_, err = b.params.SNS.PublishJSONWithOptions(ctx, b.topicArn, event, sns_connector.PublishOptions{
+		Attributes: sns_connector.NewAttributes().String(eventAttribute, eventName),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return event.ID, nil
+}
+
+// Subscribe registers a handler for events named eventName. Handlers run
+// in registration order. Subscribe before the app starts so the
+// subscription's filter policy includes the event.
+func (b *Broker) Subscribe(eventName string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventName] = append(b.handlers[eventName], handler)
+}
+
+// TopicArn returns the topic events are published to.
+func (b *Broker) TopicArn() string {
+	return b.topicArn
+}
+
+func (b *Broker) events() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	events := make([]string, 0, len(b.handlers))
+	for name := range b.handlers {
+		events = append(events, name)
+	}
+	sort.Strings(events)
+
+	return events
+}
+
+func (b *Broker) receiveLoop(ctx context.Context) {
+	defer close(b.done)
+
+	b.params.SQS.Consume(ctx, sqs_connector.ConsumeOptions{
+		MaxMessages:   b.config.MaxMessages,
+		WaitSeconds:   b.config.WaitSeconds,
+		RetryInterval: time.Duration(b.config.RetryInterval) * time.Second,
+		Logger:        b.logger,
+		Tracker:       b.tracker,
+	}, b.process)
+}
+
+// process runs the handlers of an event. Events nobody subscribes to,
+// which reach the queue when the filter policy is managed elsewhere, are
+// dropped.
+func (b *Broker) process(ctx context.Context, body string) error {
+	event, err := ParseEvent([]byte(body))
+	if err != nil {
+		return sqs_connector.Malformed(err)
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers[event.Name]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package pubsub
+
+import (
+	"strings"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. PUBSUB_PROVISION for the pubsub scope.
+type Config struct {
+	Provision     bool   `mapstructure:"provision" default:"true"`
+	TopicName     string `mapstructure:"topic_name"`
+	TopicARN      string `mapstructure:"topic_arn" default:""`
+	Source        string `mapstructure:"source" default:""`
+	MaxMessages   int32  `mapstructure:"max_messages" default:"10"`
+	WaitSeconds   int32  `mapstructure:"wait_seconds" default:"20"`
+	RetryInterval int    `mapstructure:"retry_interval" default:"5"`
+}
+
+func (b *Broker) initDefaultConfigs() {
+	moduleconfig.Register(b.scope, &Config{
+		TopicName: strings.ReplaceAll(b.scope, ".", "-"),
+	})
+}
+
+// Config returns the configuration the module started with.
+func (b *Broker) Config() Config {
+	return b.config
+}
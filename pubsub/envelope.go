@@ -0,0 +1,80 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// EnvelopeVersion is the envelope format Publish writes. Consumers
+// decode every version up to their own and reject newer ones, so roll
+// out consumers before publishers when it changes.
+const EnvelopeVersion = 1
+
+var ErrUnsupportedVersion = awserrors.New(awserrors.ErrValidation, "unsupported envelope version")
+
+// Event is a published event as subscribers receive it.
+type Event struct {
+	Version     int             `json:"version"`
+	ID          string          `json:"id"`
+	Name        string          `json:"event"`
+	Source      string          `json:"source,omitempty"`
+	PublishedAt time.Time       `json:"published_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// snsEnvelope is the SNS notification an event arrives in when the
+// subscription does not use raw message delivery.
+type snsEnvelope struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// ParseEvent decodes an event from an SQS message body, unwrapping SNS
+// envelopes.
+func ParseEvent(body []byte) (*Event, error) {
+	env := snsEnvelope{}
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, err
+	}
+
+	if env.Type == "Notification" && env.Message != "" {
+		body = []byte(env.Message)
+	}
+
+	event := &Event{}
+	if err := json.Unmarshal(body, event); err != nil {
+		return nil, err
+	}
+
+	if event.Version < 1 || event.Name == "" {
+		return nil, fmt.Errorf("not a pubsub event")
+	}
+
+	if event.Version > EnvelopeVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, event.Version)
+	}
+
+	return event, nil
+}
+
+// Handler processes an event. Returning an error leaves the message on
+// the queue so it is redelivered.
+type Handler func(ctx context.Context, event *Event) error
+
+// Typed adapts a handler taking the decoded payload, e.g.
+//
+//	broker.Subscribe("order.created", pubsub.Typed(onOrderCreated))
+func Typed[T any](handler func(ctx context.Context, event *Event, payload T) error) Handler {
+	return func(ctx context.Context, event *Event) error {
+		var payload T
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return handler(ctx, event, payload)
+	}
+}
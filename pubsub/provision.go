@@ -0,0 +1,89 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+// ensureTopic creates the topic, or looks up the ARN of an existing one;
+// CreateTopic is idempotent.
+func (b *Broker) ensureTopic(ctx context.Context) error {
+	topicName := b.config.TopicName
+
+	result, err := b.params.SNS.GetClient().CreateTopic(ctx, &sns.CreateTopicInput{
+		Name: aws.String(topicName),
+	})
+	if err != nil {
+		b.logger.Error("Create topic error", zap.String("topic_name", topicName), zap.Error(err))
+		return err
+	}
+
+	b.topicArn = aws.ToString(result.TopicArn)
+
+	return nil
+}
+
+// ensureSubscription creates the queue, lets the topic send to it and
+// subscribes it to events with raw message delivery. The topic's
+// statement is merged into the queue's access policy.
+func (b *Broker) ensureSubscription(ctx context.Context, events []string) error {
+	if err := b.params.SQS.EnsureQueue(ctx); err != nil {
+		return err
+	}
+
+	queueArn, err := b.params.SQS.GetQueueArn(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := b.params.SQS.AllowService(ctx, "sns.amazonaws.com", b.topicArn); err != nil {
+		return err
+	}
+
+	filterPolicy, err := json.Marshal(map[string][]string{eventAttribute: events})
+	if err != nil {
+		return err
+	}
+
+	attributes := map[string]string{
+		"RawMessageDelivery": "true",
+		"FilterPolicy":       string(filterPolicy),
+	}
+
+	subscriptions, err := b.params.SNS.ListSubscriptions(ctx, b.topicArn)
+	if err != nil {
+		b.logger.Error("List subscriptions error", zap.Error(err))
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		if aws.ToString(subscription.Endpoint) != queueArn {
+			continue
+		}
+
+		// Subscribe fails when an existing subscription's attributes
+		// differ, so update them in place
+		for name, value := range attributes {
+			_, err := b.params.SNS.GetClient().SetSubscriptionAttributes(ctx, &sns.SetSubscriptionAttributesInput{
+				SubscriptionArn: subscription.SubscriptionArn,
+				AttributeName:   aws.String(name),
+				AttributeValue:  aws.String(value),
+			})
+			if err != nil {
+				b.logger.Error("Set subscription attributes error", zap.String("attribute", name), zap.Error(err))
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	_, err = b.params.SNS.Subscribe(ctx, b.topicArn, "sqs", queueArn, attributes)
+
+	return err
+}
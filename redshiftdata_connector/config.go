@@ -0,0 +1,33 @@
+package redshiftdata_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. REDSHIFTDATA_CLUSTER_IDENTIFIER for the
+// redshiftdata scope.
+type Config struct {
+	ClusterIdentifier  string `mapstructure:"cluster_identifier" default:""`
+	WorkgroupName      string `mapstructure:"workgroup_name" default:""`
+	Database           string `mapstructure:"database" default:"dev"`
+	DBUser             string `mapstructure:"db_user" default:""`
+	SecretARN          string `mapstructure:"secret_arn" default:""`
+	PollInterval       int    `mapstructure:"poll_interval" default:"200"`
+	MaxPollInterval    int    `mapstructure:"max_poll_interval" default:"5000"`
+	RedshiftDataKey    string `mapstructure:"redshiftdata_key" default:"ABCDE"`
+	RedshiftDataSecret string `mapstructure:"redshiftdata_secret" default:"example_secret"`
+	RedshiftDataToken  string `mapstructure:"redshiftdata_token" default:""`
+	RedshiftDataRegion string `mapstructure:"redshiftdata_region" default:"us-west-1"`
+	VerifyCredentials  bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight          bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *RedshiftDataConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *RedshiftDataConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,261 @@
+package redshiftdata_connector
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultClusterIdentifier  = ""
+	DefaultWorkgroupName      = ""
+	DefaultDatabase           = "dev"
+	DefaultDBUser             = ""
+	DefaultSecretArn          = ""
+	DefaultPollInterval       = 200
+	DefaultMaxPollInterval    = 5000
+	DefaultRedshiftDataKey    = "ABCDE"
+	DefaultRedshiftDataSecret = "example_secret"
+	DefaultRedshiftDataToken  = ""
+	DefaultRedshiftDataRegion = "us-west-1"
+)
+
+var preflightActions = []string{
+	"redshift-data:ExecuteStatement",
+	"redshift-data:BatchExecuteStatement",
+	"redshift-data:DescribeStatement",
+	"redshift-data:GetStatementResult",
+	"redshift-data:CancelStatement",
+}
+
+type RedshiftDataConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *redshiftdata.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *redshiftdata.Client    `optional:"true"`
+}
+
+// Module provides the RedshiftDataConnector of scope. It uses the
+// *redshiftdata.Client in the graph, if any, instead of building one,
+// and adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the RedshiftDataConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *redshiftdata.Client named after scope,
+// if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *RedshiftDataConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*redshiftdata.Client](scope, named),
+		instance.Provide[*RedshiftDataConnector](scope, named, func(p Params) *RedshiftDataConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &RedshiftDataConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *RedshiftDataConnector) StatementRunner {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *RedshiftDataConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting RedshiftDataConnector",
+		zap.String("cluster_identifier", c.config.ClusterIdentifier),
+		zap.String("workgroup_name", c.config.WorkgroupName),
+		zap.String("database", c.config.Database),
+		zap.String("redshiftdata_region", c.config.RedshiftDataRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = redshiftdata.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = redshiftdata.New(c.client.Options(), func(o *redshiftdata.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *RedshiftDataConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped RedshiftDataConnector")
+
+	return nil
+}
+
+func (c *RedshiftDataConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.RedshiftDataRegion,
+		Key:         c.config.RedshiftDataKey,
+		Secret:      c.config.RedshiftDataSecret,
+		Token:       c.config.RedshiftDataToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *RedshiftDataConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("redshiftdata_region")
+
+	// A provisioned cluster or a serverless workgroup, not both
+	v.OneOf("cluster_identifier", "workgroup_name")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("redshiftdata_key", DefaultRedshiftDataKey)
+		v.NotPlaceholder("redshiftdata_secret", DefaultRedshiftDataSecret)
+	}
+
+	return v.Err()
+}
+
+// probe lists a statement.
+func (c *RedshiftDataConnector) probe(ctx context.Context) error {
+	_, err := c.client.ListStatements(ctx, &redshiftdata.ListStatementsInput{
+		MaxResults: 1,
+	})
+
+	return err
+}
+
+func (c *RedshiftDataConnector) GetClient() *redshiftdata.Client {
+	return c.client
+}
@@ -0,0 +1,20 @@
+package redshiftdata_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . StatementRunner
+
+// StatementRunner runs SQL statements.
+type StatementRunner interface {
+	ExecuteStatement(ctx context.Context, sql string, params map[string]string) (*Result, error)
+	StartStatement(ctx context.Context, sql string, params map[string]string) (string, error)
+	BatchExecuteStatement(ctx context.Context, sqls ...string) (*redshiftdata.DescribeStatementOutput, error)
+	WaitForStatement(ctx context.Context, statementID string) (*redshiftdata.DescribeStatementOutput, error)
+	GetStatementResult(ctx context.Context, statementID string) (*Result, error)
+}
+
+var _ StatementRunner = (*RedshiftDataConnector)(nil)
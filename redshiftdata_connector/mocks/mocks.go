@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/redshiftdata_connector (interfaces: StatementRunner)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . StatementRunner
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	redshiftdata "github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	redshiftdata_connector "github.com/elmntri/zeitgeber-aws-modules/redshiftdata_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStatementRunner is a mock of StatementRunner interface.
+type MockStatementRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatementRunnerMockRecorder
+}
+
+// MockStatementRunnerMockRecorder is the mock recorder for MockStatementRunner.
+type MockStatementRunnerMockRecorder struct {
+	mock *MockStatementRunner
+}
+
+// NewMockStatementRunner creates a new mock instance.
+func NewMockStatementRunner(ctrl *gomock.Controller) *MockStatementRunner {
+	mock := &MockStatementRunner{ctrl: ctrl}
+	mock.recorder = &MockStatementRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatementRunner) EXPECT() *MockStatementRunnerMockRecorder {
+	return m.recorder
+}
+
+// BatchExecuteStatement mocks base method.
+func (m *MockStatementRunner) BatchExecuteStatement(arg0 context.Context, arg1 ...string) (*redshiftdata.DescribeStatementOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchExecuteStatement", varargs...)
+	ret0, _ := ret[0].(*redshiftdata.DescribeStatementOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchExecuteStatement indicates an expected call of BatchExecuteStatement.
+func (mr *MockStatementRunnerMockRecorder) BatchExecuteStatement(arg0 any, arg1 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchExecuteStatement", reflect.TypeOf((*MockStatementRunner)(nil).BatchExecuteStatement), varargs...)
+}
+
+// ExecuteStatement mocks base method.
+func (m *MockStatementRunner) ExecuteStatement(arg0 context.Context, arg1 string, arg2 map[string]string) (*redshiftdata_connector.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteStatement", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*redshiftdata_connector.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteStatement indicates an expected call of ExecuteStatement.
+func (mr *MockStatementRunnerMockRecorder) ExecuteStatement(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteStatement", reflect.TypeOf((*MockStatementRunner)(nil).ExecuteStatement), arg0, arg1, arg2)
+}
+
+// GetStatementResult mocks base method.
+func (m *MockStatementRunner) GetStatementResult(arg0 context.Context, arg1 string) (*redshiftdata_connector.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatementResult", arg0, arg1)
+	ret0, _ := ret[0].(*redshiftdata_connector.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatementResult indicates an expected call of GetStatementResult.
+func (mr *MockStatementRunnerMockRecorder) GetStatementResult(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatementResult", reflect.TypeOf((*MockStatementRunner)(nil).GetStatementResult), arg0, arg1)
+}
+
+// StartStatement mocks base method.
+func (m *MockStatementRunner) StartStatement(arg0 context.Context, arg1 string, arg2 map[string]string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartStatement", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartStatement indicates an expected call of StartStatement.
+func (mr *MockStatementRunnerMockRecorder) StartStatement(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartStatement", reflect.TypeOf((*MockStatementRunner)(nil).StartStatement), arg0, arg1, arg2)
+}
+
+// WaitForStatement mocks base method.
+func (m *MockStatementRunner) WaitForStatement(arg0 context.Context, arg1 string) (*redshiftdata.DescribeStatementOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForStatement", arg0, arg1)
+	ret0, _ := ret[0].(*redshiftdata.DescribeStatementOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForStatement indicates an expected call of WaitForStatement.
+func (mr *MockStatementRunnerMockRecorder) WaitForStatement(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForStatement", reflect.TypeOf((*MockStatementRunner)(nil).WaitForStatement), arg0, arg1)
+}
@@ -0,0 +1,232 @@
+package redshiftdata_connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+)
+
+var ErrStatementFailed = awserrors.New(awserrors.ErrValidation, "statement failed")
+
+// ExecuteStatement runs sql and returns every row, or an empty result for
+// statements without a result set. Params bind the statement's :name
+// placeholders; Redshift casts the values to the column types.
+func (c *RedshiftDataConnector) ExecuteStatement(ctx context.Context, sql string, params map[string]string) (_ *Result, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "RedshiftDataConnector", "ExecuteStatement")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	statementID, err := c.StartStatement(ctx, sql, params)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := c.WaitForStatement(ctx, statementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !aws.ToBool(statement.HasResultSet) {
+		return &Result{StatementID: statementID}, nil
+	}
+
+	return c.GetStatementResult(ctx, statementID)
+}
+
+// StartStatement submits sql to the configured cluster or workgroup and
+// returns the statement ID.
+func (c *RedshiftDataConnector) StartStatement(ctx context.Context, sql string, params map[string]string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "RedshiftDataConnector", "StartStatement")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	input := &redshiftdata.ExecuteStatementInput{
+		Sql:               aws.String(sql),
+		Database:          aws.String(c.config.Database),
+		ClusterIdentifier: c.optional(c.config.ClusterIdentifier),
+		WorkgroupName:     c.optional(c.config.WorkgroupName),
+		DbUser:            c.optional(c.config.DBUser),
+		SecretArn:         c.optional(c.config.SecretARN),
+		Parameters:        sqlParameters(params),
+	}
+
+	result, err := c.client.ExecuteStatement(ctx, input)
+	if err != nil {
+		c.logger.Error("Execute statement error", zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.Id), nil
+}
+
+// BatchExecuteStatement runs sqls in order as one transaction; if one
+// fails, none of them is committed. The sub-statements of the returned
+// description have IDs for GetStatementResult.
+func (c *RedshiftDataConnector) BatchExecuteStatement(ctx context.Context, sqls ...string) (_ *redshiftdata.DescribeStatementOutput, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "RedshiftDataConnector", "BatchExecuteStatement")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.BatchExecuteStatement(ctx, &redshiftdata.BatchExecuteStatementInput{
+		Sqls:              sqls,
+		Database:          aws.String(c.config.Database),
+		ClusterIdentifier: c.optional(c.config.ClusterIdentifier),
+		WorkgroupName:     c.optional(c.config.WorkgroupName),
+		DbUser:            c.optional(c.config.DBUser),
+		SecretArn:         c.optional(c.config.SecretARN),
+	})
+	if err != nil {
+		c.logger.Error("Batch execute statement error", zap.Int("count", len(sqls)), zap.Error(err))
+		return nil, err
+	}
+
+	return c.WaitForStatement(ctx, aws.ToString(result.Id))
+}
+
+// WaitForStatement polls the statement, starting at poll_interval
+// milliseconds and doubling up to max_poll_interval, until it ends.
+// Cancelling ctx cancels the statement.
+func (c *RedshiftDataConnector) WaitForStatement(ctx context.Context, statementID string) (_ *redshiftdata.DescribeStatementOutput, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "RedshiftDataConnector", "WaitForStatement")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Millisecond
+	maxInterval := time.Duration(c.config.MaxPollInterval) * time.Millisecond
+
+	for {
+		statement, err := c.client.DescribeStatement(ctx, &redshiftdata.DescribeStatementInput{
+			Id: aws.String(statementID),
+		})
+		if err != nil {
+			c.logger.Error("Describe statement error", zap.String("statement_id", statementID), zap.Error(err))
+			return nil, err
+		}
+
+		switch statement.Status {
+		case types.StatusStringFinished:
+			return statement, nil
+
+		case types.StatusStringFailed, types.StatusStringAborted:
+			return nil, fmt.Errorf("%w: %s: %s: %s", ErrStatementFailed, statementID, statement.Status, aws.ToString(statement.Error))
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			c.cancelStatement(statementID)
+			return nil, ctx.Err()
+		}
+
+		interval = min(interval*2, maxInterval)
+	}
+}
+
+// GetStatementResult reads every page of results of a finished statement
+// or sub-statement.
+func (c *RedshiftDataConnector) GetStatementResult(ctx context.Context, statementID string) (_ *Result, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "RedshiftDataConnector", "GetStatementResult")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	paginator := redshiftdata.NewGetStatementResultPaginator(c.client, &redshiftdata.GetStatementResultInput{
+		Id: aws.String(statementID),
+	})
+
+	result := &Result{
+		StatementID: statementID,
+	}
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("Get statement result error", zap.String("statement_id", statementID), zap.Error(err))
+			return nil, err
+		}
+
+		if result.Columns == nil {
+			for _, column := range page.ColumnMetadata {
+				result.Columns = append(result.Columns, Column{
+					Name: aws.ToString(column.Name),
+					Type: aws.ToString(column.TypeName),
+				})
+			}
+		}
+
+		for _, record := range page.Records {
+			values := make([]interface{}, len(record))
+			for i, field := range record {
+				values[i] = fieldValue(field)
+			}
+
+			result.Rows = append(result.Rows, values)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *RedshiftDataConnector) cancelStatement(statementID string) {
+	ctx, cancel := context.WithTimeout(inflight.Internal(context.Background()), 10*time.Second)
+	defer cancel()
+
+	_, err := c.client.CancelStatement(ctx, &redshiftdata.CancelStatementInput{
+		Id: aws.String(statementID),
+	})
+	if err != nil {
+		c.logger.Warn("Cancel statement error", zap.String("statement_id", statementID), zap.Error(err))
+	}
+}
+
+func (c *RedshiftDataConnector) optional(value string) *string {
+	if value != "" {
+		return aws.String(value)
+	}
+
+	return nil
+}
+
+func sqlParameters(params map[string]string) []types.SqlParameter {
+	if len(params) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parameters := make([]types.SqlParameter, len(names))
+	for i, name := range names {
+		parameters[i] = types.SqlParameter{
+			Name:  aws.String(name),
+			Value: aws.String(params[name]),
+		}
+	}
+
+	return parameters
+}
+
+func fieldValue(field types.Field) interface{} {
+	switch f := field.(type) {
+	case *types.FieldMemberStringValue:
+		return f.Value
+	case *types.FieldMemberLongValue:
+		return f.Value
+	case *types.FieldMemberDoubleValue:
+		return f.Value
+	case *types.FieldMemberBooleanValue:
+		return f.Value
+	case *types.FieldMemberBlobValue:
+		return f.Value
+	}
+
+	// FieldMemberIsNull
+	return nil
+}
@@ -0,0 +1,31 @@
+package rekognition_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. REKOGNITION_MODERATION_CONFIDENCE for
+// the rekognition scope.
+type Config struct {
+	ModerationConfidence float64  `mapstructure:"moderation_confidence" default:"50"`
+	BlockedLabels        []string `mapstructure:"blocked_labels" default:""`
+	LabelConfidence      float64  `mapstructure:"label_confidence" default:"70"`
+	MaxLabels            int32    `mapstructure:"max_labels" default:"10"`
+	InterceptUploads     bool     `mapstructure:"intercept_uploads" default:"false"`
+	RekognitionKey       string   `mapstructure:"rekognition_key" default:"ABCDE"`
+	RekognitionSecret    string   `mapstructure:"rekognition_secret" default:"example_secret"`
+	RekognitionToken     string   `mapstructure:"rekognition_token" default:""`
+	RekognitionRegion    string   `mapstructure:"rekognition_region" default:"us-west-1"`
+	VerifyCredentials    bool     `mapstructure:"verify_credentials" default:"false"`
+	Preflight            bool     `mapstructure:"preflight" default:"false"`
+}
+
+func (c *RekognitionConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *RekognitionConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,259 @@
+package rekognition_connector
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rekognition"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/bucket_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultModerationConfidence = 50
+	DefaultLabelConfidence      = 70
+	DefaultMaxLabels            = 10
+	DefaultInterceptUploads     = false
+	DefaultRekognitionKey       = "ABCDE"
+	DefaultRekognitionSecret    = "example_secret"
+	DefaultRekognitionToken     = ""
+	DefaultRekognitionRegion    = "us-west-1"
+)
+
+var preflightActions = []string{
+	"rekognition:DetectLabels",
+	"rekognition:DetectModerationLabels",
+}
+
+// RekognitionConnector analyzes images stored in the bucket connector's
+// bucket. With intercept_uploads it moderates every image uploaded
+// through the bucket connector.
+type RekognitionConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *rekognition.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider           `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig              `optional:"true"`
+	CredentialChain *awscredentials.Chain             `optional:"true"`
+	Bucket          *bucket_connector.BucketConnector `optional:"true"`
+	Tracer          *xray.Tracer                      `optional:"true"`
+	Breaker         *circuitbreaker.Breaker           `optional:"true"`
+	Prometheus      *metrics.Metrics                  `optional:"true"`
+	Telemetry       *telemetry.Telemetry              `optional:"true"`
+	Health          *health.Health                    `optional:"true"`
+	Faults          *faults.Injector                  `optional:"true"`
+	Client          *rekognition.Client               `optional:"true"`
+}
+
+// Module provides the RekognitionConnector of scope. It uses the
+// *rekognition.Client in the graph, if any, instead of building one,
+// and adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the RekognitionConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *rekognition.Client named after scope,
+// if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *RekognitionConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*rekognition.Client](scope, named),
+		instance.Provide[*RekognitionConnector](scope, named, func(p Params) (*RekognitionConnector, error) {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &RekognitionConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			// Loaded here, as intercept_uploads is read before start
+			if err := moduleconfig.Load(scope, &c.config); err != nil {
+				return nil, err
+			}
+
+			return c, nil
+		}),
+		instance.Export(scope, named, func(c *RekognitionConnector) ImageModerator {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			if p.Bucket != nil && c.config.InterceptUploads {
+				p.Bucket.AddUploadInterceptor(c.UploadInterceptor())
+			}
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *RekognitionConnector) onStart(ctx context.Context) error {
+
+	c.logger.Info("Starting RekognitionConnector",
+		zap.String("rekognition_region", c.config.RekognitionRegion),
+		zap.Bool("intercept_uploads", c.config.InterceptUploads),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = rekognition.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = rekognition.New(c.client.Options(), func(o *rekognition.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *RekognitionConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped RekognitionConnector")
+
+	return nil
+}
+
+func (c *RekognitionConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.RekognitionRegion,
+		Key:         c.config.RekognitionKey,
+		Secret:      c.config.RekognitionSecret,
+		Token:       c.config.RekognitionToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *RekognitionConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("rekognition_region")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("rekognition_key", DefaultRekognitionKey)
+		v.NotPlaceholder("rekognition_secret", DefaultRekognitionSecret)
+	}
+
+	return v.Err()
+}
+
+// probe lists a collection.
+func (c *RekognitionConnector) probe(ctx context.Context) error {
+	_, err := c.client.ListCollections(ctx, &rekognition.ListCollectionsInput{
+		MaxResults: aws.Int32(1),
+	})
+
+	return err
+}
+
+func (c *RekognitionConnector) GetClient() *rekognition.Client {
+	return c.client
+}
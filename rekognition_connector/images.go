@@ -0,0 +1,208 @@
+package rekognition_connector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rekognition"
+	"github.com/aws/aws-sdk-go-v2/service/rekognition/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/bucket_connector"
+)
+
+var (
+	ErrUnsafeContent = awserrors.New(awserrors.ErrValidation, "unsafe content")
+	ErrNoBucket      = awserrors.New(awserrors.ErrValidation, "no bucket connector")
+
+	// ErrUnsupportedImage rejects images in formats moderation cannot
+	// read, so they cannot slip past it.
+	ErrUnsupportedImage = awserrors.New(awserrors.ErrValidation, "unsupported image format")
+)
+
+// sniffLen is how much of an upload http.DetectContentType reads.
+const sniffLen = 512
+
+// UnsafeContentError names the moderation labels that got an image
+// rejected. It matches ErrUnsafeContent with errors.Is.
+type UnsafeContentError struct {
+	Key    string
+	Labels []string
+}
+
+func (e *UnsafeContentError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrUnsafeContent, e.Key, strings.Join(e.Labels, ", "))
+}
+
+func (e *UnsafeContentError) Unwrap() error {
+	return ErrUnsafeContent
+}
+
+// DetectModerationLabels returns the moderation labels of an image in the
+// bucket with at least moderation_confidence.
+func (c *RekognitionConnector) DetectModerationLabels(ctx context.Context, key string) (_ []types.ModerationLabel, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "RekognitionConnector", "DetectModerationLabels")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	image, err := c.s3Image(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.client.DetectModerationLabels(ctx, &rekognition.DetectModerationLabelsInput{
+		Image:         image,
+		MinConfidence: aws.Float32(float32(c.config.ModerationConfidence)),
+	})
+	if err != nil {
+		c.logger.Error("Detect moderation labels error", zap.String("key", key), zap.Error(err))
+		return nil, err
+	}
+
+	return result.ModerationLabels, nil
+}
+
+// DetectLabels returns up to max_labels labels of an image in the bucket
+// with at least label_confidence.
+func (c *RekognitionConnector) DetectLabels(ctx context.Context, key string) (_ []types.Label, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "RekognitionConnector", "DetectLabels")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	image, err := c.s3Image(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.client.DetectLabels(ctx, &rekognition.DetectLabelsInput{
+		Image:         image,
+		MaxLabels:     aws.Int32(c.config.MaxLabels),
+		MinConfidence: aws.Float32(float32(c.config.LabelConfidence)),
+	})
+	if err != nil {
+		c.logger.Error("Detect labels error", zap.String("key", key), zap.Error(err))
+		return nil, err
+	}
+
+	return result.Labels, nil
+}
+
+// Moderate returns an *UnsafeContentError when the image has a label in
+// blocked_labels, matched by name or parent name, or any moderation label
+// when blocked_labels is empty.
+func (c *RekognitionConnector) Moderate(ctx context.Context, key string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "RekognitionConnector", "Moderate")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	labels, err := c.DetectModerationLabels(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	blocked := c.config.BlockedLabels
+
+	var found []string
+	for _, label := range labels {
+		name := aws.ToString(label.Name)
+		if len(blocked) == 0 || slices.Contains(blocked, name) || slices.Contains(blocked, aws.ToString(label.ParentName)) {
+			found = append(found, name)
+		}
+	}
+
+	if len(found) > 0 {
+		return &UnsafeContentError{Key: key, Labels: found}
+	}
+
+	return nil
+}
+
+// UploadInterceptor moderates JPEG and PNG uploads of the bucket
+// connector, the formats Rekognition reads. The format is sniffed from
+// the object's first bytes rather than trusted from the declared content
+// type. Other uploads pass, except those declared or named as images in
+// a format it cannot moderate, which are rejected with
+// ErrUnsupportedImage.
+func (c *RekognitionConnector) UploadInterceptor() bucket_connector.UploadInterceptor {
+	return func(ctx context.Context, key string, contentType string) error {
+		sniffed, err := c.sniff(ctx, key)
+		if err != nil {
+			c.logger.Error("Read upload error", zap.String("key", key), zap.Error(err))
+			return err
+		}
+
+		if moderated(sniffed) {
+			return c.Moderate(ctx, key)
+		}
+
+		declared, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			declared = ""
+		}
+
+		byExtension, _, err := mime.ParseMediaType(mime.TypeByExtension(path.Ext(key)))
+		if err != nil {
+			byExtension = ""
+		}
+
+		for _, t := range []string{sniffed, declared, byExtension} {
+			if strings.HasPrefix(t, "image/") {
+				return fmt.Errorf("%w: %s: %s", ErrUnsupportedImage, key, t)
+			}
+		}
+
+		return nil
+	}
+}
+
+func moderated(mediaType string) bool {
+	return mediaType == "image/jpeg" || mediaType == "image/png"
+}
+
+// sniff returns the media type of the object's content.
+func (c *RekognitionConnector) sniff(ctx context.Context, key string) (string, error) {
+	if c.params.Bucket == nil {
+		return "", ErrNoBucket
+	}
+
+	result, err := c.params.Bucket.GetClient().GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.params.Bucket.GetBucketName()),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", sniffLen-1)),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer result.Body.Close()
+
+	head, err := io.ReadAll(io.LimitReader(result.Body, sniffLen))
+	if err != nil {
+		return "", err
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "", err
+	}
+
+	return mediaType, nil
+}
+
+func (c *RekognitionConnector) s3Image(key string) (*types.Image, error) {
+	if c.params.Bucket == nil {
+		return nil, ErrNoBucket
+	}
+
+	return &types.Image{
+		S3Object: &types.S3Object{
+			Bucket: aws.String(c.params.Bucket.GetBucketName()),
+			Name:   aws.String(key),
+		},
+	}, nil
+}
@@ -0,0 +1,18 @@
+package rekognition_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/rekognition/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . ImageModerator
+
+// ImageModerator labels and moderates images of the bucket.
+type ImageModerator interface {
+	DetectModerationLabels(ctx context.Context, key string) ([]types.ModerationLabel, error)
+	DetectLabels(ctx context.Context, key string) ([]types.Label, error)
+	Moderate(ctx context.Context, key string) error
+}
+
+var _ ImageModerator = (*RekognitionConnector)(nil)
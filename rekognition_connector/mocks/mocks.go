@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/rekognition_connector (interfaces: ImageModerator)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . ImageModerator
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/rekognition/types"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImageModerator is a mock of ImageModerator interface.
+type MockImageModerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageModeratorMockRecorder
+}
+
+// MockImageModeratorMockRecorder is the mock recorder for MockImageModerator.
+type MockImageModeratorMockRecorder struct {
+	mock *MockImageModerator
+}
+
+// NewMockImageModerator creates a new mock instance.
+func NewMockImageModerator(ctrl *gomock.Controller) *MockImageModerator {
+	mock := &MockImageModerator{ctrl: ctrl}
+	mock.recorder = &MockImageModeratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageModerator) EXPECT() *MockImageModeratorMockRecorder {
+	return m.recorder
+}
+
+// DetectLabels mocks base method.
+func (m *MockImageModerator) DetectLabels(arg0 context.Context, arg1 string) ([]types.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectLabels", arg0, arg1)
+	ret0, _ := ret[0].([]types.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectLabels indicates an expected call of DetectLabels.
+func (mr *MockImageModeratorMockRecorder) DetectLabels(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectLabels", reflect.TypeOf((*MockImageModerator)(nil).DetectLabels), arg0, arg1)
+}
+
+// DetectModerationLabels mocks base method.
+func (m *MockImageModerator) DetectModerationLabels(arg0 context.Context, arg1 string) ([]types.ModerationLabel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetectModerationLabels", arg0, arg1)
+	ret0, _ := ret[0].([]types.ModerationLabel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetectModerationLabels indicates an expected call of DetectModerationLabels.
+func (mr *MockImageModeratorMockRecorder) DetectModerationLabels(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetectModerationLabels", reflect.TypeOf((*MockImageModerator)(nil).DetectModerationLabels), arg0, arg1)
+}
+
+// Moderate mocks base method.
+func (m *MockImageModerator) Moderate(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Moderate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Moderate indicates an expected call of Moderate.
+func (mr *MockImageModeratorMockRecorder) Moderate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Moderate", reflect.TypeOf((*MockImageModerator)(nil).Moderate), arg0, arg1)
+}
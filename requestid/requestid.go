@@ -0,0 +1,211 @@
+// Package requestid captures the IDs AWS gives each request, which AWS
+// support asks for when looking into a failed call. Failed calls return
+// an error carrying them, and every call is logged at debug level with
+// them. Connectors log through Logger, which adds them to the warnings
+// and errors logged with the error:
+//
+//	logger := requestid.Logger(p.Logger.Named(scope))
+//	logger.Error("Upload failed", zap.Error(err))
+//
+// Loggers not wrapped can add them with Field.
+package requestid
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// Headers some services, S3 among them, return the IDs in without the
+// SDK adding them to the operation metadata.
+const (
+	requestIDHeader = "X-Amz-Request-Id"
+	hostIDHeader    = "X-Amz-Id-2"
+)
+
+// Error is the error of a call AWS gave RequestID, and for S3 HostID, the
+// x-amz-id-2 header.
+type Error struct {
+	Service   string
+	Operation string
+	RequestID string
+	HostID    string
+	Err       error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// FromError returns the request ID carried by err.
+func FromError(err error) (string, bool) {
+	var e *Error
+	if errors.As(err, &e) && e.RequestID != "" {
+		return e.RequestID, true
+	}
+
+	var withID interface{ ServiceRequestID() string }
+	if errors.As(err, &withID) && withID.ServiceRequestID() != "" {
+		return withID.ServiceRequestID(), true
+	}
+
+	return "", false
+}
+
+// Field returns the log field of the request ID carried by err, one
+// that is skipped when it carries none.
+func Field(err error) zap.Field {
+	if id, ok := FromError(err); ok {
+		return zap.String("request_id", id)
+	}
+
+	return zap.Skip()
+}
+
+// Logger returns logger adding request_id, and host_id for S3, to the
+// warnings and errors logged with an error carrying them.
+func Logger(logger *zap.Logger) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return &core{Core: c}
+	}))
+}
+
+type core struct {
+	zapcore.Core
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(fields)}
+}
+
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if entry.Level >= zapcore.WarnLevel {
+		fields = withIDs(fields)
+	}
+
+	return c.Core.Write(entry, fields)
+}
+
+// withIDs appends the IDs of the first error field carrying them, unless
+// the entry has a request_id already.
+func withIDs(fields []zapcore.Field) []zapcore.Field {
+	var err error
+
+	for _, f := range fields {
+		if f.Key == "request_id" {
+			return fields
+		}
+
+		if e, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType && err == nil {
+			if _, found := FromError(e); found {
+				err = e
+			}
+		}
+	}
+
+	if err == nil {
+		return fields
+	}
+
+	id, _ := FromError(err)
+	fields = append(fields[:len(fields):len(fields)], zap.String("request_id", id))
+
+	var e *Error
+	if errors.As(err, &e) && e.HostID != "" {
+		fields = append(fields, zap.String("host_id", e.HostID))
+	}
+
+	return fields
+}
+
+// Instrument captures the request IDs of the clients created from cfg,
+// attaching them to the errors returned and logging each call to logger.
+// Call it before creating them.
+func Instrument(cfg *aws.Config, logger *zap.Logger) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("RequestID",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				start := time.Now()
+
+				out, metadata, err := next.HandleInitialize(ctx, in)
+
+				requestID, hostID := ids(metadata, err)
+
+				fields := []zap.Field{
+					zap.String("service", awsmiddleware.GetServiceID(ctx)),
+					zap.String("operation", awsmiddleware.GetOperationName(ctx)),
+					zap.String("request_id", requestID),
+					zap.Duration("duration", time.Since(start)),
+				}
+				if hostID != "" {
+					fields = append(fields, zap.String("host_id", hostID))
+				}
+
+				if err == nil {
+					logger.Debug("AWS operation", fields...)
+					return out, metadata, nil
+				}
+
+				logger.Debug("AWS operation failed", append(fields, zap.Error(err))...)
+
+				if requestID == "" {
+					return out, metadata, err
+				}
+
+				return out, metadata, &Error{
+					Service:   awsmiddleware.GetServiceID(ctx),
+					Operation: awsmiddleware.GetOperationName(ctx),
+					RequestID: requestID,
+					HostID:    hostID,
+					Err:       err,
+				}
+			},
+		), middleware.After)
+	})
+}
+
+// ids reads the IDs from the operation metadata, the raw response, or the
+// response error, whichever has them.
+func ids(metadata middleware.Metadata, err error) (requestID string, hostID string) {
+	requestID, _ = awsmiddleware.GetRequestIDMetadata(metadata)
+
+	if resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response); ok {
+		if requestID == "" {
+			requestID = resp.Header.Get(requestIDHeader)
+		}
+		hostID = resp.Header.Get(hostIDHeader)
+	}
+
+	if requestID == "" && err != nil {
+		requestID, _ = FromError(err)
+	}
+
+	if hostID == "" && err != nil {
+		var withHostID interface{ ServiceHostID() string }
+		if errors.As(err, &withHostID) {
+			hostID = withHostID.ServiceHostID()
+		}
+	}
+
+	return requestID, hostID
+}
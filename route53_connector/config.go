@@ -0,0 +1,30 @@
+package route53_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. ROUTE53_HOSTED_ZONE_ID for the route53
+// scope.
+type Config struct {
+	HostedZoneID      string `mapstructure:"hosted_zone_id" default:""`
+	TTL               int64  `mapstructure:"ttl" default:"300"`
+	WaitForChanges    bool   `mapstructure:"wait_for_changes" default:"false"`
+	ChangeTimeout     int    `mapstructure:"change_timeout" default:"300"`
+	Route53Key        string `mapstructure:"route53_key" default:"ABCDE"`
+	Route53Secret     string `mapstructure:"route53_secret" default:"example_secret"`
+	Route53Token      string `mapstructure:"route53_token" default:""`
+	Route53Region     string `mapstructure:"route53_region" default:"us-east-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *Route53Connector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *Route53Connector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,19 @@
+package route53_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . DNS
+
+// DNS manages records of the hosted zone.
+type DNS interface {
+	UpsertRecord(ctx context.Context, name string, recordType types.RRType, values ...string) (string, error)
+	UpsertAlias(ctx context.Context, name string, recordType types.RRType, target types.AliasTarget) (string, error)
+	DeleteRecord(ctx context.Context, name string, recordType types.RRType) (string, error)
+	GetRecord(ctx context.Context, name string, recordType types.RRType) (*types.ResourceRecordSet, error)
+}
+
+var _ DNS = (*Route53Connector)(nil)
@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/route53_connector (interfaces: DNS)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . DNS
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDNS is a mock of DNS interface.
+type MockDNS struct {
+	ctrl     *gomock.Controller
+	recorder *MockDNSMockRecorder
+}
+
+// MockDNSMockRecorder is the mock recorder for MockDNS.
+type MockDNSMockRecorder struct {
+	mock *MockDNS
+}
+
+// NewMockDNS creates a new mock instance.
+func NewMockDNS(ctrl *gomock.Controller) *MockDNS {
+	mock := &MockDNS{ctrl: ctrl}
+	mock.recorder = &MockDNSMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDNS) EXPECT() *MockDNSMockRecorder {
+	return m.recorder
+}
+
+// DeleteRecord mocks base method.
+func (m *MockDNS) DeleteRecord(arg0 context.Context, arg1 string, arg2 types.RRType) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRecord indicates an expected call of DeleteRecord.
+func (mr *MockDNSMockRecorder) DeleteRecord(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecord", reflect.TypeOf((*MockDNS)(nil).DeleteRecord), arg0, arg1, arg2)
+}
+
+// GetRecord mocks base method.
+func (m *MockDNS) GetRecord(arg0 context.Context, arg1 string, arg2 types.RRType) (*types.ResourceRecordSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*types.ResourceRecordSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecord indicates an expected call of GetRecord.
+func (mr *MockDNSMockRecorder) GetRecord(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecord", reflect.TypeOf((*MockDNS)(nil).GetRecord), arg0, arg1, arg2)
+}
+
+// UpsertAlias mocks base method.
+func (m *MockDNS) UpsertAlias(arg0 context.Context, arg1 string, arg2 types.RRType, arg3 types.AliasTarget) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAlias", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertAlias indicates an expected call of UpsertAlias.
+func (mr *MockDNSMockRecorder) UpsertAlias(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAlias", reflect.TypeOf((*MockDNS)(nil).UpsertAlias), arg0, arg1, arg2, arg3)
+}
+
+// UpsertRecord mocks base method.
+func (m *MockDNS) UpsertRecord(arg0 context.Context, arg1 string, arg2 types.RRType, arg3 ...string) (string, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpsertRecord", varargs...)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertRecord indicates an expected call of UpsertRecord.
+func (mr *MockDNSMockRecorder) UpsertRecord(arg0, arg1, arg2 any, arg3 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRecord", reflect.TypeOf((*MockDNS)(nil).UpsertRecord), varargs...)
+}
@@ -0,0 +1,208 @@
+package route53_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrRecordNotFound     = awserrors.New(awserrors.ErrNotFound, "record not found")
+	ErrInvalidChangeBatch = awserrors.New(awserrors.ErrValidation, "invalid change batch")
+)
+
+// UpsertRecord creates or replaces the record set of name and type with
+// values and the configured ttl, e.g.
+//
+//	c.UpsertRecord(ctx, "tenant.example.com", types.RRTypeCname, "lb.example.com")
+//
+// TXT values must be quoted. It returns the change ID.
+func (c *Route53Connector) UpsertRecord(ctx context.Context, name string, recordType types.RRType, values ...string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "Route53Connector", "UpsertRecord")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	records := make([]types.ResourceRecord, len(values))
+	for i, value := range values {
+		records[i] = types.ResourceRecord{Value: aws.String(value)}
+	}
+
+	return c.ChangeRecords(ctx, types.Change{
+		Action: types.ChangeActionUpsert,
+		ResourceRecordSet: &types.ResourceRecordSet{
+			Name:            aws.String(fqdn(name)),
+			Type:            recordType,
+			TTL:             aws.Int64(c.config.TTL),
+			ResourceRecords: records,
+		},
+	})
+}
+
+// UpsertAlias points name at an AWS resource such as a load balancer or
+// CloudFront distribution, given its DNS name and hosted zone.
+func (c *Route53Connector) UpsertAlias(ctx context.Context, name string, recordType types.RRType, target types.AliasTarget) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "Route53Connector", "UpsertAlias")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.ChangeRecords(ctx, types.Change{
+		Action: types.ChangeActionUpsert,
+		ResourceRecordSet: &types.ResourceRecordSet{
+			Name:        aws.String(fqdn(name)),
+			Type:        recordType,
+			AliasTarget: &target,
+		},
+	})
+}
+
+// DeleteRecord deletes the record set of name and type, or returns
+// ErrRecordNotFound. Route 53 only deletes an exact match, so the current
+// record set is read first.
+func (c *Route53Connector) DeleteRecord(ctx context.Context, name string, recordType types.RRType) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "Route53Connector", "DeleteRecord")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	set, err := c.GetRecord(ctx, name, recordType)
+	if err != nil {
+		return "", err
+	}
+
+	return c.ChangeRecords(ctx, types.Change{
+		Action:            types.ChangeActionDelete,
+		ResourceRecordSet: set,
+	})
+}
+
+// GetRecord returns the record set of name and type, or
+// ErrRecordNotFound.
+func (c *Route53Connector) GetRecord(ctx context.Context, name string, recordType types.RRType) (_ *types.ResourceRecordSet, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "Route53Connector", "GetRecord")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	name = fqdn(name)
+
+	result, err := c.client.ListResourceRecordSets(ctx, &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(c.hostedZoneID()),
+		StartRecordName: aws.String(name),
+		StartRecordType: recordType,
+		MaxItems:        aws.Int32(1),
+	})
+	if err != nil {
+		c.logger.Error("List resource record sets error", zap.String("name", name), zap.Error(err))
+		return nil, err
+	}
+
+	// The listing starts at name and type, or the record after them
+	for _, set := range result.ResourceRecordSets {
+		if recordName(set) == name && set.Type == recordType {
+			return &set, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrRecordNotFound, name, recordType)
+}
+
+// ListRecords returns the record sets of the hosted zone named suffix or
+// below it, e.g. every record of a tenant's subdomain, or all record sets
+// when suffix is empty.
+func (c *Route53Connector) ListRecords(ctx context.Context, suffix string) (_ []types.ResourceRecordSet, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "Route53Connector", "ListRecords")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	paginator := route53.NewListResourceRecordSetsPaginator(c.client, &route53.ListResourceRecordSetsInput{
+		HostedZoneId: aws.String(c.hostedZoneID()),
+	})
+
+	suffix = fqdn(suffix)
+
+	sets := []types.ResourceRecordSet{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("List resource record sets error", zap.Error(err))
+			return nil, err
+		}
+
+		for _, set := range page.ResourceRecordSets {
+			name := recordName(set)
+			if suffix == "." || name == suffix || strings.HasSuffix(name, "."+suffix) {
+				sets = append(sets, set)
+			}
+		}
+	}
+
+	return sets, nil
+}
+
+// ChangeRecords applies changes as one batch, all or nothing, and returns
+// the change ID. With wait_for_changes it returns once the change reached
+// all Route 53 name servers.
+func (c *Route53Connector) ChangeRecords(ctx context.Context, changes ...types.Change) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "Route53Connector", "ChangeRecords")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(c.hostedZoneID()),
+		ChangeBatch: &types.ChangeBatch{
+			Changes: changes,
+		},
+	})
+	if err != nil {
+		var invalid *types.InvalidChangeBatch
+		if errors.As(err, &invalid) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidChangeBatch, strings.Join(append(invalid.Messages, invalid.ErrorMessage()), "; "))
+		}
+
+		c.logger.Error("Change resource record sets error", zap.Int("count", len(changes)), zap.Error(err))
+		return "", err
+	}
+
+	changeID := aws.ToString(result.ChangeInfo.Id)
+
+	if c.config.WaitForChanges {
+		if err := c.WaitForChange(ctx, changeID); err != nil {
+			return changeID, err
+		}
+	}
+
+	return changeID, nil
+}
+
+// WaitForChange waits up to change_timeout seconds for a change to become
+// INSYNC.
+func (c *Route53Connector) WaitForChange(ctx context.Context, changeID string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "Route53Connector", "WaitForChange")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	waiter := route53.NewResourceRecordSetsChangedWaiter(c.client)
+	timeout := time.Duration(c.config.ChangeTimeout) * time.Second
+
+	if err := waiter.Wait(ctx, &route53.GetChangeInput{Id: aws.String(changeID)}, timeout); err != nil {
+		c.logger.Error("Wait for change error", zap.String("change_id", changeID), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *Route53Connector) hostedZoneID() string {
+	return c.config.HostedZoneID
+}
+
+// fqdn returns name lower case with the trailing dot Route 53 uses.
+func fqdn(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, ".")) + "."
+}
+
+// recordName returns the name of a record set with the wildcard Route 53
+// escapes as \052 restored.
+func recordName(set types.ResourceRecordSet) string {
+	return strings.ReplaceAll(aws.ToString(set.Name), `\052`, "*")
+}
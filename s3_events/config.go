@@ -0,0 +1,23 @@
+package s3_events
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. S3_EVENTS_MAX_MESSAGES for the
+// s3_events scope.
+type Config struct {
+	MaxMessages   int32 `mapstructure:"max_messages" default:"10"`
+	WaitSeconds   int32 `mapstructure:"wait_seconds" default:"20"`
+	RetryInterval int   `mapstructure:"retry_interval" default:"5"`
+}
+
+func (p *Processor) initDefaultConfigs() {
+	moduleconfig.Register(p.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (p *Processor) Config() Config {
+	return p.config
+}
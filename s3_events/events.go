@@ -0,0 +1,100 @@
+package s3_events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// testEvent is the event S3 sends when notifications are configured.
+const testEvent = "s3:TestEvent"
+
+// Record is one entry of an S3 event notification. EventName is the
+// event type without the "s3:" prefix, e.g. "ObjectCreated:Put".
+type Record struct {
+	EventVersion string    `json:"eventVersion"`
+	EventSource  string    `json:"eventSource"`
+	AWSRegion    string    `json:"awsRegion"`
+	EventTime    time.Time `json:"eventTime"`
+	EventName    string    `json:"eventName"`
+	S3           Entity    `json:"s3"`
+}
+
+type Entity struct {
+	ConfigurationID string `json:"configurationId"`
+	Bucket          Bucket `json:"bucket"`
+	Object          Object `json:"object"`
+}
+
+type Bucket struct {
+	Name string `json:"name"`
+	Arn  string `json:"arn"`
+}
+
+// Object is the object of an event. Key is URL-encoded as S3 sends it;
+// use Record.Key for the decoded key.
+type Object struct {
+	Key       string `json:"key"`
+	Size      int64  `json:"size"`
+	ETag      string `json:"eTag"`
+	VersionID string `json:"versionId"`
+	Sequencer string `json:"sequencer"`
+}
+
+// Key returns the decoded object key.
+func (r *Record) Key() (string, error) {
+	return url.QueryUnescape(r.S3.Object.Key)
+}
+
+// Created reports whether the record is for a new object.
+func (r *Record) Created() bool {
+	return strings.HasPrefix(r.EventName, "ObjectCreated:")
+}
+
+// Removed reports whether the record is for a deleted object.
+func (r *Record) Removed() bool {
+	return strings.HasPrefix(r.EventName, "ObjectRemoved:")
+}
+
+type event struct {
+	Records []Record `json:"Records"`
+	Event   string   `json:"Event"`
+}
+
+// envelope is the SNS notification S3 events arrive in when the bucket
+// notifies a topic the queue subscribes to.
+type envelope struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// ParseRecords decodes the records of an S3 event from an SQS message
+// body, unwrapping SNS envelopes. The test event S3 sends when
+// notifications are configured has no records.
+func ParseRecords(body []byte) ([]Record, error) {
+	env := envelope{}
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, err
+	}
+
+	if env.Type == "Notification" && env.Message != "" {
+		body = []byte(env.Message)
+	}
+
+	e := event{}
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, err
+	}
+
+	if e.Event == testEvent {
+		return nil, nil
+	}
+
+	if e.Records == nil {
+		return nil, fmt.Errorf("not an S3 event")
+	}
+
+	return e.Records, nil
+}
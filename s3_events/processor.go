@@ -0,0 +1,210 @@
+package s3_events
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/sqs_connector"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultMaxMessages   = 10
+	DefaultWaitSeconds   = 20
+	DefaultRetryInterval = 5
+)
+
+// Handler processes an object event. eventType is the event name without
+// the "s3:" prefix, e.g. "ObjectCreated:Put". Returning an error leaves
+// the message on the queue so it is redelivered.
+type Handler func(ctx context.Context, bucket string, key string, size int64, eventType string) error
+
+// Filter selects the events a handler receives. Empty fields match
+// everything; EventType matches by prefix, so "ObjectCreated:" selects
+// every new object.
+type Filter struct {
+	Bucket    string
+	Prefix    string
+	Suffix    string
+	EventType string
+}
+
+func (f *Filter) match(bucket string, key string, eventType string) bool {
+	return (f.Bucket == "" || f.Bucket == bucket) &&
+		strings.HasPrefix(key, f.Prefix) &&
+		strings.HasSuffix(key, f.Suffix) &&
+		strings.HasPrefix(eventType, f.EventType)
+}
+
+type route struct {
+	filter  Filter
+	handler Handler
+}
+
+// Processor receives S3 event notifications from the SQS connector's
+// queue and runs the handlers whose filter matches each object. Messages
+// which are not S3 events are logged and deleted, and a handler panic
+// fails its message like an error.
+type Processor struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	mu      sync.RWMutex
+	routes  []route
+	tracker *inflight.Tracker
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+	SQS       *sqs_connector.SQSConnector
+}
+
+func Module(scope string) fx.Option {
+
+	var p *Processor
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(params Params) *Processor {
+
+			logger = requestid.Logger(params.Logger.Named(scope))
+
+			p := &Processor{
+				params: params,
+				logger: logger,
+				scope:  scope,
+			}
+
+			p.initDefaultConfigs()
+
+			return p
+		}),
+		fx.Populate(&p),
+		fx.Invoke(func(params Params) {
+
+			params.Lifecycle.Append(
+				fx.Hook{
+					OnStart: p.onStart,
+					OnStop:  p.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (p *Processor) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(p.scope, &p.config); err != nil {
+		p.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	p.mu.RLock()
+	handlers := len(p.routes)
+	p.mu.RUnlock()
+
+	logger.Info("Starting S3 event processor",
+		zap.Int("handlers", handlers),
+	)
+
+	tracker, err := inflight.NewTracker(p.scope, p.logger)
+	if err != nil {
+		p.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+	p.tracker = tracker
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+
+	go p.receiveLoop(loopCtx)
+
+	return nil
+}
+
+func (p *Processor) onStop(ctx context.Context) error {
+
+	// Let the messages being handled finish, then interrupt the loop
+	p.tracker.Shutdown(ctx)
+
+	if p.cancel != nil {
+		p.cancel()
+		<-p.done
+	}
+
+	p.logger.Info("Stopped S3 event processor")
+
+	return nil
+}
+
+// Handle registers a handler for the objects matching filter. Handlers
+// run in registration order.
+func (p *Processor) Handle(filter Filter, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.routes = append(p.routes, route{filter: filter, handler: handler})
+}
+
+func (p *Processor) receiveLoop(ctx context.Context) {
+	defer close(p.done)
+
+	p.params.SQS.Consume(ctx, sqs_connector.ConsumeOptions{
+		MaxMessages:   p.config.MaxMessages,
+		WaitSeconds:   p.config.WaitSeconds,
+		RetryInterval: time.Duration(p.config.RetryInterval) * time.Second,
+		Logger:        p.logger,
+		Tracker:       p.tracker,
+	}, p.process)
+}
+
+// process runs the handlers of every record in a message. A record whose
+// handler fails fails the whole message, so handlers of the other
+// records may run again on redelivery.
+func (p *Processor) process(ctx context.Context, body string) error {
+	records, err := ParseRecords([]byte(body))
+	if err != nil {
+		return sqs_connector.Malformed(err)
+	}
+
+	p.mu.RLock()
+	routes := p.routes
+	p.mu.RUnlock()
+
+	for _, record := range records {
+		key, err := record.Key()
+		if err != nil {
+			return sqs_connector.Malformed(err)
+		}
+
+		bucket := record.S3.Bucket.Name
+
+		for _, r := range routes {
+			if !r.filter.match(bucket, key, record.EventName) {
+				continue
+			}
+
+			if err := r.handler(ctx, bucket, key, record.S3.Object.Size, record.EventName); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
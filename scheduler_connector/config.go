@@ -0,0 +1,30 @@
+package scheduler_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. SCHEDULER_GROUP_NAME for the scheduler
+// scope.
+type Config struct {
+	GroupName         string `mapstructure:"group_name" default:"default"`
+	RoleARN           string `mapstructure:"role_arn" default:""`
+	Timezone          string `mapstructure:"timezone" default:"UTC"`
+	DeadLetterARN     string `mapstructure:"dead_letter_arn" default:""`
+	SchedulerKey      string `mapstructure:"scheduler_key" default:"ABCDE"`
+	SchedulerSecret   string `mapstructure:"scheduler_secret" default:"example_secret"`
+	SchedulerToken    string `mapstructure:"scheduler_token" default:""`
+	SchedulerRegion   string `mapstructure:"scheduler_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *SchedulerConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *SchedulerConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,14 @@
+package scheduler_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Scheduler
+
+// Scheduler manages schedules of the group.
+type Scheduler interface {
+	CreateSchedule(ctx context.Context, s Schedule) (string, error)
+	UpdateSchedule(ctx context.Context, s Schedule) (string, error)
+	DeleteSchedule(ctx context.Context, name string) error
+}
+
+var _ Scheduler = (*SchedulerConnector)(nil)
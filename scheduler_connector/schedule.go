@@ -0,0 +1,242 @@
+package scheduler_connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrScheduleNotFound = awserrors.New(awserrors.ErrNotFound, "schedule not found")
+
+// Cron returns a cron schedule expression, e.g. Cron("0 12 * * ? *").
+func Cron(expr string) string {
+	return fmt.Sprintf("cron(%s)", expr)
+}
+
+// Rate returns a rate schedule expression in the largest whole unit of
+// d, truncated to minutes. Rates are at least a minute, so shorter
+// durations give rate(1 minute).
+func Rate(d time.Duration) string {
+	minutes := max(int64(d/time.Minute), 1)
+
+	switch {
+	case minutes%(24*60) == 0:
+		return rate(minutes/(24*60), "day")
+	case minutes%60 == 0:
+		return rate(minutes/60, "hour")
+	default:
+		return rate(minutes, "minute")
+	}
+}
+
+func rate(value int64, unit string) string {
+	if value != 1 {
+		unit += "s"
+	}
+
+	return fmt.Sprintf("rate(%d %s)", value, unit)
+}
+
+// At returns a one-time schedule expression. The time is written as a
+// wall clock in the schedule's timezone, so pass it in that location.
+func At(t time.Time) string {
+	return fmt.Sprintf("at(%s)", t.Format("2006-01-02T15:04:05"))
+}
+
+// Target is what a schedule invokes. Input is sent as the event body:
+// strings, []byte and json.RawMessage as they are, anything else as JSON.
+type Target struct {
+	Arn            string
+	Input          interface{}
+	MessageGroupID string
+}
+
+// SQSTarget sends input to a queue; groupID is required for FIFO queues.
+func SQSTarget(queueArn string, input interface{}, groupID string) Target {
+	return Target{Arn: queueArn, Input: input, MessageGroupID: groupID}
+}
+
+// LambdaTarget invokes a function asynchronously with input.
+func LambdaTarget(functionArn string, input interface{}) Target {
+	return Target{Arn: functionArn, Input: input}
+}
+
+// Schedule describes a schedule in the configured group. An empty
+// Timezone uses the configured one; FlexibleWindow lets the scheduler
+// spread invocations over up to that long after the scheduled time.
+type Schedule struct {
+	Name           string
+	Description    string
+	Expression     string
+	Timezone       string
+	Target         Target
+	FlexibleWindow time.Duration
+	Start          time.Time
+	End            time.Time
+
+	// DeleteAfterCompletion removes one-time schedules once they ran
+	DeleteAfterCompletion bool
+}
+
+// CreateSchedule creates s and returns its ARN.
+func (c *SchedulerConnector) CreateSchedule(ctx context.Context, s Schedule) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SchedulerConnector", "CreateSchedule")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	input, err := c.createInput(s)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.client.CreateSchedule(ctx, input)
+	if err != nil {
+		c.logger.Error("Create schedule error", zap.String("name", s.Name), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.ScheduleArn), nil
+}
+
+// UpdateSchedule replaces the definition of the existing schedule
+// s.Name and returns its ARN.
+func (c *SchedulerConnector) UpdateSchedule(ctx context.Context, s Schedule) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SchedulerConnector", "UpdateSchedule")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	create, err := c.createInput(s)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.client.UpdateSchedule(ctx, &scheduler.UpdateScheduleInput{
+		Name:                       create.Name,
+		GroupName:                  create.GroupName,
+		Description:                create.Description,
+		ScheduleExpression:         create.ScheduleExpression,
+		ScheduleExpressionTimezone: create.ScheduleExpressionTimezone,
+		FlexibleTimeWindow:         create.FlexibleTimeWindow,
+		StartDate:                  create.StartDate,
+		EndDate:                    create.EndDate,
+		Target:                     create.Target,
+		ActionAfterCompletion:      create.ActionAfterCompletion,
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return "", ErrScheduleNotFound
+		}
+
+		c.logger.Error("Update schedule error", zap.String("name", s.Name), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.ScheduleArn), nil
+}
+
+// DeleteSchedule deletes the named schedule and returns
+// ErrScheduleNotFound when it does not exist.
+func (c *SchedulerConnector) DeleteSchedule(ctx context.Context, name string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SchedulerConnector", "DeleteSchedule")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.DeleteSchedule(ctx, &scheduler.DeleteScheduleInput{
+		Name:      aws.String(name),
+		GroupName: aws.String(c.config.GroupName),
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return ErrScheduleNotFound
+		}
+
+		c.logger.Error("Delete schedule error", zap.String("name", name), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *SchedulerConnector) createInput(s Schedule) (*scheduler.CreateScheduleInput, error) {
+	roleArn := c.config.RoleARN
+	if roleArn == "" {
+		return nil, fmt.Errorf("%s: role_arn is required to invoke schedule targets", c.scope)
+	}
+
+	if s.Name == "" || s.Expression == "" || s.Target.Arn == "" {
+		return nil, fmt.Errorf("%s: schedules need a name, an expression and a target", c.scope)
+	}
+
+	target := &types.Target{
+		Arn:     aws.String(s.Target.Arn),
+		RoleArn: aws.String(roleArn),
+	}
+
+	switch input := s.Target.Input.(type) {
+	case nil:
+	case string:
+		target.Input = aws.String(input)
+	case []byte:
+		target.Input = aws.String(string(input))
+	case json.RawMessage:
+		target.Input = aws.String(string(input))
+	default:
+		data, err := json.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+		target.Input = aws.String(string(data))
+	}
+
+	if s.Target.MessageGroupID != "" {
+		target.SqsParameters = &types.SqsParameters{MessageGroupId: aws.String(s.Target.MessageGroupID)}
+	}
+
+	if deadLetterArn := c.config.DeadLetterARN; deadLetterArn != "" {
+		target.DeadLetterConfig = &types.DeadLetterConfig{Arn: aws.String(deadLetterArn)}
+	}
+
+	window := &types.FlexibleTimeWindow{Mode: types.FlexibleTimeWindowModeOff}
+	if s.FlexibleWindow > 0 {
+		window = &types.FlexibleTimeWindow{
+			Mode:                   types.FlexibleTimeWindowModeFlexible,
+			MaximumWindowInMinutes: aws.Int32(int32(min(max(s.FlexibleWindow/time.Minute, 1), 1440))),
+		}
+	}
+
+	timezone := s.Timezone
+	if timezone == "" {
+		timezone = c.config.Timezone
+	}
+
+	input := &scheduler.CreateScheduleInput{
+		Name:                       aws.String(s.Name),
+		GroupName:                  aws.String(c.config.GroupName),
+		ScheduleExpression:         aws.String(s.Expression),
+		ScheduleExpressionTimezone: aws.String(timezone),
+		FlexibleTimeWindow:         window,
+		Target:                     target,
+	}
+	if s.Description != "" {
+		input.Description = aws.String(s.Description)
+	}
+	if !s.Start.IsZero() {
+		input.StartDate = aws.Time(s.Start)
+	}
+	if !s.End.IsZero() {
+		input.EndDate = aws.Time(s.End)
+	}
+	if s.DeleteAfterCompletion {
+		input.ActionAfterCompletion = types.ActionAfterCompletionDelete
+	}
+
+	return input, nil
+}
@@ -0,0 +1,32 @@
+package secretsmanager_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. SECRETSMANAGER_SECRETS_KEY for the
+// secretsmanager scope.
+type Config struct {
+	SecretsKey        string `mapstructure:"secrets_key" default:"ABCDE"`
+	SecretsSecret     string `mapstructure:"secrets_secret" default:"example_secret"`
+	SecretsToken      string `mapstructure:"secrets_token" default:""`
+	SecretsRegion     string `mapstructure:"secrets_region" default:"us-west-1"`
+	CacheTTL          int    `mapstructure:"cache_ttl" default:"300"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+
+	WatchRotation        bool `mapstructure:"watch_rotation" default:"false"`
+	RotationPollInterval int  `mapstructure:"rotation_poll_interval" default:"300"`
+
+	Secrets []SecretConfig `mapstructure:"secrets"`
+}
+
+func (c *SecretsManagerConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *SecretsManagerConnector) Config() Config {
+	return c.config
+}
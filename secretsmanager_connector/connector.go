@@ -0,0 +1,424 @@
+package secretsmanager_connector
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+	"github.com/spf13/viper"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultSecretsKey    = "ABCDE"
+	DefaultSecretsSecret = "example_secret"
+	DefaultSecretsToken  = ""
+	DefaultSecretsRegion = "us-west-1"
+	DefaultCacheTTL      = 300
+)
+
+var preflightActions = []string{
+	"secretsmanager:GetSecretValue",
+	"secretsmanager:DescribeSecret",
+}
+
+// SecretConfig names a secret to load at startup. The fields of a JSON
+// secret are set as viper keys under Prefix, so a secret
+// {"bucket_key": ..., "bucket_secret": ...} with prefix "bucket" feeds
+// bucket.bucket_key and bucket.bucket_secret. A plain string secret is
+// set as Prefix itself. Rotated values are read with Value.
+type SecretConfig struct {
+	Name   string `mapstructure:"name"`
+	Prefix string `mapstructure:"prefix"`
+}
+
+type cachedSecret struct {
+	value     string
+	versionID string
+	fetchedAt time.Time
+}
+
+type SecretsManagerConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *secretsmanager.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	mu        sync.RWMutex
+	cache     map[string]cachedSecret
+	values    map[string]interface{}
+	callbacks map[string][]RotationCallback
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *secretsmanager.Client  `optional:"true"`
+}
+
+// Module loads secrets in its start hook. fx runs start hooks in the order
+// modules are given to fx.New, so list this module before the connectors
+// that read the keys it sets. It uses the *secretsmanager.Client in the
+// graph, if any, instead of building one.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the SecretsManagerConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *secretsmanager.Client named after
+// scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *SecretsManagerConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*secretsmanager.Client](scope, named),
+		instance.Provide[*SecretsManagerConnector](scope, named, func(p Params) *SecretsManagerConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &SecretsManagerConnector{
+				params:    p,
+				logger:    logger,
+				scope:     scope,
+				cache:     make(map[string]cachedSecret),
+				values:    make(map[string]interface{}),
+				callbacks: make(map[string][]RotationCallback),
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *SecretsManagerConnector) SecretStore {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *SecretsManagerConnector) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting SecretsManagerConnector",
+		zap.String("secrets_region", c.config.SecretsRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = secretsmanager.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = secretsmanager.New(c.client.Options(), func(o *secretsmanager.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	for _, s := range c.config.Secrets {
+		value, _, err := c.fetch(ctx, s.Name)
+		if err != nil {
+			return err
+		}
+
+		// Start hooks run one at a time, before the modules started
+		// after this one read their config, so viper is only set here;
+		// rotations stay in c.values.
+		for key, v := range c.apply(s, value) {
+			viper.Set(key, v)
+		}
+	}
+
+	c.startRotationWatch()
+
+	return nil
+}
+
+func (c *SecretsManagerConnector) onStop(ctx context.Context) error {
+
+	c.stopRotationWatch()
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped SecretsManagerConnector")
+
+	return nil
+}
+
+func (c *SecretsManagerConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.SecretsRegion,
+		Key:         c.config.SecretsKey,
+		Secret:      c.config.SecretsSecret,
+		Token:       c.config.SecretsToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *SecretsManagerConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("secrets_region")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("secrets_key", DefaultSecretsKey)
+		v.NotPlaceholder("secrets_secret", DefaultSecretsSecret)
+	}
+
+	return v.Err()
+}
+
+// probe lists a secret.
+func (c *SecretsManagerConnector) probe(ctx context.Context) error {
+	_, err := c.client.ListSecrets(ctx, &secretsmanager.ListSecretsInput{
+		MaxResults: aws.Int32(1),
+	})
+
+	return err
+}
+
+// apply stores the keys of a loaded secret in the connector and returns
+// them. Values are never logged.
+func (c *SecretsManagerConnector) apply(s SecretConfig, value string) map[string]interface{} {
+	keys := map[string]interface{}{}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		if s.Prefix != "" {
+			keys[s.Prefix] = value
+		}
+	} else {
+		keys = flatten(s.Prefix, fields, keys)
+	}
+
+	c.mu.Lock()
+	for key, v := range keys {
+		c.values[key] = v
+	}
+	c.mu.Unlock()
+
+	c.logger.Info("Loaded secret", zap.String("name", s.Name), zap.String("prefix", s.Prefix), zap.Int("keys", len(keys)))
+
+	return keys
+}
+
+// Value returns the current value of the secret key, as loaded at start
+// or after the latest rotation. Rotations are not written to viper,
+// which is not safe to change while other goroutines read it.
+func (c *SecretsManagerConnector) Value(key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	value, ok := c.values[key]
+
+	return value, ok
+}
+
+func flatten(prefix string, fields map[string]interface{}, out map[string]interface{}) map[string]interface{} {
+	if out == nil {
+		out = make(map[string]interface{})
+	}
+
+	for k, v := range fields {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		if nested, ok := v.(map[string]interface{}); ok {
+			flatten(key, nested, out)
+			continue
+		}
+
+		out[key] = v
+	}
+
+	return out
+}
+
+// fetch reads the current version of a secret and caches it. rotated
+// reports that a different version was cached before.
+func (c *SecretsManagerConnector) fetch(ctx context.Context, name string) (string, bool, error) {
+	result, err := c.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(name),
+	})
+	if err != nil {
+		c.logger.Error("Get secret value error", zap.String("name", name), zap.Error(err))
+		return "", false, err
+	}
+
+	value := aws.ToString(result.SecretString)
+	if result.SecretString == nil {
+		value = string(result.SecretBinary)
+	}
+
+	versionID := aws.ToString(result.VersionId)
+
+	c.mu.Lock()
+	previous, ok := c.cache[name]
+	c.cache[name] = cachedSecret{
+		value:     value,
+		versionID: versionID,
+		fetchedAt: time.Now(),
+	}
+	c.mu.Unlock()
+
+	return value, ok && previous.versionID != versionID, nil
+}
+
+// GetSecret returns the value of a secret, served from the cache for
+// cache_ttl seconds.
+func (c *SecretsManagerConnector) GetSecret(ctx context.Context, name string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SecretsManagerConnector", "GetSecret")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	ttl := time.Duration(c.config.CacheTTL) * time.Second
+
+	c.mu.RLock()
+	cached, ok := c.cache[name]
+	c.mu.RUnlock()
+
+	if ok && time.Since(cached.fetchedAt) < ttl {
+		return cached.value, nil
+	}
+
+	value, rotated, err := c.fetch(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	if rotated {
+		c.rotated(ctx, name, value)
+	}
+
+	return value, nil
+}
+
+// GetSecretJSON unmarshals a JSON secret into out.
+func (c *SecretsManagerConnector) GetSecretJSON(ctx context.Context, name string, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SecretsManagerConnector", "GetSecretJSON")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	value, err := c.GetSecret(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(value), out)
+}
+
+func (c *SecretsManagerConnector) GetClient() *secretsmanager.Client {
+	return c.client
+}
@@ -0,0 +1,16 @@
+package secretsmanager_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . SecretStore
+
+// SecretStore reads secrets.
+type SecretStore interface {
+	GetSecret(ctx context.Context, name string) (string, error)
+	GetSecretJSON(ctx context.Context, name string, out interface{}) error
+}
+
+var (
+	_ SecretStore = (*SecretsManagerConnector)(nil)
+	_ SecretStore = (*MemorySecretStore)(nil)
+)
@@ -0,0 +1,65 @@
+package secretsmanager_connector
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// MemorySecretStore is an in-process SecretStore for tests. Missing
+// secrets fail with *types.ResourceNotFoundException, like Secrets
+// Manager.
+type MemorySecretStore struct {
+	mu      sync.RWMutex
+	secrets map[string]string
+}
+
+func NewMemorySecretStore() *MemorySecretStore {
+	return &MemorySecretStore{
+		secrets: make(map[string]string),
+	}
+}
+
+// SetSecret stores value under name.
+func (s *MemorySecretStore) SetSecret(name string, value string) {
+	s.mu.Lock()
+	s.secrets[name] = value
+	s.mu.Unlock()
+}
+
+// SetSecretJSON stores v marshalled to JSON under name.
+func (s *MemorySecretStore) SetSecretJSON(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	s.SetSecret(name, string(data))
+
+	return nil
+}
+
+func (s *MemorySecretStore) GetSecret(ctx context.Context, name string) (string, error) {
+	s.mu.RLock()
+	value, ok := s.secrets[name]
+	s.mu.RUnlock()
+
+	if !ok {
+		return "", awserrors.Wrap(&types.ResourceNotFoundException{Message: aws.String(name)})
+	}
+
+	return value, nil
+}
+
+func (s *MemorySecretStore) GetSecretJSON(ctx context.Context, name string, out interface{}) error {
+	value, err := s.GetSecret(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(value), out)
+}
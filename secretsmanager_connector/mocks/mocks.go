@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/secretsmanager_connector (interfaces: SecretStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . SecretStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSecretStore is a mock of SecretStore interface.
+type MockSecretStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretStoreMockRecorder
+}
+
+// MockSecretStoreMockRecorder is the mock recorder for MockSecretStore.
+type MockSecretStoreMockRecorder struct {
+	mock *MockSecretStore
+}
+
+// NewMockSecretStore creates a new mock instance.
+func NewMockSecretStore(ctrl *gomock.Controller) *MockSecretStore {
+	mock := &MockSecretStore{ctrl: ctrl}
+	mock.recorder = &MockSecretStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecretStore) EXPECT() *MockSecretStoreMockRecorder {
+	return m.recorder
+}
+
+// GetSecret mocks base method.
+func (m *MockSecretStore) GetSecret(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecret", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecret indicates an expected call of GetSecret.
+func (mr *MockSecretStoreMockRecorder) GetSecret(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecret", reflect.TypeOf((*MockSecretStore)(nil).GetSecret), arg0, arg1)
+}
+
+// GetSecretJSON mocks base method.
+func (m *MockSecretStore) GetSecretJSON(arg0 context.Context, arg1 string, arg2 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecretJSON", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetSecretJSON indicates an expected call of GetSecretJSON.
+func (mr *MockSecretStoreMockRecorder) GetSecretJSON(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretJSON", reflect.TypeOf((*MockSecretStore)(nil).GetSecretJSON), arg0, arg1, arg2)
+}
@@ -0,0 +1,137 @@
+package secretsmanager_connector
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+)
+
+const (
+	DefaultWatchRotation        = false
+	DefaultRotationPollInterval = 300
+)
+
+const stageCurrent = "AWSCURRENT"
+
+// RotationCallback is called with the new value after a secret rotated,
+// once the cache and the keys read by Value are updated. Dependent clients can
+// rebuild themselves from it.
+type RotationCallback func(ctx context.Context, name string, value string)
+
+// OnRotate registers callback for rotations of the named secret, or of
+// every watched secret when name is empty. Only secrets that have been
+// read (configured or via GetSecret) are watched.
+func (c *SecretsManagerConnector) OnRotate(name string, callback RotationCallback) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.callbacks[name] = append(c.callbacks[name], callback)
+}
+
+func (c *SecretsManagerConnector) startRotationWatch() {
+	if !c.config.WatchRotation {
+		return
+	}
+
+	loopCtx, cancel := context.WithCancel(inflight.Internal(context.Background()))
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go c.rotationLoop(loopCtx)
+}
+
+func (c *SecretsManagerConnector) stopRotationWatch() {
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+}
+
+func (c *SecretsManagerConnector) rotationLoop(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(time.Duration(c.config.RotationPollInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.RLock()
+		versions := make(map[string]string, len(c.cache))
+		for name, cached := range c.cache {
+			versions[name] = cached.versionID
+		}
+		c.mu.RUnlock()
+
+		for name, versionID := range versions {
+			if err := c.checkRotation(ctx, name, versionID); err != nil && ctx.Err() == nil {
+				c.logger.Warn("Check secret rotation error", zap.String("name", name), zap.Error(err))
+			}
+		}
+	}
+}
+
+// checkRotation compares the AWSCURRENT version with the cached one and
+// reloads the secret when it changed.
+func (c *SecretsManagerConnector) checkRotation(ctx context.Context, name string, versionID string) error {
+	result, err := c.client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(name),
+	})
+	if err != nil {
+		return err
+	}
+
+	current := ""
+	for id, stages := range result.VersionIdsToStages {
+		for _, stage := range stages {
+			if stage == stageCurrent {
+				current = id
+			}
+		}
+	}
+
+	if current == "" || current == versionID {
+		return nil
+	}
+
+	value, rotated, err := c.fetch(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if rotated {
+		c.rotated(ctx, name, value)
+	}
+
+	return nil
+}
+
+// rotated updates the keys of a configured secret and notifies the
+// callbacks.
+func (c *SecretsManagerConnector) rotated(ctx context.Context, name string, value string) {
+	c.logger.Info("Secret rotated", zap.String("name", name))
+
+	for _, s := range c.config.Secrets {
+		if s.Name == name {
+			c.apply(s, value)
+		}
+	}
+
+	c.mu.RLock()
+	callbacks := append(append([]RotationCallback{}, c.callbacks[name]...), c.callbacks[""]...)
+	c.mu.RUnlock()
+
+	for _, callback := range callbacks {
+		callback(ctx, name, value)
+	}
+}
@@ -0,0 +1,161 @@
+package ses_connector
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+const maxBulkEntries = 50
+
+const (
+	DefaultBulkBatchSize   = maxBulkEntries
+	DefaultBulkMaxSendRate = 0
+)
+
+// BulkRecipient is one destination of a bulk send. Data is merged over
+// the default template data for this recipient only.
+type BulkRecipient struct {
+	Email string
+	Data  map[string]interface{}
+}
+
+// BulkResult is the outcome of a single recipient. Status is SUCCESS when
+// SES accepted the message.
+type BulkResult struct {
+	Email     string
+	MessageID string
+	Status    types.BulkEmailStatus
+	Error     string
+}
+
+// sendLimiter paces bulk sends to bulk_max_send_rate recipients per
+// second, or the account's MaxSendRate when that is not set. Batches are
+// no larger than one second's quota so a single call can't exceed it.
+func (c *SESConnector) sendLimiter(ctx context.Context) (*rate.Limiter, int, error) {
+	c.bulkMu.Lock()
+	defer c.bulkMu.Unlock()
+
+	if c.limiter != nil {
+		return c.limiter, c.bulkBatch, nil
+	}
+
+	maxRate := c.config.BulkMaxSendRate
+
+	if maxRate <= 0 {
+		account, err := c.client.GetAccount(ctx, &sesv2.GetAccountInput{})
+		if err != nil {
+			c.logger.Error("Get account error", zap.Error(err))
+			return nil, 0, err
+		}
+
+		if account.SendQuota != nil {
+			maxRate = account.SendQuota.MaxSendRate
+		}
+	}
+
+	batch := min(c.config.BulkBatchSize, maxBulkEntries)
+	limit := rate.Inf
+	if maxRate > 0 {
+		batch = max(min(batch, int(maxRate)), 1)
+		limit = rate.Limit(maxRate)
+	}
+
+	c.logger.Info("Bulk email rate", zap.Float64("max_send_rate", maxRate), zap.Int("batch_size", batch))
+
+	c.limiter = rate.NewLimiter(limit, batch)
+	c.bulkBatch = batch
+
+	return c.limiter, c.bulkBatch, nil
+}
+
+// SendBulkEmail sends templateName to every recipient in rate-limited
+// SendBulkEmail batches. Results are returned in recipient order; on a
+// request error the results of the batches sent so far are returned with
+// it.
+func (c *SESConnector) SendBulkEmail(ctx context.Context, templateName string, defaultData map[string]interface{}, recipients []BulkRecipient) (_ []BulkResult, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SESConnector", "SendBulkEmail")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	limiter, batch, err := c.sendLimiter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if defaultData == nil {
+		defaultData = map[string]interface{}{}
+	}
+
+	defaults, err := json.Marshal(defaultData)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, 0, len(recipients))
+
+	for start := 0; start < len(recipients); start += batch {
+		end := min(start+batch, len(recipients))
+		chunk := recipients[start:end]
+
+		entries := make([]types.BulkEmailEntry, 0, len(chunk))
+		for _, r := range chunk {
+			entry := types.BulkEmailEntry{
+				Destination: &types.Destination{
+					ToAddresses: []string{r.Email},
+				},
+			}
+
+			if len(r.Data) > 0 {
+				data, err := json.Marshal(r.Data)
+				if err != nil {
+					return results, err
+				}
+
+				entry.ReplacementEmailContent = &types.ReplacementEmailContent{
+					ReplacementTemplate: &types.ReplacementTemplate{
+						ReplacementTemplateData: aws.String(string(data)),
+					},
+				}
+			}
+
+			entries = append(entries, entry)
+		}
+
+		if err := limiter.WaitN(ctx, len(chunk)); err != nil {
+			return results, err
+		}
+
+		result, err := c.client.SendBulkEmail(ctx, &sesv2.SendBulkEmailInput{
+			FromEmailAddress: aws.String(c.fromAddress()),
+			DefaultContent: &types.BulkEmailContent{
+				Template: &types.Template{
+					TemplateName: aws.String(templateName),
+					TemplateData: aws.String(string(defaults)),
+				},
+			},
+			BulkEmailEntries:     entries,
+			ConfigurationSetName: c.configurationSet(),
+		})
+		if err != nil {
+			c.logger.Error("Send bulk email error", zap.String("template", templateName), zap.Int("recipients", len(chunk)), zap.Error(err))
+			return results, err
+		}
+
+		for i, r := range result.BulkEmailEntryResults {
+			results = append(results, BulkResult{
+				Email:     chunk[i].Email,
+				MessageID: aws.ToString(r.MessageId),
+				Status:    r.Status,
+				Error:     aws.ToString(r.Error),
+			})
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,33 @@
+package ses_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. SES_SENDER for the ses scope.
+type Config struct {
+	Sender            string `mapstructure:"sender" default:"no-reply@example.com"`
+	SenderName        string `mapstructure:"sender_name" default:""`
+	ConfigurationSet  string `mapstructure:"configuration_set" default:""`
+	EmailKey          string `mapstructure:"email_key" default:"ABCDE"`
+	EmailSecret       string `mapstructure:"email_secret" default:"example_secret"`
+	EmailToken        string `mapstructure:"email_token" default:""`
+	EmailRegion       string `mapstructure:"email_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+
+	SyncTemplates bool `mapstructure:"sync_templates" default:"true"`
+
+	BulkBatchSize   int     `mapstructure:"bulk_batch_size" default:"50"`
+	BulkMaxSendRate float64 `mapstructure:"bulk_max_send_rate" default:"0"`
+}
+
+func (c *SESConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *SESConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,324 @@
+package ses_connector
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultSender           = "no-reply@example.com"
+	DefaultSenderName       = ""
+	DefaultConfigurationSet = ""
+	DefaultEmailKey         = "ABCDE"
+	DefaultEmailSecret      = "example_secret"
+	DefaultEmailToken       = ""
+	DefaultEmailRegion      = "us-west-1"
+)
+
+var preflightActions = []string{
+	"ses:SendEmail",
+	"ses:SendBulkEmail",
+}
+
+type SESConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *sesv2.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	templates []Template
+
+	bulkMu    sync.Mutex
+	limiter   *rate.Limiter
+	bulkBatch int
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *sesv2.Client           `optional:"true"`
+}
+
+// Module provides the SESConnector of scope. It uses the *sesv2.Client
+// in the graph, if any, instead of building one, and adds the
+// connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the SESConnector of scope, and its interfaces,
+// named after scope, for apps with more than one; see package instance.
+// It uses the *sesv2.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *SESConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*sesv2.Client](scope, named),
+		instance.Provide[*SESConnector](scope, named, func(p Params) *SESConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &SESConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *SESConnector) Mailer {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *SESConnector) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting SESConnector",
+		zap.String("sender", c.config.Sender),
+		zap.String("configuration_set", c.config.ConfigurationSet),
+		zap.String("email_region", c.config.EmailRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = sesv2.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = sesv2.New(c.client.Options(), func(o *sesv2.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	if c.config.SyncTemplates && len(c.templates) > 0 {
+		if err := c.SyncTemplates(ctx, c.templates...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *SESConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped SESConnector")
+
+	return nil
+}
+
+func (c *SESConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.EmailRegion,
+		Key:         c.config.EmailKey,
+		Secret:      c.config.EmailSecret,
+		Token:       c.config.EmailToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *SESConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("email_region")
+
+	v.Required("sender")
+	v.NotPlaceholder("sender", DefaultSender)
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("email_key", DefaultEmailKey)
+		v.NotPlaceholder("email_secret", DefaultEmailSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the sending account.
+func (c *SESConnector) probe(ctx context.Context) error {
+	_, err := c.client.GetAccount(ctx, &sesv2.GetAccountInput{})
+
+	return err
+}
+
+// fromAddress formats the configured sender with the optional display
+// name.
+func (c *SESConnector) fromAddress() string {
+	addr := mail.Address{
+		Name:    c.config.SenderName,
+		Address: c.config.Sender,
+	}
+
+	return addr.String()
+}
+
+func (c *SESConnector) configurationSet() *string {
+	if set := c.config.ConfigurationSet; set != "" {
+		return aws.String(set)
+	}
+
+	return nil
+}
+
+// SendEmail sends a simple email from the configured sender. Either body
+// may be empty, but not both.
+func (c *SESConnector) SendEmail(ctx context.Context, to []string, subject string, htmlBody string, textBody string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SESConnector", "SendEmail")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if htmlBody == "" && textBody == "" {
+		return "", fmt.Errorf("email body is empty")
+	}
+
+	body := &types.Body{}
+	if htmlBody != "" {
+		body.Html = &types.Content{Data: aws.String(htmlBody), Charset: aws.String("UTF-8")}
+	}
+	if textBody != "" {
+		body.Text = &types.Content{Data: aws.String(textBody), Charset: aws.String("UTF-8")}
+	}
+
+	result, err := c.client.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(c.fromAddress()),
+		Destination: &types.Destination{
+			ToAddresses: to,
+		},
+		Content: &types.EmailContent{
+			Simple: &types.Message{
+				Subject: &types.Content{Data: aws.String(subject), Charset: aws.String("UTF-8")},
+				Body:    body,
+			},
+		},
+		ConfigurationSetName: c.configurationSet(),
+	})
+	if err != nil {
+		c.logger.Error("Send email error", zap.Strings("to", to), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.MessageId), nil
+}
+
+func (c *SESConnector) GetClient() *sesv2.Client {
+	return c.client
+}
@@ -0,0 +1,18 @@
+package ses_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Mailer
+
+// Mailer sends email.
+type Mailer interface {
+	SendEmail(ctx context.Context, to []string, subject string, htmlBody string, textBody string) (string, error)
+	SendRawEmail(ctx context.Context, msg *Message) (string, error)
+	SendTemplatedEmail(ctx context.Context, to []string, templateName string, data map[string]interface{}) (string, error)
+	SendBulkEmail(ctx context.Context, templateName string, defaultData map[string]interface{}, recipients []BulkRecipient) ([]BulkResult, error)
+}
+
+var (
+	_ Mailer = (*SESConnector)(nil)
+	_ Mailer = (*MemoryMailer)(nil)
+)
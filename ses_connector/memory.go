@@ -0,0 +1,90 @@
+package ses_connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// SentEmail is an email recorded by MemoryMailer. Template and Data are
+// set for templated and bulk sends, Message for raw ones.
+type SentEmail struct {
+	MessageID string
+	To        []string
+	Subject   string
+	HTML      string
+	Text      string
+	Template  string
+	Data      map[string]interface{}
+	Message   *Message
+}
+
+// MemoryMailer is an in-process Mailer for tests. It records what would
+// have been sent.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []SentEmail
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) SendEmail(ctx context.Context, to []string, subject string, htmlBody string, textBody string) (string, error) {
+	if htmlBody == "" && textBody == "" {
+		return "", fmt.Errorf("email body is empty")
+	}
+
+	return m.record(SentEmail{To: to, Subject: subject, HTML: htmlBody, Text: textBody}), nil
+}
+
+func (m *MemoryMailer) SendRawEmail(ctx context.Context, msg *Message) (string, error) {
+	return m.record(SentEmail{To: msg.To, Subject: msg.Subject, HTML: msg.HTML, Text: msg.Text, Message: msg}), nil
+}
+
+func (m *MemoryMailer) SendTemplatedEmail(ctx context.Context, to []string, templateName string, data map[string]interface{}) (string, error) {
+	return m.record(SentEmail{To: to, Template: templateName, Data: data}), nil
+}
+
+func (m *MemoryMailer) SendBulkEmail(ctx context.Context, templateName string, defaultData map[string]interface{}, recipients []BulkRecipient) ([]BulkResult, error) {
+	results := make([]BulkResult, 0, len(recipients))
+
+	for _, recipient := range recipients {
+		data := make(map[string]interface{}, len(defaultData)+len(recipient.Data))
+		for k, v := range defaultData {
+			data[k] = v
+		}
+		for k, v := range recipient.Data {
+			data[k] = v
+		}
+
+		results = append(results, BulkResult{
+			Email:     recipient.Email,
+			MessageID: m.record(SentEmail{To: []string{recipient.Email}, Template: templateName, Data: data}),
+			Status:    types.BulkEmailStatusSuccess,
+		})
+	}
+
+	return results, nil
+}
+
+// Sent returns the recorded emails in the order they were sent.
+func (m *MemoryMailer) Sent() []SentEmail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]SentEmail(nil), m.sent...)
+}
+
+func (m *MemoryMailer) record(email SentEmail) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	email.MessageID = strconv.Itoa(len(m.sent) + 1)
+	m.sent = append(m.sent, email)
+
+	return email.MessageID
+}
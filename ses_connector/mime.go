@@ -0,0 +1,326 @@
+package ses_connector
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// Attachment is a file attached to a message. Attachments with a
+// ContentID are sent inline and can be referenced from HTML as
+// "cid:<ContentID>".
+type Attachment struct {
+	Filename    string
+	ContentType string
+	ContentID   string
+	Data        []byte
+}
+
+// Message builds a MIME email:
+//
+//	multipart/mixed           (when there are attachments)
+//	└ multipart/related       (when there are inline parts)
+//	  └ multipart/alternative (when there are both text and HTML)
+type Message struct {
+	From        string
+	To          []string
+	Cc          []string
+	Bcc         []string
+	ReplyTo     []string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []Attachment
+	Headers     map[string]string
+}
+
+func NewMessage(to []string, subject string) *Message {
+	return &Message{
+		To:      to,
+		Subject: subject,
+		Headers: map[string]string{},
+	}
+}
+
+func (m *Message) Attach(filename string, contentType string, data []byte) *Message {
+	m.Attachments = append(m.Attachments, Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Data:        data,
+	})
+	return m
+}
+
+func (m *Message) Inline(contentID string, filename string, contentType string, data []byte) *Message {
+	m.Attachments = append(m.Attachments, Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		ContentID:   contentID,
+		Data:        data,
+	})
+	return m
+}
+
+type mimePart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// Build renders the message as RFC 5322 bytes. Bcc recipients are not
+// written to the headers. Header values with line breaks and addresses
+// that do not parse are rejected.
+func (m *Message) Build() ([]byte, error) {
+	if m.HTML == "" && m.Text == "" {
+		return nil, fmt.Errorf("email body is empty")
+	}
+
+	var inline, attached []Attachment
+	for _, a := range m.Attachments {
+		if a.ContentID != "" {
+			inline = append(inline, a)
+		} else {
+			attached = append(attached, a)
+		}
+	}
+
+	var content []mimePart
+	if m.Text != "" {
+		content = append(content, textPart("text/plain", m.Text))
+	}
+	if m.HTML != "" {
+		content = append(content, textPart("text/html", m.HTML))
+	}
+
+	body := content[0]
+	if len(content) > 1 {
+		body = multipartPart("alternative", content)
+	}
+
+	if len(inline) > 0 {
+		parts := []mimePart{body}
+		for _, a := range inline {
+			parts = append(parts, attachmentPart(a, "inline"))
+		}
+		body = multipartPart("related", parts)
+	}
+
+	if len(attached) > 0 {
+		parts := []mimePart{body}
+		for _, a := range attached {
+			parts = append(parts, attachmentPart(a, "attachment"))
+		}
+		body = multipartPart("mixed", parts)
+	}
+
+	var buf bytes.Buffer
+
+	writeHeader := func(key string, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	from, err := formatAddresses("From", []string{m.From})
+	if err != nil {
+		return nil, err
+	}
+	writeHeader("From", from)
+
+	for _, h := range []struct {
+		key       string
+		addresses []string
+	}{
+		{"To", m.To},
+		{"Cc", m.Cc},
+		{"Reply-To", m.ReplyTo},
+	} {
+		if len(h.addresses) == 0 {
+			continue
+		}
+
+		value, err := formatAddresses(h.key, h.addresses)
+		if err != nil {
+			return nil, err
+		}
+		writeHeader(h.key, value)
+	}
+
+	if err := checkHeader("Subject", m.Subject); err != nil {
+		return nil, err
+	}
+	writeHeader("Subject", mime.QEncoding.Encode("UTF-8", m.Subject))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("MIME-Version", "1.0")
+
+	// Sorted, so the same message always renders the same bytes
+	keys := make([]string, 0, len(m.Headers))
+	for key := range m.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := checkHeader(key, m.Headers[key]); err != nil {
+			return nil, err
+		}
+		writeHeader(key, mime.QEncoding.Encode("UTF-8", m.Headers[key]))
+	}
+
+	keys = keys[:0]
+	for key := range body.header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		writeHeader(key, body.header.Get(key))
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body.body)
+
+	return buf.Bytes(), nil
+}
+
+// checkHeader rejects header names that are not RFC 5322 field names
+// and values with line breaks, which would start headers of their own.
+func checkHeader(key string, value string) error {
+	if key == "" {
+		return fmt.Errorf("header name is empty")
+	}
+
+	for _, r := range key {
+		if r <= ' ' || r > '~' || r == ':' {
+			return fmt.Errorf("header name %q is invalid", key)
+		}
+	}
+
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("header %s contains a line break", key)
+	}
+
+	return nil
+}
+
+// formatAddresses parses the addresses of the header key and renders
+// them with non-ASCII display names encoded.
+func formatAddresses(key string, addresses []string) (string, error) {
+	formatted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if err := checkHeader(key, address); err != nil {
+			return "", err
+		}
+
+		parsed, err := mail.ParseAddress(address)
+		if err != nil {
+			return "", fmt.Errorf("header %s: %w", key, err)
+		}
+
+		formatted = append(formatted, parsed.String())
+	}
+
+	return strings.Join(formatted, ", "), nil
+}
+
+func textPart(contentType string, text string) mimePart {
+	var buf bytes.Buffer
+	w := quotedprintable.NewWriter(&buf)
+	w.Write([]byte(text))
+	w.Close()
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType+"; charset=UTF-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	return mimePart{header: header, body: buf.Bytes()}
+}
+
+func attachmentPart(a Attachment, disposition string) mimePart {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"name": a.Filename}))
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename}))
+	header.Set("Content-Transfer-Encoding", "base64")
+	if a.ContentID != "" {
+		header.Set("Content-ID", "<"+a.ContentID+">")
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+
+	// RFC 2045 limits encoded lines to 76 characters
+	var buf bytes.Buffer
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+
+	return mimePart{header: header, body: buf.Bytes()}
+}
+
+func multipartPart(subtype string, parts []mimePart) mimePart {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for _, p := range parts {
+		pw, _ := w.CreatePart(p.header)
+		pw.Write(p.body)
+	}
+	w.Close()
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", fmt.Sprintf("multipart/%s; boundary=%q", subtype, w.Boundary()))
+
+	return mimePart{header: header, body: buf.Bytes()}
+}
+
+// SendRawEmail builds msg and sends it as a raw MIME message. An empty
+// From uses the configured sender.
+func (c *SESConnector) SendRawEmail(ctx context.Context, msg *Message) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SESConnector", "SendRawEmail")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if msg.From == "" {
+		msg.From = c.fromAddress()
+	}
+
+	data, err := msg.Build()
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.client.SendEmail(ctx, &sesv2.SendEmailInput{
+		Destination: &types.Destination{
+			ToAddresses:  msg.To,
+			CcAddresses:  msg.Cc,
+			BccAddresses: msg.Bcc,
+		},
+		Content: &types.EmailContent{
+			Raw: &types.RawMessage{Data: data},
+		},
+		ConfigurationSetName: c.configurationSet(),
+	})
+	if err != nil {
+		c.logger.Error("Send raw email error", zap.Strings("to", msg.To), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.MessageId), nil
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/ses_connector (interfaces: Mailer)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . Mailer
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	ses_connector "github.com/elmntri/zeitgeber-aws-modules/ses_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMailer is a mock of Mailer interface.
+type MockMailer struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailerMockRecorder
+}
+
+// MockMailerMockRecorder is the mock recorder for MockMailer.
+type MockMailerMockRecorder struct {
+	mock *MockMailer
+}
+
+// NewMockMailer creates a new mock instance.
+func NewMockMailer(ctrl *gomock.Controller) *MockMailer {
+	mock := &MockMailer{ctrl: ctrl}
+	mock.recorder = &MockMailerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailer) EXPECT() *MockMailerMockRecorder {
+	return m.recorder
+}
+
+// SendBulkEmail mocks base method.
+func (m *MockMailer) SendBulkEmail(arg0 context.Context, arg1 string, arg2 map[string]any, arg3 []ses_connector.BulkRecipient) ([]ses_connector.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBulkEmail", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]ses_connector.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendBulkEmail indicates an expected call of SendBulkEmail.
+func (mr *MockMailerMockRecorder) SendBulkEmail(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBulkEmail", reflect.TypeOf((*MockMailer)(nil).SendBulkEmail), arg0, arg1, arg2, arg3)
+}
+
+// SendEmail mocks base method.
+func (m *MockMailer) SendEmail(arg0 context.Context, arg1 []string, arg2, arg3, arg4 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendEmail", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendEmail indicates an expected call of SendEmail.
+func (mr *MockMailerMockRecorder) SendEmail(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEmail", reflect.TypeOf((*MockMailer)(nil).SendEmail), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SendRawEmail mocks base method.
+func (m *MockMailer) SendRawEmail(arg0 context.Context, arg1 *ses_connector.Message) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendRawEmail", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendRawEmail indicates an expected call of SendRawEmail.
+func (mr *MockMailerMockRecorder) SendRawEmail(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRawEmail", reflect.TypeOf((*MockMailer)(nil).SendRawEmail), arg0, arg1)
+}
+
+// SendTemplatedEmail mocks base method.
+func (m *MockMailer) SendTemplatedEmail(arg0 context.Context, arg1 []string, arg2 string, arg3 map[string]any) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendTemplatedEmail", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendTemplatedEmail indicates an expected call of SendTemplatedEmail.
+func (mr *MockMailerMockRecorder) SendTemplatedEmail(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTemplatedEmail", reflect.TypeOf((*MockMailer)(nil).SendTemplatedEmail), arg0, arg1, arg2, arg3)
+}
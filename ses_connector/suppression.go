@@ -0,0 +1,137 @@
+package ses_connector
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrNotSuppressed = awserrors.New(awserrors.ErrNotFound, "address is not on the suppression list")
+
+type SuppressionReason = types.SuppressionListReason
+
+const (
+	SuppressionBounce    = types.SuppressionListReasonBounce
+	SuppressionComplaint = types.SuppressionListReasonComplaint
+)
+
+// SuppressedAddress is an entry of the account-level suppression list.
+type SuppressedAddress struct {
+	EmailAddress   string
+	Reason         SuppressionReason
+	LastUpdateTime time.Time
+}
+
+// GetSuppressedAddress returns the suppression entry for address, or
+// ErrNotSuppressed.
+func (c *SESConnector) GetSuppressedAddress(ctx context.Context, address string) (_ *SuppressedAddress, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SESConnector", "GetSuppressedAddress")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.GetSuppressedDestination(ctx, &sesv2.GetSuppressedDestinationInput{
+		EmailAddress: aws.String(address),
+	})
+	if err != nil {
+		var notFound *types.NotFoundException
+		if errors.As(err, &notFound) {
+			return nil, ErrNotSuppressed
+		}
+
+		c.logger.Error("Get suppressed destination error", zap.Error(err))
+		return nil, err
+	}
+
+	d := result.SuppressedDestination
+
+	return &SuppressedAddress{
+		EmailAddress:   aws.ToString(d.EmailAddress),
+		Reason:         d.Reason,
+		LastUpdateTime: aws.ToTime(d.LastUpdateTime),
+	}, nil
+}
+
+func (c *SESConnector) SuppressAddress(ctx context.Context, address string, reason SuppressionReason) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SESConnector", "SuppressAddress")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.PutSuppressedDestination(ctx, &sesv2.PutSuppressedDestinationInput{
+		EmailAddress: aws.String(address),
+		Reason:       reason,
+	})
+	if err != nil {
+		c.logger.Error("Put suppressed destination error", zap.String("reason", string(reason)), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// UnsuppressAddress removes address from the suppression list so it can
+// be mailed again. Removing an address that is not listed is not an
+// error.
+func (c *SESConnector) UnsuppressAddress(ctx context.Context, address string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SESConnector", "UnsuppressAddress")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.DeleteSuppressedDestination(ctx, &sesv2.DeleteSuppressedDestinationInput{
+		EmailAddress: aws.String(address),
+	})
+	if err != nil {
+		var notFound *types.NotFoundException
+		if errors.As(err, &notFound) {
+			return nil
+		}
+
+		c.logger.Error("Delete suppressed destination error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Removed address from suppression list")
+
+	return nil
+}
+
+// ListSuppressedAddresses pages through the suppression list. Empty
+// reasons match all reasons; zero times leave the range open.
+func (c *SESConnector) ListSuppressedAddresses(ctx context.Context, reasons []SuppressionReason, since time.Time, until time.Time) (_ []SuppressedAddress, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SESConnector", "ListSuppressedAddresses")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	input := &sesv2.ListSuppressedDestinationsInput{
+		Reasons: reasons,
+	}
+	if !since.IsZero() {
+		input.StartDate = aws.Time(since)
+	}
+	if !until.IsZero() {
+		input.EndDate = aws.Time(until)
+	}
+
+	var addresses []SuppressedAddress
+
+	paginator := sesv2.NewListSuppressedDestinationsPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("List suppressed destinations error", zap.Error(err))
+			return nil, err
+		}
+
+		for _, d := range page.SuppressedDestinationSummaries {
+			addresses = append(addresses, SuppressedAddress{
+				EmailAddress:   aws.ToString(d.EmailAddress),
+				Reason:         d.Reason,
+				LastUpdateTime: aws.ToTime(d.LastUpdateTime),
+			})
+		}
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,181 @@
+package ses_connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+const (
+	DefaultSyncTemplates = true
+)
+
+// Template is an SES email template. Subject, HTML and Text use SES
+// ({{name}}) placeholder syntax.
+type Template struct {
+	Name    string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// AddTemplates registers templates to be created or updated in SES when
+// the module starts. Call it before the fx app starts, e.g. from an
+// fx.Invoke.
+func (c *SESConnector) AddTemplates(templates ...Template) {
+	c.templates = append(c.templates, templates...)
+}
+
+// LoadTemplates reads templates from dir in fsys, typically an embed.FS.
+// Each template is a set of files sharing a base name: <name>.subject,
+// <name>.html and/or <name>.txt.
+func LoadTemplates(fsys fs.FS, dir string) ([]Template, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]*Template{}
+	var names []string
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := path.Ext(entry.Name())
+		name := strings.TrimSuffix(entry.Name(), ext)
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		t, ok := byName[name]
+		if !ok {
+			t = &Template{Name: name}
+			byName[name] = t
+			names = append(names, name)
+		}
+
+		switch ext {
+		case ".subject":
+			t.Subject = strings.TrimSpace(string(data))
+		case ".html":
+			t.HTML = string(data)
+		case ".txt":
+			t.Text = string(data)
+		}
+	}
+
+	templates := make([]Template, 0, len(names))
+	for _, name := range names {
+		templates = append(templates, *byName[name])
+	}
+
+	return templates, nil
+}
+
+// SyncTemplates creates missing templates and updates those whose
+// content differs from the given version.
+func (c *SESConnector) SyncTemplates(ctx context.Context, templates ...Template) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SESConnector", "SyncTemplates")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	for _, t := range templates {
+		content := &types.EmailTemplateContent{
+			Subject: aws.String(t.Subject),
+		}
+		if t.HTML != "" {
+			content.Html = aws.String(t.HTML)
+		}
+		if t.Text != "" {
+			content.Text = aws.String(t.Text)
+		}
+
+		existing, err := c.client.GetEmailTemplate(ctx, &sesv2.GetEmailTemplateInput{
+			TemplateName: aws.String(t.Name),
+		})
+		if err != nil {
+			var notFound *types.NotFoundException
+			if !errors.As(err, &notFound) {
+				c.logger.Error("Get email template error", zap.String("template", t.Name), zap.Error(err))
+				return err
+			}
+
+			c.logger.Info("Creating email template", zap.String("template", t.Name))
+
+			_, err = c.client.CreateEmailTemplate(ctx, &sesv2.CreateEmailTemplateInput{
+				TemplateName:    aws.String(t.Name),
+				TemplateContent: content,
+			})
+			if err != nil {
+				c.logger.Error("Create email template error", zap.String("template", t.Name), zap.Error(err))
+				return err
+			}
+
+			continue
+		}
+
+		current := existing.TemplateContent
+		if current != nil &&
+			aws.ToString(current.Subject) == t.Subject &&
+			aws.ToString(current.Html) == t.HTML &&
+			aws.ToString(current.Text) == t.Text {
+			continue
+		}
+
+		c.logger.Info("Updating email template", zap.String("template", t.Name))
+
+		_, err = c.client.UpdateEmailTemplate(ctx, &sesv2.UpdateEmailTemplateInput{
+			TemplateName:    aws.String(t.Name),
+			TemplateContent: content,
+		})
+		if err != nil {
+			c.logger.Error("Update email template error", zap.String("template", t.Name), zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendTemplatedEmail renders templateName in SES with data.
+func (c *SESConnector) SendTemplatedEmail(ctx context.Context, to []string, templateName string, data map[string]interface{}) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SESConnector", "SendTemplatedEmail")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	templateData, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.client.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(c.fromAddress()),
+		Destination: &types.Destination{
+			ToAddresses: to,
+		},
+		Content: &types.EmailContent{
+			Template: &types.Template{
+				TemplateName: aws.String(templateName),
+				TemplateData: aws.String(string(templateData)),
+			},
+		},
+		ConfigurationSetName: c.configurationSet(),
+	})
+	if err != nil {
+		c.logger.Error("Send templated email error", zap.String("template", templateName), zap.Strings("to", to), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.MessageId), nil
+}
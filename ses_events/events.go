@@ -0,0 +1,138 @@
+package ses_events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventBounce           EventType = "Bounce"
+	EventComplaint        EventType = "Complaint"
+	EventDelivery         EventType = "Delivery"
+	EventSend             EventType = "Send"
+	EventReject           EventType = "Reject"
+	EventOpen             EventType = "Open"
+	EventClick            EventType = "Click"
+	EventRenderingFailure EventType = "Rendering Failure"
+	EventDeliveryDelay    EventType = "DeliveryDelay"
+)
+
+const (
+	BounceTypePermanent    = "Permanent"
+	BounceTypeTransient    = "Transient"
+	BounceTypeUndetermined = "Undetermined"
+)
+
+// Event is an SES sending event. Configuration set event destinations
+// set EventType; identity notifications set NotificationType instead.
+type Event struct {
+	EventType        EventType  `json:"eventType"`
+	NotificationType EventType  `json:"notificationType"`
+	Mail             Mail       `json:"mail"`
+	Bounce           *Bounce    `json:"bounce,omitempty"`
+	Complaint        *Complaint `json:"complaint,omitempty"`
+	Delivery         *Delivery  `json:"delivery,omitempty"`
+}
+
+// Type returns the event type regardless of the notification format.
+func (e *Event) Type() EventType {
+	if e.EventType != "" {
+		return e.EventType
+	}
+
+	return e.NotificationType
+}
+
+type Mail struct {
+	Timestamp        time.Time           `json:"timestamp"`
+	MessageID        string              `json:"messageId"`
+	Source           string              `json:"source"`
+	SourceArn        string              `json:"sourceArn"`
+	SendingAccountID string              `json:"sendingAccountId"`
+	Destination      []string            `json:"destination"`
+	Tags             map[string][]string `json:"tags,omitempty"`
+}
+
+type Bounce struct {
+	BounceType        string             `json:"bounceType"`
+	BounceSubType     string             `json:"bounceSubType"`
+	BouncedRecipients []BouncedRecipient `json:"bouncedRecipients"`
+	Timestamp         time.Time          `json:"timestamp"`
+	FeedbackID        string             `json:"feedbackId"`
+	ReportingMTA      string             `json:"reportingMTA,omitempty"`
+	RemoteMtaIP       string             `json:"remoteMtaIp,omitempty"`
+}
+
+// Permanent reports a hard bounce; the recipients should not be mailed
+// again.
+func (b *Bounce) Permanent() bool {
+	return b.BounceType == BounceTypePermanent
+}
+
+type BouncedRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	Action         string `json:"action,omitempty"`
+	Status         string `json:"status,omitempty"`
+	DiagnosticCode string `json:"diagnosticCode,omitempty"`
+}
+
+type Complaint struct {
+	ComplainedRecipients  []ComplainedRecipient `json:"complainedRecipients"`
+	Timestamp             time.Time             `json:"timestamp"`
+	FeedbackID            string                `json:"feedbackId"`
+	ComplaintSubType      string                `json:"complaintSubType,omitempty"`
+	ComplaintFeedbackType string                `json:"complaintFeedbackType,omitempty"`
+	UserAgent             string                `json:"userAgent,omitempty"`
+	ArrivalDate           string                `json:"arrivalDate,omitempty"`
+}
+
+type ComplainedRecipient struct {
+	EmailAddress string `json:"emailAddress"`
+}
+
+type Delivery struct {
+	Timestamp            time.Time `json:"timestamp"`
+	ProcessingTimeMillis int64     `json:"processingTimeMillis"`
+	Recipients           []string  `json:"recipients"`
+	SMTPResponse         string    `json:"smtpResponse"`
+	ReportingMTA         string    `json:"reportingMTA,omitempty"`
+	RemoteMtaIP          string    `json:"remoteMtaIp,omitempty"`
+}
+
+// envelope covers the wrappers SES events arrive in: an SNS notification
+// (Message) or an EventBridge event (detail).
+type envelope struct {
+	Type    string          `json:"Type"`
+	Message string          `json:"Message"`
+	Detail  json.RawMessage `json:"detail"`
+}
+
+// ParseEvent decodes an SES event from an SQS message body, unwrapping
+// SNS and EventBridge envelopes.
+func ParseEvent(body []byte) (*Event, error) {
+	env := envelope{}
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case env.Type == "Notification" && env.Message != "":
+		body = []byte(env.Message)
+	case len(env.Detail) > 0:
+		body = env.Detail
+	}
+
+	event := &Event{}
+	if err := json.Unmarshal(body, event); err != nil {
+		return nil, err
+	}
+
+	if event.Type() == "" {
+		return nil, fmt.Errorf("not an SES event")
+	}
+
+	return event, nil
+}
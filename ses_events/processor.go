@@ -0,0 +1,216 @@
+package ses_events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/ses_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/sqs_connector"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultMaxMessages   = 10
+	DefaultWaitSeconds   = 20
+	DefaultRetryInterval = 5
+	DefaultSuppress      = false
+)
+
+// Handler processes an SES event. Returning an error leaves the message
+// on the queue so it is redelivered.
+type Handler func(ctx context.Context, event *Event) error
+
+type Processor struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	mu       sync.RWMutex
+	handlers map[EventType][]Handler
+	tracker  *inflight.Tracker
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+	SQS       *sqs_connector.SQSConnector
+	SES       *ses_connector.SESConnector `optional:"true"`
+}
+
+func Module(scope string) fx.Option {
+
+	var p *Processor
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(params Params) *Processor {
+
+			logger = requestid.Logger(params.Logger.Named(scope))
+
+			p := &Processor{
+				params:   params,
+				logger:   logger,
+				scope:    scope,
+				handlers: make(map[EventType][]Handler),
+			}
+
+			p.initDefaultConfigs()
+
+			return p
+		}),
+		fx.Populate(&p),
+		fx.Invoke(func(params Params) {
+
+			params.Lifecycle.Append(
+				fx.Hook{
+					OnStart: p.onStart,
+					OnStop:  p.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (p *Processor) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(p.scope, &p.config); err != nil {
+		p.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	suppress := p.config.Suppress
+
+	logger.Info("Starting SES event processor",
+		zap.Bool("suppress", suppress),
+	)
+
+	if suppress {
+		if p.params.SES == nil {
+			return fmt.Errorf("%s: suppress requires an ses_connector module", p.scope)
+		}
+
+		p.Handle(EventBounce, p.suppressBounce)
+		p.Handle(EventComplaint, p.suppressComplaint)
+	}
+
+	tracker, err := inflight.NewTracker(p.scope, p.logger)
+	if err != nil {
+		p.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+	p.tracker = tracker
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+
+	go p.receiveLoop(loopCtx)
+
+	return nil
+}
+
+func (p *Processor) onStop(ctx context.Context) error {
+
+	// Let the messages being handled finish, then interrupt the loop
+	p.tracker.Shutdown(ctx)
+
+	if p.cancel != nil {
+		p.cancel()
+		<-p.done
+	}
+
+	p.logger.Info("Stopped SES event processor")
+
+	return nil
+}
+
+// Handle registers a handler for events of the given type. Handlers run
+// in registration order.
+func (p *Processor) Handle(eventType EventType, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handlers[eventType] = append(p.handlers[eventType], handler)
+}
+
+func (p *Processor) receiveLoop(ctx context.Context) {
+	defer close(p.done)
+
+	p.params.SQS.Consume(ctx, sqs_connector.ConsumeOptions{
+		MaxMessages:   p.config.MaxMessages,
+		WaitSeconds:   p.config.WaitSeconds,
+		RetryInterval: time.Duration(p.config.RetryInterval) * time.Second,
+		Logger:        p.logger,
+		Tracker:       p.tracker,
+	}, p.process)
+}
+
+func (p *Processor) process(ctx context.Context, body string) error {
+	event, err := ParseEvent([]byte(body))
+	if err != nil {
+		return sqs_connector.Malformed(err)
+	}
+
+	p.mu.RLock()
+	handlers := p.handlers[event.Type()]
+	p.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Processor) suppressBounce(ctx context.Context, event *Event) error {
+	if event.Bounce == nil || !event.Bounce.Permanent() {
+		return nil
+	}
+
+	for _, r := range event.Bounce.BouncedRecipients {
+		if err := p.suppress(ctx, r.EmailAddress, ses_connector.SuppressionBounce); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Processor) suppressComplaint(ctx context.Context, event *Event) error {
+	if event.Complaint == nil {
+		return nil
+	}
+
+	for _, r := range event.Complaint.ComplainedRecipients {
+		if err := p.suppress(ctx, r.EmailAddress, ses_connector.SuppressionComplaint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Processor) suppress(ctx context.Context, address string, reason ses_connector.SuppressionReason) error {
+	if err := p.params.SES.SuppressAddress(ctx, address, reason); err != nil {
+		return err
+	}
+
+	p.logger.Info("Suppressed address", zap.String("reason", string(reason)))
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package sfn_activity
+
+import (
+	"os"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. SFN_ACTIVITY_WORKER_NAME for the
+// sfn_activity scope.
+type Config struct {
+	WorkerName        string `mapstructure:"worker_name"`
+	Concurrency       int    `mapstructure:"concurrency" default:"1"`
+	HeartbeatInterval int    `mapstructure:"heartbeat_interval" default:"0"`
+	RetryInterval     int    `mapstructure:"retry_interval" default:"5"`
+}
+
+func (w *Worker) initDefaultConfigs() {
+	hostname, _ := os.Hostname()
+
+	moduleconfig.Register(w.scope, &Config{
+		WorkerName: hostname,
+	})
+}
+
+// Config returns the configuration the module started with.
+func (w *Worker) Config() Config {
+	return w.config
+}
@@ -0,0 +1,315 @@
+package sfn_activity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	"github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/sfn_connector"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultConcurrency       = 1
+	DefaultHeartbeatInterval = 0
+	DefaultRetryInterval     = 5
+	DefaultErrorCode         = "TaskFailed"
+)
+
+// Handler runs one task and returns its output, encoded as JSON. Its ctx
+// is cancelled when Step Functions reports the task timed out.
+type Handler func(ctx context.Context, input json.RawMessage) (interface{}, error)
+
+// TaskError lets a handler choose the error code a state machine can
+// catch or retry on; other errors are reported with DefaultErrorCode.
+type TaskError struct {
+	Code  string
+	Cause string
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Cause)
+}
+
+// Worker polls activities for tasks. Each registered activity runs up to
+// concurrency tasks at a time.
+type Worker struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+	name   string
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+
+	tracker     *inflight.Tracker
+	cancel      context.CancelFunc
+	cancelTasks context.CancelFunc
+	pollers     sync.WaitGroup
+	tasks       sync.WaitGroup
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+	SFN       *sfn_connector.SFNConnector
+}
+
+func Module(scope string) fx.Option {
+
+	var w *Worker
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) *Worker {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			w := &Worker{
+				params:   p,
+				logger:   logger,
+				scope:    scope,
+				handlers: make(map[string]Handler),
+			}
+
+			w.initDefaultConfigs()
+
+			return w
+		}),
+		fx.Populate(&w),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: w.onStart,
+					OnStop:  w.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (w *Worker) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(w.scope, &w.config); err != nil {
+		w.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	w.name = w.config.WorkerName
+
+	w.mu.Lock()
+	handlers := make(map[string]Handler, len(w.handlers))
+	for activityArn, handler := range w.handlers {
+		handlers[activityArn] = handler
+	}
+	w.mu.Unlock()
+
+	logger.Info("Starting activity worker",
+		zap.String("worker_name", w.name),
+		zap.Int("activities", len(handlers)),
+		zap.Int("concurrency", w.config.Concurrency),
+	)
+
+	tracker, err := inflight.NewTracker(w.scope, w.logger)
+	if err != nil {
+		w.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+	w.tracker = tracker
+
+	pollCtx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	// Tasks outlive the pollers so they can finish during shutdown
+	taskCtx, cancelTasks := context.WithCancel(context.Background())
+	w.cancelTasks = cancelTasks
+
+	for activityArn, handler := range handlers {
+		w.pollers.Add(1)
+		go w.pollLoop(pollCtx, taskCtx, activityArn, handler)
+	}
+
+	return nil
+}
+
+// onStop stops polling and waits for running tasks until the shutdown
+// timeout or ctx is done, then cancels them. A poll cut short may leave
+// a task undelivered until its state times out.
+func (w *Worker) onStop(ctx context.Context) error {
+
+	if w.cancel != nil {
+		w.cancel()
+		w.pollers.Wait()
+
+		if abandoned := w.tracker.Shutdown(ctx); len(abandoned) > 0 {
+			w.logger.Warn("Cancelling running tasks", zap.Int("tasks", len(abandoned)))
+		}
+
+		w.cancelTasks()
+		w.tasks.Wait()
+	}
+
+	w.logger.Info("Stopped activity worker")
+
+	return nil
+}
+
+// Handle registers the handler of an activity. Register handlers before
+// the app starts.
+func (w *Worker) Handle(activityArn string, handler Handler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.handlers[activityArn] = handler
+}
+
+func (w *Worker) pollLoop(ctx context.Context, taskCtx context.Context, activityArn string, handler Handler) {
+	defer w.pollers.Done()
+
+	slots := make(chan struct{}, max(w.config.Concurrency, 1))
+	retryInterval := time.Duration(w.config.RetryInterval) * time.Second
+	client := w.params.SFN.GetClient()
+
+	for {
+		// Only poll when a task could be run right away
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		task, err := client.GetActivityTask(ctx, &sfn.GetActivityTaskInput{
+			ActivityArn: aws.String(activityArn),
+			WorkerName:  aws.String(w.name),
+		})
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				return
+			}
+
+			w.logger.Error("Get activity task error", zap.String("activity_arn", activityArn), zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+
+			continue
+		}
+
+		// The long poll ended without a task
+		if task.TaskToken == nil {
+			<-slots
+			continue
+		}
+
+		// The task is ours now, so it is tracked even while stopping
+		done, _ := w.tracker.Begin(inflight.Internal(taskCtx), "task "+activityArn)
+
+		w.tasks.Add(1)
+		go func() {
+			defer w.tasks.Done()
+			defer func() { <-slots }()
+			defer done()
+
+			w.run(taskCtx, activityArn, handler, aws.ToString(task.TaskToken), aws.ToString(task.Input))
+		}()
+	}
+}
+
+func (w *Worker) run(ctx context.Context, activityArn string, handler Handler, token string, input string) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if interval := w.config.HeartbeatInterval; interval > 0 {
+		go w.heartbeat(ctx, cancel, token, time.Duration(interval)*time.Second)
+	}
+
+	client := w.params.SFN.GetClient()
+
+	output, err := handler(ctx, json.RawMessage(input))
+
+	// Report the result even when the task ctx was cancelled
+	sendCtx := context.WithoutCancel(ctx)
+
+	if err == nil {
+		var data []byte
+		if data, err = json.Marshal(output); err == nil {
+			_, err = client.SendTaskSuccess(sendCtx, &sfn.SendTaskSuccessInput{
+				TaskToken: aws.String(token),
+				Output:    aws.String(string(data)),
+			})
+			if err != nil {
+				w.logger.Error("Send task success error", zap.String("activity_arn", activityArn), zap.Error(err))
+			}
+			return
+		}
+	}
+
+	code, cause := DefaultErrorCode, err.Error()
+	var taskErr *TaskError
+	if errors.As(err, &taskErr) {
+		code, cause = taskErr.Code, taskErr.Cause
+	}
+
+	w.logger.Warn("Activity task failed", zap.String("activity_arn", activityArn), zap.String("code", code), zap.Error(err))
+
+	_, err = client.SendTaskFailure(sendCtx, &sfn.SendTaskFailureInput{
+		TaskToken: aws.String(token),
+		Error:     aws.String(code),
+		Cause:     aws.String(cause),
+	})
+	if err != nil {
+		w.logger.Error("Send task failure error", zap.String("activity_arn", activityArn), zap.Error(err))
+	}
+}
+
+// heartbeat keeps the task alive and cancels it once Step Functions no
+// longer accepts heartbeats for it.
+func (w *Worker) heartbeat(ctx context.Context, cancel context.CancelFunc, token string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, err := w.params.SFN.GetClient().SendTaskHeartbeat(ctx, &sfn.SendTaskHeartbeatInput{
+			TaskToken: aws.String(token),
+		})
+		if err == nil || ctx.Err() != nil {
+			continue
+		}
+
+		var timedOut *types.TaskTimedOut
+		var missing *types.TaskDoesNotExist
+		if errors.As(err, &timedOut) || errors.As(err, &missing) {
+			w.logger.Warn("Activity task expired", zap.Error(err))
+			cancel()
+			return
+		}
+
+		w.logger.Error("Send task heartbeat error", zap.Error(err))
+	}
+}
@@ -0,0 +1,29 @@
+package sfn_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. SFN_STATE_MACHINE_ARN for the sfn
+// scope.
+type Config struct {
+	StateMachineARN   string `mapstructure:"state_machine_arn" default:""`
+	PollInterval      int    `mapstructure:"poll_interval" default:"1"`
+	MaxPollInterval   int    `mapstructure:"max_poll_interval" default:"30"`
+	SFNKey            string `mapstructure:"sfn_key" default:"ABCDE"`
+	SFNSecret         string `mapstructure:"sfn_secret" default:"example_secret"`
+	SFNToken          string `mapstructure:"sfn_token" default:""`
+	SFNRegion         string `mapstructure:"sfn_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *SFNConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *SFNConnector) Config() Config {
+	return c.config
+}
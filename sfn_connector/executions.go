@@ -0,0 +1,220 @@
+package sfn_connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	"github.com/aws/aws-sdk-go-v2/service/sfn/types"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var ErrExecutionFailed = awserrors.New(awserrors.ErrTransient, "execution failed")
+
+// ExecutionError is returned for executions that did not succeed; it
+// matches ErrExecutionFailed with errors.Is.
+type ExecutionError struct {
+	ExecutionArn string
+	Status       string
+	Err          string
+	Cause        string
+}
+
+func (e *ExecutionError) Error() string {
+	return fmt.Sprintf("%s: %s: %s: %s", e.ExecutionArn, e.Status, e.Err, e.Cause)
+}
+
+func (e *ExecutionError) Unwrap() error {
+	return ErrExecutionFailed
+}
+
+// StartExecution starts the configured state machine with input encoded
+// as JSON and returns the execution ARN. An empty name lets Step
+// Functions generate one; a name makes the start idempotent.
+func (c *SFNConnector) StartExecution(ctx context.Context, name string, input interface{}) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SFNConnector", "StartExecution")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.StartStateMachine(ctx, c.config.StateMachineARN, name, input)
+}
+
+// StartStateMachine is StartExecution for another state machine.
+func (c *SFNConnector) StartStateMachine(ctx context.Context, stateMachineArn string, name string, input interface{}) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SFNConnector", "StartStateMachine")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	data, err := marshalInput(input)
+	if err != nil {
+		return "", err
+	}
+
+	params := &sfn.StartExecutionInput{
+		StateMachineArn: aws.String(stateMachineArn),
+		Input:           aws.String(data),
+	}
+	if name != "" {
+		params.Name = aws.String(name)
+	}
+
+	result, err := c.client.StartExecution(ctx, params)
+	if err != nil {
+		c.logger.Error("Start execution error", zap.String("state_machine_arn", stateMachineArn), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.ExecutionArn), nil
+}
+
+// StartSyncExecution runs an express state machine to completion and
+// decodes its output into out, which may be nil.
+func (c *SFNConnector) StartSyncExecution(ctx context.Context, name string, input interface{}, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SFNConnector", "StartSyncExecution")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	data, err := marshalInput(input)
+	if err != nil {
+		return err
+	}
+
+	stateMachineArn := c.config.StateMachineARN
+
+	params := &sfn.StartSyncExecutionInput{
+		StateMachineArn: aws.String(stateMachineArn),
+		Input:           aws.String(data),
+	}
+	if name != "" {
+		params.Name = aws.String(name)
+	}
+
+	result, err := c.client.StartSyncExecution(ctx, params)
+	if err != nil {
+		c.logger.Error("Start sync execution error", zap.String("state_machine_arn", stateMachineArn), zap.Error(err))
+		return err
+	}
+
+	if result.Status != types.SyncExecutionStatusSucceeded {
+		return &ExecutionError{
+			ExecutionArn: aws.ToString(result.ExecutionArn),
+			Status:       string(result.Status),
+			Err:          aws.ToString(result.Error),
+			Cause:        aws.ToString(result.Cause),
+		}
+	}
+
+	return unmarshalOutput(result.Output, out)
+}
+
+func (c *SFNConnector) DescribeExecution(ctx context.Context, executionArn string) (_ *sfn.DescribeExecutionOutput, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SFNConnector", "DescribeExecution")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.DescribeExecution(ctx, &sfn.DescribeExecutionInput{
+		ExecutionArn: aws.String(executionArn),
+	})
+	if err != nil {
+		c.logger.Error("Describe execution error", zap.String("execution_arn", executionArn), zap.Error(err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// StopExecution aborts a running execution with an optional error code
+// and cause.
+func (c *SFNConnector) StopExecution(ctx context.Context, executionArn string, code string, cause string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SFNConnector", "StopExecution")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	params := &sfn.StopExecutionInput{
+		ExecutionArn: aws.String(executionArn),
+	}
+	if code != "" {
+		params.Error = aws.String(code)
+	}
+	if cause != "" {
+		params.Cause = aws.String(cause)
+	}
+
+	_, err = c.client.StopExecution(ctx, params)
+	if err != nil {
+		c.logger.Error("Stop execution error", zap.String("execution_arn", executionArn), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// WaitForCompletion polls the execution until it ends, starting at
+// poll_interval and doubling up to max_poll_interval, and decodes the
+// output of a successful execution into out. Bound the wait with ctx.
+func (c *SFNConnector) WaitForCompletion(ctx context.Context, executionArn string, out interface{}) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SFNConnector", "WaitForCompletion")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Second
+	maxInterval := time.Duration(c.config.MaxPollInterval) * time.Second
+
+	for {
+		result, err := c.DescribeExecution(ctx, executionArn)
+		if err != nil {
+			return err
+		}
+
+		switch result.Status {
+		case types.ExecutionStatusSucceeded:
+			return unmarshalOutput(result.Output, out)
+
+		case types.ExecutionStatusFailed, types.ExecutionStatusTimedOut, types.ExecutionStatusAborted:
+			return &ExecutionError{
+				ExecutionArn: executionArn,
+				Status:       string(result.Status),
+				Err:          aws.ToString(result.Error),
+				Cause:        aws.ToString(result.Cause),
+			}
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		interval = min(interval*2, maxInterval)
+	}
+}
+
+// marshalInput passes strings and []byte through as JSON documents and
+// encodes anything else; nil becomes an empty object.
+func marshalInput(input interface{}) (string, error) {
+	switch in := input.(type) {
+	case nil:
+		return "{}", nil
+	case string:
+		return in, nil
+	case []byte:
+		return string(in), nil
+	case json.RawMessage:
+		return string(in), nil
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func unmarshalOutput(output *string, out interface{}) error {
+	if out == nil || output == nil {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(*output), out)
+}
@@ -0,0 +1,20 @@
+package sfn_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . WorkflowRunner
+
+// WorkflowRunner starts and tracks executions of the state machine.
+type WorkflowRunner interface {
+	StartExecution(ctx context.Context, name string, input interface{}) (string, error)
+	StartSyncExecution(ctx context.Context, name string, input interface{}, out interface{}) error
+	DescribeExecution(ctx context.Context, executionArn string) (*sfn.DescribeExecutionOutput, error)
+	StopExecution(ctx context.Context, executionArn string, code string, cause string) error
+	WaitForCompletion(ctx context.Context, executionArn string, out interface{}) error
+}
+
+var _ WorkflowRunner = (*SFNConnector)(nil)
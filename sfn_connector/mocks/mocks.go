@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/sfn_connector (interfaces: WorkflowRunner)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . WorkflowRunner
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sfn "github.com/aws/aws-sdk-go-v2/service/sfn"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWorkflowRunner is a mock of WorkflowRunner interface.
+type MockWorkflowRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkflowRunnerMockRecorder
+}
+
+// MockWorkflowRunnerMockRecorder is the mock recorder for MockWorkflowRunner.
+type MockWorkflowRunnerMockRecorder struct {
+	mock *MockWorkflowRunner
+}
+
+// NewMockWorkflowRunner creates a new mock instance.
+func NewMockWorkflowRunner(ctrl *gomock.Controller) *MockWorkflowRunner {
+	mock := &MockWorkflowRunner{ctrl: ctrl}
+	mock.recorder = &MockWorkflowRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkflowRunner) EXPECT() *MockWorkflowRunnerMockRecorder {
+	return m.recorder
+}
+
+// DescribeExecution mocks base method.
+func (m *MockWorkflowRunner) DescribeExecution(arg0 context.Context, arg1 string) (*sfn.DescribeExecutionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeExecution", arg0, arg1)
+	ret0, _ := ret[0].(*sfn.DescribeExecutionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeExecution indicates an expected call of DescribeExecution.
+func (mr *MockWorkflowRunnerMockRecorder) DescribeExecution(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeExecution", reflect.TypeOf((*MockWorkflowRunner)(nil).DescribeExecution), arg0, arg1)
+}
+
+// StartExecution mocks base method.
+func (m *MockWorkflowRunner) StartExecution(arg0 context.Context, arg1 string, arg2 any) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartExecution", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartExecution indicates an expected call of StartExecution.
+func (mr *MockWorkflowRunnerMockRecorder) StartExecution(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartExecution", reflect.TypeOf((*MockWorkflowRunner)(nil).StartExecution), arg0, arg1, arg2)
+}
+
+// StartSyncExecution mocks base method.
+func (m *MockWorkflowRunner) StartSyncExecution(arg0 context.Context, arg1 string, arg2, arg3 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSyncExecution", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartSyncExecution indicates an expected call of StartSyncExecution.
+func (mr *MockWorkflowRunnerMockRecorder) StartSyncExecution(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSyncExecution", reflect.TypeOf((*MockWorkflowRunner)(nil).StartSyncExecution), arg0, arg1, arg2, arg3)
+}
+
+// StopExecution mocks base method.
+func (m *MockWorkflowRunner) StopExecution(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopExecution", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopExecution indicates an expected call of StopExecution.
+func (mr *MockWorkflowRunnerMockRecorder) StopExecution(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopExecution", reflect.TypeOf((*MockWorkflowRunner)(nil).StopExecution), arg0, arg1, arg2, arg3)
+}
+
+// WaitForCompletion mocks base method.
+func (m *MockWorkflowRunner) WaitForCompletion(arg0 context.Context, arg1 string, arg2 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForCompletion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForCompletion indicates an expected call of WaitForCompletion.
+func (mr *MockWorkflowRunnerMockRecorder) WaitForCompletion(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForCompletion", reflect.TypeOf((*MockWorkflowRunner)(nil).WaitForCompletion), arg0, arg1, arg2)
+}
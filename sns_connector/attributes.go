@@ -0,0 +1,102 @@
+package sns_connector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+// Attributes is a message attribute map usable in subscription filter
+// policies. The chainable setters pick the matching SNS data type.
+type Attributes map[string]types.MessageAttributeValue
+
+func NewAttributes() Attributes {
+	return Attributes{}
+}
+
+func (a Attributes) String(name string, value string) Attributes {
+	a[name] = types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+	return a
+}
+
+func (a Attributes) Int(name string, value int64) Attributes {
+	a[name] = types.MessageAttributeValue{
+		DataType:    aws.String("Number"),
+		StringValue: aws.String(strconv.FormatInt(value, 10)),
+	}
+	return a
+}
+
+func (a Attributes) Float(name string, value float64) Attributes {
+	a[name] = types.MessageAttributeValue{
+		DataType:    aws.String("Number"),
+		StringValue: aws.String(strconv.FormatFloat(value, 'f', -1, 64)),
+	}
+	return a
+}
+
+// Bool is stored as a String attribute since SNS has no boolean type.
+func (a Attributes) Bool(name string, value bool) Attributes {
+	return a.String(name, strconv.FormatBool(value))
+}
+
+func (a Attributes) StringArray(name string, values []string) Attributes {
+	data, _ := json.Marshal(values)
+	a[name] = types.MessageAttributeValue{
+		DataType:    aws.String("String.Array"),
+		StringValue: aws.String(string(data)),
+	}
+	return a
+}
+
+func (a Attributes) Binary(name string, value []byte) Attributes {
+	a[name] = types.MessageAttributeValue{
+		DataType:    aws.String("Binary"),
+		BinaryValue: value,
+	}
+	return a
+}
+
+// AttributesFrom builds an attribute map from plain Go values.
+func AttributesFrom(values map[string]interface{}) (Attributes, error) {
+	a := NewAttributes()
+
+	for name, value := range values {
+		switch v := value.(type) {
+		case string:
+			a.String(name, v)
+		case bool:
+			a.Bool(name, v)
+		case int:
+			a.Int(name, int64(v))
+		case int32:
+			a.Int(name, int64(v))
+		case int64:
+			a.Int(name, v)
+		case uint:
+			a.Int(name, int64(v))
+		case uint32:
+			a.Int(name, int64(v))
+		case float32:
+			a.Float(name, float64(v))
+		case float64:
+			a.Float(name, v)
+		case []string:
+			a.StringArray(name, v)
+		case []byte:
+			a.Binary(name, v)
+		case fmt.Stringer:
+			a.String(name, v.String())
+		default:
+			return nil, fmt.Errorf("unsupported attribute type %T for %q", value, name)
+		}
+	}
+
+	return a, nil
+}
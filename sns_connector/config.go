@@ -0,0 +1,35 @@
+package sns_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. SNS_TOPIC_ARN for the sns scope.
+type Config struct {
+	TopicARN          string   `mapstructure:"topic_arn" default:""`
+	TopicKey          string   `mapstructure:"topic_key" default:"ABCDE"`
+	TopicSecret       string   `mapstructure:"topic_secret" default:"example_secret"`
+	TopicToken        string   `mapstructure:"topic_token" default:""`
+	TopicRegion       string   `mapstructure:"topic_region" default:"us-west-1"`
+	MessageGroupID    string   `mapstructure:"message_group_id" default:""`
+	VerifyTopicARNs   []string `mapstructure:"verify_topic_arns" default:""`
+	VerifyCredentials bool     `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool     `mapstructure:"preflight" default:"false"`
+
+	SMSType                          string `mapstructure:"sms_type" default:"Transactional"`
+	SMSSenderID                      string `mapstructure:"sms_sender_id" default:""`
+	SMSMaxPrice                      string `mapstructure:"sms_max_price" default:""`
+	SMSMonthlySpendLimit             string `mapstructure:"sms_monthly_spend_limit" default:""`
+	SMSDeliveryStatusRoleARN         string `mapstructure:"sms_delivery_status_role_arn" default:""`
+	SMSDeliveryStatusSuccessSampling int    `mapstructure:"sms_delivery_status_success_sampling" default:"100"`
+}
+
+func (c *SNSConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *SNSConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,365 @@
+package sns_connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultTopicArn    = ""
+	DefaultTopicKey    = "ABCDE"
+	DefaultTopicSecret = "example_secret"
+	DefaultTopicToken  = ""
+	DefaultTopicRegion = "us-west-1"
+
+	DefaultMessageGroupID = ""
+)
+
+var preflightActions = []string{
+	"sns:Publish",
+}
+
+type SNSConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *sns.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	verifier *Verifier
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *sns.Client             `optional:"true"`
+}
+
+// Module provides the SNSConnector of scope. It uses the *sns.Client in
+// the graph, if any, instead of building one, and adds the connector's
+// middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the SNSConnector of scope, and its interfaces,
+// named after scope, for apps with more than one; see package instance.
+// It uses the *sns.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *SNSConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*sns.Client](scope, named),
+		instance.Provide[*SNSConnector](scope, named, func(p Params) *SNSConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &SNSConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *SNSConnector) Publisher {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *SNSConnector) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting SNSConnector",
+		zap.String("topic_arn", c.config.TopicARN),
+		zap.String("topic_region", c.config.TopicRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = sns.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = sns.New(c.client.Options(), func(o *sns.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+	c.verifier = NewVerifier(append(
+		c.config.VerifyTopicARNs,
+		c.config.TopicARN,
+	)...)
+
+	if err := c.applySMSAttributes(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *SNSConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped SNSConnector")
+
+	return nil
+}
+
+func (c *SNSConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.TopicRegion,
+		Key:         c.config.TopicKey,
+		Secret:      c.config.TopicSecret,
+		Token:       c.config.TopicToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *SNSConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("topic_region")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("topic_key", DefaultTopicKey)
+		v.NotPlaceholder("topic_secret", DefaultTopicSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the topic, or lists topics when none is configured.
+func (c *SNSConnector) probe(ctx context.Context) error {
+	if arn := c.config.TopicARN; arn != "" {
+		_, err := c.client.GetTopicAttributes(ctx, &sns.GetTopicAttributesInput{
+			TopicArn: aws.String(arn),
+		})
+
+		return err
+	}
+
+	_, err := c.client.ListTopics(ctx, &sns.ListTopicsInput{})
+
+	return err
+}
+
+type PublishOptions struct {
+	Subject    string
+	Attributes Attributes
+
+	// MessageGroupID and DeduplicationID apply to FIFO topics only.
+	MessageGroupID  string
+	DeduplicationID string
+}
+
+// Publish sends message to topicArn, falling back to the configured
+// topic_arn when topicArn is empty. attrs are sent as String attributes.
+func (c *SNSConnector) Publish(ctx context.Context, topicArn string, message string, attrs map[string]string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SNSConnector", "Publish")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	attributes := NewAttributes()
+	for k, v := range attrs {
+		attributes.String(k, v)
+	}
+
+	return c.PublishWithOptions(ctx, topicArn, message, PublishOptions{
+		Attributes: attributes,
+	})
+}
+
+// PublishJSON marshals v to JSON and publishes it like Publish.
+func (c *SNSConnector) PublishJSON(ctx context.Context, topicArn string, v interface{}, attrs map[string]string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SNSConnector", "PublishJSON")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Publish(ctx, topicArn, string(data), attrs)
+}
+
+// PublishJSONWithOptions marshals v to JSON and publishes it like
+// PublishWithOptions.
+func (c *SNSConnector) PublishJSONWithOptions(ctx context.Context, topicArn string, v interface{}, opts PublishOptions) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SNSConnector", "PublishJSONWithOptions")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return c.PublishWithOptions(ctx, topicArn, string(data), opts)
+}
+
+func (c *SNSConnector) PublishWithOptions(ctx context.Context, topicArn string, message string, opts PublishOptions) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SNSConnector", "PublishWithOptions")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	topicArn, err = c.resolveTopicArn(topicArn)
+	if err != nil {
+		return "", err
+	}
+
+	input := &sns.PublishInput{
+		TopicArn: aws.String(topicArn),
+		Message:  aws.String(message),
+	}
+
+	if opts.Subject != "" {
+		input.Subject = aws.String(opts.Subject)
+	}
+
+	if len(opts.Attributes) > 0 {
+		input.MessageAttributes = opts.Attributes
+	}
+
+	if strings.HasSuffix(topicArn, ".fifo") {
+		groupID := opts.MessageGroupID
+		if groupID == "" {
+			groupID = c.config.MessageGroupID
+		}
+
+		if groupID == "" {
+			return "", fmt.Errorf("%s: FIFO topic %s requires a message group ID", c.scope, topicArn)
+		}
+
+		input.MessageGroupId = aws.String(groupID)
+
+		if opts.DeduplicationID != "" {
+			input.MessageDeduplicationId = aws.String(opts.DeduplicationID)
+		}
+	}
+
+	result, err := c.client.Publish(ctx, input)
+	if err != nil {
+		c.logger.Error("Publish to SNS error", zap.String("topic_arn", topicArn), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.MessageId), nil
+}
+
+func (c *SNSConnector) GetClient() *sns.Client {
+	return c.client
+}
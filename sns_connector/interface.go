@@ -0,0 +1,18 @@
+package sns_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Publisher
+
+// Publisher publishes messages to topics.
+type Publisher interface {
+	Publish(ctx context.Context, topicArn string, message string, attrs map[string]string) (string, error)
+	PublishJSON(ctx context.Context, topicArn string, v interface{}, attrs map[string]string) (string, error)
+	PublishWithOptions(ctx context.Context, topicArn string, message string, opts PublishOptions) (string, error)
+	PublishJSONWithOptions(ctx context.Context, topicArn string, v interface{}, opts PublishOptions) (string, error)
+}
+
+var (
+	_ Publisher = (*SNSConnector)(nil)
+	_ Publisher = (*MemoryPublisher)(nil)
+)
@@ -0,0 +1,80 @@
+package sns_connector
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+)
+
+// PublishedMessage is a message recorded by MemoryPublisher.
+type PublishedMessage struct {
+	MessageID string
+	TopicArn  string
+	Message   string
+	Options   PublishOptions
+}
+
+// MemoryPublisher is an in-process Publisher for tests. It records what
+// would have been published.
+type MemoryPublisher struct {
+	mu        sync.Mutex
+	published []PublishedMessage
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, topicArn string, message string, attrs map[string]string) (string, error) {
+	attributes := NewAttributes()
+	for k, v := range attrs {
+		attributes.String(k, v)
+	}
+
+	return p.PublishWithOptions(ctx, topicArn, message, PublishOptions{
+		Attributes: attributes,
+	})
+}
+
+func (p *MemoryPublisher) PublishJSON(ctx context.Context, topicArn string, v interface{}, attrs map[string]string) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return p.Publish(ctx, topicArn, string(data), attrs)
+}
+
+func (p *MemoryPublisher) PublishJSONWithOptions(ctx context.Context, topicArn string, v interface{}, opts PublishOptions) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return p.PublishWithOptions(ctx, topicArn, string(data), opts)
+}
+
+func (p *MemoryPublisher) PublishWithOptions(ctx context.Context, topicArn string, message string, opts PublishOptions) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := strconv.Itoa(len(p.published) + 1)
+	p.published = append(p.published, PublishedMessage{
+		MessageID: id,
+		TopicArn:  topicArn,
+		Message:   message,
+		Options:   opts,
+	})
+
+	return id, nil
+}
+
+// Published returns the recorded messages in the order they were
+// published.
+func (p *MemoryPublisher) Published() []PublishedMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]PublishedMessage(nil), p.published...)
+}
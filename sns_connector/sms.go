@@ -0,0 +1,118 @@
+package sns_connector
+
+import (
+	"context"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+type SMSType string
+
+const (
+	SMSTypeTransactional SMSType = "Transactional"
+	SMSTypePromotional   SMSType = "Promotional"
+)
+
+const (
+	DefaultSMSType                          = string(SMSTypeTransactional)
+	DefaultSMSSenderID                      = ""
+	DefaultSMSMaxPrice                      = ""
+	DefaultSMSMonthlySpendLimit             = ""
+	DefaultSMSDeliveryStatusRoleArn         = ""
+	DefaultSMSDeliveryStatusSuccessSampling = 100
+)
+
+// applySMSAttributes pushes the account-level spend limit and delivery
+// status logging settings to SNS when they are configured.
+func (c *SNSConnector) applySMSAttributes(ctx context.Context) error {
+	attributes := map[string]string{}
+
+	if limit := c.config.SMSMonthlySpendLimit; limit != "" {
+		attributes["MonthlySpendLimit"] = limit
+	}
+
+	if roleArn := c.config.SMSDeliveryStatusRoleARN; roleArn != "" {
+		attributes["DeliveryStatusIAMRole"] = roleArn
+		attributes["DeliveryStatusSuccessSamplingRate"] = strconv.Itoa(c.config.SMSDeliveryStatusSuccessSampling)
+	}
+
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	_, err := c.client.SetSMSAttributes(ctx, &sns.SetSMSAttributesInput{
+		Attributes: attributes,
+	})
+	if err != nil {
+		c.logger.Error("Set SMS attributes error", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// SendSMS sends message directly to phoneNumber (E.164). An empty smsType
+// uses the configured sms_type.
+func (c *SNSConnector) SendSMS(ctx context.Context, phoneNumber string, message string, smsType SMSType) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SNSConnector", "SendSMS")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if smsType == "" {
+		smsType = SMSType(c.config.SMSType)
+	}
+
+	attributes := NewAttributes().String("AWS.SNS.SMS.SMSType", string(smsType))
+
+	if senderID := c.config.SMSSenderID; senderID != "" {
+		attributes.String("AWS.SNS.SMS.SenderID", senderID)
+	}
+
+	if maxPrice := c.config.SMSMaxPrice; maxPrice != "" {
+		attributes["AWS.SNS.SMS.MaxPrice"] = types.MessageAttributeValue{
+			DataType:    aws.String("Number"),
+			StringValue: aws.String(maxPrice),
+		}
+	}
+
+	result, err := c.client.Publish(ctx, &sns.PublishInput{
+		PhoneNumber:       aws.String(phoneNumber),
+		Message:           aws.String(message),
+		MessageAttributes: attributes,
+	})
+	if err != nil {
+		c.logger.Error("Send SMS error",
+			zap.String("phone_number", maskPhoneNumber(phoneNumber)),
+			zap.String("sms_type", string(smsType)),
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	c.logger.Info("SMS sent",
+		zap.String("phone_number", maskPhoneNumber(phoneNumber)),
+		zap.String("sms_type", string(smsType)),
+		zap.String("message_id", aws.ToString(result.MessageId)),
+	)
+
+	return aws.ToString(result.MessageId), nil
+}
+
+func maskPhoneNumber(phoneNumber string) string {
+	if len(phoneNumber) <= 4 {
+		return phoneNumber
+	}
+
+	masked := []byte(phoneNumber)
+	for i := 0; i < len(masked)-4; i++ {
+		if masked[i] >= '0' && masked[i] <= '9' {
+			masked[i] = '*'
+		}
+	}
+
+	return string(masked)
+}
@@ -0,0 +1,161 @@
+package sns_connector
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+const (
+	MessageTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	MessageTypeNotification             = "Notification"
+	MessageTypeUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// Message is the JSON document SNS POSTs to HTTP/HTTPS subscribers.
+type Message struct {
+	Type              string                      `json:"Type"`
+	MessageId         string                      `json:"MessageId"`
+	Token             string                      `json:"Token,omitempty"`
+	TopicArn          string                      `json:"TopicArn"`
+	Subject           string                      `json:"Subject,omitempty"`
+	Message           string                      `json:"Message"`
+	Timestamp         string                      `json:"Timestamp"`
+	SignatureVersion  string                      `json:"SignatureVersion"`
+	Signature         string                      `json:"Signature"`
+	SigningCertURL    string                      `json:"SigningCertURL"`
+	SubscribeURL      string                      `json:"SubscribeURL,omitempty"`
+	UnsubscribeURL    string                      `json:"UnsubscribeURL,omitempty"`
+	MessageAttributes map[string]MessageAttribute `json:"MessageAttributes,omitempty"`
+}
+
+type MessageAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+type NotificationHandler func(ctx context.Context, msg *Message) error
+
+func (c *SNSConnector) resolveTopicArn(topicArn string) (string, error) {
+	if topicArn == "" {
+		topicArn = c.config.TopicARN
+	}
+
+	if topicArn == "" {
+		return "", fmt.Errorf("%s: no topic ARN given and topic_arn is not configured", c.scope)
+	}
+
+	return topicArn, nil
+}
+
+// Subscribe subscribes endpoint to topicArn. HTTP(S) subscriptions return
+// "pending confirmation" until the endpoint confirms them.
+func (c *SNSConnector) Subscribe(ctx context.Context, topicArn string, protocol string, endpoint string, attributes map[string]string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SNSConnector", "Subscribe")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	topicArn, err = c.resolveTopicArn(topicArn)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.client.Subscribe(ctx, &sns.SubscribeInput{
+		TopicArn:              aws.String(topicArn),
+		Protocol:              aws.String(protocol),
+		Endpoint:              aws.String(endpoint),
+		Attributes:            attributes,
+		ReturnSubscriptionArn: true,
+	})
+	if err != nil {
+		c.logger.Error("Subscribe to SNS topic error", zap.String("topic_arn", topicArn), zap.Error(err))
+		return "", err
+	}
+
+	c.logger.Info("Subscribed to SNS topic",
+		zap.String("topic_arn", topicArn),
+		zap.String("protocol", protocol),
+		zap.String("endpoint", endpoint),
+	)
+
+	return aws.ToString(result.SubscriptionArn), nil
+}
+
+func (c *SNSConnector) Unsubscribe(ctx context.Context, subscriptionArn string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SNSConnector", "Unsubscribe")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.Unsubscribe(ctx, &sns.UnsubscribeInput{
+		SubscriptionArn: aws.String(subscriptionArn),
+	})
+	if err != nil {
+		c.logger.Error("Unsubscribe from SNS topic error", zap.String("subscription_arn", subscriptionArn), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ListSubscriptions returns every subscription of topicArn (or the
+// configured topic_arn when empty).
+func (c *SNSConnector) ListSubscriptions(ctx context.Context, topicArn string) (_ []types.Subscription, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SNSConnector", "ListSubscriptions")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	topicArn, err = c.resolveTopicArn(topicArn)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscriptions []types.Subscription
+
+	paginator := sns.NewListSubscriptionsByTopicPaginator(c.client, &sns.ListSubscriptionsByTopicInput{
+		TopicArn: aws.String(topicArn),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, page.Subscriptions...)
+	}
+
+	return subscriptions, nil
+}
+
+// ConfirmSubscription confirms a SubscriptionConfirmation message through
+// the API (rather than fetching SubscribeURL) and returns the
+// subscription ARN.
+func (c *SNSConnector) ConfirmSubscription(ctx context.Context, msg *Message) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SNSConnector", "ConfirmSubscription")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if msg.Type != MessageTypeSubscriptionConfirmation {
+		return "", fmt.Errorf("unexpected SNS message type %q", msg.Type)
+	}
+
+	if msg.Token == "" || msg.TopicArn == "" {
+		return "", fmt.Errorf("SNS subscription confirmation is missing token or topic ARN")
+	}
+
+	result, err := c.client.ConfirmSubscription(ctx, &sns.ConfirmSubscriptionInput{
+		TopicArn: aws.String(msg.TopicArn),
+		Token:    aws.String(msg.Token),
+	})
+	if err != nil {
+		c.logger.Error("Confirm SNS subscription error", zap.String("topic_arn", msg.TopicArn), zap.Error(err))
+		return "", err
+	}
+
+	c.logger.Info("Confirmed SNS subscription",
+		zap.String("topic_arn", msg.TopicArn),
+		zap.String("subscription_arn", aws.ToString(result.SubscriptionArn)),
+	)
+
+	return aws.ToString(result.SubscriptionArn), nil
+}
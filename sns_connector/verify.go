@@ -0,0 +1,210 @@
+package sns_connector
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrInvalidSignature   = awserrors.New(awserrors.ErrUnauthorized, "invalid SNS message signature")
+	ErrInvalidCertURL     = awserrors.New(awserrors.ErrUnauthorized, "invalid SNS signing certificate URL")
+	ErrUnexpectedTopic    = awserrors.New(awserrors.ErrUnauthorized, "unexpected SNS topic")
+	ErrCertificateExpired = awserrors.New(awserrors.ErrUnauthorized, "SNS signing certificate is expired")
+)
+
+var signingCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// Verifier checks SNS HTTP messages against the signing certificate
+// published by SNS. Certificates are cached by URL.
+type Verifier struct {
+	client    *http.Client
+	topicArns map[string]bool
+
+	mu    sync.RWMutex
+	certs map[string]*x509.Certificate
+}
+
+// NewVerifier returns a Verifier accepting messages from topicArns, or
+// from any topic when none are given.
+func NewVerifier(topicArns ...string) *Verifier {
+	v := &Verifier{
+		client:    &http.Client{Timeout: 10 * time.Second},
+		topicArns: make(map[string]bool, len(topicArns)),
+		certs:     make(map[string]*x509.Certificate),
+	}
+
+	for _, arn := range topicArns {
+		if arn != "" {
+			v.topicArns[arn] = true
+		}
+	}
+
+	return v
+}
+
+func (v *Verifier) Verify(ctx context.Context, msg *Message) error {
+	if len(v.topicArns) > 0 && !v.topicArns[msg.TopicArn] {
+		return fmt.Errorf("%w: %s", ErrUnexpectedTopic, msg.TopicArn)
+	}
+
+	var hash crypto.Hash
+	switch msg.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("%w: unsupported signature version %q", ErrInvalidSignature, msg.SignatureVersion)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	cert, err := v.getCertificate(ctx, msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	if time.Now().After(cert.NotAfter) {
+		return ErrCertificateExpired
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%w: unexpected certificate key type", ErrInvalidSignature)
+	}
+
+	payload := []byte(stringToSign(msg))
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum(payload)
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256(payload)
+		digest = sum[:]
+	}
+
+	if err := rsa.VerifyPKCS1v15(publicKey, hash, digest, signature); err != nil {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func (v *Verifier) getCertificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	u, err := url.Parse(certURL)
+	if err != nil || u.Scheme != "https" || !signingCertHost.MatchString(u.Host) || !strings.HasSuffix(u.Path, ".pem") {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCertURL, certURL)
+	}
+
+	v.mu.RLock()
+	cert, ok := v.certs[certURL]
+	v.mu.RUnlock()
+
+	if ok {
+		return cert, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch SNS signing certificate: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM data in %s", ErrInvalidCertURL, certURL)
+	}
+
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	v.certs[certURL] = cert
+	v.mu.Unlock()
+
+	return cert, nil
+}
+
+func stringToSign(msg *Message) string {
+	var b strings.Builder
+
+	add := func(key string, value string) {
+		b.WriteString(key)
+		b.WriteString("\n")
+		b.WriteString(value)
+		b.WriteString("\n")
+	}
+
+	add("Message", msg.Message)
+	add("MessageId", msg.MessageId)
+
+	if msg.Type == MessageTypeNotification {
+		if msg.Subject != "" {
+			add("Subject", msg.Subject)
+		}
+		add("Timestamp", msg.Timestamp)
+		add("TopicArn", msg.TopicArn)
+		add("Type", msg.Type)
+
+		return b.String()
+	}
+
+	add("SubscribeURL", msg.SubscribeURL)
+	add("Timestamp", msg.Timestamp)
+	add("Token", msg.Token)
+	add("TopicArn", msg.TopicArn)
+	add("Type", msg.Type)
+
+	return b.String()
+}
+
+// Decode unmarshals the inner JSON message into v.
+func (m *Message) Decode(v interface{}) error {
+	return json.Unmarshal([]byte(m.Message), v)
+}
+
+// Verify checks msg against the signing certificate, accepting the
+// configured topic_arn and verify_topic_arns.
+func (c *SNSConnector) Verify(ctx context.Context, msg *Message) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SNSConnector", "Verify")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.verifier.Verify(ctx, msg)
+}
@@ -0,0 +1,231 @@
+package sns_subscription_apis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/sns_connector"
+	"github.com/elmntri/zeitgeber-common-modules/http_server"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultPath              = "/sns"
+	DefaultEndpoint          = ""
+	DefaultTopicArn          = ""
+	DefaultUnsubscribeOnStop = false
+	DefaultVerifySignature   = true
+)
+
+type APIs struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	mu              sync.RWMutex
+	handlers        []sns_connector.NotificationHandler
+	subscriptionArn string
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle  fx.Lifecycle
+	Logger     *zap.Logger
+	HTTPServer *http_server.HTTPServer
+	SNS        *sns_connector.SNSConnector
+}
+
+func Module(scope string) fx.Option {
+
+	var a *APIs
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) *APIs {
+
+			a := &APIs{
+				params: p,
+				logger: requestid.Logger(p.Logger.Named(scope)),
+				scope:  scope,
+			}
+
+			a.initDefaultConfigs()
+
+			return a
+		}),
+		fx.Populate(&a),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: a.onStart,
+					OnStop:  a.onStop,
+				},
+			)
+		}),
+	)
+
+}
+
+func (a *APIs) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(a.scope, &a.config); err != nil {
+		a.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	path := a.config.Path
+	endpoint := a.config.Endpoint
+
+	a.logger.Info("Starting SNS subscription APIs",
+		zap.String("path", path),
+		zap.String("endpoint", endpoint),
+	)
+
+	router := a.params.HTTPServer.GetRouter()
+
+	router.POST(path, a.receive)
+
+	// Self-subscribe the public endpoint; SNS confirms it through receive.
+	if endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return err
+		}
+
+		_, err = a.params.SNS.Subscribe(ctx, a.config.TopicARN, u.Scheme, endpoint, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *APIs) onStop(ctx context.Context) error {
+
+	a.mu.RLock()
+	subscriptionArn := a.subscriptionArn
+	a.mu.RUnlock()
+
+	if a.config.UnsubscribeOnStop && subscriptionArn != "" {
+		if err := a.params.SNS.Unsubscribe(ctx, subscriptionArn); err != nil {
+			return err
+		}
+	}
+
+	a.logger.Info("Stopped SNS subscription APIs")
+
+	return nil
+}
+
+// Handle registers a handler invoked for every Notification received.
+func (a *APIs) Handle(handler sns_connector.NotificationHandler) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.handlers = append(a.handlers, handler)
+}
+
+func (a *APIs) receive(c *gin.Context) {
+
+	msg := &sns_connector.Message{}
+	if err := json.NewDecoder(c.Request.Body).Decode(msg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid SNS message",
+		})
+
+		return
+	}
+
+	if msgType := c.GetHeader("x-amz-sns-message-type"); msgType != "" && msgType != msg.Type {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "message type mismatch",
+		})
+
+		return
+	}
+
+	if topicArn := a.config.TopicARN; topicArn != "" && topicArn != msg.TopicArn {
+		a.logger.Warn("Rejected SNS message from unexpected topic", zap.String("topic_arn", msg.TopicArn))
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "unexpected topic",
+		})
+
+		return
+	}
+
+	if a.config.VerifySignature {
+		if err := a.params.SNS.Verify(c.Request.Context(), msg); err != nil {
+			a.logger.Warn("Rejected SNS message", zap.String("message_id", msg.MessageId), zap.Error(err))
+
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "invalid signature",
+			})
+
+			return
+		}
+	}
+
+	switch msg.Type {
+	case sns_connector.MessageTypeSubscriptionConfirmation:
+
+		subscriptionArn, err := a.params.SNS.ConfirmSubscription(c.Request.Context(), msg)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "confirmation failed",
+			})
+
+			return
+		}
+
+		a.mu.Lock()
+		a.subscriptionArn = subscriptionArn
+		a.mu.Unlock()
+
+	case sns_connector.MessageTypeNotification:
+
+		a.mu.RLock()
+		handlers := a.handlers
+		a.mu.RUnlock()
+
+		for _, handler := range handlers {
+			if err := handler(c.Request.Context(), msg); err != nil {
+				a.logger.Error("SNS notification handler error", zap.String("message_id", msg.MessageId), zap.Error(err))
+
+				// A non-2xx response makes SNS retry the delivery
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "handler failed",
+				})
+
+				return
+			}
+		}
+
+	case sns_connector.MessageTypeUnsubscribeConfirmation:
+
+		a.logger.Info("SNS subscription removed", zap.String("topic_arn", msg.TopicArn))
+
+	default:
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "unknown message type",
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
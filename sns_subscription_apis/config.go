@@ -0,0 +1,25 @@
+package sns_subscription_apis
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. SNS_SUBSCRIPTION_APIS_PATH for the
+// sns_subscription_apis scope.
+type Config struct {
+	Path              string `mapstructure:"path" default:"/sns"`
+	Endpoint          string `mapstructure:"endpoint" default:""`
+	TopicARN          string `mapstructure:"topic_arn" default:""`
+	UnsubscribeOnStop bool   `mapstructure:"unsubscribe_on_stop" default:"false"`
+	VerifySignature   bool   `mapstructure:"verify_signature" default:"true"`
+}
+
+func (a *APIs) initDefaultConfigs() {
+	moduleconfig.Register(a.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (a *APIs) Config() Config {
+	return a.config
+}
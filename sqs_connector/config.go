@@ -0,0 +1,41 @@
+package sqs_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. SQS_QUEUE_NAME for the sqs scope.
+type Config struct {
+	QueueName         string `mapstructure:"queue_name" default:"example-queue"`
+	QueueURL          string `mapstructure:"queue_url" default:""`
+	QueueKey          string `mapstructure:"queue_key" default:"ABCDE"`
+	QueueSecret       string `mapstructure:"queue_secret" default:"example_secret"`
+	QueueToken        string `mapstructure:"queue_token" default:""`
+	QueueRegion       string `mapstructure:"queue_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+
+	// Provisioning, see EnsureQueue
+	EnsureQueue            bool   `mapstructure:"ensure_queue" default:"false"`
+	QueueFIFO              bool   `mapstructure:"queue_fifo" default:"false"`
+	QueueContentBasedDedup bool   `mapstructure:"queue_content_based_dedup" default:"false"`
+	QueueVisibilityTimeout int    `mapstructure:"queue_visibility_timeout" default:"30"`
+	QueueDLQArn            string `mapstructure:"queue_dlq_arn" default:""`
+	QueueMaxReceiveCount   int    `mapstructure:"queue_max_receive_count" default:"5"`
+	QueueKMSKeyID          string `mapstructure:"queue_kms_key_id" default:""`
+
+	// Large message bodies are stored in the bucket of a bucket_connector
+	OffloadEnabled   bool   `mapstructure:"offload_enabled" default:"false"`
+	OffloadThreshold int    `mapstructure:"offload_threshold" default:"262144"`
+	OffloadPrefix    string `mapstructure:"offload_prefix" default:"sqs-payloads"`
+}
+
+func (c *SQSConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *SQSConnector) Config() Config {
+	return c.config
+}
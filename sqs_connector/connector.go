@@ -0,0 +1,445 @@
+package sqs_connector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/bucket_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultQueueName   = "example-queue"
+	DefaultQueueURL    = ""
+	DefaultQueueKey    = "ABCDE"
+	DefaultQueueSecret = "example_secret"
+	DefaultQueueToken  = ""
+	DefaultQueueRegion = "us-west-1"
+)
+
+var preflightActions = []string{
+	"sqs:GetQueueUrl",
+	"sqs:GetQueueAttributes",
+	"sqs:SendMessage",
+	"sqs:ReceiveMessage",
+	"sqs:DeleteMessage",
+}
+
+type SQSConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *sqs.Client
+	scope   string
+	tracker *inflight.Tracker
+
+	config Config
+
+	mu       sync.Mutex
+	queueURL string
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Bucket          *bucket_connector.BucketConnector `optional:"true"`
+	Credentials     aws.CredentialsProvider           `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig              `optional:"true"`
+	CredentialChain *awscredentials.Chain             `optional:"true"`
+	Tracer          *xray.Tracer                      `optional:"true"`
+	Breaker         *circuitbreaker.Breaker           `optional:"true"`
+	Prometheus      *metrics.Metrics                  `optional:"true"`
+	Telemetry       *telemetry.Telemetry              `optional:"true"`
+	Health          *health.Health                    `optional:"true"`
+	Faults          *faults.Injector                  `optional:"true"`
+	Client          *sqs.Client                       `optional:"true"`
+}
+
+// Module provides the SQSConnector of scope. It uses the *sqs.Client in
+// the graph, if any, instead of building one, and adds the connector's
+// middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the SQSConnector of scope, and its interfaces,
+// named after scope, for apps with more than one; see package instance.
+// It uses the *sqs.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *SQSConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*sqs.Client](scope, named),
+		instance.Provide[*SQSConnector](scope, named, func(p Params) *SQSConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &SQSConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *SQSConnector) Queue {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *SQSConnector) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting SQSConnector",
+		zap.String("queue_name", c.config.QueueName),
+		zap.String("queue_url", c.config.QueueURL),
+		zap.String("queue_region", c.config.QueueRegion),
+		zap.Bool("offload_enabled", c.offloadEnabled()),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	if c.offloadEnabled() && c.params.Bucket == nil {
+		return fmt.Errorf("%s: offload_enabled requires a bucket_connector module", c.scope)
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions,
+			"arn:{partition}:sqs:{region}:{account}:"+c.config.QueueName,
+		)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = sqs.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = sqs.New(c.client.Options(), func(o *sqs.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	c.setQueueURL(c.config.QueueURL)
+
+	if c.config.EnsureQueue {
+		if err := c.EnsureQueue(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *SQSConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped SQSConnector")
+
+	return nil
+}
+
+func (c *SQSConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.QueueRegion,
+		Key:         c.config.QueueKey,
+		Secret:      c.config.QueueSecret,
+		Token:       c.config.QueueToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *SQSConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("queue_region")
+
+	if c.config.QueueURL == "" {
+		v.Required("queue_name")
+		v.NotPlaceholder("queue_name", DefaultQueueName)
+	}
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("queue_key", DefaultQueueKey)
+		v.NotPlaceholder("queue_secret", DefaultQueueSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads an attribute of the queue.
+func (c *SQSConnector) probe(ctx context.Context) error {
+	queueURL, err := c.GetQueueURL(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+
+	return err
+}
+
+// GetQueueURL returns the URL of the configured queue, resolving it from
+// queue_name on first use when queue_url is not set.
+func (c *SQSConnector) GetQueueURL(ctx context.Context) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SQSConnector", "GetQueueURL")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.queueURL != "" {
+		return c.queueURL, nil
+	}
+
+	result, err := c.client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName: aws.String(c.config.QueueName),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	c.queueURL = aws.ToString(result.QueueUrl)
+
+	return c.queueURL, nil
+}
+
+// GetQueueArn returns the ARN of the configured queue.
+func (c *SQSConnector) GetQueueArn(ctx context.Context) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SQSConnector", "GetQueueArn")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	queueURL, err := c.GetQueueURL(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result.Attributes[string(types.QueueAttributeNameQueueArn)], nil
+}
+
+func (c *SQSConnector) SendMessage(ctx context.Context, body string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SQSConnector", "SendMessage")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	queueURL, err := c.GetQueueURL(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	body, attributes, err := c.offloadBody(ctx, body)
+	if err != nil {
+		c.logger.Error("Offload message body error", zap.Error(err))
+		return "", err
+	}
+
+	result, err := c.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:          aws.String(queueURL),
+		MessageBody:       aws.String(body),
+		MessageAttributes: attributes,
+	})
+	if err != nil {
+		c.logger.Error("Send to SQS error", zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.MessageId), nil
+}
+
+// ReceiveMessages receives up to maxMessages messages. A message whose
+// offloaded payload cannot be resolved is left out, to be redelivered
+// once its visibility timeout expires.
+func (c *SQSConnector) ReceiveMessages(ctx context.Context, maxMessages int32, waitSeconds int32) (_ []types.Message, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SQSConnector", "ReceiveMessages")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	received, err := c.receive(ctx, maxMessages, waitSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]types.Message, 0, len(received))
+	for _, msg := range received {
+		if msg.err != nil {
+			c.logger.Error("Resolve message error", zap.String("message_id", aws.ToString(msg.MessageId)), zap.Error(msg.err))
+			continue
+		}
+
+		messages = append(messages, msg.Message)
+	}
+
+	return messages, nil
+}
+
+// received is a message with the error resolving its offloaded payload,
+// if any.
+type received struct {
+	types.Message
+	err error
+}
+
+// receive receives messages and resolves their offloaded payloads, each
+// on its own, so one failing does not fail the others.
+func (c *SQSConnector) receive(ctx context.Context, maxMessages int32, waitSeconds int32) ([]received, error) {
+	queueURL, err := c.GetQueueURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:              aws.String(queueURL),
+		MaxNumberOfMessages:   maxMessages,
+		WaitTimeSeconds:       waitSeconds,
+		MessageAttributeNames: []string{"All"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]received, len(result.Messages))
+	for i, msg := range result.Messages {
+		messages[i].Message = msg
+
+		if c.offloadEnabled() {
+			messages[i].err = c.resolveMessage(ctx, &messages[i].Message)
+		}
+	}
+
+	return messages, nil
+}
+
+func (c *SQSConnector) DeleteMessage(ctx context.Context, receiptHandle string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SQSConnector", "DeleteMessage")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	queueURL, err := c.GetQueueURL(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.offloadEnabled() {
+		receiptHandle, err = c.releaseReceiptHandle(ctx, receiptHandle)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = c.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(queueURL),
+		ReceiptHandle: aws.String(receiptHandle),
+	})
+
+	return err
+}
+
+func (c *SQSConnector) GetClient() *sqs.Client {
+	return c.client
+}
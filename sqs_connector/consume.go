@@ -0,0 +1,122 @@
+package sqs_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+)
+
+// ErrMalformedMessage marks messages which can never be processed, such
+// as bodies failing to parse. Consume deletes them rather than having
+// them redelivered until they expire.
+var ErrMalformedMessage = awserrors.New(awserrors.ErrValidation, "malformed message")
+
+// Malformed wraps err, the reason a message can never be processed, with
+// ErrMalformedMessage.
+func Malformed(err error) error {
+	return fmt.Errorf("%w: %w", ErrMalformedMessage, err)
+}
+
+// ConsumeOptions set how Consume receives messages. Logger, when not
+// nil, replaces the connector's, so errors are logged under the
+// consuming module's name. Tracker, when not nil, tracks every message
+// being processed, so the consuming module's shutdown waits for them.
+type ConsumeOptions struct {
+	MaxMessages   int32
+	WaitSeconds   int32
+	RetryInterval time.Duration
+	Logger        *zap.Logger
+	Tracker       *inflight.Tracker
+}
+
+// Consume receives messages until ctx is done and calls process with the
+// body of each. Processed messages are deleted; those failing, in
+// process or in resolving their offloaded payload, are left on the queue
+// to be redelivered, unless the error is ErrMalformedMessage. A panic in
+// process fails the message like an error. Receive errors are retried
+// after RetryInterval. Consume returns once Tracker is shutting down,
+// leaving the messages it has not processed to be redelivered.
+func (c *SQSConnector) Consume(ctx context.Context, opts ConsumeOptions, process func(ctx context.Context, body string) error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = c.logger
+	}
+
+	for ctx.Err() == nil {
+		messages, err := c.receive(ctx, opts.MaxMessages, opts.WaitSeconds)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.Error("Receive messages error", zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(opts.RetryInterval):
+			}
+
+			continue
+		}
+
+		for _, msg := range messages {
+			if err := c.consume(ctx, logger, opts.Tracker, msg, process); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// consume processes and deletes msg, whose payload may have failed to
+// resolve. It only fails when tracker is shutting down, before
+// processing.
+func (c *SQSConnector) consume(ctx context.Context, logger *zap.Logger, tracker *inflight.Tracker, msg received, process func(ctx context.Context, body string) error) error {
+	messageID := aws.ToString(msg.MessageId)
+
+	done, err := tracker.Begin(ctx, "message "+messageID)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	err = msg.err
+	if err == nil {
+		err = processMessage(ctx, logger, msg.Message, process)
+	}
+
+	if err != nil {
+		if !errors.Is(err, ErrMalformedMessage) {
+			logger.Error("Process message error", zap.String("message_id", messageID), zap.Error(err))
+			return nil
+		}
+
+		logger.Error("Dropping malformed message", zap.String("message_id", messageID), zap.Error(err))
+	}
+
+	if err := c.DeleteMessage(ctx, aws.ToString(msg.ReceiptHandle)); err != nil {
+		logger.Error("Delete message error", zap.String("message_id", messageID), zap.Error(err))
+	}
+
+	return nil
+}
+
+// processMessage calls process, turning a panic into an error.
+func processMessage(ctx context.Context, logger *zap.Logger, msg types.Message, process func(ctx context.Context, body string) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Message handler panicked", zap.String("message_id", aws.ToString(msg.MessageId)), zap.Any("panic", r), zap.Stack("stack"))
+			err = fmt.Errorf("message handler panicked: %v", r)
+		}
+	}()
+
+	return process(ctx, aws.ToString(msg.Body))
+}
@@ -0,0 +1,21 @@
+package sqs_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Queue
+
+// Queue sends and receives messages of the queue.
+type Queue interface {
+	SendMessage(ctx context.Context, body string) (string, error)
+	ReceiveMessages(ctx context.Context, maxMessages int32, waitSeconds int32) ([]types.Message, error)
+	DeleteMessage(ctx context.Context, receiptHandle string) error
+}
+
+var (
+	_ Queue = (*SQSConnector)(nil)
+	_ Queue = (*MemoryQueue)(nil)
+)
@@ -0,0 +1,77 @@
+package sqs_connector
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// MemoryQueue is an in-process Queue for tests. Received messages stay
+// invisible until they are deleted; there is no visibility timeout.
+type MemoryQueue struct {
+	mu       sync.Mutex
+	seq      int
+	messages []types.Message
+	inFlight map[string]types.Message
+}
+
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		inFlight: make(map[string]types.Message),
+	}
+}
+
+func (q *MemoryQueue) SendMessage(ctx context.Context, body string) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.seq++
+	id := strconv.Itoa(q.seq)
+
+	q.messages = append(q.messages, types.Message{
+		MessageId: aws.String(id),
+		Body:      aws.String(body),
+	})
+
+	return id, nil
+}
+
+// ReceiveMessages returns up to maxMessages visible messages without
+// waiting.
+func (q *MemoryQueue) ReceiveMessages(ctx context.Context, maxMessages int32, waitSeconds int32) ([]types.Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := min(int(maxMessages), len(q.messages))
+	received := make([]types.Message, 0, n)
+
+	for _, message := range q.messages[:n] {
+		q.seq++
+		message.ReceiptHandle = aws.String(strconv.Itoa(q.seq))
+		q.inFlight[aws.ToString(message.ReceiptHandle)] = message
+		received = append(received, message)
+	}
+
+	q.messages = q.messages[n:]
+
+	return received, nil
+}
+
+func (q *MemoryQueue) DeleteMessage(ctx context.Context, receiptHandle string) error {
+	q.mu.Lock()
+	delete(q.inFlight, receiptHandle)
+	q.mu.Unlock()
+
+	return nil
+}
+
+// Len returns the number of messages not yet deleted, received or not.
+func (q *MemoryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.messages) + len(q.inFlight)
+}
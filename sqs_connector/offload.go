@@ -0,0 +1,153 @@
+package sqs_connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/google/uuid"
+)
+
+// The pointer, attribute and receipt handle formats match the AWS SQS
+// Extended Client Library so payloads can be exchanged with Java/Python
+// consumers using it.
+const (
+	ExtendedPayloadSizeAttribute = "ExtendedPayloadSize"
+
+	payloadPointerClass = "software.amazon.payloadoffloading.PayloadS3Pointer"
+	s3BucketNameMarker  = "-..s3BucketName..-"
+	s3KeyMarker         = "-..s3Key..-"
+)
+
+const (
+	DefaultOffloadEnabled   = false
+	DefaultOffloadThreshold = 262144
+	DefaultOffloadPrefix    = "sqs-payloads"
+)
+
+type payloadPointer struct {
+	S3BucketName string `json:"s3BucketName"`
+	S3Key        string `json:"s3Key"`
+}
+
+func (c *SQSConnector) offloadEnabled() bool {
+	return c.config.OffloadEnabled
+}
+
+// offloadBody stores body in the bucket when it exceeds the configured
+// threshold and returns the pointer body and attributes to send instead.
+func (c *SQSConnector) offloadBody(ctx context.Context, body string) (string, map[string]types.MessageAttributeValue, error) {
+	if !c.offloadEnabled() || len(body) <= c.config.OffloadThreshold {
+		return body, nil, nil
+	}
+
+	key := fmt.Sprintf("%s/%s", c.config.OffloadPrefix, uuid.New().String())
+
+	c.logger.Info("Offloading message body to S3", zap.String("key", key), zap.Int("size", len(body)))
+
+	if err := c.params.Bucket.PutObject(ctx, key, []byte(body), "application/octet-stream"); err != nil {
+		return "", nil, err
+	}
+
+	pointer, err := json.Marshal([]interface{}{
+		payloadPointerClass,
+		payloadPointer{
+			S3BucketName: c.params.Bucket.GetBucketName(),
+			S3Key:        key,
+		},
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	attributes := map[string]types.MessageAttributeValue{
+		ExtendedPayloadSizeAttribute: {
+			DataType:    aws.String("Number"),
+			StringValue: aws.String(strconv.Itoa(len(body))),
+		},
+	}
+
+	return string(pointer), attributes, nil
+}
+
+// resolveMessage replaces a pointer body with the payload stored in the
+// bucket and encodes the pointer into the receipt handle for cleanup.
+// Invalid pointers and missing payloads are Malformed.
+func (c *SQSConnector) resolveMessage(ctx context.Context, msg *types.Message) error {
+	if _, ok := msg.MessageAttributes[ExtendedPayloadSizeAttribute]; !ok {
+		return nil
+	}
+
+	pointer, err := c.parsePointer(aws.ToString(msg.Body))
+	if err != nil {
+		return Malformed(err)
+	}
+
+	data, err := c.params.Bucket.GetObject(ctx, pointer.S3Key)
+	if err != nil {
+		c.logger.Error("Get offloaded payload error", zap.String("key", pointer.S3Key), zap.Error(err))
+
+		if errors.Is(err, awserrors.ErrNotFound) {
+			return Malformed(err)
+		}
+
+		return err
+	}
+
+	msg.Body = aws.String(string(data))
+	msg.ReceiptHandle = aws.String(s3BucketNameMarker + pointer.S3BucketName + s3BucketNameMarker +
+		s3KeyMarker + pointer.S3Key + s3KeyMarker + aws.ToString(msg.ReceiptHandle))
+
+	return nil
+}
+
+func (c *SQSConnector) parsePointer(body string) (*payloadPointer, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(body), &raw); err != nil || len(raw) != 2 {
+		return nil, fmt.Errorf("invalid payload pointer: %s", body)
+	}
+
+	pointer := &payloadPointer{}
+	if err := json.Unmarshal(raw[1], pointer); err != nil {
+		return nil, err
+	}
+
+	if pointer.S3BucketName != c.params.Bucket.GetBucketName() {
+		return nil, fmt.Errorf("payload pointer references bucket %q, expected %q", pointer.S3BucketName, c.params.Bucket.GetBucketName())
+	}
+
+	return pointer, nil
+}
+
+// releaseReceiptHandle deletes the offloaded payload referenced by an
+// encoded receipt handle and returns the original SQS receipt handle.
+func (c *SQSConnector) releaseReceiptHandle(ctx context.Context, receiptHandle string) (string, error) {
+	if !strings.HasPrefix(receiptHandle, s3BucketNameMarker) {
+		return receiptHandle, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(receiptHandle, s3BucketNameMarker), s3BucketNameMarker, 2)
+	if len(parts) != 2 || !strings.HasPrefix(parts[1], s3KeyMarker) {
+		return "", fmt.Errorf("invalid receipt handle: %s", receiptHandle)
+	}
+
+	parts = strings.SplitN(strings.TrimPrefix(parts[1], s3KeyMarker), s3KeyMarker, 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid receipt handle: %s", receiptHandle)
+	}
+
+	if err := c.params.Bucket.DeleteObject(ctx, parts[0]); err != nil {
+		c.logger.Error("Delete offloaded payload error", zap.String("key", parts[0]), zap.Error(err))
+		return "", err
+	}
+
+	return parts[1], nil
+}
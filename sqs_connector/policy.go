@@ -0,0 +1,119 @@
+package sqs_connector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const policyVersion = "2012-10-17"
+
+// AllowService lets the AWS service principal, such as
+// events.amazonaws.com, send messages to the queue on behalf of
+// sourceArn. The statement is merged into the queue's access policy
+// under a Sid derived from sourceArn, so the other statements are kept
+// and calling it again replaces only its own.
+func (c *SQSConnector) AllowService(ctx context.Context, service string, sourceArn string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SQSConnector", "AllowService")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	queueURL, err := c.GetQueueURL(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		AttributeNames: []types.QueueAttributeName{
+			types.QueueAttributeNameQueueArn,
+			types.QueueAttributeNamePolicy,
+		},
+	})
+	if err != nil {
+		c.logger.Error("Get queue policy error", zap.Error(err))
+		return err
+	}
+
+	policy, err := mergeStatement(result.Attributes[string(types.QueueAttributeNamePolicy)], map[string]interface{}{
+		"Sid":       StatementID(sourceArn),
+		"Effect":    "Allow",
+		"Principal": map[string]string{"Service": service},
+		"Action":    "sqs:SendMessage",
+		"Resource":  result.Attributes[string(types.QueueAttributeNameQueueArn)],
+		"Condition": map[string]interface{}{
+			"ArnEquals": map[string]string{"aws:SourceArn": sourceArn},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.SetQueueAttributes(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		Attributes: map[string]string{
+			string(types.QueueAttributeNamePolicy): policy,
+		},
+	})
+	if err != nil {
+		c.logger.Error("Set queue policy error", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// StatementID is the Sid of the statement AllowService adds for
+// sourceArn. Sids are alphanumeric, so it is a digest of the ARN.
+func StatementID(sourceArn string) string {
+	sum := sha256.Sum256([]byte(sourceArn))
+
+	return "Allow" + hex.EncodeToString(sum[:8])
+}
+
+// mergeStatement adds statement to the policy document, replacing the
+// statement with the same Sid if there is one.
+func mergeStatement(document string, statement map[string]interface{}) (string, error) {
+	policy := map[string]interface{}{}
+	if document != "" {
+		if err := json.Unmarshal([]byte(document), &policy); err != nil {
+			return "", err
+		}
+	}
+
+	if _, ok := policy["Version"]; !ok {
+		policy["Version"] = policyVersion
+	}
+
+	var statements []interface{}
+	switch existing := policy["Statement"].(type) {
+	case []interface{}:
+		statements = existing
+	case map[string]interface{}:
+		// A single statement may be given as an object
+		statements = []interface{}{existing}
+	}
+
+	merged := make([]interface{}, 0, len(statements)+1)
+	for _, s := range statements {
+		if m, ok := s.(map[string]interface{}); ok && m["Sid"] == statement["Sid"] {
+			continue
+		}
+
+		merged = append(merged, s)
+	}
+	policy["Statement"] = append(merged, statement)
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
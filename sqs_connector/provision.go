@@ -0,0 +1,114 @@
+package sqs_connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	DefaultEnsureQueue            = false
+	DefaultQueueFIFO              = false
+	DefaultQueueContentBasedDedup = false
+	DefaultQueueVisibilityTimeout = 30
+	DefaultQueueDLQArn            = ""
+	DefaultQueueMaxReceiveCount   = 5
+	DefaultQueueKMSKeyID          = ""
+)
+
+// EnsureQueue creates the configured queue when it does not exist yet and
+// caches its URL. An existing queue is left untouched.
+func (c *SQSConnector) EnsureQueue(ctx context.Context) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SQSConnector", "EnsureQueue")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	queueName := c.config.QueueName
+	fifo := c.config.QueueFIFO
+
+	if fifo && !strings.HasSuffix(queueName, ".fifo") {
+		return fmt.Errorf("FIFO queue name %q must end with .fifo", queueName)
+	}
+
+	result, err := c.client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName: aws.String(queueName),
+	})
+	if err == nil {
+		c.logger.Info("Queue exists", zap.String("queue_name", queueName))
+		c.setQueueURL(aws.ToString(result.QueueUrl))
+		return nil
+	}
+
+	var notFound *types.QueueDoesNotExist
+	if !errors.As(err, &notFound) {
+		c.logger.Error("Get queue URL error", zap.Error(err))
+		return err
+	}
+
+	attributes, err := c.queueAttributes()
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info("Creating queue", zap.String("queue_name", queueName))
+
+	created, err := c.client.CreateQueue(ctx, &sqs.CreateQueueInput{
+		QueueName:  aws.String(queueName),
+		Attributes: attributes,
+	})
+	if err != nil {
+		c.logger.Error("Create queue error", zap.Error(err))
+		return err
+	}
+
+	c.setQueueURL(aws.ToString(created.QueueUrl))
+
+	return nil
+}
+
+func (c *SQSConnector) queueAttributes() (map[string]string, error) {
+	attributes := map[string]string{
+		string(types.QueueAttributeNameVisibilityTimeout): strconv.Itoa(c.config.QueueVisibilityTimeout),
+	}
+
+	if c.config.QueueFIFO {
+		attributes[string(types.QueueAttributeNameFifoQueue)] = "true"
+
+		if c.config.QueueContentBasedDedup {
+			attributes[string(types.QueueAttributeNameContentBasedDeduplication)] = "true"
+		}
+	}
+
+	if dlqArn := c.config.QueueDLQArn; dlqArn != "" {
+		policy, err := json.Marshal(map[string]string{
+			"deadLetterTargetArn": dlqArn,
+			"maxReceiveCount":     strconv.Itoa(c.config.QueueMaxReceiveCount),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		attributes[string(types.QueueAttributeNameRedrivePolicy)] = string(policy)
+	}
+
+	if kmsKeyID := c.config.QueueKMSKeyID; kmsKeyID != "" {
+		attributes[string(types.QueueAttributeNameKmsMasterKeyId)] = kmsKeyID
+	}
+
+	return attributes, nil
+}
+
+func (c *SQSConnector) setQueueURL(queueURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.queueURL = queueURL
+}
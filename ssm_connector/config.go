@@ -0,0 +1,30 @@
+package ssm_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. SSM_PARAMETER_KEY for the ssm scope.
+type Config struct {
+	ParameterKey      string `mapstructure:"parameter_key" default:"ABCDE"`
+	ParameterSecret   string `mapstructure:"parameter_secret" default:"example_secret"`
+	ParameterToken    string `mapstructure:"parameter_token" default:""`
+	ParameterRegion   string `mapstructure:"parameter_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+
+	Watch        bool `mapstructure:"watch" default:"false"`
+	PollInterval int  `mapstructure:"poll_interval" default:"60"`
+
+	Paths []PathConfig `mapstructure:"paths"`
+}
+
+func (c *SSMConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *SSMConnector) Config() Config {
+	return c.config
+}
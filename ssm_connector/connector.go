@@ -0,0 +1,455 @@
+package ssm_connector
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+	"github.com/spf13/viper"
+)
+
+var logger *zap.Logger
+
+var ErrParameterNotFound = awserrors.New(awserrors.ErrNotFound, "parameter not found")
+
+const (
+	DefaultParameterKey    = "ABCDE"
+	DefaultParameterSecret = "example_secret"
+	DefaultParameterToken  = ""
+	DefaultParameterRegion = "us-west-1"
+)
+
+var preflightActions = []string{
+	"ssm:GetParameter",
+	"ssm:GetParametersByPath",
+}
+
+// PathConfig loads every parameter below Path into viper under Prefix.
+// The rest of the parameter name becomes the key, with "/" read as ".",
+// so /myapp/prod/bucket/bucket_name with path /myapp/prod and prefix ""
+// sets bucket.bucket_name.
+type PathConfig struct {
+	Path   string `mapstructure:"path"`
+	Prefix string `mapstructure:"prefix"`
+}
+
+type SSMConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *ssm.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	mu      sync.RWMutex
+	values  map[string]string
+	changes *Changes
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *ssm.Client             `optional:"true"`
+}
+
+// Module loads parameters in its start hook. fx runs start hooks in the
+// order modules are given to fx.New, so list this module before the
+// connectors that read the keys it sets. It uses the *ssm.Client in the
+// graph, if any, instead of building one.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the SSMConnector of scope, and its interfaces,
+// named after scope, for apps with more than one; see package instance.
+// It uses the *ssm.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *SSMConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*ssm.Client](scope, named),
+		instance.Provide[*SSMConnector](scope, named, func(p Params) *SSMConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &SSMConnector{
+				params:  p,
+				logger:  logger,
+				scope:   scope,
+				changes: &Changes{},
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *SSMConnector) *Changes {
+			return c.changes
+		}),
+		instance.Export(scope, named, func(c *SSMConnector) ParameterStore {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *SSMConnector) onStart(ctx context.Context) error {
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting SSMConnector",
+		zap.String("parameter_region", c.config.ParameterRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = ssm.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = ssm.New(c.client.Options(), func(o *ssm.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	values, err := c.loadAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Start hooks run one at a time, before the modules started after
+	// this one read their config, so viper is only set here; later
+	// changes stay in c.values.
+	c.mu.Lock()
+	for key, value := range values {
+		viper.Set(key, value)
+	}
+	c.values = values
+	c.mu.Unlock()
+
+	c.startWatch()
+
+	return nil
+}
+
+func (c *SSMConnector) onStop(ctx context.Context) error {
+
+	c.stopWatch()
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped SSMConnector")
+
+	return nil
+}
+
+func (c *SSMConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.ParameterRegion,
+		Key:         c.config.ParameterKey,
+		Secret:      c.config.ParameterSecret,
+		Token:       c.config.ParameterToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *SSMConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("parameter_region")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("parameter_key", DefaultParameterKey)
+		v.NotPlaceholder("parameter_secret", DefaultParameterSecret)
+	}
+
+	return v.Err()
+}
+
+// probe lists a parameter.
+func (c *SSMConnector) probe(ctx context.Context) error {
+	_, err := c.client.DescribeParameters(ctx, &ssm.DescribeParametersInput{
+		MaxResults: aws.Int32(1),
+	})
+
+	return err
+}
+
+// loadAll reads every configured path. Later paths win on key
+// collisions.
+func (c *SSMConnector) loadAll(ctx context.Context) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, p := range c.config.Paths {
+		loaded, err := c.loadPath(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range loaded {
+			values[key] = value
+		}
+
+		c.logger.Debug("Loaded parameters", zap.String("path", p.Path), zap.String("prefix", p.Prefix), zap.Int("keys", len(loaded)))
+	}
+
+	return values, nil
+}
+
+// loadPath reads the parameter tree below p.Path, decrypting
+// SecureStrings, and returns the values keyed by viper key.
+func (c *SSMConnector) loadPath(ctx context.Context, p PathConfig) (map[string]string, error) {
+	root := strings.TrimSuffix(p.Path, "/")
+	values := make(map[string]string)
+
+	paginator := ssm.NewGetParametersByPathPaginator(c.client, &ssm.GetParametersByPathInput{
+		Path:           aws.String(p.Path),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("Get parameters by path error", zap.String("path", p.Path), zap.Error(err))
+			return nil, err
+		}
+
+		for _, param := range page.Parameters {
+			values[viperKey(root, p.Prefix, aws.ToString(param.Name))] = aws.ToString(param.Value)
+		}
+	}
+
+	return values, nil
+}
+
+func viperKey(root string, prefix string, name string) string {
+	key := strings.ReplaceAll(strings.Trim(strings.TrimPrefix(name, root), "/"), "/", ".")
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+// GetParameter returns the decrypted value of a parameter.
+func (c *SSMConnector) GetParameter(ctx context.Context, name string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SSMConnector", "GetParameter")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		var notFound *types.ParameterNotFound
+		if errors.As(err, &notFound) {
+			return "", ErrParameterNotFound
+		}
+
+		c.logger.Error("Get parameter error", zap.String("name", name), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.Parameter.Value), nil
+}
+
+func (c *SSMConnector) GetInt(ctx context.Context, name string) (_ int, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SSMConnector", "GetInt")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	value, err := c.GetParameter(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(value)
+}
+
+func (c *SSMConnector) GetBool(ctx context.Context, name string) (_ bool, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SSMConnector", "GetBool")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	value, err := c.GetParameter(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	return strconv.ParseBool(value)
+}
+
+// GetStringList splits a StringList parameter on commas.
+func (c *SSMConnector) GetStringList(ctx context.Context, name string) (_ []string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SSMConnector", "GetStringList")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	value, err := c.GetParameter(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(value, ","), nil
+}
+
+// PutParameter creates or overwrites a String parameter, or a
+// SecureString encrypted with the account's default key when secure is
+// set.
+func (c *SSMConnector) PutParameter(ctx context.Context, name string, value string, secure bool) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SSMConnector", "PutParameter")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	paramType := types.ParameterTypeString
+	if secure {
+		paramType = types.ParameterTypeSecureString
+	}
+
+	return c.put(ctx, name, value, paramType)
+}
+
+func (c *SSMConnector) PutInt(ctx context.Context, name string, value int) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SSMConnector", "PutInt")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.put(ctx, name, strconv.Itoa(value), types.ParameterTypeString)
+}
+
+func (c *SSMConnector) PutBool(ctx context.Context, name string, value bool) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SSMConnector", "PutBool")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.put(ctx, name, strconv.FormatBool(value), types.ParameterTypeString)
+}
+
+func (c *SSMConnector) PutStringList(ctx context.Context, name string, values []string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "SSMConnector", "PutStringList")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.put(ctx, name, strings.Join(values, ","), types.ParameterTypeStringList)
+}
+
+func (c *SSMConnector) put(ctx context.Context, name string, value string, paramType types.ParameterType) error {
+	_, err := c.client.PutParameter(ctx, &ssm.PutParameterInput{
+		Name:      aws.String(name),
+		Value:     aws.String(value),
+		Type:      paramType,
+		Overwrite: aws.Bool(true),
+	})
+	if err != nil {
+		c.logger.Error("Put parameter error", zap.String("name", name), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *SSMConnector) GetClient() *ssm.Client {
+	return c.client
+}
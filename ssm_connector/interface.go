@@ -0,0 +1,22 @@
+package ssm_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . ParameterStore
+
+// ParameterStore reads and writes parameters.
+type ParameterStore interface {
+	GetParameter(ctx context.Context, name string) (string, error)
+	GetInt(ctx context.Context, name string) (int, error)
+	GetBool(ctx context.Context, name string) (bool, error)
+	GetStringList(ctx context.Context, name string) ([]string, error)
+	PutParameter(ctx context.Context, name string, value string, secure bool) error
+	PutInt(ctx context.Context, name string, value int) error
+	PutBool(ctx context.Context, name string, value bool) error
+	PutStringList(ctx context.Context, name string, values []string) error
+}
+
+var (
+	_ ParameterStore = (*SSMConnector)(nil)
+	_ ParameterStore = (*MemoryParameterStore)(nil)
+)
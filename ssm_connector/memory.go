@@ -0,0 +1,80 @@
+package ssm_connector
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MemoryParameterStore is an in-process ParameterStore for tests. Missing
+// parameters fail with ErrParameterNotFound.
+type MemoryParameterStore struct {
+	mu         sync.RWMutex
+	parameters map[string]string
+}
+
+func NewMemoryParameterStore() *MemoryParameterStore {
+	return &MemoryParameterStore{
+		parameters: make(map[string]string),
+	}
+}
+
+func (s *MemoryParameterStore) GetParameter(ctx context.Context, name string) (string, error) {
+	s.mu.RLock()
+	value, ok := s.parameters[name]
+	s.mu.RUnlock()
+
+	if !ok {
+		return "", ErrParameterNotFound
+	}
+
+	return value, nil
+}
+
+func (s *MemoryParameterStore) GetInt(ctx context.Context, name string) (int, error) {
+	value, err := s.GetParameter(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(value)
+}
+
+func (s *MemoryParameterStore) GetBool(ctx context.Context, name string) (bool, error) {
+	value, err := s.GetParameter(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	return strconv.ParseBool(value)
+}
+
+func (s *MemoryParameterStore) GetStringList(ctx context.Context, name string) ([]string, error) {
+	value, err := s.GetParameter(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(value, ","), nil
+}
+
+func (s *MemoryParameterStore) PutParameter(ctx context.Context, name string, value string, secure bool) error {
+	s.mu.Lock()
+	s.parameters[name] = value
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *MemoryParameterStore) PutInt(ctx context.Context, name string, value int) error {
+	return s.PutParameter(ctx, name, strconv.Itoa(value), false)
+}
+
+func (s *MemoryParameterStore) PutBool(ctx context.Context, name string, value bool) error {
+	return s.PutParameter(ctx, name, strconv.FormatBool(value), false)
+}
+
+func (s *MemoryParameterStore) PutStringList(ctx context.Context, name string, values []string) error {
+	return s.PutParameter(ctx, name, strings.Join(values, ","), false)
+}
@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/ssm_connector (interfaces: ParameterStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . ParameterStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockParameterStore is a mock of ParameterStore interface.
+type MockParameterStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockParameterStoreMockRecorder
+}
+
+// MockParameterStoreMockRecorder is the mock recorder for MockParameterStore.
+type MockParameterStoreMockRecorder struct {
+	mock *MockParameterStore
+}
+
+// NewMockParameterStore creates a new mock instance.
+func NewMockParameterStore(ctrl *gomock.Controller) *MockParameterStore {
+	mock := &MockParameterStore{ctrl: ctrl}
+	mock.recorder = &MockParameterStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockParameterStore) EXPECT() *MockParameterStoreMockRecorder {
+	return m.recorder
+}
+
+// GetBool mocks base method.
+func (m *MockParameterStore) GetBool(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBool", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBool indicates an expected call of GetBool.
+func (mr *MockParameterStoreMockRecorder) GetBool(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBool", reflect.TypeOf((*MockParameterStore)(nil).GetBool), arg0, arg1)
+}
+
+// GetInt mocks base method.
+func (m *MockParameterStore) GetInt(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInt", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInt indicates an expected call of GetInt.
+func (mr *MockParameterStoreMockRecorder) GetInt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInt", reflect.TypeOf((*MockParameterStore)(nil).GetInt), arg0, arg1)
+}
+
+// GetParameter mocks base method.
+func (m *MockParameterStore) GetParameter(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParameter", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParameter indicates an expected call of GetParameter.
+func (mr *MockParameterStoreMockRecorder) GetParameter(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameter", reflect.TypeOf((*MockParameterStore)(nil).GetParameter), arg0, arg1)
+}
+
+// GetStringList mocks base method.
+func (m *MockParameterStore) GetStringList(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStringList", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStringList indicates an expected call of GetStringList.
+func (mr *MockParameterStoreMockRecorder) GetStringList(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStringList", reflect.TypeOf((*MockParameterStore)(nil).GetStringList), arg0, arg1)
+}
+
+// PutBool mocks base method.
+func (m *MockParameterStore) PutBool(arg0 context.Context, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutBool", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutBool indicates an expected call of PutBool.
+func (mr *MockParameterStoreMockRecorder) PutBool(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutBool", reflect.TypeOf((*MockParameterStore)(nil).PutBool), arg0, arg1, arg2)
+}
+
+// PutInt mocks base method.
+func (m *MockParameterStore) PutInt(arg0 context.Context, arg1 string, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutInt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutInt indicates an expected call of PutInt.
+func (mr *MockParameterStoreMockRecorder) PutInt(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutInt", reflect.TypeOf((*MockParameterStore)(nil).PutInt), arg0, arg1, arg2)
+}
+
+// PutParameter mocks base method.
+func (m *MockParameterStore) PutParameter(arg0 context.Context, arg1, arg2 string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutParameter", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutParameter indicates an expected call of PutParameter.
+func (mr *MockParameterStoreMockRecorder) PutParameter(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutParameter", reflect.TypeOf((*MockParameterStore)(nil).PutParameter), arg0, arg1, arg2, arg3)
+}
+
+// PutStringList mocks base method.
+func (m *MockParameterStore) PutStringList(arg0 context.Context, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutStringList", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutStringList indicates an expected call of PutStringList.
+func (mr *MockParameterStoreMockRecorder) PutStringList(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutStringList", reflect.TypeOf((*MockParameterStore)(nil).PutStringList), arg0, arg1, arg2)
+}
@@ -0,0 +1,147 @@
+package ssm_connector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+)
+
+const (
+	DefaultWatch        = false
+	DefaultPollInterval = 60
+)
+
+// ChangeEvent lists the keys whose parameters changed in one poll. The
+// changes are not written to viper, which is not safe to change while
+// other goroutines read it; read the current values with Value.
+type ChangeEvent struct {
+	Changed map[string]string
+	Removed []string
+}
+
+type ChangeHandler func(ctx context.Context, event ChangeEvent)
+
+// Changes publishes parameter changes. It is provided to the fx graph so
+// modules can react to tunables without depending on the connector.
+type Changes struct {
+	mu       sync.RWMutex
+	handlers []ChangeHandler
+}
+
+func (ch *Changes) Subscribe(handler ChangeHandler) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	ch.handlers = append(ch.handlers, handler)
+}
+
+func (ch *Changes) publish(ctx context.Context, event ChangeEvent) {
+	ch.mu.RLock()
+	handlers := ch.handlers
+	ch.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
+
+func (c *SSMConnector) startWatch() {
+	if !c.config.Watch {
+		return
+	}
+
+	loopCtx, cancel := context.WithCancel(inflight.Internal(context.Background()))
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go c.watchLoop(loopCtx)
+}
+
+func (c *SSMConnector) stopWatch() {
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+}
+
+func (c *SSMConnector) watchLoop(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(time.Duration(c.config.PollInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := c.reload(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Warn("Reload parameters error", zap.Error(err))
+		}
+	}
+}
+
+// reload reads all paths and applies the differences in one step, so a
+// failed or partial read never leaves the values half updated.
+func (c *SSMConnector) reload(ctx context.Context) error {
+	values, err := c.loadAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+
+	event := ChangeEvent{Changed: make(map[string]string)}
+	for key, value := range values {
+		if previous, ok := c.values[key]; !ok || previous != value {
+			event.Changed[key] = value
+		}
+	}
+	for key := range c.values {
+		if _, ok := values[key]; !ok {
+			event.Removed = append(event.Removed, key)
+		}
+	}
+
+	c.values = values
+
+	c.mu.Unlock()
+
+	if len(event.Changed) == 0 && len(event.Removed) == 0 {
+		return nil
+	}
+
+	// Values may be secrets, so only the keys are logged
+	keys := make([]string, 0, len(event.Changed))
+	for key := range event.Changed {
+		keys = append(keys, key)
+	}
+
+	c.logger.Info("Parameters changed", zap.Strings("changed", keys), zap.Strings("removed", event.Removed))
+
+	c.changes.publish(ctx, event)
+
+	return nil
+}
+
+// Value returns the current value of the parameter loaded under key,
+// including the changes found since start.
+func (c *SSMConnector) Value(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	value, ok := c.values[key]
+
+	return value, ok
+}
+
+// Changes returns the change feed, also available from the fx graph.
+func (c *SSMConnector) Changes() *Changes {
+	return c.changes
+}
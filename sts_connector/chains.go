@@ -0,0 +1,86 @@
+package sts_connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+)
+
+// HopConfig is one role assumption of a chain.
+type HopConfig struct {
+	RoleArn           string            `mapstructure:"role_arn"`
+	ExternalID        string            `mapstructure:"external_id"`
+	SessionTags       map[string]string `mapstructure:"session_tags"`
+	TransitiveTagKeys []string          `mapstructure:"transitive_tag_keys"`
+}
+
+// ChainConfig assumes Hops in order, each with the credentials of the
+// previous one, e.g. a hub account role followed by a spoke account role.
+type ChainConfig struct {
+	Name string      `mapstructure:"name"`
+	Hops []HopConfig `mapstructure:"hops"`
+}
+
+// buildChains creates a cached provider per configured chain, starting
+// from the module's base credentials.
+func (c *STSConnector) buildChains(cfg aws.Config) error {
+	chains := c.config.Chains
+
+	sessionName := c.config.RoleSessionName
+	duration := time.Duration(c.config.RoleDuration) * time.Second
+
+	c.chains = make(map[string]aws.CredentialsProvider, len(chains))
+
+	for _, chain := range chains {
+		if chain.Name == "" || len(chain.Hops) == 0 {
+			return fmt.Errorf("%s: chains need a name and at least one hop", c.scope)
+		}
+
+		provider := cfg.Credentials
+
+		for _, hop := range chain.Hops {
+			hopCfg := cfg.Copy()
+			hopCfg.Credentials = provider
+
+			hop := hop
+			provider = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(hopCfg), hop.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+				o.RoleSessionName = sessionName
+				o.Duration = duration
+				if hop.ExternalID != "" {
+					o.ExternalID = aws.String(hop.ExternalID)
+				}
+				for key, value := range hop.SessionTags {
+					o.Tags = append(o.Tags, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+				}
+				o.TransitiveTagKeys = hop.TransitiveTagKeys
+			}))
+		}
+
+		c.chains[chain.Name] = provider
+
+		c.logger.Info("Configured role chain", zap.String("name", chain.Name), zap.String("target_role_arn", chain.Hops[len(chain.Hops)-1].RoleArn))
+	}
+
+	return nil
+}
+
+// Provider returns the credentials of the named role chain.
+func (c *STSConnector) Provider(name string) (aws.CredentialsProvider, error) {
+	if _, err := c.setup(context.Background()); err != nil {
+		return nil, err
+	}
+
+	provider, ok := c.chains[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown role chain %q", c.scope, name)
+	}
+
+	return provider, nil
+}
@@ -0,0 +1,31 @@
+package sts_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. STS_ROLE_ARN for the sts scope.
+type Config struct {
+	RoleARN           string `mapstructure:"role_arn" default:""`
+	RoleSessionName   string `mapstructure:"role_session_name" default:"zeitgeber"`
+	ExternalID        string `mapstructure:"external_id" default:""`
+	RoleDuration      int    `mapstructure:"role_duration" default:"3600"`
+	STSKey            string `mapstructure:"sts_key" default:"ABCDE"`
+	STSSecret         string `mapstructure:"sts_secret" default:"example_secret"`
+	STSToken          string `mapstructure:"sts_token" default:""`
+	STSRegion         string `mapstructure:"sts_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+
+	Chains []ChainConfig `mapstructure:"chains"`
+}
+
+func (c *STSConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *STSConnector) Config() Config {
+	return c.config
+}
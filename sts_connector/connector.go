@@ -0,0 +1,346 @@
+package sts_connector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultRoleArn         = ""
+	DefaultRoleSessionName = "zeitgeber"
+	DefaultExternalID      = ""
+	DefaultRoleDuration    = 3600
+	DefaultSTSKey          = "ABCDE"
+	DefaultSTSSecret       = "example_secret"
+	DefaultSTSToken        = ""
+	DefaultSTSRegion       = "us-west-1"
+)
+
+var preflightActions = []string{
+	"sts:AssumeRole",
+}
+
+type STSConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *sts.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+
+	once sync.Once
+	cfg  aws.Config
+	err  error
+
+	provider    aws.CredentialsProvider
+	credentials *aws.CredentialsCache
+	chains      map[string]aws.CredentialsProvider
+
+	mu    sync.Mutex
+	roles map[string]aws.CredentialsProvider
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *sts.Client             `optional:"true"`
+}
+
+// Module provides the assumed-role credentials as an
+// aws.CredentialsProvider, which the other connectors use in place of
+// their static keys. Credentials are refreshed before they expire.
+// Without role_arn, when the module only serves role chains, the
+// provider is nil and the other connectors keep their own credentials.
+// It uses the *sts.Client in the graph, if any, instead of building
+// one, and adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the STSConnector of scope, and its interfaces,
+// named after scope, for apps with more than one; see package instance.
+// It uses the *sts.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *STSConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*sts.Client](scope, named),
+		instance.Provide[*STSConnector](scope, named, func(p Params) (*STSConnector, error) {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &STSConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			// Connectors capture the provider when they are built; the role
+			// is only assumed on first use, which sets the client up.
+			c.credentials = aws.NewCredentialsCache(aws.CredentialsProviderFunc(c.retrieve))
+
+			c.initDefaultConfigs()
+
+			// Loaded here, as other connectors may fetch credentials before onStart
+			if err := moduleconfig.Load(scope, &c.config); err != nil {
+				return nil, err
+			}
+
+			return c, nil
+		}),
+		instance.Export(scope, named, func(c *STSConnector) aws.CredentialsProvider {
+			// A provider without a role could never resolve
+			if c.config.RoleARN == "" {
+				return nil
+			}
+
+			return c.credentials
+		}),
+		instance.Export(scope, named, func(c *STSConnector) RoleCredentials {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *STSConnector) onStart(ctx context.Context) error {
+	roleArn := c.config.RoleARN
+
+	c.logger.Info("Starting STSConnector",
+		zap.String("role_arn", roleArn),
+		zap.String("role_session_name", c.config.RoleSessionName),
+		zap.String("sts_region", c.config.STSRegion),
+	)
+
+	cfg, err := c.setup(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	// Without role_arn the module only serves role chains
+	if roleArn == "" && len(c.chains) == 0 {
+		return fmt.Errorf("%s: role_arn or chains is required", c.scope)
+	}
+
+	return nil
+}
+
+func (c *STSConnector) onStop(ctx context.Context) error {
+
+	if c.tracker != nil {
+		c.tracker.Shutdown(ctx)
+	}
+
+	c.logger.Info("Stopped STSConnector")
+
+	return nil
+}
+
+// setup builds the client and the role providers once. The other
+// connectors may fetch credentials in their own onStart, before this
+// module starts, so whichever comes first sets them up.
+func (c *STSConnector) setup(ctx context.Context) (aws.Config, error) {
+	c.once.Do(func() {
+		c.cfg, c.err = c.build(ctx)
+	})
+
+	return c.cfg, c.err
+}
+
+func (c *STSConnector) build(ctx context.Context) (aws.Config, error) {
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return aws.Config{}, err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return aws.Config{}, err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = sts.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = sts.New(c.client.Options(), func(o *sts.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if err := c.buildChains(cfg); err != nil {
+		return aws.Config{}, err
+	}
+
+	if roleArn := c.config.RoleARN; roleArn != "" {
+		c.provider = stscreds.NewAssumeRoleProvider(c.client, roleArn, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = c.config.RoleSessionName
+			o.Duration = time.Duration(c.config.RoleDuration) * time.Second
+			if externalID := c.config.ExternalID; externalID != "" {
+				o.ExternalID = aws.String(externalID)
+			}
+		})
+	}
+
+	return cfg, nil
+}
+
+func (c *STSConnector) retrieve(ctx context.Context) (aws.Credentials, error) {
+	if _, err := c.setup(ctx); err != nil {
+		return aws.Credentials{}, err
+	}
+
+	if c.provider == nil {
+		return aws.Credentials{}, fmt.Errorf("%s: no role assumed; role_arn is not set", c.scope)
+	}
+
+	creds, err := c.provider.Retrieve(ctx)
+	if err != nil {
+		c.logger.Error("Assume role error", zap.Error(err))
+		return aws.Credentials{}, err
+	}
+
+	c.logger.Info("Assumed role", zap.Time("expires", creds.Expires))
+
+	return creds, nil
+}
+
+// loadConfig takes the base credentials the roles are assumed with from
+// the shared config when there is one. The connector cannot use its own
+// assumed-role credentials.
+func (c *STSConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared: c.params.AWSConfig,
+		Chain:  c.params.CredentialChain,
+		Region: c.config.STSRegion,
+		Key:    c.config.STSKey,
+		Secret: c.config.STSSecret,
+		Token:  c.config.STSToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *STSConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("sts_region")
+
+	if c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("sts_key", DefaultSTSKey)
+		v.NotPlaceholder("sts_secret", DefaultSTSSecret)
+	}
+
+	return v.Err()
+}
+
+// probe checks who the credentials belong to.
+func (c *STSConnector) probe(ctx context.Context) error {
+	_, err := c.client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+
+	return err
+}
+
+// GetCredentials returns the cached assumed-role credentials provider.
+func (c *STSConnector) GetCredentials() aws.CredentialsProvider {
+	return c.credentials
+}
+
+func (c *STSConnector) GetClient() *sts.Client {
+	return c.client
+}
@@ -0,0 +1,65 @@
+package sts_connector_test
+
+import (
+	"testing"
+
+	"go.uber.org/fx"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/elmntri/zeitgeber-aws-modules/sts_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/testsupport"
+)
+
+type consumer struct {
+	fx.In
+
+	Credentials aws.CredentialsProvider `optional:"true"`
+}
+
+func setKeys(t *testing.T) {
+	testsupport.Set(t, "sts.sts_key", "AKIDEXAMPLE")
+	testsupport.Set(t, "sts.sts_secret", "secret")
+}
+
+func TestChainsOnly(t *testing.T) {
+	setKeys(t)
+	testsupport.Set(t, "sts.chains", []map[string]interface{}{
+		{"name": "spoke", "hops": []map[string]interface{}{
+			{"role_arn": "arn:aws:iam::123456789012:role/hub"},
+			{"role_arn": "arn:aws:iam::210987654321:role/spoke"},
+		}},
+	})
+
+	var (
+		c   consumer
+		sts *sts_connector.STSConnector
+	)
+	testsupport.NewApp(t,
+		sts_connector.Module("sts"),
+		fx.Populate(&sts),
+		fx.Invoke(func(p consumer) { c = p }),
+	)
+
+	if c.Credentials != nil {
+		t.Error("credentials provided without role_arn")
+	}
+
+	if _, err := sts.Provider("spoke"); err != nil {
+		t.Errorf("chain provider: %v", err)
+	}
+}
+
+func TestRoleARN(t *testing.T) {
+	setKeys(t)
+	testsupport.Set(t, "sts.role_arn", "arn:aws:iam::123456789012:role/app")
+
+	var c consumer
+	testsupport.NewApp(t,
+		sts_connector.Module("sts"),
+		fx.Invoke(func(p consumer) { c = p }),
+	)
+
+	if c.Credentials == nil {
+		t.Error("no credentials with role_arn set")
+	}
+}
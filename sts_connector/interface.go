@@ -0,0 +1,13 @@
+package sts_connector
+
+import "github.com/aws/aws-sdk-go-v2/aws"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . RoleCredentials
+
+// RoleCredentials provides credentials of assumed roles.
+type RoleCredentials interface {
+	Provider(name string) (aws.CredentialsProvider, error)
+	RoleProvider(roleArn string) (aws.CredentialsProvider, error)
+}
+
+var _ RoleCredentials = (*STSConnector)(nil)
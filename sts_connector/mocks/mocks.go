@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/sts_connector (interfaces: RoleCredentials)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . RoleCredentials
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	aws "github.com/aws/aws-sdk-go-v2/aws"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRoleCredentials is a mock of RoleCredentials interface.
+type MockRoleCredentials struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleCredentialsMockRecorder
+}
+
+// MockRoleCredentialsMockRecorder is the mock recorder for MockRoleCredentials.
+type MockRoleCredentialsMockRecorder struct {
+	mock *MockRoleCredentials
+}
+
+// NewMockRoleCredentials creates a new mock instance.
+func NewMockRoleCredentials(ctrl *gomock.Controller) *MockRoleCredentials {
+	mock := &MockRoleCredentials{ctrl: ctrl}
+	mock.recorder = &MockRoleCredentialsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleCredentials) EXPECT() *MockRoleCredentialsMockRecorder {
+	return m.recorder
+}
+
+// Provider mocks base method.
+func (m *MockRoleCredentials) Provider(arg0 string) (aws.CredentialsProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Provider", arg0)
+	ret0, _ := ret[0].(aws.CredentialsProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Provider indicates an expected call of Provider.
+func (mr *MockRoleCredentialsMockRecorder) Provider(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Provider", reflect.TypeOf((*MockRoleCredentials)(nil).Provider), arg0)
+}
+
+// RoleProvider mocks base method.
+func (m *MockRoleCredentials) RoleProvider(arg0 string) (aws.CredentialsProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RoleProvider", arg0)
+	ret0, _ := ret[0].(aws.CredentialsProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RoleProvider indicates an expected call of RoleProvider.
+func (mr *MockRoleCredentialsMockRecorder) RoleProvider(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleProvider", reflect.TypeOf((*MockRoleCredentials)(nil).RoleProvider), arg0)
+}
@@ -0,0 +1,42 @@
+package sts_connector
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+)
+
+// RoleProvider returns cached credentials for roleArn, assumed with the
+// module's base credentials like role_arn. It serves roles only known at
+// runtime, such as the same role in every account of an organization;
+// providers are kept per role for the life of the module.
+func (c *STSConnector) RoleProvider(roleArn string) (aws.CredentialsProvider, error) {
+	if _, err := c.setup(context.Background()); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if provider, ok := c.roles[roleArn]; ok {
+		return provider, nil
+	}
+
+	if c.roles == nil {
+		c.roles = make(map[string]aws.CredentialsProvider)
+	}
+
+	provider := aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(c.client, roleArn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = c.config.RoleSessionName
+		o.Duration = time.Duration(c.config.RoleDuration) * time.Second
+	}))
+	c.roles[roleArn] = provider
+
+	c.logger.Debug("Added role provider", zap.String("role_arn", roleArn))
+
+	return provider, nil
+}
@@ -0,0 +1,21 @@
+package telemetry
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. TELEMETRY_ENABLED for the telemetry
+// scope.
+type Config struct {
+	Enabled bool `mapstructure:"enabled" default:"true"`
+}
+
+func (t *Telemetry) initDefaultConfigs() {
+	moduleconfig.Register(t.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (t *Telemetry) Config() Config {
+	return t.config
+}
@@ -0,0 +1,177 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultEnabled = true
+
+	// InstrumentationName is the name of the tracer of connector spans.
+	InstrumentationName = "github.com/elmntri/zeitgeber-aws-modules"
+)
+
+// Attributes set on the spans of every connector, next to the rpc.* and
+// aws.* ones of otelaws on AWS calls. Method spans name the connector
+// type and method, e.g. BucketConnector and PutObject.
+const (
+	AttrConnector = attribute.Key("zeitgeber.connector")
+	AttrMethod    = attribute.Key("code.function")
+	AttrNamespace = attribute.Key("code.namespace")
+)
+
+// Telemetry traces connectors with OpenTelemetry. Connectors given
+// Telemetry record a span for every public method taking a context, and
+// under it one for every AWS call the method makes. Spans go to the
+// trace.TracerProvider in the graph, or to the global one. Everything is
+// switched off with enabled under the module's scope:
+//
+//	tracing:
+//	  enabled: false
+type Telemetry struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle      fx.Lifecycle
+	Logger         *zap.Logger
+	TracerProvider trace.TracerProvider `optional:"true"`
+}
+
+func Module(scope string) fx.Option {
+
+	var t *Telemetry
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) (*Telemetry, error) {
+
+			logger = p.Logger.Named(scope)
+
+			t := &Telemetry{
+				params:   p,
+				logger:   logger,
+				scope:    scope,
+				provider: p.TracerProvider,
+			}
+
+			if t.provider == nil {
+				t.provider = otel.GetTracerProvider()
+			}
+
+			t.tracer = t.provider.Tracer(InstrumentationName)
+
+			t.initDefaultConfigs()
+
+			// Loaded here, as connectors may start before the module
+			if err := moduleconfig.Load(scope, &t.config); err != nil {
+				return nil, err
+			}
+
+			return t, nil
+		}),
+		fx.Populate(&t),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: t.onStart,
+					OnStop:  t.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (t *Telemetry) onStart(ctx context.Context) error {
+
+	logger.Info("Starting telemetry",
+		zap.Bool("enabled", t.Enabled()),
+		zap.Bool("tracer_provider", t.params.TracerProvider != nil),
+	)
+
+	return nil
+}
+
+func (t *Telemetry) onStop(ctx context.Context) error {
+
+	t.logger.Info("Stopped telemetry")
+
+	return nil
+}
+
+// Enabled reports whether spans are recorded. It is false for a nil
+// Telemetry, so connectors need not check for the module.
+func (t *Telemetry) Enabled() bool {
+	return t != nil && t.config.Enabled
+}
+
+// Instrument adds otelaws middleware to the clients created from cfg by
+// the connector of scope. Call it before creating them.
+func (t *Telemetry) Instrument(cfg *aws.Config, scope string) {
+	if !t.Enabled() {
+		return
+	}
+
+	otelaws.AppendMiddlewares(&cfg.APIOptions,
+		otelaws.WithTracerProvider(t.provider),
+		otelaws.WithAttributeSetter(
+			otelaws.DefaultAttributeSetter,
+			func(ctx context.Context, in middleware.InitializeInput) []attribute.KeyValue {
+				return []attribute.KeyValue{AttrConnector.String(scope)}
+			},
+		),
+	)
+}
+
+// Start begins the span of method of the connector type namespace of
+// scope, which the AWS calls made with the returned context are children
+// of. End it with End. Without Telemetry, or when it is disabled, the
+// span records nothing.
+func (t *Telemetry) Start(ctx context.Context, scope string, namespace string, method string) (context.Context, trace.Span) {
+	if !t.Enabled() {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	return t.tracer.Start(ctx, namespace+"."+method,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			AttrConnector.String(scope),
+			AttrNamespace.String(namespace),
+			AttrMethod.String(method),
+		),
+	)
+}
+
+// End ends span, recording err on it.
+func (t *Telemetry) End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
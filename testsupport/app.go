@@ -0,0 +1,25 @@
+package testsupport
+
+import (
+	"testing"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap/zaptest"
+)
+
+// NewApp starts an app of the given modules with a logger writing to the
+// test log, and stops it when the test ends. Call it after Use and Set,
+// since connectors read their config as they start.
+func NewApp(t *testing.T, opts ...fx.Option) *fxtest.App {
+	t.Helper()
+
+	app := fxtest.New(t, append([]fx.Option{
+		fx.Supply(zaptest.NewLogger(t)),
+	}, opts...)...)
+
+	app.RequireStart()
+	t.Cleanup(app.RequireStop)
+
+	return app
+}
@@ -0,0 +1,145 @@
+package testsupport
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/spf13/viper"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	DefaultLocalStackImage = "localstack/localstack:3.6"
+	DefaultMinIOImage      = "minio/minio:RELEASE.2024-07-31T05-46-26Z"
+	DefaultElasticMQImage  = "softwaremill/elasticmq-native:1.6.7"
+)
+
+// MinIO checks credentials and wants a secret of at least 8 characters.
+const (
+	minioKey    = "minioadmin"
+	minioSecret = "minioadmin"
+)
+
+// Service is an emulator container serving the AWS APIs at Endpoint.
+// Integration tests start one, point the connectors at it and provision
+// what they need:
+//
+//	func TestUpload(t *testing.T) {
+//		stack := testsupport.StartLocalStack(t, "s3")
+//		stack.Use(t)
+//		stack.Provision(t, testsupport.Resources{Buckets: []string{"uploads"}})
+//
+//		testsupport.Set(t, "s3.bucket_name", "uploads")
+//
+//		var bucket *bucket_connector.BucketConnector
+//		testsupport.NewApp(t, bucket_connector.Module("s3"), fx.Populate(&bucket))
+//		...
+//	}
+//
+// Tests are skipped when Docker is not available.
+type Service struct {
+	Endpoint  string
+	Key       string
+	Secret    string
+	Container testcontainers.Container
+}
+
+// StartLocalStack starts LocalStack with every service, or only those
+// listed, e.g. "s3", "sqs", "dynamodb". It stops when the test ends.
+func StartLocalStack(t *testing.T, services ...string) *Service {
+	req := testcontainers.ContainerRequest{
+		Image:        DefaultLocalStackImage,
+		ExposedPorts: []string{"4566/tcp"},
+		Env:          map[string]string{},
+		WaitingFor:   wait.ForHTTP("/_localstack/health").WithPort("4566/tcp"),
+	}
+
+	if len(services) > 0 {
+		req.Env["SERVICES"] = strings.Join(services, ",")
+	}
+
+	return start(t, req, "4566/tcp", "test", "test")
+}
+
+// StartMinIO starts MinIO for S3.
+func StartMinIO(t *testing.T) *Service {
+	req := testcontainers.ContainerRequest{
+		Image:        DefaultMinIOImage,
+		ExposedPorts: []string{"9000/tcp"},
+		Cmd:          []string{"server", "/data"},
+		Env: map[string]string{
+			"MINIO_ROOT_USER":     minioKey,
+			"MINIO_ROOT_PASSWORD": minioSecret,
+		},
+		WaitingFor: wait.ForHTTP("/minio/health/live").WithPort("9000/tcp"),
+	}
+
+	return start(t, req, "9000/tcp", minioKey, minioSecret)
+}
+
+// StartElasticMQ starts ElasticMQ for SQS.
+func StartElasticMQ(t *testing.T) *Service {
+	req := testcontainers.ContainerRequest{
+		Image:        DefaultElasticMQImage,
+		ExposedPorts: []string{"9324/tcp"},
+		WaitingFor:   wait.ForListeningPort("9324/tcp"),
+	}
+
+	return start(t, req, "9324/tcp", "test", "test")
+}
+
+func start(t *testing.T, req testcontainers.ContainerRequest, port string, key string, secret string) *Service {
+	t.Helper()
+
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("start %s: %v", req.Image, err)
+	}
+
+	t.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			t.Logf("terminate %s: %v", req.Image, err)
+		}
+	})
+
+	endpoint, err := container.PortEndpoint(ctx, nat.Port(port), "http")
+	if err != nil {
+		t.Fatalf("endpoint of %s: %v", req.Image, err)
+	}
+
+	return &Service{
+		Endpoint:  endpoint,
+		Key:       key,
+		Secret:    secret,
+		Container: container,
+	}
+}
+
+// Use points every connector at the service through local mode until
+// the test ends.
+func (s *Service) Use(t *testing.T) {
+	Set(t, "aws.local_mode", true)
+	Set(t, "aws.local_endpoint", s.Endpoint)
+	Set(t, "aws.local_key", s.Key)
+	Set(t, "aws.local_secret", s.Secret)
+}
+
+// Set sets a config key until the test ends.
+func Set(t *testing.T, key string, value interface{}) {
+	previous := viper.Get(key)
+	viper.Set(key, value)
+
+	t.Cleanup(func() {
+		viper.Set(key, previous)
+	})
+}
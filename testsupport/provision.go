@@ -0,0 +1,166 @@
+package testsupport
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Region is where resources are provisioned. Emulators accept any, but
+// connectors have to use the same one to see them.
+const Region = "us-west-1"
+
+// Resources are what a test needs to exist before the connectors start.
+type Resources struct {
+	Buckets []string
+	Queues  []string
+	Tables  []Table
+}
+
+// Table is a DynamoDB table with string keys. RangeKey and TTLAttribute
+// are optional.
+type Table struct {
+	Name         string
+	HashKey      string
+	RangeKey     string
+	TTLAttribute string
+}
+
+// Provision creates resources on the service. Queues named *.fifo are
+// FIFO queues.
+func (s *Service) Provision(t *testing.T, resources Resources) {
+	t.Helper()
+
+	ctx := context.Background()
+	cfg := s.config(t)
+
+	if len(resources.Buckets) > 0 {
+		client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+			o.UsePathStyle = true
+		})
+
+		for _, bucket := range resources.Buckets {
+			_, err := client.CreateBucket(ctx, &s3.CreateBucketInput{
+				Bucket: aws.String(bucket),
+				CreateBucketConfiguration: &s3types.CreateBucketConfiguration{
+					LocationConstraint: s3types.BucketLocationConstraint(Region),
+				},
+			})
+			if err != nil {
+				t.Fatalf("create bucket %s: %v", bucket, err)
+			}
+		}
+	}
+
+	if len(resources.Queues) > 0 {
+		client := sqs.NewFromConfig(cfg)
+
+		for _, queue := range resources.Queues {
+			input := &sqs.CreateQueueInput{QueueName: aws.String(queue)}
+			if strings.HasSuffix(queue, ".fifo") {
+				input.Attributes = map[string]string{
+					string(sqstypes.QueueAttributeNameFifoQueue): "true",
+				}
+			}
+
+			if _, err := client.CreateQueue(ctx, input); err != nil {
+				t.Fatalf("create queue %s: %v", queue, err)
+			}
+		}
+	}
+
+	if len(resources.Tables) > 0 {
+		client := dynamodb.NewFromConfig(cfg)
+
+		for _, table := range resources.Tables {
+			createTable(ctx, t, client, table)
+		}
+	}
+}
+
+// QueueURL returns the URL of a provisioned queue.
+func (s *Service) QueueURL(t *testing.T, queue string) string {
+	t.Helper()
+
+	result, err := sqs.NewFromConfig(s.config(t)).GetQueueUrl(context.Background(), &sqs.GetQueueUrlInput{
+		QueueName: aws.String(queue),
+	})
+	if err != nil {
+		t.Fatalf("get queue url %s: %v", queue, err)
+	}
+
+	return aws.ToString(result.QueueUrl)
+}
+
+func createTable(ctx context.Context, t *testing.T, client *dynamodb.Client, table Table) {
+	input := &dynamodb.CreateTableInput{
+		TableName:   aws.String(table.Name),
+		BillingMode: dynamodbtypes.BillingModePayPerRequest,
+		AttributeDefinitions: []dynamodbtypes.AttributeDefinition{
+			{AttributeName: aws.String(table.HashKey), AttributeType: dynamodbtypes.ScalarAttributeTypeS},
+		},
+		KeySchema: []dynamodbtypes.KeySchemaElement{
+			{AttributeName: aws.String(table.HashKey), KeyType: dynamodbtypes.KeyTypeHash},
+		},
+	}
+
+	if table.RangeKey != "" {
+		input.AttributeDefinitions = append(input.AttributeDefinitions, dynamodbtypes.AttributeDefinition{
+			AttributeName: aws.String(table.RangeKey),
+			AttributeType: dynamodbtypes.ScalarAttributeTypeS,
+		})
+		input.KeySchema = append(input.KeySchema, dynamodbtypes.KeySchemaElement{
+			AttributeName: aws.String(table.RangeKey),
+			KeyType:       dynamodbtypes.KeyTypeRange,
+		})
+	}
+
+	if _, err := client.CreateTable(ctx, input); err != nil {
+		t.Fatalf("create table %s: %v", table.Name, err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(client)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(table.Name)}, time.Minute); err != nil {
+		t.Fatalf("wait for table %s: %v", table.Name, err)
+	}
+
+	if table.TTLAttribute == "" {
+		return
+	}
+
+	_, err := client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(table.Name),
+		TimeToLiveSpecification: &dynamodbtypes.TimeToLiveSpecification{
+			AttributeName: aws.String(table.TTLAttribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		t.Fatalf("enable ttl on %s: %v", table.Name, err)
+	}
+}
+
+func (s *Service) config(t *testing.T) aws.Config {
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(Region),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(s.Key, s.Secret, "")),
+	)
+	if err != nil {
+		t.Fatalf("load aws config: %v", err)
+	}
+
+	cfg.BaseEndpoint = aws.String(s.Endpoint)
+
+	return cfg
+}
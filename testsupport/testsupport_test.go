@@ -0,0 +1,135 @@
+package testsupport_test
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/fx"
+
+	"github.com/elmntri/zeitgeber-aws-modules/bucket_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/dynamodb_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/sqs_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/testsupport"
+)
+
+type item struct {
+	PK    string `dynamodbav:"pk"`
+	Value string `dynamodbav:"value"`
+}
+
+func TestLocalStack(t *testing.T) {
+	stack := testsupport.StartLocalStack(t, "s3", "sqs", "dynamodb")
+	stack.Use(t)
+	stack.Provision(t, testsupport.Resources{
+		Buckets: []string{"uploads"},
+		Queues:  []string{"jobs", "events.fifo"},
+		Tables:  []testsupport.Table{{Name: "items", HashKey: "pk", TTLAttribute: "expires"}},
+	})
+
+	testsupport.Set(t, "s3.bucket_name", "uploads")
+	testsupport.Set(t, "sqs.queue_name", "jobs")
+	testsupport.Set(t, "dynamodb.table_name", "items")
+
+	var (
+		bucket *bucket_connector.BucketConnector
+		queue  *sqs_connector.SQSConnector
+		table  *dynamodb_connector.DynamoDBConnector
+	)
+	testsupport.NewApp(t,
+		bucket_connector.Module("s3"),
+		sqs_connector.Module("sqs"),
+		dynamodb_connector.Module("dynamodb"),
+		fx.Populate(&bucket, &queue, &table),
+	)
+
+	ctx := context.Background()
+
+	roundTripObject(ctx, t, bucket)
+	roundTripMessage(ctx, t, queue)
+
+	if err := table.PutItem(ctx, item{PK: "a", Value: "x"}); err != nil {
+		t.Fatalf("put item: %v", err)
+	}
+
+	var got item
+	if err := table.GetItem(ctx, map[string]string{"pk": "a"}, &got); err != nil {
+		t.Fatalf("get item: %v", err)
+	}
+	if got.Value != "x" {
+		t.Errorf("item value = %q, want %q", got.Value, "x")
+	}
+
+	ttl, err := table.DescribeTTL(ctx)
+	if err != nil {
+		t.Fatalf("describe ttl: %v", err)
+	}
+	if name := ttl.AttributeName; name == nil || *name != "expires" {
+		t.Errorf("ttl attribute = %v, want expires", name)
+	}
+
+	if url := stack.QueueURL(t, "events.fifo"); url == "" {
+		t.Error("no url for the fifo queue")
+	}
+}
+
+func TestMinIO(t *testing.T) {
+	minio := testsupport.StartMinIO(t)
+	minio.Use(t)
+	minio.Provision(t, testsupport.Resources{Buckets: []string{"uploads"}})
+
+	testsupport.Set(t, "s3.bucket_name", "uploads")
+
+	var bucket *bucket_connector.BucketConnector
+	testsupport.NewApp(t, bucket_connector.Module("s3"), fx.Populate(&bucket))
+
+	roundTripObject(context.Background(), t, bucket)
+}
+
+func TestElasticMQ(t *testing.T) {
+	elasticMQ := testsupport.StartElasticMQ(t)
+	elasticMQ.Use(t)
+	elasticMQ.Provision(t, testsupport.Resources{Queues: []string{"jobs"}})
+
+	testsupport.Set(t, "sqs.queue_url", elasticMQ.QueueURL(t, "jobs"))
+
+	var queue *sqs_connector.SQSConnector
+	testsupport.NewApp(t, sqs_connector.Module("sqs"), fx.Populate(&queue))
+
+	roundTripMessage(context.Background(), t, queue)
+}
+
+func roundTripObject(ctx context.Context, t *testing.T, bucket *bucket_connector.BucketConnector) {
+	t.Helper()
+
+	if err := bucket.PutObject(ctx, "a/b.txt", []byte("hello"), "text/plain"); err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+
+	data, err := bucket.GetObject(ctx, "a/b.txt")
+	if err != nil {
+		t.Fatalf("get object: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("object = %q, want %q", data, "hello")
+	}
+}
+
+func roundTripMessage(ctx context.Context, t *testing.T, queue *sqs_connector.SQSConnector) {
+	t.Helper()
+
+	if _, err := queue.SendMessage(ctx, "hello"); err != nil {
+		t.Fatalf("send message: %v", err)
+	}
+
+	messages, err := queue.ReceiveMessages(ctx, 1, 5)
+	if err != nil {
+		t.Fatalf("receive messages: %v", err)
+	}
+	if len(messages) != 1 || *messages[0].Body != "hello" {
+		t.Fatalf("messages = %v, want one saying hello", messages)
+	}
+
+	if err := queue.DeleteMessage(ctx, *messages[0].ReceiptHandle); err != nil {
+		t.Fatalf("delete message: %v", err)
+	}
+}
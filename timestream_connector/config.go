@@ -0,0 +1,32 @@
+package timestream_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. TIMESTREAM_DATABASE for the timestream
+// scope.
+type Config struct {
+	Database          string            `mapstructure:"database" default:""`
+	Table             string            `mapstructure:"table" default:""`
+	Dimensions        map[string]string `mapstructure:"dimensions"`
+	BufferSize        int               `mapstructure:"buffer_size" default:"10000"`
+	FlushInterval     int               `mapstructure:"flush_interval" default:"1"`
+	MaxRetries        int               `mapstructure:"max_retries" default:"3"`
+	TimestreamKey     string            `mapstructure:"timestream_key" default:"ABCDE"`
+	TimestreamSecret  string            `mapstructure:"timestream_secret" default:"example_secret"`
+	TimestreamToken   string            `mapstructure:"timestream_token" default:""`
+	TimestreamRegion  string            `mapstructure:"timestream_region" default:"us-west-1"`
+	VerifyCredentials bool              `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool              `mapstructure:"preflight" default:"false"`
+}
+
+func (c *TimestreamConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *TimestreamConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,348 @@
+package timestream_connector
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/timestreamquery"
+	"github.com/aws/aws-sdk-go-v2/service/timestreamwrite"
+	"github.com/aws/aws-sdk-go-v2/service/timestreamwrite/types"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/batcher"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultDatabase         = ""
+	DefaultTable            = ""
+	DefaultBufferSize       = 10000
+	DefaultFlushInterval    = 1
+	DefaultMaxRetries       = 3
+	DefaultTimestreamKey    = "ABCDE"
+	DefaultTimestreamSecret = "example_secret"
+	DefaultTimestreamToken  = ""
+	DefaultTimestreamRegion = "us-west-1"
+)
+
+var preflightActions = []string{
+	"timestream:DescribeEndpoints",
+	"timestream:WriteRecords",
+	"timestream:Select",
+}
+
+type TimestreamConnector struct {
+	params      Params
+	logger      *zap.Logger
+	client      *timestreamwrite.Client
+	queryClient *timestreamquery.Client
+	scope       string
+	config      Config
+	tracker     *inflight.Tracker
+
+	database string
+	table    string
+
+	// common holds the configured dimensions sent once per WriteRecords
+	// call instead of with every record
+	common *types.Record
+
+	rejectedMu sync.RWMutex
+	rejected   []RejectedHandler
+
+	batcher *batcher.Batcher[types.Record]
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig    `optional:"true"`
+	CredentialChain *awscredentials.Chain   `optional:"true"`
+	Tracer          *xray.Tracer            `optional:"true"`
+	Breaker         *circuitbreaker.Breaker `optional:"true"`
+	Prometheus      *metrics.Metrics        `optional:"true"`
+	Telemetry       *telemetry.Telemetry    `optional:"true"`
+	Health          *health.Health          `optional:"true"`
+	Faults          *faults.Injector        `optional:"true"`
+	Client          *timestreamwrite.Client `optional:"true"`
+	QueryClient     *timestreamquery.Client `optional:"true"`
+}
+
+// Module provides the TimestreamConnector of scope. It uses the
+// *timestreamwrite.Client and *timestreamquery.Client in the graph, if
+// any, instead of building them, and adds the connector's middleware to
+// them.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the TimestreamConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *timestreamwrite.Client and
+// *timestreamquery.Client named after scope, if any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *TimestreamConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*timestreamwrite.Client](scope, named),
+		instance.Client[*timestreamquery.Client](scope, named),
+		instance.Provide[*TimestreamConnector](scope, named, func(p Params) (*TimestreamConnector, error) {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &TimestreamConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			// Loaded here, as the buffer and common attributes are set up before start
+			if err := moduleconfig.Load(scope, &c.config); err != nil {
+				return nil, err
+			}
+
+			c.batcher = batcher.New(batcher.Options[types.Record]{
+				BufferSize: c.config.BufferSize,
+				BatchSize:  maxBatchRecords,
+				Interval:   time.Duration(c.config.FlushInterval) * time.Second,
+				Block:      true,
+				Send:       c.send,
+				OnError: func(err error) {
+					c.logger.Error("Flush records error", zap.Error(err))
+				},
+			})
+			c.common = commonAttributes(c.config.Dimensions)
+
+			return c, nil
+		}),
+		instance.Export(scope, named, func(c *TimestreamConnector) TimeSeriesStore {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *TimestreamConnector) onStart(ctx context.Context) error {
+	c.database = c.config.Database
+	c.table = c.config.Table
+
+	c.logger.Info("Starting TimestreamConnector",
+		zap.String("database", c.database),
+		zap.String("table", c.table),
+		zap.String("timestream_region", c.config.TimestreamRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	// Both clients discover their cell endpoints on first use
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = timestreamwrite.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = timestreamwrite.New(c.client.Options(), func(o *timestreamwrite.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	c.queryClient = c.params.QueryClient
+	if c.queryClient == nil {
+		c.queryClient = timestreamquery.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.queryClient = timestreamquery.New(c.queryClient.Options(), func(o *timestreamquery.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	c.batcher.Start(inflight.Internal(context.Background()))
+
+	return nil
+}
+
+func (c *TimestreamConnector) onStop(ctx context.Context) error {
+
+	c.batcher.Stop()
+
+	c.tracker.Shutdown(ctx)
+
+	// Write whatever is left before the process exits
+	if err := c.Flush(inflight.Internal(ctx)); err != nil {
+		c.logger.Warn("Flush records on shutdown error", zap.Error(err))
+	}
+
+	c.logger.Info("Stopped TimestreamConnector")
+
+	return nil
+}
+
+func (c *TimestreamConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.TimestreamRegion,
+		Key:         c.config.TimestreamKey,
+		Secret:      c.config.TimestreamSecret,
+		Token:       c.config.TimestreamToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *TimestreamConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("timestream_region")
+
+	v.Required("database", "table")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("timestream_key", DefaultTimestreamKey)
+		v.NotPlaceholder("timestream_secret", DefaultTimestreamSecret)
+	}
+
+	return v.Err()
+}
+
+// probe reads the table.
+func (c *TimestreamConnector) probe(ctx context.Context) error {
+	_, err := c.client.DescribeTable(ctx, &timestreamwrite.DescribeTableInput{
+		DatabaseName: aws.String(c.config.Database),
+		TableName:    aws.String(c.config.Table),
+	})
+
+	return err
+}
+
+// commonAttributes turns the configured dimensions into the attributes
+// shared by every record of a write, or nil when there are none.
+func commonAttributes(dimensions map[string]string) *types.Record {
+	if len(dimensions) == 0 {
+		return nil
+	}
+
+	return &types.Record{
+		Dimensions: Dimensions(dimensions),
+	}
+}
+
+// Dimensions converts a map to Timestream dimensions sorted by name.
+func Dimensions(dimensions map[string]string) []types.Dimension {
+	names := make([]string, 0, len(dimensions))
+	for name := range dimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]types.Dimension, len(names))
+	for i, name := range names {
+		result[i] = types.Dimension{
+			Name:  aws.String(name),
+			Value: aws.String(dimensions[name]),
+		}
+	}
+
+	return result
+}
+
+func (c *TimestreamConnector) GetClient() *timestreamwrite.Client {
+	return c.client
+}
+
+func (c *TimestreamConnector) GetQueryClient() *timestreamquery.Client {
+	return c.queryClient
+}
@@ -0,0 +1,19 @@
+package timestream_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/timestreamwrite/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . TimeSeriesStore
+
+// TimeSeriesStore writes and queries records of the table.
+type TimeSeriesStore interface {
+	Put(ctx context.Context, record types.Record) error
+	WriteRecords(ctx context.Context, common *types.Record, records ...types.Record) error
+	Flush(ctx context.Context) error
+	Query(ctx context.Context, sql string) (*QueryResult, error)
+}
+
+var _ TimeSeriesStore = (*TimestreamConnector)(nil)
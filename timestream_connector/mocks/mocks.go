@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/timestream_connector (interfaces: TimeSeriesStore)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . TimeSeriesStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/timestreamwrite/types"
+	timestream_connector "github.com/elmntri/zeitgeber-aws-modules/timestream_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTimeSeriesStore is a mock of TimeSeriesStore interface.
+type MockTimeSeriesStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTimeSeriesStoreMockRecorder
+}
+
+// MockTimeSeriesStoreMockRecorder is the mock recorder for MockTimeSeriesStore.
+type MockTimeSeriesStoreMockRecorder struct {
+	mock *MockTimeSeriesStore
+}
+
+// NewMockTimeSeriesStore creates a new mock instance.
+func NewMockTimeSeriesStore(ctrl *gomock.Controller) *MockTimeSeriesStore {
+	mock := &MockTimeSeriesStore{ctrl: ctrl}
+	mock.recorder = &MockTimeSeriesStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTimeSeriesStore) EXPECT() *MockTimeSeriesStoreMockRecorder {
+	return m.recorder
+}
+
+// Flush mocks base method.
+func (m *MockTimeSeriesStore) Flush(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockTimeSeriesStoreMockRecorder) Flush(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockTimeSeriesStore)(nil).Flush), arg0)
+}
+
+// Put mocks base method.
+func (m *MockTimeSeriesStore) Put(arg0 context.Context, arg1 types.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockTimeSeriesStoreMockRecorder) Put(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockTimeSeriesStore)(nil).Put), arg0, arg1)
+}
+
+// Query mocks base method.
+func (m *MockTimeSeriesStore) Query(arg0 context.Context, arg1 string) (*timestream_connector.QueryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query", arg0, arg1)
+	ret0, _ := ret[0].(*timestream_connector.QueryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockTimeSeriesStoreMockRecorder) Query(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockTimeSeriesStore)(nil).Query), arg0, arg1)
+}
+
+// WriteRecords mocks base method.
+func (m *MockTimeSeriesStore) WriteRecords(arg0 context.Context, arg1 *types.Record, arg2 ...types.Record) error {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WriteRecords", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteRecords indicates an expected call of WriteRecords.
+func (mr *MockTimeSeriesStoreMockRecorder) WriteRecords(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteRecords", reflect.TypeOf((*MockTimeSeriesStore)(nil).WriteRecords), varargs...)
+}
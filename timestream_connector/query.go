@@ -0,0 +1,193 @@
+package timestream_connector
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/timestreamquery"
+	"github.com/aws/aws-sdk-go-v2/service/timestreamquery/types"
+)
+
+// Timestream renders dates and timestamps in these layouts.
+const (
+	dateLayout      = "2006-01-02"
+	timestampLayout = "2006-01-02 15:04:05.999999999"
+)
+
+type Column struct {
+	Name string
+	Type types.ScalarType
+}
+
+// QueryResult holds the rows of a query keyed by column name. BIGINT and
+// INTEGER values are int64, DOUBLE float64, BOOLEAN bool, DATE and
+// TIMESTAMP time.Time in UTC and NULL nil; other scalars stay strings.
+// Arrays are []interface{}, rows map[string]interface{} and time series
+// []TimeSeriesPoint.
+type QueryResult struct {
+	QueryID string
+	Columns []Column
+	Rows    []map[string]interface{}
+}
+
+type TimeSeriesPoint struct {
+	Time  time.Time
+	Value interface{}
+}
+
+// Query runs sql and reads every page of its results. Tables are named
+// "database"."table" in sql.
+func (c *TimestreamConnector) Query(ctx context.Context, sql string) (_ *QueryResult, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TimestreamConnector", "Query")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	paginator := timestreamquery.NewQueryPaginator(c.queryClient, &timestreamquery.QueryInput{
+		QueryString: aws.String(sql),
+	})
+
+	result := &QueryResult{}
+
+	var columns []types.ColumnInfo
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			c.logger.Error("Query error", zap.String("query_id", result.QueryID), zap.Error(err))
+			return nil, err
+		}
+
+		result.QueryID = aws.ToString(page.QueryId)
+
+		if columns == nil && len(page.ColumnInfo) > 0 {
+			columns = page.ColumnInfo
+			for _, column := range columns {
+				result.Columns = append(result.Columns, Column{
+					Name: aws.ToString(column.Name),
+					Type: scalarType(column.Type),
+				})
+			}
+		}
+
+		for _, row := range page.Rows {
+			m, err := rowValue(columns, row.Data)
+			if err != nil {
+				return nil, err
+			}
+
+			result.Rows = append(result.Rows, m)
+		}
+	}
+
+	return result, nil
+}
+
+func rowValue(columns []types.ColumnInfo, data []types.Datum) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, len(columns))
+
+	for i, column := range columns {
+		if i >= len(data) {
+			m[aws.ToString(column.Name)] = nil
+			continue
+		}
+
+		value, err := datumValue(column.Type, data[i])
+		if err != nil {
+			return nil, err
+		}
+
+		m[aws.ToString(column.Name)] = value
+	}
+
+	return m, nil
+}
+
+func datumValue(t *types.Type, datum types.Datum) (interface{}, error) {
+	switch {
+	case aws.ToBool(datum.NullValue):
+		return nil, nil
+
+	case datum.ScalarValue != nil:
+		return scalarValue(*datum.ScalarValue, scalarType(t))
+
+	case datum.ArrayValue != nil:
+		var elemType *types.Type
+		if t != nil && t.ArrayColumnInfo != nil {
+			elemType = t.ArrayColumnInfo.Type
+		}
+
+		values := make([]interface{}, len(datum.ArrayValue))
+		for i, elem := range datum.ArrayValue {
+			value, err := datumValue(elemType, elem)
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = value
+		}
+
+		return values, nil
+
+	case datum.RowValue != nil:
+		var columns []types.ColumnInfo
+		if t != nil {
+			columns = t.RowColumnInfo
+		}
+
+		return rowValue(columns, datum.RowValue.Data)
+
+	case datum.TimeSeriesValue != nil:
+		var valueType *types.Type
+		if t != nil && t.TimeSeriesMeasureValueColumnInfo != nil {
+			valueType = t.TimeSeriesMeasureValueColumnInfo.Type
+		}
+
+		points := make([]TimeSeriesPoint, len(datum.TimeSeriesValue))
+		for i, point := range datum.TimeSeriesValue {
+			at, err := time.Parse(timestampLayout, aws.ToString(point.Time))
+			if err != nil {
+				return nil, err
+			}
+
+			var value interface{}
+			if point.Value != nil {
+				if value, err = datumValue(valueType, *point.Value); err != nil {
+					return nil, err
+				}
+			}
+
+			points[i] = TimeSeriesPoint{Time: at, Value: value}
+		}
+
+		return points, nil
+	}
+
+	return nil, nil
+}
+
+func scalarValue(value string, scalarType types.ScalarType) (interface{}, error) {
+	switch scalarType {
+	case types.ScalarTypeBigint, types.ScalarTypeInteger:
+		return strconv.ParseInt(value, 10, 64)
+	case types.ScalarTypeDouble:
+		return strconv.ParseFloat(value, 64)
+	case types.ScalarTypeBoolean:
+		return strconv.ParseBool(value)
+	case types.ScalarTypeDate:
+		return time.Parse(dateLayout, value)
+	case types.ScalarTypeTimestamp:
+		return time.Parse(timestampLayout, value)
+	}
+
+	return value, nil
+}
+
+func scalarType(t *types.Type) types.ScalarType {
+	if t == nil {
+		return ""
+	}
+
+	return t.ScalarType
+}
@@ -0,0 +1,215 @@
+package timestream_connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/timestreamwrite"
+	"github.com/aws/aws-sdk-go-v2/service/timestreamwrite/types"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// maxBatchRecords is the number of records WriteRecords accepts per call.
+const maxBatchRecords = 100
+
+var ErrRecordsRejected = awserrors.New(awserrors.ErrValidation, "records rejected")
+
+// RejectedRecord is a record Timestream refused to store, e.g. because
+// its time is outside the memory store retention or a record with the
+// same dimensions, time and measure name but another value exists.
+type RejectedRecord struct {
+	Record          types.Record
+	Reason          string
+	ExistingVersion *int64
+}
+
+// RejectedRecordsError lists the records of a write that were rejected.
+// The other records of the write were stored.
+type RejectedRecordsError struct {
+	Records []RejectedRecord
+}
+
+func (e *RejectedRecordsError) Error() string {
+	if len(e.Records) == 0 {
+		return ErrRecordsRejected.Error()
+	}
+
+	return fmt.Sprintf("%d records rejected: %s", len(e.Records), e.Records[0].Reason)
+}
+
+func (e *RejectedRecordsError) Unwrap() error {
+	return ErrRecordsRejected
+}
+
+// RejectedHandler receives the rejected records of buffered writes, e.g.
+// to send them to a dead letter queue. Rejected records are not retried.
+type RejectedHandler func(ctx context.Context, records []RejectedRecord)
+
+// OnRejected adds a handler for records rejected while flushing.
+func (c *TimestreamConnector) OnRejected(handler RejectedHandler) {
+	c.rejectedMu.Lock()
+	defer c.rejectedMu.Unlock()
+
+	c.rejected = append(c.rejected, handler)
+}
+
+// NewRecord returns a DOUBLE measure at t with millisecond precision.
+func NewRecord(measureName string, value float64, t time.Time, dimensions map[string]string) types.Record {
+	return types.Record{
+		Dimensions:       Dimensions(dimensions),
+		MeasureName:      aws.String(measureName),
+		MeasureValue:     aws.String(strconv.FormatFloat(value, 'f', -1, 64)),
+		MeasureValueType: types.MeasureValueTypeDouble,
+		Time:             aws.String(strconv.FormatInt(t.UnixMilli(), 10)),
+		TimeUnit:         types.TimeUnitMilliseconds,
+	}
+}
+
+// NewMultiRecord returns a multi-measure record of DOUBLE values at t with
+// millisecond precision.
+func NewMultiRecord(measureName string, values map[string]float64, t time.Time, dimensions map[string]string) types.Record {
+	measures := make([]types.MeasureValue, 0, len(values))
+	for name, value := range values {
+		measures = append(measures, types.MeasureValue{
+			Name:  aws.String(name),
+			Value: aws.String(strconv.FormatFloat(value, 'f', -1, 64)),
+			Type:  types.MeasureValueTypeDouble,
+		})
+	}
+
+	return types.Record{
+		Dimensions:       Dimensions(dimensions),
+		MeasureName:      aws.String(measureName),
+		MeasureValues:    measures,
+		MeasureValueType: types.MeasureValueTypeMulti,
+		Time:             aws.String(strconv.FormatInt(t.UnixMilli(), 10)),
+		TimeUnit:         types.TimeUnitMilliseconds,
+	}
+}
+
+// Put buffers a record for the next write to the configured table. When
+// buffer_size records are waiting, Put blocks until a batch has been
+// written or ctx is done.
+func (c *TimestreamConnector) Put(ctx context.Context, record types.Record) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TimestreamConnector", "Put")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.batcher.Add(ctx, record)
+}
+
+// Flush writes all buffered records. Rejected records go to the
+// OnRejected handlers; batches still throttled after max_retries are
+// dropped. Both are reported in the returned error.
+func (c *TimestreamConnector) Flush(ctx context.Context) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TimestreamConnector", "Flush")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	return c.batcher.Flush(ctx)
+}
+
+// send splits records into WriteRecords calls.
+func (c *TimestreamConnector) send(ctx context.Context, records []types.Record) error {
+	if c.client == nil {
+		return fmt.Errorf("%s: connector is not started", c.scope)
+	}
+
+	var errs []error
+	for offset := 0; offset < len(records); offset += maxBatchRecords {
+		err := c.WriteRecords(ctx, c.common, records[offset:min(offset+maxBatchRecords, len(records))]...)
+
+		var rejected *RejectedRecordsError
+		if errors.As(err, &rejected) {
+			c.handleRejected(ctx, rejected.Records)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WriteRecords writes up to 100 records to the configured table right
+// away. Attributes set on common, such as dimensions, apply to every
+// record. Throttled writes are retried up to max_retries times with an
+// exponential backoff; rejected records are returned in a
+// *RejectedRecordsError.
+func (c *TimestreamConnector) WriteRecords(ctx context.Context, common *types.Record, records ...types.Record) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TimestreamConnector", "WriteRecords")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	maxRetries := c.config.MaxRetries
+	backoff := 100 * time.Millisecond
+
+	for attempt := 0; ; attempt++ {
+		_, err := c.client.WriteRecords(ctx, &timestreamwrite.WriteRecordsInput{
+			DatabaseName:     aws.String(c.database),
+			TableName:        aws.String(c.table),
+			CommonAttributes: common,
+			Records:          records,
+		})
+		if err == nil {
+			return nil
+		}
+
+		var rejected *types.RejectedRecordsException
+		if errors.As(err, &rejected) {
+			return c.rejectedError(records, rejected.RejectedRecords)
+		}
+
+		var throttled *types.ThrottlingException
+		if !errors.As(err, &throttled) || attempt >= maxRetries {
+			c.logger.Error("Write records error", zap.Int("count", len(records)), zap.Error(err))
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		backoff *= 2
+	}
+}
+
+func (c *TimestreamConnector) rejectedError(records []types.Record, rejected []types.RejectedRecord) error {
+	err := &RejectedRecordsError{}
+
+	for _, r := range rejected {
+		if int(r.RecordIndex) >= len(records) {
+			continue
+		}
+
+		err.Records = append(err.Records, RejectedRecord{
+			Record:          records[r.RecordIndex],
+			Reason:          aws.ToString(r.Reason),
+			ExistingVersion: r.ExistingVersion,
+		})
+
+		c.logger.Warn("Record rejected",
+			zap.Int32("record_index", r.RecordIndex),
+			zap.String("measure_name", aws.ToString(records[r.RecordIndex].MeasureName)),
+			zap.String("reason", aws.ToString(r.Reason)),
+		)
+	}
+
+	return err
+}
+
+func (c *TimestreamConnector) handleRejected(ctx context.Context, records []RejectedRecord) {
+	c.rejectedMu.RLock()
+	defer c.rejectedMu.RUnlock()
+
+	for _, handler := range c.rejected {
+		handler(ctx, records)
+	}
+}
@@ -0,0 +1,30 @@
+package transcribe_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. TRANSCRIBE_LANGUAGE_CODE for the
+// transcribe scope.
+type Config struct {
+	LanguageCode      string `mapstructure:"language_code" default:"en-US"`
+	OutputPrefix      string `mapstructure:"output_prefix" default:"transcripts/"`
+	MaxSpeakers       int32  `mapstructure:"max_speakers" default:"0"`
+	PollInterval      int    `mapstructure:"poll_interval" default:"5"`
+	TranscribeKey     string `mapstructure:"transcribe_key" default:"ABCDE"`
+	TranscribeSecret  string `mapstructure:"transcribe_secret" default:"example_secret"`
+	TranscribeToken   string `mapstructure:"transcribe_token" default:""`
+	TranscribeRegion  string `mapstructure:"transcribe_region" default:"us-west-1"`
+	VerifyCredentials bool   `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool   `mapstructure:"preflight" default:"false"`
+}
+
+func (c *TranscribeConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *TranscribeConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,15 @@
+package transcribe_connector
+
+import "context"
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Transcriber
+
+// Transcriber transcribes audio of the bucket.
+type Transcriber interface {
+	Transcribe(ctx context.Context, jobName string, key string) (*Transcript, error)
+	StartTranscription(ctx context.Context, jobName string, key string) error
+	WaitForTranscription(ctx context.Context, jobName string) (*Transcript, error)
+	GetTranscript(ctx context.Context, jobName string) (*Transcript, error)
+}
+
+var _ Transcriber = (*TranscribeConnector)(nil)
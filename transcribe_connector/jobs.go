@@ -0,0 +1,162 @@
+package transcribe_connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/transcribe"
+	"github.com/aws/aws-sdk-go-v2/service/transcribe/types"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrJobFailed   = awserrors.New(awserrors.ErrTransient, "transcription job failed")
+	ErrJobNotReady = awserrors.New(awserrors.ErrConflict, "transcription job not completed")
+)
+
+// JobStateChangeDetailType is the detail-type of the EventBridge events
+// Transcribe emits when a job completes or fails; decode their detail
+// into JobStateChange, e.g. with eventbridge_events.Typed, instead of
+// polling with WaitForTranscription.
+const JobStateChangeDetailType = "Transcribe Job State Change"
+
+type JobStateChange struct {
+	TranscriptionJobName   string `json:"TranscriptionJobName"`
+	TranscriptionJobStatus string `json:"TranscriptionJobStatus"`
+	FailureReason          string `json:"FailureReason"`
+}
+
+// StartTranscription starts a job on an audio object in the bucket. The
+// job name must be unique in the account and region; the transcript is
+// written to output_prefix + jobName + ".json". Without language_code the
+// language is identified.
+func (c *TranscribeConnector) StartTranscription(ctx context.Context, jobName string, key string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranscribeConnector", "StartTranscription")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	params := &transcribe.StartTranscriptionJobInput{
+		TranscriptionJobName: aws.String(jobName),
+		Media: &types.Media{
+			MediaFileUri: aws.String(fmt.Sprintf("s3://%s/%s", c.params.Bucket.GetBucketName(), key)),
+		},
+		OutputBucketName: aws.String(c.params.Bucket.GetBucketName()),
+		OutputKey:        aws.String(c.outputKey(jobName)),
+	}
+
+	if languageCode := c.config.LanguageCode; languageCode != "" {
+		params.LanguageCode = types.LanguageCode(languageCode)
+	} else {
+		params.IdentifyLanguage = aws.Bool(true)
+	}
+
+	if maxSpeakers := c.config.MaxSpeakers; maxSpeakers >= 2 {
+		params.Settings = &types.Settings{
+			ShowSpeakerLabels: aws.Bool(true),
+			MaxSpeakerLabels:  aws.Int32(maxSpeakers),
+		}
+	}
+
+	_, err = c.client.StartTranscriptionJob(ctx, params)
+	if err != nil {
+		c.logger.Error("Start transcription job error", zap.String("job_name", jobName), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *TranscribeConnector) GetTranscriptionJob(ctx context.Context, jobName string) (_ *types.TranscriptionJob, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranscribeConnector", "GetTranscriptionJob")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.GetTranscriptionJob(ctx, &transcribe.GetTranscriptionJobInput{
+		TranscriptionJobName: aws.String(jobName),
+	})
+	if err != nil {
+		c.logger.Error("Get transcription job error", zap.String("job_name", jobName), zap.Error(err))
+		return nil, err
+	}
+
+	return result.TranscriptionJob, nil
+}
+
+// WaitForTranscription polls the job every poll_interval until it ends
+// and returns its transcript. Bound the wait with ctx.
+func (c *TranscribeConnector) WaitForTranscription(ctx context.Context, jobName string) (_ *Transcript, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranscribeConnector", "WaitForTranscription")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Second
+
+	for {
+		job, err := c.GetTranscriptionJob(ctx, jobName)
+		if err != nil {
+			return nil, err
+		}
+
+		switch job.TranscriptionJobStatus {
+		case types.TranscriptionJobStatusCompleted:
+			return c.fetchTranscript(ctx, job)
+
+		case types.TranscriptionJobStatusFailed:
+			return nil, fmt.Errorf("%w: %s: %s", ErrJobFailed, jobName, aws.ToString(job.FailureReason))
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Transcribe starts a job and waits for its transcript.
+func (c *TranscribeConnector) Transcribe(ctx context.Context, jobName string, key string) (_ *Transcript, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranscribeConnector", "Transcribe")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if err := c.StartTranscription(ctx, jobName, key); err != nil {
+		return nil, err
+	}
+
+	return c.WaitForTranscription(ctx, jobName)
+}
+
+// GetTranscript returns the transcript of a completed job, e.g. after a
+// JobStateChange event.
+func (c *TranscribeConnector) GetTranscript(ctx context.Context, jobName string) (_ *Transcript, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranscribeConnector", "GetTranscript")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	job, err := c.GetTranscriptionJob(ctx, jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.TranscriptionJobStatus != types.TranscriptionJobStatusCompleted {
+		return nil, fmt.Errorf("%w: %s: %s", ErrJobNotReady, jobName, job.TranscriptionJobStatus)
+	}
+
+	return c.fetchTranscript(ctx, job)
+}
+
+func (c *TranscribeConnector) fetchTranscript(ctx context.Context, job *types.TranscriptionJob) (*Transcript, error) {
+	jobName := aws.ToString(job.TranscriptionJobName)
+
+	data, err := c.params.Bucket.GetObject(ctx, c.outputKey(jobName))
+	if err != nil {
+		c.logger.Error("Get transcript error", zap.String("job_name", jobName), zap.Error(err))
+		return nil, err
+	}
+
+	return ParseTranscript(data)
+}
+
+func (c *TranscribeConnector) outputKey(jobName string) string {
+	return c.config.OutputPrefix + jobName + ".json"
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/transcribe_connector (interfaces: Transcriber)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . Transcriber
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	transcribe_connector "github.com/elmntri/zeitgeber-aws-modules/transcribe_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTranscriber is a mock of Transcriber interface.
+type MockTranscriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockTranscriberMockRecorder
+}
+
+// MockTranscriberMockRecorder is the mock recorder for MockTranscriber.
+type MockTranscriberMockRecorder struct {
+	mock *MockTranscriber
+}
+
+// NewMockTranscriber creates a new mock instance.
+func NewMockTranscriber(ctrl *gomock.Controller) *MockTranscriber {
+	mock := &MockTranscriber{ctrl: ctrl}
+	mock.recorder = &MockTranscriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTranscriber) EXPECT() *MockTranscriberMockRecorder {
+	return m.recorder
+}
+
+// GetTranscript mocks base method.
+func (m *MockTranscriber) GetTranscript(arg0 context.Context, arg1 string) (*transcribe_connector.Transcript, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTranscript", arg0, arg1)
+	ret0, _ := ret[0].(*transcribe_connector.Transcript)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTranscript indicates an expected call of GetTranscript.
+func (mr *MockTranscriberMockRecorder) GetTranscript(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTranscript", reflect.TypeOf((*MockTranscriber)(nil).GetTranscript), arg0, arg1)
+}
+
+// StartTranscription mocks base method.
+func (m *MockTranscriber) StartTranscription(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartTranscription", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartTranscription indicates an expected call of StartTranscription.
+func (mr *MockTranscriberMockRecorder) StartTranscription(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartTranscription", reflect.TypeOf((*MockTranscriber)(nil).StartTranscription), arg0, arg1, arg2)
+}
+
+// Transcribe mocks base method.
+func (m *MockTranscriber) Transcribe(arg0 context.Context, arg1, arg2 string) (*transcribe_connector.Transcript, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transcribe", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*transcribe_connector.Transcript)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transcribe indicates an expected call of Transcribe.
+func (mr *MockTranscriberMockRecorder) Transcribe(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transcribe", reflect.TypeOf((*MockTranscriber)(nil).Transcribe), arg0, arg1, arg2)
+}
+
+// WaitForTranscription mocks base method.
+func (m *MockTranscriber) WaitForTranscription(arg0 context.Context, arg1 string) (*transcribe_connector.Transcript, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForTranscription", arg0, arg1)
+	ret0, _ := ret[0].(*transcribe_connector.Transcript)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForTranscription indicates an expected call of WaitForTranscription.
+func (mr *MockTranscriberMockRecorder) WaitForTranscription(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForTranscription", reflect.TypeOf((*MockTranscriber)(nil).WaitForTranscription), arg0, arg1)
+}
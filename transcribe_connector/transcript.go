@@ -0,0 +1,163 @@
+package transcribe_connector
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transcript is a completed transcription. Segments group consecutive
+// words by speaker and are empty unless max_speakers enabled speaker
+// labels.
+type Transcript struct {
+	JobName  string
+	Text     string
+	Items    []Item
+	Segments []Segment
+}
+
+// Item is a word or punctuation mark. Punctuation has no timing.
+type Item struct {
+	Type       string
+	Content    string
+	Confidence float64
+	Start      time.Duration
+	End        time.Duration
+	Speaker    string
+}
+
+type Segment struct {
+	Speaker string
+	Start   time.Duration
+	End     time.Duration
+	Text    string
+}
+
+const itemTypePunctuation = "punctuation"
+
+type transcriptResult struct {
+	JobName string `json:"jobName"`
+	Results struct {
+		Transcripts []struct {
+			Transcript string `json:"transcript"`
+		} `json:"transcripts"`
+		SpeakerLabels struct {
+			Segments []struct {
+				Items []struct {
+					StartTime    string `json:"start_time"`
+					SpeakerLabel string `json:"speaker_label"`
+				} `json:"items"`
+			} `json:"segments"`
+		} `json:"speaker_labels"`
+		Items []struct {
+			Type         string `json:"type"`
+			StartTime    string `json:"start_time"`
+			EndTime      string `json:"end_time"`
+			SpeakerLabel string `json:"speaker_label"`
+			Alternatives []struct {
+				Confidence string `json:"confidence"`
+				Content    string `json:"content"`
+			} `json:"alternatives"`
+		} `json:"items"`
+	} `json:"results"`
+}
+
+// ParseTranscript decodes the result JSON written by a transcription job.
+func ParseTranscript(data []byte) (*Transcript, error) {
+	var result transcriptResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	transcript := &Transcript{
+		JobName: result.JobName,
+	}
+
+	texts := make([]string, 0, len(result.Results.Transcripts))
+	for _, t := range result.Results.Transcripts {
+		texts = append(texts, t.Transcript)
+	}
+	transcript.Text = strings.Join(texts, " ")
+
+	// Older results only label speakers in speaker_labels, keyed by the
+	// start time of each word
+	speakers := map[string]string{}
+	for _, segment := range result.Results.SpeakerLabels.Segments {
+		for _, item := range segment.Items {
+			speakers[item.StartTime] = item.SpeakerLabel
+		}
+	}
+
+	speaker := ""
+	for _, raw := range result.Results.Items {
+		item := Item{
+			Type:  raw.Type,
+			Start: seconds(raw.StartTime),
+			End:   seconds(raw.EndTime),
+		}
+
+		if len(raw.Alternatives) > 0 {
+			item.Content = raw.Alternatives[0].Content
+			item.Confidence, _ = strconv.ParseFloat(raw.Alternatives[0].Confidence, 64)
+		}
+
+		// Punctuation belongs to the preceding word's speaker
+		switch {
+		case raw.SpeakerLabel != "":
+			speaker = raw.SpeakerLabel
+		case raw.Type != itemTypePunctuation && speakers[raw.StartTime] != "":
+			speaker = speakers[raw.StartTime]
+		}
+		if len(speakers) > 0 || raw.SpeakerLabel != "" {
+			item.Speaker = speaker
+		}
+
+		transcript.Items = append(transcript.Items, item)
+	}
+
+	transcript.Segments = segments(transcript.Items)
+
+	return transcript, nil
+}
+
+func segments(items []Item) []Segment {
+	var result []Segment
+
+	for _, item := range items {
+		if item.Speaker == "" {
+			continue
+		}
+
+		if n := len(result); n > 0 && result[n-1].Speaker == item.Speaker {
+			segment := &result[n-1]
+
+			if item.Type == itemTypePunctuation {
+				segment.Text += item.Content
+				continue
+			}
+
+			segment.Text += " " + item.Content
+			segment.End = item.End
+			continue
+		}
+
+		result = append(result, Segment{
+			Speaker: item.Speaker,
+			Start:   item.Start,
+			End:     item.End,
+			Text:    item.Content,
+		})
+	}
+
+	return result
+}
+
+func seconds(s string) time.Duration {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(f * float64(time.Second))
+}
@@ -0,0 +1,33 @@
+package translate_connector
+
+import (
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. TRANSLATE_SOURCE_LANGUAGE for the
+// translate scope.
+type Config struct {
+	SourceLanguage    string   `mapstructure:"source_language" default:"auto"`
+	TargetLanguage    string   `mapstructure:"target_language" default:"en"`
+	Terminologies     []string `mapstructure:"terminologies" default:""`
+	DataAccessRoleARN string   `mapstructure:"data_access_role_arn" default:""`
+	OutputPrefix      string   `mapstructure:"output_prefix" default:"translate/output/"`
+	ContentType       string   `mapstructure:"content_type" default:"text/plain"`
+	PollInterval      int      `mapstructure:"poll_interval" default:"30"`
+	TranslateKey      string   `mapstructure:"translate_key" default:"ABCDE"`
+	TranslateSecret   string   `mapstructure:"translate_secret" default:"example_secret"`
+	TranslateToken    string   `mapstructure:"translate_token" default:""`
+	TranslateRegion   string   `mapstructure:"translate_region" default:"us-west-1"`
+	VerifyCredentials bool     `mapstructure:"verify_credentials" default:"false"`
+	Preflight         bool     `mapstructure:"preflight" default:"false"`
+}
+
+func (c *TranslateConnector) initDefaultConfigs() {
+	moduleconfig.Register(c.scope, &Config{})
+}
+
+// Config returns the configuration the module started with.
+func (c *TranslateConnector) Config() Config {
+	return c.config
+}
@@ -0,0 +1,257 @@
+package translate_connector
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/translate"
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awscredentials"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+	"github.com/elmntri/zeitgeber-aws-modules/bucket_connector"
+	"github.com/elmntri/zeitgeber-aws-modules/circuitbreaker"
+	"github.com/elmntri/zeitgeber-aws-modules/faults"
+	"github.com/elmntri/zeitgeber-aws-modules/health"
+	"github.com/elmntri/zeitgeber-aws-modules/identity"
+	"github.com/elmntri/zeitgeber-aws-modules/inflight"
+	"github.com/elmntri/zeitgeber-aws-modules/instance"
+	"github.com/elmntri/zeitgeber-aws-modules/metrics"
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/requestid"
+	"github.com/elmntri/zeitgeber-aws-modules/telemetry"
+	"github.com/elmntri/zeitgeber-aws-modules/validation"
+	"github.com/elmntri/zeitgeber-aws-modules/xray"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultSourceLanguage    = "auto"
+	DefaultTargetLanguage    = "en"
+	DefaultDataAccessRoleArn = ""
+	DefaultOutputPrefix      = "translate/output/"
+	DefaultContentType       = "text/plain"
+	DefaultPollInterval      = 30
+	DefaultTranslateKey      = "ABCDE"
+	DefaultTranslateSecret   = "example_secret"
+	DefaultTranslateToken    = ""
+	DefaultTranslateRegion   = "us-west-1"
+)
+
+var preflightActions = []string{
+	"translate:TranslateText",
+}
+
+// TranslateConnector translates text and, with the bucket connector,
+// batches of documents in its bucket.
+type TranslateConnector struct {
+	params  Params
+	logger  *zap.Logger
+	client  *translate.Client
+	scope   string
+	config  Config
+	tracker *inflight.Tracker
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle       fx.Lifecycle
+	Logger          *zap.Logger
+	Credentials     aws.CredentialsProvider           `optional:"true"`
+	AWSConfig       *awsconfig.AWSConfig              `optional:"true"`
+	CredentialChain *awscredentials.Chain             `optional:"true"`
+	Bucket          *bucket_connector.BucketConnector `optional:"true"`
+	Tracer          *xray.Tracer                      `optional:"true"`
+	Breaker         *circuitbreaker.Breaker           `optional:"true"`
+	Prometheus      *metrics.Metrics                  `optional:"true"`
+	Telemetry       *telemetry.Telemetry              `optional:"true"`
+	Health          *health.Health                    `optional:"true"`
+	Faults          *faults.Injector                  `optional:"true"`
+	Client          *translate.Client                 `optional:"true"`
+}
+
+// Module provides the TranslateConnector of scope. It uses the
+// *translate.Client in the graph, if any, instead of building one, and
+// adds the connector's middleware to it.
+func Module(scope string) fx.Option {
+	return module(scope, false)
+}
+
+// NamedModule provides the TranslateConnector of scope, and its
+// interfaces, named after scope, for apps with more than one; see
+// package instance. It uses the *translate.Client named after scope, if
+// any.
+func NamedModule(scope string) fx.Option {
+	return module(scope, true)
+}
+
+func module(scope string, named bool) fx.Option {
+
+	var c *TranslateConnector
+
+	return fx.Module(
+		scope,
+		instance.Client[*translate.Client](scope, named),
+		instance.Provide[*TranslateConnector](scope, named, func(p Params) *TranslateConnector {
+
+			logger = requestid.Logger(p.Logger.Named(scope))
+
+			c := &TranslateConnector{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			c.initDefaultConfigs()
+
+			return c
+		}),
+		instance.Export(scope, named, func(c *TranslateConnector) Translator {
+			return c
+		}),
+		fx.Populate(&c),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: c.onStart,
+					OnStop:  c.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (c *TranslateConnector) onStart(ctx context.Context) error {
+
+	if err := moduleconfig.Load(c.scope, &c.config); err != nil {
+		c.logger.Error("Load configuration error", zap.Error(err))
+		return err
+	}
+
+	c.logger.Info("Starting TranslateConnector",
+		zap.String("target_language", c.config.TargetLanguage),
+		zap.String("translate_region", c.config.TranslateRegion),
+	)
+
+	if err := c.validate(); err != nil {
+		c.logger.Error("Invalid configuration", zap.Error(err))
+		return err
+	}
+
+	cfg, err := c.loadConfig(ctx)
+	if err != nil {
+		c.logger.Error("Load AWS config error", zap.Error(err))
+		return err
+	}
+
+	c.tracker, err = inflight.NewTracker(c.scope, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.tracker.Instrument(&cfg)
+	awserrors.Instrument(&cfg)
+	requestid.Instrument(&cfg, c.logger)
+
+	if c.params.Tracer != nil {
+		c.params.Tracer.Instrument(&cfg)
+	}
+
+	if c.params.Telemetry != nil {
+		c.params.Telemetry.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Prometheus != nil {
+		c.params.Prometheus.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Breaker != nil {
+		c.params.Breaker.Instrument(&cfg, c.scope)
+	}
+
+	if c.params.Faults != nil {
+		c.params.Faults.Instrument(&cfg, c.scope)
+	}
+
+	if c.config.VerifyCredentials {
+		if _, err := identity.Verify(ctx, cfg, c.logger); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Preflight {
+		identity.Preflight(ctx, cfg, c.logger, preflightActions)
+	}
+
+	c.client = c.params.Client
+	if c.client == nil {
+		c.client = translate.NewFromConfig(cfg)
+	} else {
+		// A provided client keeps its own settings and gains the
+		// middleware added to cfg above
+		c.client = translate.New(c.client.Options(), func(o *translate.Options) {
+			o.APIOptions = append(o.APIOptions, cfg.APIOptions...)
+		})
+	}
+
+	if c.params.Health != nil {
+		c.params.Health.Register(c.scope, health.Readiness, c.probe)
+	}
+
+	return nil
+}
+
+func (c *TranslateConnector) onStop(ctx context.Context) error {
+
+	c.tracker.Shutdown(ctx)
+
+	c.logger.Info("Stopped TranslateConnector")
+
+	return nil
+}
+
+func (c *TranslateConnector) loadConfig(ctx context.Context) (aws.Config, error) {
+	return awsconfig.Source{
+		Shared:      c.params.AWSConfig,
+		Credentials: c.params.Credentials,
+		Chain:       c.params.CredentialChain,
+		Region:      c.config.TranslateRegion,
+		Key:         c.config.TranslateKey,
+		Secret:      c.config.TranslateSecret,
+		Token:       c.config.TranslateToken,
+	}.Load(ctx, c.scope)
+}
+
+// validate reports every problem with the configuration at once.
+func (c *TranslateConnector) validate() error {
+	v := validation.New(c.scope)
+
+	v.Region("translate_region")
+
+	v.Required("target_language")
+
+	if c.params.Credentials == nil && c.params.CredentialChain == nil && c.params.AWSConfig == nil {
+		v.NotPlaceholder("translate_key", DefaultTranslateKey)
+		v.NotPlaceholder("translate_secret", DefaultTranslateSecret)
+	}
+
+	return v.Err()
+}
+
+// probe lists a terminology.
+func (c *TranslateConnector) probe(ctx context.Context) error {
+	_, err := c.client.ListTerminologies(ctx, &translate.ListTerminologiesInput{
+		MaxResults: aws.Int32(1),
+	})
+
+	return err
+}
+
+func (c *TranslateConnector) GetClient() *translate.Client {
+	return c.client
+}
@@ -0,0 +1,18 @@
+package translate_connector
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/translate/types"
+)
+
+//go:generate mockgen -destination=mocks/mocks.go -package=mocks . Translator
+
+// Translator translates text and documents.
+type Translator interface {
+	TranslateText(ctx context.Context, text string, targetLanguage string) (*Translation, error)
+	StartTranslationJob(ctx context.Context, jobName string, inputPrefix string, targetLanguages ...string) (string, error)
+	WaitForTranslationJob(ctx context.Context, jobID string) (*types.TextTranslationJobProperties, error)
+}
+
+var _ Translator = (*TranslateConnector)(nil)
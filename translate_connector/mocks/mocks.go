@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/elmntri/zeitgeber-aws-modules/translate_connector (interfaces: Translator)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mocks.go -package=mocks . Translator
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	types "github.com/aws/aws-sdk-go-v2/service/translate/types"
+	translate_connector "github.com/elmntri/zeitgeber-aws-modules/translate_connector"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTranslator is a mock of Translator interface.
+type MockTranslator struct {
+	ctrl     *gomock.Controller
+	recorder *MockTranslatorMockRecorder
+}
+
+// MockTranslatorMockRecorder is the mock recorder for MockTranslator.
+type MockTranslatorMockRecorder struct {
+	mock *MockTranslator
+}
+
+// NewMockTranslator creates a new mock instance.
+func NewMockTranslator(ctrl *gomock.Controller) *MockTranslator {
+	mock := &MockTranslator{ctrl: ctrl}
+	mock.recorder = &MockTranslatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTranslator) EXPECT() *MockTranslatorMockRecorder {
+	return m.recorder
+}
+
+// StartTranslationJob mocks base method.
+func (m *MockTranslator) StartTranslationJob(arg0 context.Context, arg1, arg2 string, arg3 ...string) (string, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartTranslationJob", varargs...)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartTranslationJob indicates an expected call of StartTranslationJob.
+func (mr *MockTranslatorMockRecorder) StartTranslationJob(arg0, arg1, arg2 any, arg3 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartTranslationJob", reflect.TypeOf((*MockTranslator)(nil).StartTranslationJob), varargs...)
+}
+
+// TranslateText mocks base method.
+func (m *MockTranslator) TranslateText(arg0 context.Context, arg1, arg2 string) (*translate_connector.Translation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TranslateText", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*translate_connector.Translation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TranslateText indicates an expected call of TranslateText.
+func (mr *MockTranslatorMockRecorder) TranslateText(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TranslateText", reflect.TypeOf((*MockTranslator)(nil).TranslateText), arg0, arg1, arg2)
+}
+
+// WaitForTranslationJob mocks base method.
+func (m *MockTranslator) WaitForTranslationJob(arg0 context.Context, arg1 string) (*types.TextTranslationJobProperties, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForTranslationJob", arg0, arg1)
+	ret0, _ := ret[0].(*types.TextTranslationJobProperties)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForTranslationJob indicates an expected call of WaitForTranslationJob.
+func (mr *MockTranslatorMockRecorder) WaitForTranslationJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForTranslationJob", reflect.TypeOf((*MockTranslator)(nil).WaitForTranslationJob), arg0, arg1)
+}
@@ -0,0 +1,191 @@
+package translate_connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/translate"
+	"github.com/aws/aws-sdk-go-v2/service/translate/types"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+var (
+	ErrNoBucket  = awserrors.New(awserrors.ErrValidation, "no bucket connector")
+	ErrJobFailed = awserrors.New(awserrors.ErrTransient, "translation job failed")
+)
+
+type Translation struct {
+	Text           string
+	SourceLanguage string
+	TargetLanguage string
+}
+
+// TranslateText translates text into targetLanguage, or target_language
+// when empty. With source_language "auto" the source language is
+// detected and returned. The configured terminologies are applied.
+func (c *TranslateConnector) TranslateText(ctx context.Context, text string, targetLanguage string) (_ *Translation, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranslateConnector", "TranslateText")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if targetLanguage == "" {
+		targetLanguage = c.config.TargetLanguage
+	}
+
+	params := &translate.TranslateTextInput{
+		Text:               aws.String(text),
+		SourceLanguageCode: aws.String(c.config.SourceLanguage),
+		TargetLanguageCode: aws.String(targetLanguage),
+	}
+
+	if terminologies := c.config.Terminologies; len(terminologies) > 0 {
+		params.TerminologyNames = terminologies
+	}
+
+	result, err := c.client.TranslateText(ctx, params)
+	if err != nil {
+		c.logger.Error("Translate text error", zap.String("target_language", targetLanguage), zap.Error(err))
+		return nil, err
+	}
+
+	return &Translation{
+		Text:           aws.ToString(result.TranslatedText),
+		SourceLanguage: aws.ToString(result.SourceLanguageCode),
+		TargetLanguage: aws.ToString(result.TargetLanguageCode),
+	}, nil
+}
+
+// StartTranslationJob translates the documents of content_type under
+// inputPrefix in the bucket into targetLanguages, or target_language when
+// none are given, and returns the job ID. Results are written under
+// output_prefix. Translate reads and writes the bucket with
+// data_access_role_arn.
+func (c *TranslateConnector) StartTranslationJob(ctx context.Context, jobName string, inputPrefix string, targetLanguages ...string) (_ string, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranslateConnector", "StartTranslationJob")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	if c.params.Bucket == nil {
+		return "", ErrNoBucket
+	}
+
+	if len(targetLanguages) == 0 {
+		targetLanguages = []string{c.config.TargetLanguage}
+	}
+
+	bucketName := c.params.Bucket.GetBucketName()
+
+	params := &translate.StartTextTranslationJobInput{
+		JobName:           aws.String(jobName),
+		ClientToken:       aws.String(jobName),
+		DataAccessRoleArn: aws.String(c.config.DataAccessRoleARN),
+		InputDataConfig: &types.InputDataConfig{
+			S3Uri:       aws.String(fmt.Sprintf("s3://%s/%s", bucketName, inputPrefix)),
+			ContentType: aws.String(c.config.ContentType),
+		},
+		OutputDataConfig: &types.OutputDataConfig{
+			S3Uri: aws.String(fmt.Sprintf("s3://%s/%s", bucketName, c.config.OutputPrefix)),
+		},
+		SourceLanguageCode:  aws.String(c.config.SourceLanguage),
+		TargetLanguageCodes: targetLanguages,
+	}
+
+	if terminologies := c.config.Terminologies; len(terminologies) > 0 {
+		params.TerminologyNames = terminologies
+	}
+
+	result, err := c.client.StartTextTranslationJob(ctx, params)
+	if err != nil {
+		c.logger.Error("Start translation job error", zap.String("job_name", jobName), zap.Error(err))
+		return "", err
+	}
+
+	return aws.ToString(result.JobId), nil
+}
+
+func (c *TranslateConnector) DescribeTranslationJob(ctx context.Context, jobID string) (_ *types.TextTranslationJobProperties, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranslateConnector", "DescribeTranslationJob")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	result, err := c.client.DescribeTextTranslationJob(ctx, &translate.DescribeTextTranslationJobInput{
+		JobId: aws.String(jobID),
+	})
+	if err != nil {
+		c.logger.Error("Describe translation job error", zap.String("job_id", jobID), zap.Error(err))
+		return nil, err
+	}
+
+	return result.TextTranslationJobProperties, nil
+}
+
+// WaitForTranslationJob polls the job every poll_interval until it ends.
+// Jobs completed with errors for some documents are returned without an
+// error; check JobDetails. Bound the wait with ctx.
+func (c *TranslateConnector) WaitForTranslationJob(ctx context.Context, jobID string) (_ *types.TextTranslationJobProperties, err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranslateConnector", "WaitForTranslationJob")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	interval := time.Duration(c.config.PollInterval) * time.Second
+
+	for {
+		job, err := c.DescribeTranslationJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch job.JobStatus {
+		case types.JobStatusCompleted, types.JobStatusCompletedWithError:
+			return job, nil
+
+		case types.JobStatusFailed, types.JobStatusStopped:
+			return nil, fmt.Errorf("%w: %s: %s: %s", ErrJobFailed, jobID, job.JobStatus, aws.ToString(job.Message))
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// ImportTerminology creates or overwrites a custom terminology from CSV
+// data whose header row lists language codes. Add its name to
+// terminologies to apply it.
+func (c *TranslateConnector) ImportTerminology(ctx context.Context, name string, csv []byte) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranslateConnector", "ImportTerminology")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.ImportTerminology(ctx, &translate.ImportTerminologyInput{
+		Name:          aws.String(name),
+		MergeStrategy: types.MergeStrategyOverwrite,
+		TerminologyData: &types.TerminologyData{
+			File:   csv,
+			Format: types.TerminologyDataFormatCsv,
+		},
+	})
+	if err != nil {
+		c.logger.Error("Import terminology error", zap.String("name", name), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *TranslateConnector) DeleteTerminology(ctx context.Context, name string) (err error) {
+	ctx, span := c.params.Telemetry.Start(ctx, c.scope, "TranslateConnector", "DeleteTerminology")
+	defer func() { c.params.Telemetry.End(span, err) }()
+
+	_, err = c.client.DeleteTerminology(ctx, &translate.DeleteTerminologyInput{
+		Name: aws.String(name),
+	})
+	if err != nil {
+		c.logger.Error("Delete terminology error", zap.String("name", name), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/elmntri/zeitgeber-aws-modules/awsconfig"
+	"github.com/elmntri/zeitgeber-aws-modules/awserrors"
+)
+
+// The environment is a top-level key shared by every module:
+//
+//	aws:
+//	  environment: production
+//
+// Placeholder defaults are accepted in development environments and in
+// local mode only, so an unset environment is treated as production.
+const environmentKey = "aws.environment"
+
+var developmentEnvironments = map[string]bool{
+	"development": true,
+	"dev":         true,
+	"local":       true,
+	"test":        true,
+}
+
+var ErrInvalidConfig = awserrors.New(awserrors.ErrValidation, "invalid configuration")
+
+var (
+	regionPattern     = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-[0-9]+$`)
+	bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+)
+
+// Error lists every problem found in the configuration of a module.
+type Error struct {
+	Scope    string
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Scope, ErrInvalidConfig, strings.Join(e.Problems, "; "))
+}
+
+func (e *Error) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// Development reports whether placeholder defaults are acceptable.
+func Development() bool {
+	return developmentEnvironments[strings.ToLower(viper.GetString(environmentKey))] || awsconfig.LocalMode()
+}
+
+// Validator collects the configuration problems of a module so they are
+// reported together, rather than one per restart. Keys are relative to
+// the module's scope.
+type Validator struct {
+	scope    string
+	problems []string
+}
+
+func New(scope string) *Validator {
+	return &Validator{
+		scope: scope,
+	}
+}
+
+func (v *Validator) get(key string) string {
+	return viper.GetString(fmt.Sprintf("%s.%s", v.scope, key))
+}
+
+// Addf records a problem the other checks don't cover.
+func (v *Validator) Addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// Required checks that keys are set to a non-empty value.
+func (v *Validator) Required(keys ...string) {
+	for _, key := range keys {
+		if v.get(key) == "" {
+			v.Addf("%s is required", key)
+		}
+	}
+}
+
+// OneOf checks that exactly one of keys is set.
+func (v *Validator) OneOf(keys ...string) {
+	set := 0
+	for _, key := range keys {
+		if v.get(key) != "" {
+			set++
+		}
+	}
+
+	if set != 1 {
+		v.Addf("one of %s is required", strings.Join(keys, " and "))
+	}
+}
+
+// NotPlaceholder checks that key was changed from the placeholder it
+// defaults to, outside development environments.
+func (v *Validator) NotPlaceholder(key string, placeholder string) {
+	if v.get(key) == placeholder && !Development() {
+		v.Addf("%s is the placeholder %q; set it, or set %s to development", key, placeholder, environmentKey)
+	}
+}
+
+// Region checks that key names an AWS region, e.g. us-west-1.
+func (v *Validator) Region(key string) {
+	region := v.get(key)
+
+	switch {
+	case region == "":
+		v.Addf("%s is required", key)
+	case !regionPattern.MatchString(region):
+		v.Addf("%s %q is not an AWS region", key, region)
+	}
+}
+
+// BucketName checks that key follows the S3 bucket naming rules.
+func (v *Validator) BucketName(key string) {
+	name := v.get(key)
+	if name == "" {
+		return
+	}
+
+	if !bucketNamePattern.MatchString(name) || strings.Contains(name, "..") || net.ParseIP(name) != nil {
+		v.Addf("%s %q is not a valid bucket name", key, name)
+	}
+}
+
+// Err returns an *Error listing the problems found, or nil.
+func (v *Validator) Err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	return &Error{
+		Scope:    v.scope,
+		Problems: v.problems,
+	}
+}
@@ -0,0 +1,31 @@
+package xray
+
+import (
+	"strings"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+// Config holds the keys of the module under its scope. Each can also be
+// set from the environment, e.g. XRAY_SERVICE_NAME for the xray scope.
+type Config struct {
+	ServiceName         string        `mapstructure:"service_name"`
+	ServiceVersion      string        `mapstructure:"service_version" default:""`
+	DaemonAddress       string        `mapstructure:"daemon_address" default:"127.0.0.1:2000"`
+	ContextMissing      string        `mapstructure:"context_missing" default:"ignore"`
+	CentralizedSampling bool          `mapstructure:"centralized_sampling" default:"false"`
+	SamplingFixedTarget int           `mapstructure:"sampling_fixed_target" default:"1"`
+	SamplingRate        float64       `mapstructure:"sampling_rate" default:"0.05"`
+	SamplingRules       []interface{} `mapstructure:"sampling_rules"`
+}
+
+func (t *Tracer) initDefaultConfigs() {
+	moduleconfig.Register(t.scope, &Config{
+		ServiceName: strings.ReplaceAll(t.scope, ".", "-"),
+	})
+}
+
+// Config returns the configuration the module started with.
+func (t *Tracer) Config() Config {
+	return t.config
+}
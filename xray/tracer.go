@@ -0,0 +1,241 @@
+package xray
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-xray-sdk-go/instrumentation/awsv2"
+	"github.com/aws/aws-xray-sdk-go/strategy/ctxmissing"
+	"github.com/aws/aws-xray-sdk-go/strategy/sampling"
+	awsxray "github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/aws/aws-xray-sdk-go/xraylog"
+
+	"github.com/elmntri/zeitgeber-aws-modules/moduleconfig"
+)
+
+var logger *zap.Logger
+
+const (
+	DefaultDaemonAddress       = "127.0.0.1:2000"
+	DefaultServiceVersion      = ""
+	DefaultContextMissing      = "ignore"
+	DefaultCentralizedSampling = false
+	DefaultSamplingFixedTarget = 1
+	DefaultSamplingRate        = 0.05
+)
+
+// Tracer sends traces to the X-Ray daemon. Connectors given a Tracer
+// record a subsegment for every AWS call made within a traced context.
+// Add the module before the connectors so it is configured before they
+// start.
+type Tracer struct {
+	params Params
+	logger *zap.Logger
+	scope  string
+	config Config
+}
+
+type Params struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+}
+
+func Module(scope string) fx.Option {
+
+	var t *Tracer
+
+	return fx.Module(
+		scope,
+		fx.Provide(func(p Params) (*Tracer, error) {
+
+			logger = p.Logger.Named(scope)
+
+			t := &Tracer{
+				params: p,
+				logger: logger,
+				scope:  scope,
+			}
+
+			t.initDefaultConfigs()
+
+			// Loaded here, as connectors may start before the module
+			if err := moduleconfig.Load(scope, &t.config); err != nil {
+				return nil, err
+			}
+
+			return t, nil
+		}),
+		fx.Populate(&t),
+		fx.Invoke(func(p Params) {
+
+			p.Lifecycle.Append(
+				fx.Hook{
+					OnStart: t.onStart,
+					OnStop:  t.onStop,
+				},
+			)
+		}),
+	)
+}
+
+func (t *Tracer) onStart(ctx context.Context) error {
+	centralized := t.config.CentralizedSampling
+
+	logger.Info("Starting X-Ray tracer",
+		zap.String("service_name", t.ServiceName()),
+		zap.String("daemon_address", t.config.DaemonAddress),
+		zap.Bool("centralized_sampling", centralized),
+	)
+
+	strategy, err := t.samplingStrategy(centralized)
+	if err != nil {
+		t.logger.Error("Load sampling rules error", zap.Error(err))
+		return err
+	}
+
+	contextMissing, err := t.contextMissingStrategy()
+	if err != nil {
+		return err
+	}
+
+	awsxray.SetLogger(&zapLogger{logger: t.logger})
+
+	err = awsxray.Configure(awsxray.Config{
+		DaemonAddr:             t.config.DaemonAddress,
+		ServiceVersion:         t.config.ServiceVersion,
+		SamplingStrategy:       strategy,
+		ContextMissingStrategy: contextMissing,
+	})
+	if err != nil {
+		t.logger.Error("Configure X-Ray error", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (t *Tracer) onStop(ctx context.Context) error {
+
+	t.logger.Info("Stopped X-Ray tracer")
+
+	return nil
+}
+
+// samplingStrategy builds the version 2 sampling document from
+// sampling_fixed_target, sampling_rate and sampling_rules, a list of
+// rules such as
+//
+//   - description: health checks
+//     url_path: /healthz
+//     fixed_target: 0
+//     rate: 0
+//
+// With centralized_sampling, the rules defined in X-Ray take precedence
+// and the document only applies until they are fetched.
+func (t *Tracer) samplingStrategy(centralized bool) (sampling.Strategy, error) {
+	rules := t.config.SamplingRules
+	if rules == nil {
+		rules = []interface{}{}
+	}
+
+	document, err := json.Marshal(map[string]interface{}{
+		"version": 2,
+		"default": map[string]interface{}{
+			"fixed_target": t.config.SamplingFixedTarget,
+			"rate":         t.config.SamplingRate,
+		},
+		"rules": rules,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if centralized {
+		return sampling.NewCentralizedStrategyWithJSONBytes(document)
+	}
+
+	return sampling.NewLocalizedStrategyFromJSONBytes(document)
+}
+
+// contextMissingStrategy picks what happens to AWS calls made outside a
+// segment, such as those of background pollers: "ignore" skips tracing
+// them, "log" logs an error and "panic" panics.
+func (t *Tracer) contextMissingStrategy() (ctxmissing.Strategy, error) {
+	switch contextMissing := t.config.ContextMissing; contextMissing {
+	case "ignore":
+		return ctxmissing.NewDefaultIgnoreErrorStrategy(), nil
+	case "log":
+		return ctxmissing.NewDefaultLogErrorStrategy(), nil
+	case "panic":
+		return ctxmissing.NewDefaultRuntimeErrorStrategy(), nil
+	default:
+		return nil, fmt.Errorf("%s: unknown context_missing %q", t.scope, contextMissing)
+	}
+}
+
+// ServiceName is the name of the segments the tracer begins.
+func (t *Tracer) ServiceName() string {
+	return t.config.ServiceName
+}
+
+// Instrument adds X-Ray middleware to the clients created from cfg.
+// Call it before creating them.
+func (t *Tracer) Instrument(cfg *aws.Config) {
+	awsv2.AWSV2Instrumentor(&cfg.APIOptions)
+}
+
+// BeginSegment starts a segment for work that does not come in through
+// Handler, such as a job or a consumed message. Close the segment when
+// the work is done.
+func (t *Tracer) BeginSegment(ctx context.Context, name string) (context.Context, *awsxray.Segment) {
+	return awsxray.BeginSegment(ctx, name)
+}
+
+// Segment runs fn in a new segment named name and closes it with fn's
+// error.
+func (t *Tracer) Segment(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	ctx, seg := awsxray.BeginSegment(ctx, name)
+
+	err := fn(ctx)
+	seg.Close(err)
+
+	return err
+}
+
+// Subsegment runs fn in a subsegment of the segment in ctx and records
+// fn's error on it.
+func (t *Tracer) Subsegment(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	return awsxray.Capture(ctx, name, fn)
+}
+
+// Handler begins a segment named after the service for every request to
+// h, continuing the trace of an incoming X-Amzn-Trace-Id header.
+func (t *Tracer) Handler(h http.Handler) http.Handler {
+	return awsxray.Handler(awsxray.NewFixedSegmentNamer(t.ServiceName()), h)
+}
+
+// zapLogger routes the X-Ray SDK's logs to the tracer's logger.
+type zapLogger struct {
+	logger *zap.Logger
+}
+
+func (l *zapLogger) Log(level xraylog.LogLevel, msg fmt.Stringer) {
+	switch level {
+	case xraylog.LogLevelDebug:
+		l.logger.Debug(msg.String())
+	case xraylog.LogLevelInfo:
+		l.logger.Info(msg.String())
+	case xraylog.LogLevelWarn:
+		l.logger.Warn(msg.String())
+	default:
+		l.logger.Error(msg.String())
+	}
+}